@@ -66,6 +66,29 @@ func (_m *AddressBook) DeleteContact(id uint64) error {
 	return r0
 }
 
+// FindContactsByPrefix provides a mock function with given fields: prefix
+func (_m *AddressBook) FindContactsByPrefix(prefix string) ([]core.Contact, error) {
+	ret := _m.Called(prefix)
+
+	var r0 []core.Contact
+	if rf, ok := ret.Get(0).(func(string) []core.Contact); ok {
+		r0 = rf(prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Contact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetContact provides a mock function with given fields: id
 func (_m *AddressBook) GetContact(id uint64) (core.Contact, error) {
 	ret := _m.Called(id)
@@ -212,6 +235,165 @@ func (_m *AddressBook) ListContact() ([]core.Contact, error) {
 	return r0, r1
 }
 
+// ListContactsByCoin provides a mock function with given fields: coinType
+func (_m *AddressBook) ListContactsByCoin(coinType string) ([]core.Contact, error) {
+	ret := _m.Called(coinType)
+
+	var r0 []core.Contact
+	if rf, ok := ret.Get(0).(func(string) []core.Contact); ok {
+		r0 = rf(coinType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Contact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(coinType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListContactsByGroup provides a mock function with given fields: group
+func (_m *AddressBook) ListContactsByGroup(group string) ([]core.Contact, error) {
+	ret := _m.Called(group)
+
+	var r0 []core.Contact
+	if rf, ok := ret.Get(0).(func(string) []core.Contact); ok {
+		r0 = rf(group)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Contact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFavouriteContacts provides a mock function with given fields:
+func (_m *AddressBook) ListFavouriteContacts() ([]core.Contact, error) {
+	ret := _m.Called()
+
+	var r0 []core.Contact
+	if rf, ok := ret.Get(0).(func() []core.Contact); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Contact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListGroups provides a mock function with given fields:
+func (_m *AddressBook) ListGroups() ([]string, error) {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LookupAddress provides a mock function with given fields: address, coinType
+func (_m *AddressBook) LookupAddress(address string, coinType string) (core.Contact, error) {
+	ret := _m.Called(address, coinType)
+
+	var r0 core.Contact
+	if rf, ok := ret.Get(0).(func(string, string) core.Contact); ok {
+		r0 = rf(address, coinType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(core.Contact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(address, coinType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecoverSecurity provides a mock function with given fields: previousPassword
+func (_m *AddressBook) RecoverSecurity(previousPassword string) (int, error) {
+	ret := _m.Called(previousPassword)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(previousPassword)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(previousPassword)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchContacts provides a mock function with given fields: query
+func (_m *AddressBook) SearchContacts(query string) ([]core.Contact, error) {
+	ret := _m.Called(query)
+
+	var r0 []core.Contact
+	if rf, ok := ret.Get(0).(func(string) []core.Contact); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Contact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateContact provides a mock function with given fields: id, contact
 func (_m *AddressBook) UpdateContact(id uint64, contact core.Contact) error {
 	ret := _m.Called(id, contact)
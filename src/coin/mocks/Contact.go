@@ -26,6 +26,22 @@ func (_m *Contact) GetAddresses() []core.StringAddress {
 	return r0
 }
 
+// GetGroups provides a mock function with given fields:
+func (_m *Contact) GetGroups() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // GetID provides a mock function with given fields:
 func (_m *Contact) GetID() uint64 {
 	ret := _m.Called()
@@ -54,6 +70,34 @@ func (_m *Contact) GetName() string {
 	return r0
 }
 
+// GetNotes provides a mock function with given fields:
+func (_m *Contact) GetNotes() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// IsFavourite provides a mock function with given fields:
+func (_m *Contact) IsFavourite() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // IsValid provides a mock function with given fields:
 func (_m *Contact) IsValid() bool {
 	ret := _m.Called()
@@ -73,6 +117,16 @@ func (_m *Contact) SetAddresses(_a0 []core.StringAddress) {
 	_m.Called(_a0)
 }
 
+// SetFavourite provides a mock function with given fields: _a0
+func (_m *Contact) SetFavourite(_a0 bool) {
+	_m.Called(_a0)
+}
+
+// SetGroups provides a mock function with given fields: _a0
+func (_m *Contact) SetGroups(_a0 []string) {
+	_m.Called(_a0)
+}
+
 // SetID provides a mock function with given fields: id
 func (_m *Contact) SetID(id uint64) {
 	_m.Called(id)
@@ -82,3 +136,8 @@ func (_m *Contact) SetID(id uint64) {
 func (_m *Contact) SetName(_a0 string) {
 	_m.Called(_a0)
 }
+
+// SetNotes provides a mock function with given fields: _a0
+func (_m *Contact) SetNotes(_a0 string) {
+	_m.Called(_a0)
+}
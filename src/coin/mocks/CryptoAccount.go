@@ -31,6 +31,29 @@ func (_m *CryptoAccount) GetBalance(ticker string) (uint64, error) {
 	return r0, r1
 }
 
+// GetBalanceBreakdown provides a mock function with given fields: ticker
+func (_m *CryptoAccount) GetBalanceBreakdown(ticker string) (*core.Balance, error) {
+	ret := _m.Called(ticker)
+
+	var r0 *core.Balance
+	if rf, ok := ret.Get(0).(func(string) *core.Balance); ok {
+		r0 = rf(ticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Balance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListAssets provides a mock function with given fields:
 func (_m *CryptoAccount) ListAssets() []string {
 	ret := _m.Called()
@@ -87,7 +110,7 @@ func (_m *CryptoAccount) ListTransactions() core.TransactionIterator {
 }
 
 // ScanUnspentOutputs provides a mock function with given fields:
-func (_m *CryptoAccount) ScanUnspentOutputs() core.TransactionOutputIterator {
+func (_m *CryptoAccount) ScanUnspentOutputs() (core.TransactionOutputIterator, error) {
 	ret := _m.Called()
 
 	var r0 core.TransactionOutputIterator
@@ -99,5 +122,12 @@ func (_m *CryptoAccount) ScanUnspentOutputs() core.TransactionOutputIterator {
 		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
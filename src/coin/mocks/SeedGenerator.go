@@ -9,20 +9,87 @@ type SeedGenerator struct {
 	mock.Mock
 }
 
-// GenerateMnemonic provides a mock function with given fields: wordCount
-func (_m *SeedGenerator) GenerateMnemonic(wordCount int) (string, error) {
-	ret := _m.Called(wordCount)
+// CompleteWord provides a mock function with given fields: prefix, language
+func (_m *SeedGenerator) CompleteWord(prefix string, language string) ([]string, error) {
+	ret := _m.Called(prefix, language)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, string) []string); ok {
+		r0 = rf(prefix, language)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(prefix, language)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateMnemonic provides a mock function with given fields: wordCount, language
+func (_m *SeedGenerator) GenerateMnemonic(wordCount int, language string) (string, error) {
+	ret := _m.Called(wordCount, language)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int, string) string); ok {
+		r0 = rf(wordCount, language)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(wordCount, language)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MnemonicLanguage provides a mock function with given fields: seed
+func (_m *SeedGenerator) MnemonicLanguage(seed string) (string, error) {
+	ret := _m.Called(seed)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(int) string); ok {
-		r0 = rf(wordCount)
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(seed)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(wordCount)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(seed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestWords provides a mock function with given fields: word, language
+func (_m *SeedGenerator) SuggestWords(word string, language string) ([]string, error) {
+	ret := _m.Called(word, language)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, string) []string); ok {
+		r0 = rf(word, language)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(word, language)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -30,6 +97,22 @@ func (_m *SeedGenerator) GenerateMnemonic(wordCount int) (string, error) {
 	return r0, r1
 }
 
+// SupportedLanguages provides a mock function with given fields:
+func (_m *SeedGenerator) SupportedLanguages() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // VerifyMnemonic provides a mock function with given fields: seed
 func (_m *SeedGenerator) VerifyMnemonic(seed string) (bool, error) {
 	ret := _m.Called(seed)
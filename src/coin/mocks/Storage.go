@@ -2,6 +2,7 @@
 
 package mocks
 
+import core "github.com/fibercrypto/fibercryptowallet/src/core"
 import mock "github.com/stretchr/testify/mock"
 
 // Storage is an autogenerated mock type for the Storage type
@@ -148,6 +149,46 @@ func (_m *Storage) Path() string {
 	return r0
 }
 
+// SchemaVersion provides a mock function with given fields:
+func (_m *Storage) SchemaVersion() (uint64, error) {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCollectionCodec provides a mock function with given fields: collection, codec
+func (_m *Storage) SetCollectionCodec(collection string, codec core.StorageCodec) {
+	_m.Called(collection, codec)
+}
+
+// UpdateCollections provides a mock function with given fields: fn
+func (_m *Storage) UpdateCollections(fn func(core.StorageTxn) error) error {
+	ret := _m.Called(fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(func(core.StorageTxn) error) error); ok {
+		r0 = rf(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateValue provides a mock function with given fields: key, newValue
 func (_m *Storage) UpdateValue(key uint64, newValue interface{}) error {
 	ret := _m.Called(key, newValue)
@@ -161,3 +202,17 @@ func (_m *Storage) UpdateValue(key uint64, newValue interface{}) error {
 
 	return r0
 }
+
+// ViewCollections provides a mock function with given fields: fn
+func (_m *Storage) ViewCollections(fn func(core.StorageTxn) error) error {
+	ret := _m.Called(fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(func(core.StorageTxn) error) error); ok {
+		r0 = rf(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -25,6 +25,20 @@ func (_m *StringAddress) GetCoinType() []byte {
 	return r0
 }
 
+// GetLabel provides a mock function with given fields:
+func (_m *StringAddress) GetLabel() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetValue provides a mock function with given fields:
 func (_m *StringAddress) GetValue() []byte {
 	ret := _m.Called()
@@ -60,6 +74,11 @@ func (_m *StringAddress) SetCoinType(val []byte) {
 	_m.Called(val)
 }
 
+// SetLabel provides a mock function with given fields: _a0
+func (_m *StringAddress) SetLabel(_a0 string) {
+	_m.Called(_a0)
+}
+
 // SetValue provides a mock function with given fields: val
 func (_m *StringAddress) SetValue(val []byte) {
 	_m.Called(val)
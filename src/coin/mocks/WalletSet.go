@@ -10,13 +10,34 @@ type WalletSet struct {
 	mock.Mock
 }
 
-// CreateWallet provides a mock function with given fields: name, seed, walletType, isEncryptrd, pwd, scanAddressesN
-func (_m *WalletSet) CreateWallet(name string, seed string, walletType string, isEncryptrd bool, pwd core.PasswordReader, scanAddressesN int) (core.Wallet, error) {
-	ret := _m.Called(name, seed, walletType, isEncryptrd, pwd, scanAddressesN)
+// ArchiveWallet provides a mock function with given fields: id
+func (_m *WalletSet) ArchiveWallet(id string) (string, error) {
+	ret := _m.Called(id)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWallet provides a mock function with given fields: name, seed, seedPassphrase, walletType, isEncryptrd, pwd, scanAddressesN
+func (_m *WalletSet) CreateWallet(name string, seed string, seedPassphrase string, walletType string, isEncryptrd bool, pwd core.PasswordReader, scanAddressesN int) (core.Wallet, error) {
+	ret := _m.Called(name, seed, seedPassphrase, walletType, isEncryptrd, pwd, scanAddressesN)
 
 	var r0 core.Wallet
-	if rf, ok := ret.Get(0).(func(string, string, string, bool, core.PasswordReader, int) core.Wallet); ok {
-		r0 = rf(name, seed, walletType, isEncryptrd, pwd, scanAddressesN)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, bool, core.PasswordReader, int) core.Wallet); ok {
+		r0 = rf(name, seed, seedPassphrase, walletType, isEncryptrd, pwd, scanAddressesN)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(core.Wallet)
@@ -24,8 +45,8 @@ func (_m *WalletSet) CreateWallet(name string, seed string, walletType string, i
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, bool, core.PasswordReader, int) error); ok {
-		r1 = rf(name, seed, walletType, isEncryptrd, pwd, scanAddressesN)
+	if rf, ok := ret.Get(1).(func(string, string, string, string, bool, core.PasswordReader, int) error); ok {
+		r1 = rf(name, seed, seedPassphrase, walletType, isEncryptrd, pwd, scanAddressesN)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -47,6 +68,20 @@ func (_m *WalletSet) DefaultWalletType() string {
 	return r0
 }
 
+// DeleteWallet provides a mock function with given fields: id, confirmation, pwd
+func (_m *WalletSet) DeleteWallet(id string, confirmation string, pwd core.PasswordReader) error {
+	ret := _m.Called(id, confirmation, pwd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, core.PasswordReader) error); ok {
+		r0 = rf(id, confirmation, pwd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetWallet provides a mock function with given fields: id
 func (_m *WalletSet) GetWallet(id string) core.Wallet {
 	ret := _m.Called(id)
@@ -63,6 +98,22 @@ func (_m *WalletSet) GetWallet(id string) core.Wallet {
 	return r0
 }
 
+// ListArchivedWallets provides a mock function with given fields:
+func (_m *WalletSet) ListArchivedWallets() core.WalletIterator {
+	ret := _m.Called()
+
+	var r0 core.WalletIterator
+	if rf, ok := ret.Get(0).(func() core.WalletIterator); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(core.WalletIterator)
+		}
+	}
+
+	return r0
+}
+
 // ListWallets provides a mock function with given fields:
 func (_m *WalletSet) ListWallets() core.WalletIterator {
 	ret := _m.Called()
@@ -79,6 +130,29 @@ func (_m *WalletSet) ListWallets() core.WalletIterator {
 	return r0
 }
 
+// RestoreWallet provides a mock function with given fields: archiveId
+func (_m *WalletSet) RestoreWallet(archiveId string) (core.Wallet, error) {
+	ret := _m.Called(archiveId)
+
+	var r0 core.Wallet
+	if rf, ok := ret.Get(0).(func(string) core.Wallet); ok {
+		r0 = rf(archiveId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(core.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(archiveId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SupportedWalletTypes provides a mock function with given fields:
 func (_m *WalletSet) SupportedWalletTypes() []string {
 	ret := _m.Called()
@@ -10,14 +10,67 @@ type WalletStorage struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: walletName, cryptoType, oldPassword, newPassword
+func (_m *WalletStorage) ChangePassword(walletName string, cryptoType string, oldPassword core.PasswordReader, newPassword core.PasswordReader) error {
+	ret := _m.Called(walletName, cryptoType, oldPassword, newPassword)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, core.PasswordReader, core.PasswordReader) error); ok {
+		r0 = rf(walletName, cryptoType, oldPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CryptoType provides a mock function with given fields: walletName
+func (_m *WalletStorage) CryptoType(walletName string) (string, error) {
+	ret := _m.Called(walletName)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(walletName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Decrypt provides a mock function with given fields: walletName, password
-func (_m *WalletStorage) Decrypt(walletName string, password core.PasswordReader) {
-	_m.Called(walletName, password)
+func (_m *WalletStorage) Decrypt(walletName string, password core.PasswordReader) error {
+	ret := _m.Called(walletName, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, core.PasswordReader) error); ok {
+		r0 = rf(walletName, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
-// Encrypt provides a mock function with given fields: walletName, password
-func (_m *WalletStorage) Encrypt(walletName string, password core.PasswordReader) {
-	_m.Called(walletName, password)
+// Encrypt provides a mock function with given fields: walletName, cryptoType, password
+func (_m *WalletStorage) Encrypt(walletName string, cryptoType string, password core.PasswordReader) error {
+	ret := _m.Called(walletName, cryptoType, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, core.PasswordReader) error); ok {
+		r0 = rf(walletName, cryptoType, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // IsEncrypted provides a mock function with given fields: walletName
@@ -40,3 +93,33 @@ func (_m *WalletStorage) IsEncrypted(walletName string) (bool, error) {
 
 	return r0, r1
 }
+
+// SupportedCryptoTypes provides a mock function with given fields:
+func (_m *WalletStorage) SupportedCryptoTypes() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// UpgradeEncryption provides a mock function with given fields: walletName, password
+func (_m *WalletStorage) UpgradeEncryption(walletName string, password core.PasswordReader) error {
+	ret := _m.Called(walletName, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, core.PasswordReader) error); ok {
+		r0 = rf(walletName, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
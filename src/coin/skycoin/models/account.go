@@ -3,6 +3,7 @@ package skycoin // nolint goimports
 import (
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/SkycoinProject/skycoin/src/cli"
 	"github.com/SkycoinProject/skycoin/src/readable"
@@ -39,6 +40,12 @@ func (addr *SkycoinAddress) GetBalance(ticker string) (uint64, error) {
 		return 0, errorTickerInvalid{ticker}
 	}
 }
+
+// GetBalanceBreakdown retrieves the funds breakdown of the address for asset represented by ticker
+func (addr *SkycoinAddress) GetBalanceBreakdown(ticker string) (*core.Balance, error) {
+	return getBalanceBreakdown(PoolSection, []string{addr.String()}, ticker)
+}
+
 func (addr *SkycoinAddress) ListAssets() []string {
 	return []string{Sky, CoinHour}
 }
@@ -133,6 +140,24 @@ func (wlt *RemoteWallet) GetBalance(ticker string) (uint64, error) {
 
 }
 
+// GetBalanceBreakdown retrieves the funds breakdown of the wallet for asset represented by ticker
+func (wlt *RemoteWallet) GetBalanceBreakdown(ticker string) (*core.Balance, error) {
+	if ticker != Sky && ticker != CoinHour {
+		return nil, errorTickerInvalid{ticker}
+	}
+	log.Info("Calling RemoteWallet.GetLoadedAddresses()")
+	addressesIter, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		log.WithError(err).Error("RemoteWallet.GetLoadedAddresses() failed")
+		return nil, err
+	}
+	addrs := make([]string, 0)
+	for addressesIter.Next() {
+		addrs = append(addrs, addressesIter.Value().String())
+	}
+	return getBalanceBreakdown(wlt.poolSection, addrs, ticker)
+}
+
 func (wlt *RemoteWallet) ListAssets() []string {
 	return []string{Sky, CoinHour}
 }
@@ -196,19 +221,28 @@ func (wlt *RemoteWallet) ListPendingTransactions() (core.TransactionIterator, er
 	return NewSkycoinTransactionIterator(txns), nil
 }
 
-func (wlt *LocalWallet) updateBalances() error {
+// addressStrings loads the addresses stored in the wallet file
+func (wlt *LocalWallet) addressStrings() ([]string, error) {
 	walletName := filepath.Join(wlt.WalletDir, wlt.Id)
 	log.WithField("walletName", walletName).Info("Calling wallet.Load(walletName)")
 	walletLoaded, err := wallet.Load(walletName)
 	if err != nil {
 		log.WithError(err).Error("wallet.Load(walletName) failed")
-		return err
+		return nil, err
 	}
 	var addrs []string
 	addresses := walletLoaded.GetAddresses()
 	for _, addr := range addresses {
 		addrs = append(addrs, addr.String())
 	}
+	return addrs, nil
+}
+
+func (wlt *LocalWallet) updateBalances() error {
+	addrs, err := wlt.addressStrings()
+	if err != nil {
+		return err
+	}
 
 	c, err := NewSkycoinApiClient(PoolSection)
 	if err != nil {
@@ -269,6 +303,18 @@ func (wlt *LocalWallet) GetBalance(ticker string) (uint64, error) {
 	return 0, errorTickerInvalid{ticker}
 }
 
+// GetBalanceBreakdown retrieves the funds breakdown of the wallet for asset represented by ticker
+func (wlt *LocalWallet) GetBalanceBreakdown(ticker string) (*core.Balance, error) {
+	if ticker != Sky && ticker != CoinHour {
+		return nil, errorTickerInvalid{ticker}
+	}
+	addrs, err := wlt.addressStrings()
+	if err != nil {
+		return nil, err
+	}
+	return getBalanceBreakdown(PoolSection, addrs, ticker)
+}
+
 func (wlt *LocalWallet) ListAssets() []string {
 	return []string{Sky, CoinHour}
 }
@@ -483,3 +529,80 @@ func getBalanceOfAddresses(outs *readable.UnspentOutputsSummary, addrs []string)
 
 	return balRlt, nil
 }
+
+// getBalanceBreakdown requests unspent outputs of addrs to the node and computes the funds breakdown
+func getBalanceBreakdown(poolSection string, addrs []string, ticker string) (*core.Balance, error) {
+	if ticker != Sky && ticker != CoinHour {
+		return nil, errorTickerInvalid{ticker}
+	}
+	c, err := NewSkycoinApiClient(poolSection)
+	if err != nil {
+		log.WithError(err).Error("Couldn't get API client")
+		return nil, err
+	}
+	defer ReturnSkycoinClient(c)
+	log.Info("POST /api/v1/outputs?addrs=xxx")
+	outs, err := c.OutputsForAddresses(addrs)
+	if err != nil {
+		log.WithError(err).WithField("Length of addrs", len(addrs)).Error("Couldn't POST /api/v1/outputs?addrs=xxx")
+		return nil, err
+	}
+	return balanceBreakdown(outs, ticker, uint64(time.Now().UTC().Unix()))
+}
+
+// balanceBreakdown computes the funds breakdown out of an unspent outputs summary.
+// Coin hours accrued are estimated from head block time up to now.
+func balanceBreakdown(outs *readable.UnspentOutputsSummary, ticker string, now uint64) (*core.Balance, error) {
+	confirmedCoins, confirmedHours, err := sumUnspentOutputs(outs.HeadOutputs)
+	if err != nil {
+		return nil, err
+	}
+	spendableCoins, spendableHours, err := sumUnspentOutputs(outs.SpendableOutputs())
+	if err != nil {
+		return nil, err
+	}
+	predictedCoins, predictedHours, err := sumUnspentOutputs(outs.ExpectedOutputs())
+	if err != nil {
+		return nil, err
+	}
+
+	switch ticker {
+	case Sky:
+		return &core.Balance{
+			Confirmed: confirmedCoins,
+			Predicted: predictedCoins,
+			Spendable: spendableCoins,
+			Locked:    confirmedCoins - spendableCoins,
+		}, nil
+	case CoinHour:
+		bl := &core.Balance{
+			Confirmed: confirmedHours,
+			Predicted: predictedHours,
+			Spendable: spendableHours,
+			Locked:    confirmedHours - spendableHours,
+		}
+		if now > outs.Head.Time {
+			seconds := now - outs.Head.Time
+			wholeCoinSeconds := seconds * (confirmedCoins / droplet.Multiplier)
+			dropletSeconds := seconds * (confirmedCoins % droplet.Multiplier) / droplet.Multiplier
+			bl.Accrued = (wholeCoinSeconds + dropletSeconds) / 3600
+		}
+		return bl, nil
+	}
+	return nil, errorTickerInvalid{ticker}
+}
+
+// sumUnspentOutputs adds up droplets and calculated hours of outputs
+func sumUnspentOutputs(outs readable.UnspentOutputs) (uint64, uint64, error) {
+	var coins, hours uint64
+	for _, o := range outs {
+		amt, err := droplet.FromString(o.Coins)
+		if err != nil {
+			log.WithError(err).Error("droplet.FromString failed")
+			return 0, 0, errors.ErrParseTxnCoins
+		}
+		coins += amt
+		hours += o.CalculatedHours
+	}
+	return coins, hours, nil
+}
@@ -586,3 +586,82 @@ func Test_getBalanceOfAddresses(t *testing.T) {
 		})
 	}
 }
+
+func TestBalanceBreakdown(t *testing.T) {
+	head := readable.UnspentOutput{Hash: "head", Coins: "10", CalculatedHours: 100}
+	spending := readable.UnspentOutput{Hash: "spending", Coins: "2.5", CalculatedHours: 20}
+	incoming := readable.UnspentOutput{Hash: "incoming", Coins: "1", CalculatedHours: 5}
+	outs := &readable.UnspentOutputsSummary{
+		Head:            readable.BlockHeader{Time: 1000},
+		HeadOutputs:     readable.UnspentOutputs{head, spending},
+		OutgoingOutputs: readable.UnspentOutputs{spending},
+		IncomingOutputs: readable.UnspentOutputs{incoming},
+	}
+
+	tests := []struct {
+		name   string
+		ticker string
+		now    uint64
+		want   *core.Balance
+	}{
+		{name: "sky", ticker: Sky, now: 1000 + 3600, want: &core.Balance{
+			Confirmed: 12500000, Predicted: 11000000, Spendable: 10000000, Locked: 2500000,
+		}},
+		{name: "hours", ticker: CoinHour, now: 1000 + 3600, want: &core.Balance{
+			Confirmed: 120, Predicted: 105, Spendable: 100, Locked: 20, Accrued: 12,
+		}},
+		{name: "hours-at-head", ticker: CoinHour, now: 1000, want: &core.Balance{
+			Confirmed: 120, Predicted: 105, Spendable: 100, Locked: 20,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bl, err := balanceBreakdown(outs, tt.ticker, tt.now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, bl)
+		})
+	}
+
+	_, err := balanceBreakdown(outs, "INVALID_TICKER", 0)
+	require.Error(t, err)
+	outs.IncomingOutputs[0].Coins = "invalid"
+	_, err = balanceBreakdown(outs, Sky, 0)
+	require.Error(t, err)
+}
+
+func TestAccountsGetBalanceBreakdown(t *testing.T) {
+	CleanGlobalMock()
+	out := readable.UnspentOutput{Hash: "hash1", Coins: "42", CalculatedHours: 42}
+	response := &readable.UnspentOutputsSummary{
+		HeadOutputs:     readable.UnspentOutputs{out},
+		OutgoingOutputs: readable.UnspentOutputs{out},
+	}
+	addr, err := NewSkycoinAddress("2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt")
+	require.NoError(t, err)
+	global_mock.On("OutputsForAddresses", []string{addr.String()}).Return(response, errors.New("failure")).Once()
+	global_mock.On("OutputsForAddresses", []string{addr.String()}).Return(response, nil)
+	wltAddrs := []string{
+		"2HPiZkMTD2pB9FZ6HbCxFSXa1FGeNkLeEbP",
+		"7wqRjpVwg5uSsz72oAZcDrBevHQRHQudyj",
+		"2G9wDPX14WsbZuZU1f7MveYc9vpLxj2qNsz",
+		"6gnBM5gMSSb7XRUEap7q3WxFnuvbN9usTq",
+	}
+	global_mock.On("OutputsForAddresses", wltAddrs).Return(response, nil)
+
+	account := addr.GetCryptoAccount()
+	_, err = account.GetBalanceBreakdown(Sky)
+	require.Error(t, err)
+
+	for _, account := range []core.CryptoAccount{account, &LocalWallet{WalletDir: "./testdata", Id: "test.wlt"}} {
+		bl, err := account.GetBalanceBreakdown(Sky)
+		require.NoError(t, err)
+		require.Equal(t, &core.Balance{Confirmed: 42000000, Predicted: 0, Spendable: 0, Locked: 42000000}, bl)
+		_, err = account.GetBalanceBreakdown("INVALID_TICKER")
+		require.Error(t, err)
+	}
+
+	_, err = (&LocalWallet{WalletDir: "./testdata", Id: "no_wallet.wlt"}).GetBalanceBreakdown(Sky)
+	require.Error(t, err)
+	_, err = (&RemoteWallet{Id: "wallet", poolSection: PoolSection}).GetBalanceBreakdown("INVALID_TICKER")
+	require.Error(t, err)
+}
@@ -86,7 +86,7 @@ type SkycoinBlockchainInfo struct {
 }
 
 type SkycoinBlockchain struct { //Implements BlockchainStatus interface
-	lastTimeStatusRequested uint64 //nolint structcheck TODO: Not used
+	lastTimeStatusRequested uint64
 	lastTimeSupplyRequested uint64
 	CacheTime               uint64
 	cachedStatus            *SkycoinBlockchainInfo
@@ -125,8 +125,8 @@ func (ss *SkycoinBlockchain) GetCoinValue(coinvalue core.CoinValueMetric, ticker
 
 func (ss *SkycoinBlockchain) GetLastBlock() (core.Block, error) {
 	logBlockchain.Info("Getting last block")
-	elapsed := uint64(time.Now().UTC().UnixNano()) - ss.lastTimeSupplyRequested
-	if elapsed > ss.CacheTime || ss.cachedStatus == nil {
+	elapsed := uint64(time.Now().UTC().UnixNano()) - ss.lastTimeStatusRequested
+	if elapsed > ss.CacheTime || ss.cachedStatus == nil || ss.cachedStatus.LastBlockInfo == nil {
 		if ss.cachedStatus == nil {
 			ss.cachedStatus = new(SkycoinBlockchainInfo)
 		}
@@ -139,8 +139,11 @@ func (ss *SkycoinBlockchain) GetLastBlock() (core.Block, error) {
 
 func (ss *SkycoinBlockchain) GetNumberOfBlocks() (uint64, error) {
 	logBlockchain.Info("Getting number of blocks")
-	if ss.cachedStatus == nil {
-		ss.cachedStatus = new(SkycoinBlockchainInfo)
+	elapsed := uint64(time.Now().UTC().UnixNano()) - ss.lastTimeStatusRequested
+	if elapsed > ss.CacheTime || ss.cachedStatus == nil || ss.cachedStatus.NumberOfBlocks == nil {
+		if ss.cachedStatus == nil {
+			ss.cachedStatus = new(SkycoinBlockchainInfo)
+		}
 		if err := ss.requestStatusInfo(); err != nil {
 			logBlockchain.Errorf("Skycoin node API error for status info %s", err)
 			return 0, err
@@ -234,29 +237,150 @@ func (ss *SkycoinBlockchain) requestStatusInfo() error {
 		Highest: progress.Highest,
 		Peers:   progress.Peers,
 	}
+	ss.lastTimeStatusRequested = uint64(time.Now().UTC().UnixNano())
 
 	return nil
 }
 
+// scanBlocksBatch blocks requested at once while scanning address history
+const scanBlocksBatch = 100
+
+// ScanBlockTxns lists transactions in blocks startSeq to endSeq , both included , involving any of addresses
+func (ss *SkycoinBlockchain) ScanBlockTxns(addresses []string, startSeq, endSeq uint64) ([]core.Transaction, error) {
+	logBlockchain.Info("Scanning blocks for address history")
+	c, err := NewSkycoinApiClient(PoolSection)
+	if err != nil {
+		logBlockchain.WithError(err).Warn("Couldn't load client")
+		return nil, err
+	}
+	defer ReturnSkycoinClient(c)
+
+	watched := make(map[string]struct{}, len(addresses))
+	for _, addr := range addresses {
+		watched[addr] = struct{}{}
+	}
+	involves := func(txn readable.BlockTransactionVerbose) bool {
+		for _, in := range txn.In {
+			if _, isWatched := watched[in.Address]; isWatched {
+				return true
+			}
+		}
+		for _, out := range txn.Out {
+			if _, isWatched := watched[out.Address]; isWatched {
+				return true
+			}
+		}
+		return false
+	}
+
+	txns := make([]core.Transaction, 0)
+	for start := startSeq; start <= endSeq; start += scanBlocksBatch {
+		end := start + scanBlocksBatch - 1
+		if end > endSeq {
+			end = endSeq
+		}
+		logBlockchain.Info("GET /api/v1/blocks?verbose=1")
+		blocks, err := c.BlocksInRangeVerbose(start, end)
+		if err != nil {
+			logBlockchain.WithError(err).Warn("Couldn't get blocks")
+			return nil, err
+		}
+		for _, block := range blocks.Blocks {
+			for _, txn := range block.Body.Transactions {
+				if !involves(txn) {
+					continue
+				}
+				txns = append(txns, &SkycoinTransaction{
+					skyTxn: readable.TransactionVerbose{
+						Status: &readable.TransactionStatus{
+							Confirmed: true,
+							Height:    endSeq - block.Head.BkSeq + 1,
+							BlockSeq:  block.Head.BkSeq,
+						},
+						Timestamp:               block.Head.Time,
+						BlockTransactionVerbose: txn,
+					},
+					status: core.TXN_STATUS_CONFIRMED,
+				})
+			}
+		}
+	}
+	return txns, nil
+}
+
+// PendingTxns lists unconfirmed transactions involving any of addresses
+func (ss *SkycoinBlockchain) PendingTxns(addresses []string) ([]core.Transaction, error) {
+	logBlockchain.Info("Getting pending transactions of addresses")
+	c, err := NewSkycoinApiClient(PoolSection)
+	if err != nil {
+		logBlockchain.WithError(err).Warn("Couldn't load client")
+		return nil, err
+	}
+	defer ReturnSkycoinClient(c)
+	logBlockchain.Info("POST /api/v1/transactions?verbose=1&confirmed=0")
+	pending, err := c.UnconfirmedTransactionsVerbose(addresses)
+	if err != nil {
+		logBlockchain.WithError(err).Warn("Couldn't get pending transactions")
+		return nil, err
+	}
+	txns := make([]core.Transaction, len(pending))
+	for i, txn := range pending {
+		txns[i] = &SkycoinTransaction{
+			skyTxn: txn.Transaction,
+			status: core.TXN_STATUS_PENDING,
+		}
+	}
+	return txns, nil
+}
+
 // SendFromAddress instantiates a transaction to send funds from specific source addresses
 // to multiple destination addresses
 func (ss *SkycoinBlockchain) SendFromAddress(from []core.WalletAddress, to []core.TransactionOutput, change core.Address, options core.KeyValueStore) (core.Transaction, error) {
 	logBlockchain.Info("Sending coins from addresses via blockchain API")
 	addresses := make([]core.Address, len(from))
+	walletIDs := make([]string, 0)
 	for i, wa := range from {
 		addresses[i] = wa.GetAddress()
+		if wlt := wa.GetWallet(); wlt != nil {
+			walletIDs = append(walletIDs, wlt.GetId())
+		}
+	}
+	excluded, err := excludedOutputs(options, walletIDs...)
+	if err != nil {
+		return nil, err
+	}
+	addresses, uxOuts, err := selectInputs(PoolSection, addresses, excluded)
+	if err != nil {
+		return nil, err
 	}
 	createTxnFunc := skyAPICreateTxn
-	return createTransaction(addresses, to, nil, change, options, createTxnFunc)
+	return createTransaction(addresses, to, uxOuts, change, options, createTxnFunc)
 }
 
 // Spend instantiates a transaction that spends specific outputs to send to multiple destination addresses
 func (ss *SkycoinBlockchain) Spend(unspent []core.WalletOutput, new []core.TransactionOutput, change core.Address, options core.KeyValueStore) (core.Transaction, error) {
 	logBlockchain.Info("Spending coins from outputs via blockchain API")
 	uxouts := make([]core.TransactionOutput, len(unspent))
+	walletIDs := make([]string, 0)
 	for i, wu := range unspent {
 		uxouts[i] = wu.GetOutput()
+		if wlt := wu.GetWallet(); wlt != nil {
+			walletIDs = append(walletIDs, wlt.GetId())
+		}
+	}
+	excluded, err := excludedOutputs(options, walletIDs...)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSpendable(uxouts, excluded, options); err != nil {
+		return nil, err
 	}
 	createTxnFunc := skyAPICreateTxn
 	return createTransaction(nil, new, uxouts, change, options, createTxnFunc)
 }
+
+// Type assertions
+var (
+	_ core.BlockchainStatus = &SkycoinBlockchain{}
+	_ core.BlockTxnScanner  = &SkycoinBlockchain{}
+)
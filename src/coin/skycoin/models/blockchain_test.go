@@ -3,6 +3,7 @@ package skycoin
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -134,6 +135,65 @@ func TestSkycoinBlockchainStatusGetNumberOfBlocks(t *testing.T) {
 	val, err := block.GetNumberOfBlocks()
 	require.NoError(t, err)
 	require.Equal(t, val, uint64(20))
+
+	// expired cache is refreshed
+	CleanGlobalMock()
+	global_mock.On("LastBlocks", uint64(1)).Return(&readable.Blocks{Blocks: []readable.Block{readable.Block{}}}, nil)
+	global_mock.On("BlockchainProgress").Return(&readable.BlockchainProgress{Current: uint64(21)}, nil)
+	val, err = block.GetNumberOfBlocks()
+	require.NoError(t, err)
+	require.Equal(t, val, uint64(21))
+
+	// cached value is kept until cache time elapses
+	block = &SkycoinBlockchain{CacheTime: uint64(time.Hour)}
+	val, err = block.GetNumberOfBlocks()
+	require.NoError(t, err)
+	require.Equal(t, val, uint64(21))
+	CleanGlobalMock()
+	global_mock.On("BlockchainProgress").Return(&readable.BlockchainProgress{Current: uint64(22)}, nil)
+	val, err = block.GetNumberOfBlocks()
+	require.NoError(t, err)
+	require.Equal(t, val, uint64(21))
+}
+
+func TestSkycoinBlockchainScanBlockTxns(t *testing.T) {
+	CleanGlobalMock()
+	block := func(seq uint64, txns ...readable.BlockTransactionVerbose) readable.BlockVerbose {
+		return readable.BlockVerbose{
+			Head: readable.BlockHeader{BkSeq: seq, Time: 1000 + seq},
+			Body: readable.BlockBodyVerbose{Transactions: txns},
+		}
+	}
+	received := readable.BlockTransactionVerbose{Hash: "received", Out: []readable.TransactionOutput{{Address: "mine"}}}
+	sent := readable.BlockTransactionVerbose{Hash: "sent", In: []readable.TransactionInput{{Address: "mine"}}, Out: []readable.TransactionOutput{{Address: "other"}}}
+	foreign := readable.BlockTransactionVerbose{Hash: "foreign", In: []readable.TransactionInput{{Address: "other"}}, Out: []readable.TransactionOutput{{Address: "other"}}}
+	blocks := make([]readable.BlockVerbose, 0)
+	for seq := uint64(11); seq <= 110; seq++ {
+		blocks = append(blocks, block(seq, foreign))
+	}
+	blocks[0].Body.Transactions = append(blocks[0].Body.Transactions, received)
+	global_mock.On("BlocksInRangeVerbose", uint64(11), uint64(110)).Return(&readable.BlocksVerbose{Blocks: blocks}, nil)
+	global_mock.On("BlocksInRangeVerbose", uint64(111), uint64(120)).Return(&readable.BlocksVerbose{Blocks: []readable.BlockVerbose{block(115, sent)}}, nil)
+
+	bc := &SkycoinBlockchain{}
+	txns, err := bc.ScanBlockTxns([]string{"mine"}, 11, 120)
+	require.NoError(t, err)
+	require.Len(t, txns, 2)
+	require.Equal(t, "received", txns[0].GetId())
+	require.Equal(t, core.TXN_STATUS_CONFIRMED, txns[0].GetStatus())
+	require.Equal(t, uint64(11), txns[0].(core.BlockchainTransaction).GetBlockSeq())
+	require.Equal(t, core.Timestamp(1011), txns[0].GetTimestamp())
+	require.Equal(t, "sent", txns[1].GetId())
+	require.Equal(t, uint64(115), txns[1].(core.BlockchainTransaction).GetBlockSeq())
+
+	global_mock.On("UnconfirmedTransactionsVerbose", []string{"mine"}).Return(
+		[]readable.TransactionWithStatusVerbose{
+			{Transaction: readable.TransactionVerbose{BlockTransactionVerbose: readable.BlockTransactionVerbose{Hash: "pending"}}},
+		}, nil)
+	txns, err = bc.PendingTxns([]string{"mine"})
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	require.Equal(t, "pending", txns[0].GetId())
 }
 
 func TestSkycoinBlockchainStatusGetLastBlock(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"github.com/SkycoinProject/skycoin/src/cipher"
 	"github.com/SkycoinProject/skycoin/src/coin"
 	"github.com/SkycoinProject/skycoin/src/readable"
+	"github.com/SkycoinProject/skycoin/src/util/droplet"
 	"github.com/SkycoinProject/skycoin/src/visor"
 	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/skytypes"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
@@ -458,6 +459,11 @@ func (txn *SkycoinTransaction) GetId() string {
 	return txn.skyTxn.Hash
 }
 
+// GetBlockSeq returns the sequence number of the block including this transaction
+func (txn *SkycoinTransaction) GetBlockSeq() uint64 {
+	return txn.skyTxn.Status.BlockSeq
+}
+
 func (txn *SkycoinTransaction) ComputeFee(ticker string) (uint64, error) {
 	logCoin.Info("Compute fee for transaction with " + ticker + "ticker")
 	if ticker == CoinHour {
@@ -598,7 +604,7 @@ func (in *SkycoinTransactionInput) GetSpentOutput() (core.TransactionOutput, err
 		if err != nil {
 			return nil, err
 		}
-		coins := util.FormatCoins(out.Coins, skyAccuracy)
+		coins := formatRequestAmount(out.Coins, skyAccuracy)
 		skyOut := &SkycoinTransactionOutput{
 			skyOut: readable.TransactionOutput{
 				Address: out.OwnerAddress,
@@ -627,11 +633,7 @@ func (in *SkycoinTransactionInput) GetCoins(ticker string) (uint64, error) {
 		return uint64(0), err2
 	}
 	if ticker == Sky {
-		skyf, err := strconv.ParseFloat(in.skyIn.Coins, 64)
-		if err != nil {
-			return 0, err
-		}
-		return uint64(skyf * float64(accuracy)), nil
+		return droplet.FromString(in.skyIn.Coins)
 	} else if ticker == CoinHour {
 		return in.skyIn.Hours * accuracy, nil
 	} else if ticker == CalculatedHour {
@@ -707,11 +709,7 @@ func (out *SkycoinTransactionOutput) GetCoins(ticker string) (uint64, error) {
 		return uint64(0), err2
 	}
 	if ticker == Sky {
-		skyf, err := strconv.ParseFloat(out.skyOut.Coins, 64)
-		if err != nil {
-			return 0, err
-		}
-		return uint64(skyf * float64(accuracy)), nil
+		return droplet.FromString(out.skyOut.Coins)
 	} else if ticker == CoinHour {
 		return out.skyOut.Hours * accuracy, nil
 	} else if ticker == CalculatedHour {
@@ -1015,6 +1013,7 @@ var (
 	_ skytypes.SkycoinTxn            = &SkycoinTransaction{}
 	_ skytypes.ReadableTxn           = &SkycoinTransaction{}
 	_ core.Transaction               = &SkycoinTransaction{}
+	_ core.BlockchainTransaction     = &SkycoinTransaction{}
 	_ core.TransactionInput          = &SkycoinTransactionInput{}
 	_ core.TransactionOutput         = &SkycoinTransactionOutput{}
 	_ skytypes.SkycoinTxn            = &SkycoinCreatedTransaction{}
@@ -0,0 +1,107 @@
+package skycoin
+
+import (
+	"github.com/SkycoinProject/skycoin/src/readable"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+// excludedOutputs collects outputs of the given wallets excluded from coin selection
+// by the coin control set in transaction options , if any
+func excludedOutputs(options core.KeyValueStore, walletIDs ...string) (map[string]core.OutputState, error) {
+	excluded := make(map[string]core.OutputState)
+	if options == nil {
+		return excluded, nil
+	}
+	coinControl, isCoinControl := options.GetValue(core.StrCoinControl).(core.CoinControl)
+	if !isCoinControl {
+		return excluded, nil
+	}
+	for _, walletID := range walletIDs {
+		outs, err := coinControl.ListExcludedOutputs(walletID)
+		if err != nil {
+			logWallet.WithError(err).WithField("wallet", walletID).Warn("Couldn't list outputs excluded by coin control")
+			return nil, err
+		}
+		for outID, state := range outs {
+			excluded[outID] = state
+		}
+	}
+	return excluded, nil
+}
+
+// selectInputs restricts coin selection to spendable outputs owned by from which are not excluded.
+// Addresses are returned untouched if nothing is excluded , so that the node selects inputs by itself.
+func selectInputs(poolSection string, from []core.Address, excluded map[string]core.OutputState) ([]core.Address, []core.TransactionOutput, error) {
+	if len(excluded) == 0 {
+		return from, nil, nil
+	}
+	addrs := make([]string, 0, len(from))
+	for _, addr := range from {
+		addrs = append(addrs, addr.String())
+	}
+	c, err := NewSkycoinApiClient(poolSection)
+	if err != nil {
+		logWallet.WithError(err).Warn("Couldn't load api client")
+		return nil, nil, err
+	}
+	defer ReturnSkycoinClient(c)
+	logWallet.Info("POST /api/v1/outputs?addrs=xxx")
+	summary, err := c.OutputsForAddresses(addrs)
+	if err != nil {
+		logWallet.WithError(err).WithField("Length of addrs", len(addrs)).Warn("Couldn't POST /api/v1/outputs?addrs=xxx")
+		return nil, nil, err
+	}
+	uxOuts := make([]core.TransactionOutput, 0)
+	for _, out := range summary.SpendableOutputs() {
+		if _, isExcluded := excluded[out.Hash]; isExcluded {
+			continue
+		}
+		uxOuts = append(uxOuts, &SkycoinTransactionOutput{
+			skyOut: readable.TransactionOutput{
+				Address: out.Address,
+				Coins:   out.Coins,
+				Hours:   out.Hours,
+				Hash:    out.Hash,
+			},
+			calculatedHours: out.CalculatedHours,
+		})
+	}
+	if len(uxOuts) == 0 {
+		logWallet.Warn("Every spendable output is excluded by coin control")
+		return nil, nil, errors.ErrNoSpendableOutputs
+	}
+	return nil, uxOuts, nil
+}
+
+// checkSpendable rejects explicitly chosen outputs excluded by coin control.
+// Frozen outputs are accepted if core.StrOverrideFrozen option is set.
+func checkSpendable(unspent []core.TransactionOutput, excluded map[string]core.OutputState, options core.KeyValueStore) error {
+	overrideFrozen := false
+	if options != nil {
+		overrideFrozen, _ = options.GetValue(core.StrOverrideFrozen).(bool)
+	}
+	for _, out := range unspent {
+		state, isExcluded := excluded[out.GetId()]
+		if !isExcluded || (state == core.OutputFrozen && overrideFrozen) {
+			continue
+		}
+		logWallet.WithField("output", out.GetId()).Warn("Output excluded by coin control")
+		return errors.ErrOutputExcluded
+	}
+	return nil
+}
+
+// loadedAddresses lists addresses of a wallet
+func loadedAddresses(wlt core.Wallet) ([]core.Address, error) {
+	addresses := make([]core.Address, 0)
+	iterAddr, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		logWallet.WithError(err).Warn("Couldn't get loaded addresses")
+		return nil, err
+	}
+	for iterAddr.Next() {
+		addresses = append(addresses, iterAddr.Value())
+	}
+	return addresses, nil
+}
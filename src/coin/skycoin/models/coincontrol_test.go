@@ -0,0 +1,125 @@
+package skycoin
+
+import (
+	"testing"
+
+	"github.com/SkycoinProject/skycoin/src/api"
+	"github.com/SkycoinProject/skycoin/src/coin"
+	"github.com/SkycoinProject/skycoin/src/readable"
+	"github.com/SkycoinProject/skycoin/src/testutil"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalWalletCoinControl(t *testing.T) {
+	CleanGlobalMock()
+	wlt := makeLocalWallet(t)
+	addrs, err := loadedAddresses(wlt)
+	require.NoError(t, err)
+	addrStrs := make([]string, 0)
+	for _, addr := range addrs {
+		addrStrs = append(addrStrs, addr.String())
+	}
+	destinationAddress := testutil.MakeAddress().String()
+
+	summary := &readable.UnspentOutputsSummary{
+		HeadOutputs: readable.UnspentOutputs{
+			{Hash: "frozen", Address: addrStrs[0], Coins: "1", CalculatedHours: 10},
+			{Hash: "reserved", Address: addrStrs[0], Coins: "1", CalculatedHours: 10},
+			{Hash: "free", Address: addrStrs[0], Coins: "1", CalculatedHours: 10},
+		},
+	}
+	global_mock.On("OutputsForAddresses", addrStrs).Return(summary, nil)
+	global_mock.On("OutputsForAddresses", addrStrs[:1]).Return(summary, nil)
+
+	coinControl := new(mocks.CoinControl)
+	coinControl.On("ListExcludedOutputs", wlt.GetId()).Return(map[string]core.OutputState{
+		"frozen":   core.OutputFrozen,
+		"reserved": core.OutputReserved,
+	}, nil)
+	opt := NewTransferOptions()
+	opt.SetValue("BurnFactor", "0.5")
+	opt.SetValue("CoinHoursSelectionType", "auto")
+	opt.SetValue(core.StrCoinControl, coinControl)
+
+	req := api.CreateTransactionRequest{
+		HoursSelection: api.HoursSelection{
+			Type:        "auto",
+			Mode:        "share",
+			ShareFactor: "0.5",
+		},
+		To: []api.Receiver{
+			{Address: destinationAddress, Coins: "1"},
+		},
+		UxOuts: []string{"free"},
+	}
+	crtTxn, err := api.NewCreateTransactionResponse(&coin.Transaction{InnerHash: testutil.RandSHA256(t)}, nil)
+	require.NoError(t, err)
+	crtTxn.Transaction.Fee = "5"
+	mockSkyApiCreateTransaction(global_mock, &req, crtTxn)
+
+	to := &SkycoinTransactionOutput{
+		skyOut: readable.TransactionOutput{
+			Address: destinationAddress,
+			Coins:   "1",
+		},
+	}
+
+	// Excluded outputs are not selected
+	ret, err := wlt.Transfer(to, opt)
+	require.NoError(t, err)
+	require.Equal(t, crtTxn.Transaction.TxID, ret.GetId())
+	ret, err = wlt.SendFromAddress(addrs[:1], []core.TransactionOutput{to}, nil, opt)
+	require.NoError(t, err)
+	require.Equal(t, crtTxn.Transaction.TxID, ret.GetId())
+
+	// Explicitly chosen outputs
+	outputOf := func(id string) []core.TransactionOutput {
+		return []core.TransactionOutput{&SkycoinTransactionOutput{skyOut: readable.TransactionOutput{Hash: id}}}
+	}
+	_, err = wlt.Spend(outputOf("frozen"), []core.TransactionOutput{to}, nil, opt)
+	require.Equal(t, errors.ErrOutputExcluded, err)
+	opt.SetValue(core.StrOverrideFrozen, true)
+	req.UxOuts = []string{"frozen"}
+	mockSkyApiCreateTransaction(global_mock, &req, crtTxn)
+	ret, err = wlt.Spend(outputOf("frozen"), []core.TransactionOutput{to}, nil, opt)
+	require.NoError(t, err)
+	require.Equal(t, crtTxn.Transaction.TxID, ret.GetId())
+	_, err = wlt.Spend(outputOf("reserved"), []core.TransactionOutput{to}, nil, opt)
+	require.Equal(t, errors.ErrOutputExcluded, err)
+}
+
+func TestSelectInputs(t *testing.T) {
+	CleanGlobalMock()
+	addr, err := NewSkycoinAddress("2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt")
+	require.NoError(t, err)
+	from := []core.Address{&addr}
+	summary := &readable.UnspentOutputsSummary{
+		HeadOutputs: readable.UnspentOutputs{
+			{Hash: "out1", Address: addr.String(), Coins: "1"},
+			{Hash: "out2", Address: addr.String(), Coins: "1"},
+		},
+		OutgoingOutputs: readable.UnspentOutputs{
+			{Hash: "out2", Address: addr.String(), Coins: "1"},
+		},
+	}
+	global_mock.On("OutputsForAddresses", []string{addr.String()}).Return(summary, nil)
+
+	// Nothing excluded
+	addrs, uxOuts, err := selectInputs(PoolSection, from, nil)
+	require.NoError(t, err)
+	require.Equal(t, from, addrs)
+	require.Nil(t, uxOuts)
+
+	// Pending outputs are not spendable
+	addrs, uxOuts, err = selectInputs(PoolSection, from, map[string]core.OutputState{"out3": core.OutputFrozen})
+	require.NoError(t, err)
+	require.Nil(t, addrs)
+	require.Len(t, uxOuts, 1)
+	require.Equal(t, "out1", uxOuts[0].GetId())
+
+	_, _, err = selectInputs(PoolSection, from, map[string]core.OutputState{"out1": core.OutputReserved})
+	require.Equal(t, errors.ErrNoSpendableOutputs, err)
+}
@@ -2,9 +2,12 @@ package skycoin
 
 import (
 	"encoding/hex"
+	"net/http"
 	"strings"
 
 	"github.com/SkycoinProject/skycoin/src/api"
+	"github.com/SkycoinProject/skycoin/src/cipher"
+	"github.com/SkycoinProject/skycoin/src/coin"
 	"github.com/SkycoinProject/skycoin/src/readable"
 	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/skytypes"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
@@ -102,21 +105,96 @@ func (spex *SkycoinPEX) BroadcastTxn(txn core.Transaction) error {
 	if !ok {
 		return errors.ErrInvalidTxn
 	}
+	txnBytes, err := unTxn.EncodeSkycoinTransaction()
+	if err != nil {
+		return err
+	}
+	return spex.BroadcastRawTxn(txnBytes)
+}
+
+// BroadcastRawTxn injects an encoded transaction for confirmation by network peers
+func (spex *SkycoinPEX) BroadcastRawTxn(raw []byte) error {
 	c, err := NewSkycoinApiClient(spex.poolSection)
 	if err != nil {
 		return err
 	}
 	defer ReturnSkycoinClient(c)
-	txnBytes, err := unTxn.EncodeSkycoinTransaction()
+	_, err = c.InjectEncodedTransaction(hex.EncodeToString(raw))
+	return err
+}
+
+// GetTxnStatus returns the status of a transaction , TXN_STATUS_CREATED if unknown to the node
+func (spex *SkycoinPEX) GetTxnStatus(txnID string) (core.TransactionStatus, error) {
+	logNetwork.Info("Getting transaction status")
+	c, err := NewSkycoinApiClient(spex.poolSection)
 	if err != nil {
-		return err
+		return core.TXN_STATUS_CREATED, err
 	}
-	_, err = c.InjectEncodedTransaction(hex.EncodeToString(txnBytes))
+	defer ReturnSkycoinClient(c)
+	txn, err := c.Transaction(txnID)
 	if err != nil {
-		return err
+		if isNotFoundError(err) {
+			return core.TXN_STATUS_CREATED, nil
+		}
+		return core.TXN_STATUS_CREATED, err
+	}
+	if txn.Status.Confirmed {
+		return core.TXN_STATUS_CONFIRMED, nil
+	}
+	if txn.Status.Unconfirmed {
+		return core.TXN_STATUS_PENDING, nil
+	}
+	return core.TXN_STATUS_CREATED, nil
+}
+
+// GetTxnBlockSeq returns the sequence of the block including a transaction , errors.ErrTxnNotConfirmed if none
+func (spex *SkycoinPEX) GetTxnBlockSeq(txnID string) (uint64, error) {
+	logNetwork.Info("Getting transaction block")
+	c, err := NewSkycoinApiClient(spex.poolSection)
+	if err != nil {
+		return 0, err
+	}
+	defer ReturnSkycoinClient(c)
+	txn, err := c.Transaction(txnID)
+	if err != nil {
+		if isNotFoundError(err) {
+			return 0, errors.ErrTxnNotConfirmed
+		}
+		return 0, err
 	}
+	if !txn.Status.Confirmed {
+		return 0, errors.ErrTxnNotConfirmed
+	}
+	return txn.Status.BlockSeq, nil
+}
 
-	return nil
+// IsTxnInvalidated determines whether inputs of an encoded transaction were spent by another transaction
+func (spex *SkycoinPEX) IsTxnInvalidated(txnID string, raw []byte) (bool, error) {
+	logNetwork.Info("Checking transaction inputs")
+	txn, err := coin.DeserializeTransaction(raw)
+	if err != nil {
+		return false, err
+	}
+	c, err := NewSkycoinApiClient(spex.poolSection)
+	if err != nil {
+		return false, err
+	}
+	defer ReturnSkycoinClient(c)
+	unspent := cipher.SHA256{}.Hex()
+	for _, in := range txn.In {
+		out, err := c.UxOut(in.Hex())
+		if err != nil {
+			if isNotFoundError(err) {
+				// Output created by a transaction not confirmed yet
+				continue
+			}
+			return false, err
+		}
+		if out.SpentTxnID != "" && out.SpentTxnID != unspent && out.SpentTxnID != txnID {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (spex *SkycoinPEX) GetTxnPool() (core.TransactionIterator, error) {
@@ -237,3 +315,12 @@ func connectionsToNetwork(connection readable.Connection) *SkycoinPexNode {
 		Source:      connection.IsTrustedPeer,
 	}
 }
+
+// isNotFoundError determines whether the node replied that a requested object does not exist
+func isNotFoundError(err error) bool {
+	clientErr, isClientErr := err.(api.ClientError)
+	return isClientErr && clientErr.StatusCode == http.StatusNotFound
+}
+
+// Type assertions
+var _ core.TxnTracker = &SkycoinPEX{}
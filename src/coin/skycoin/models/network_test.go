@@ -2,8 +2,12 @@ package skycoin
 
 import (
 	"encoding/hex"
+	"net/http"
 	"testing"
 
+	"github.com/SkycoinProject/skycoin/src/api"
+	"github.com/SkycoinProject/skycoin/src/cipher"
+	"github.com/SkycoinProject/skycoin/src/testutil"
 	"github.com/SkycoinProject/skycoin/src/visor"
 
 	"github.com/stretchr/testify/require"
@@ -11,6 +15,7 @@ import (
 	"github.com/SkycoinProject/skycoin/src/readable"
 	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
 )
 
 func TestSkycoinPEXGetTxnPool(t *testing.T) {
@@ -94,6 +99,88 @@ func TestSkycoinPEXBroadcastTxn(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSkycoinPEXGetTxnStatus(t *testing.T) {
+	CleanGlobalMock()
+	notFound := api.NewClientError("404 Not Found", http.StatusNotFound, "not found")
+	global_mock.On("Transaction", "confirmed").Return(&readable.TransactionWithStatus{Status: readable.TransactionStatus{Confirmed: true}}, nil)
+	global_mock.On("Transaction", "pending").Return(&readable.TransactionWithStatus{Status: readable.TransactionStatus{Unconfirmed: true}}, nil)
+	global_mock.On("Transaction", "unknown").Return(nil, notFound)
+	global_mock.On("Transaction", "failure").Return(nil, api.NewClientError("500 Internal Server Error", http.StatusInternalServerError, "failure"))
+
+	pex := &SkycoinPEX{poolSection: PoolSection}
+	tests := []struct {
+		txnID  string
+		status core.TransactionStatus
+		fails  bool
+	}{
+		{txnID: "confirmed", status: core.TXN_STATUS_CONFIRMED},
+		{txnID: "pending", status: core.TXN_STATUS_PENDING},
+		{txnID: "unknown", status: core.TXN_STATUS_CREATED},
+		{txnID: "failure", fails: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.txnID, func(t *testing.T) {
+			status, err := pex.GetTxnStatus(tt.txnID)
+			if tt.fails {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.status, status)
+		})
+	}
+}
+
+func TestSkycoinPEXGetTxnBlockSeq(t *testing.T) {
+	CleanGlobalMock()
+	global_mock.On("Transaction", "confirmed").Return(&readable.TransactionWithStatus{Status: readable.TransactionStatus{Confirmed: true, BlockSeq: 42}}, nil)
+	global_mock.On("Transaction", "pending").Return(&readable.TransactionWithStatus{Status: readable.TransactionStatus{Unconfirmed: true}}, nil)
+	global_mock.On("Transaction", "unknown").Return(nil, api.NewClientError("404 Not Found", http.StatusNotFound, "not found"))
+
+	pex := &SkycoinPEX{poolSection: PoolSection}
+	seq, err := pex.GetTxnBlockSeq("confirmed")
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), seq)
+	_, err = pex.GetTxnBlockSeq("pending")
+	require.Equal(t, errors.ErrTxnNotConfirmed, err)
+	_, err = pex.GetTxnBlockSeq("unknown")
+	require.Equal(t, errors.ErrTxnNotConfirmed, err)
+}
+
+func TestSkycoinPEXIsTxnInvalidated(t *testing.T) {
+	CleanGlobalMock()
+	txn, _, uxs, err := makeTransactionMultipleInputs(t, 2)
+	require.NoError(t, err)
+	raw, err := txn.Serialize()
+	require.NoError(t, err)
+	txnID := txn.Hash()
+
+	unspent := makeSpentOutput(uxs[0], 0, cipher.SHA256{})
+	global_mock.On("UxOut", uxs[0].Hash().Hex()).Return(&unspent, nil)
+	global_mock.On("UxOut", uxs[1].Hash().Hex()).Return(nil, api.NewClientError("404 Not Found", http.StatusNotFound, "not found")).Once()
+
+	pex := &SkycoinPEX{poolSection: PoolSection}
+	invalidated, err := pex.IsTxnInvalidated(txnID.Hex(), raw)
+	require.NoError(t, err)
+	require.False(t, invalidated)
+
+	// Spent by the tracked transaction itself
+	spentBySelf := makeSpentOutput(uxs[1], 10, txnID)
+	global_mock.On("UxOut", uxs[1].Hash().Hex()).Return(&spentBySelf, nil).Once()
+	invalidated, err = pex.IsTxnInvalidated(txnID.Hex(), raw)
+	require.NoError(t, err)
+	require.False(t, invalidated)
+
+	spentElsewhere := makeSpentOutput(uxs[1], 10, testutil.RandSHA256(t))
+	global_mock.On("UxOut", uxs[1].Hash().Hex()).Return(&spentElsewhere, nil).Once()
+	invalidated, err = pex.IsTxnInvalidated(txnID.Hex(), raw)
+	require.NoError(t, err)
+	require.True(t, invalidated)
+
+	_, err = pex.IsTxnInvalidated(txnID.Hex(), []byte{1, 2, 3})
+	require.Error(t, err)
+}
+
 func TestSkycoinPexNode(t *testing.T) {
 	addr := "addr"
 	port := uint16(8000)
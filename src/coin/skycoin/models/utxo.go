@@ -0,0 +1,273 @@
+package skycoin
+
+import (
+	"sort"
+
+	skyparams "github.com/SkycoinProject/skycoin/src/params"
+	"github.com/SkycoinProject/skycoin/src/readable"
+	"github.com/SkycoinProject/skycoin/src/util/droplet"
+	"github.com/SkycoinProject/skycoin/src/util/fee"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+)
+
+const (
+	// txnHeaderSize bytes taken by length , type , inner hash and array length prefixes
+	txnHeaderSize = 4 + 1 + 32 + 4 + 4 + 4
+	// txnInputSize bytes taken by an input hash and its signature
+	txnInputSize = 32 + 65
+	// txnOutputSize bytes taken by an output address , coins and hours
+	txnOutputSize = 21 + 8 + 8
+)
+
+// UtxoPlan preview of a transaction rearranging the unspent outputs of a wallet
+type UtxoPlan struct {
+	wallet core.Wallet
+	// Inputs outputs spent by the transaction
+	Inputs []core.TransactionOutput
+	// Outputs created by the transaction , change excluded
+	Outputs []core.TransactionOutput
+	// Change output returning leftover coins to the owner , nil if none
+	Change core.TransactionOutput
+	// Fee coin hours burned by the transaction
+	Fee uint64
+}
+
+// NewConsolidationPlan merges spendable outputs of a wallet holding less than threshold droplets
+// into a single output sent to destination. Smallest outputs are selected first , as many as fit
+// in the maximum transaction size. Outputs excluded by coin control are left untouched.
+func NewConsolidationPlan(wlt core.Wallet, threshold uint64, destination core.Address, options core.KeyValueStore) (*UtxoPlan, error) {
+	outs, err := spendableWalletOutputs(wlt, options)
+	if err != nil {
+		return nil, err
+	}
+	type candidate struct {
+		out   core.TransactionOutput
+		coins uint64
+	}
+	candidates := make([]candidate, 0, len(outs))
+	for _, out := range outs {
+		coins, err := out.GetCoins(Sky)
+		if err != nil {
+			return nil, err
+		}
+		if coins < threshold {
+			candidates = append(candidates, candidate{out: out, coins: coins})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].coins < candidates[j].coins
+	})
+	if max := maxTxnInputs(1); len(candidates) > max {
+		candidates = candidates[:max]
+	}
+	if len(candidates) < 2 {
+		return nil, errors.ErrNoOutputsToConsolidate
+	}
+
+	plan := &UtxoPlan{wallet: wlt}
+	var coins, hours uint64
+	for _, c := range candidates {
+		outHours, err := inputHours(c.out)
+		if err != nil {
+			return nil, err
+		}
+		plan.Inputs = append(plan.Inputs, c.out)
+		coins += c.coins
+		hours += outHours
+	}
+	remaining, err := plan.payFee(hours)
+	if err != nil {
+		return nil, err
+	}
+	out, err := newPlannedOutput(destination.String(), coins, remaining)
+	if err != nil {
+		return nil, err
+	}
+	plan.Outputs = []core.TransactionOutput{out}
+	return plan, nil
+}
+
+// NewSplitPlan divides a spendable output of a wallet into outputs holding amounts droplets
+// sent to destination. Coin hours are shared in proportion to coins , leftover coins return
+// to the address owning the output.
+func NewSplitPlan(wlt core.Wallet, outputID string, amounts []uint64, destination core.Address, options core.KeyValueStore) (*UtxoPlan, error) {
+	outs, err := spendableWalletOutputs(wlt, options)
+	if err != nil {
+		return nil, err
+	}
+	var source core.TransactionOutput
+	for _, out := range outs {
+		if out.GetId() == outputID {
+			source = out
+			break
+		}
+	}
+	if source == nil {
+		return nil, errors.ErrNotFound
+	}
+	coins, err := source.GetCoins(Sky)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := inputHours(source)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := source.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	precision := DropletPrecision()
+	var total uint64
+	for _, amount := range amounts {
+		if amount == 0 || amount%precision != 0 {
+			return nil, errors.ErrInvalidSplitAmounts
+		}
+		total += amount
+		if total > coins {
+			return nil, errors.ErrInvalidSplitAmounts
+		}
+	}
+	if len(amounts) == 0 {
+		return nil, errors.ErrInvalidSplitAmounts
+	}
+	outputCount := len(amounts)
+	if total < coins {
+		outputCount++
+	}
+	if maxTxnInputs(outputCount) < 1 {
+		return nil, errors.ErrTxnTooLarge
+	}
+
+	plan := &UtxoPlan{wallet: wlt, Inputs: []core.TransactionOutput{source}}
+	remaining, err := plan.payFee(hours)
+	if err != nil {
+		return nil, err
+	}
+	var assigned uint64
+	for i, amount := range amounts {
+		outHours := remaining * amount / coins
+		if i == len(amounts)-1 && total == coins {
+			// Rounding leftovers would be burned otherwise
+			outHours = remaining - assigned
+		}
+		out, err := newPlannedOutput(destination.String(), amount, outHours)
+		if err != nil {
+			return nil, err
+		}
+		plan.Outputs = append(plan.Outputs, out)
+		assigned += outHours
+	}
+	if total < coins {
+		if plan.Change, err = newPlannedOutput(owner.String(), coins-total, remaining-assigned); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// Build creates the unsigned transaction described by the plan via Wallet.Spend.
+// Coin hours are assigned manually , as previewed.
+func (p *UtxoPlan) Build(options core.KeyValueStore) (core.Transaction, error) {
+	if options == nil {
+		options = util.NewKeyValueMap()
+	}
+	options.SetValue("CoinHoursSelectionType", "manual")
+	if _, isString := options.GetValue("BurnFactor").(string); !isString {
+		options.SetValue("BurnFactor", "0.5")
+	}
+	var change core.Address
+	if p.Change != nil {
+		addr, err := p.Change.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		change = addr
+	}
+	return p.wallet.Spend(p.Inputs, p.Outputs, change, options)
+}
+
+// payFee records the fee required to spend hours and returns the coin hours left
+func (p *UtxoPlan) payFee(hours uint64) (uint64, error) {
+	p.Fee = fee.RequiredFee(hours, skyparams.UserVerifyTxn.BurnFactor)
+	if p.Fee == 0 {
+		return 0, errors.ErrInsufficientCoinHours
+	}
+	return hours - p.Fee, nil
+}
+
+// spendableWalletOutputs lists unspent outputs of a wallet not excluded by coin control
+func spendableWalletOutputs(wlt core.Wallet, options core.KeyValueStore) ([]core.TransactionOutput, error) {
+	excluded, err := excludedOutputs(options, wlt.GetId())
+	if err != nil {
+		return nil, err
+	}
+	it, err := wlt.GetCryptoAccount().ScanUnspentOutputs()
+	if err != nil {
+		return nil, err
+	}
+	outs := make([]core.TransactionOutput, 0)
+	for it.Next() {
+		if _, isExcluded := excluded[it.Value().GetId()]; !isExcluded {
+			outs = append(outs, it.Value())
+		}
+	}
+	return outs, nil
+}
+
+// maxTxnInputs returns how many inputs fit in a transaction with the given number of outputs
+func maxTxnInputs(outputs int) int {
+	available := int(skyparams.UserVerifyTxn.MaxTransactionSize) - txnHeaderSize - outputs*txnOutputSize
+	if available < 0 {
+		return 0
+	}
+	return available / txnInputSize
+}
+
+// MaxTxnOutputs returns how many outputs fit in a transaction with the given number of inputs
+func MaxTxnOutputs(inputs int) int {
+	available := int(skyparams.UserVerifyTxn.MaxTransactionSize) - txnHeaderSize - inputs*txnInputSize
+	if available < 0 {
+		return 0
+	}
+	return available / txnOutputSize
+}
+
+// DropletPrecision returns the smallest coin amount accepted by the node in transaction outputs , in droplets
+func DropletPrecision() uint64 {
+	precision := uint64(1)
+	for i := skyparams.UserVerifyTxn.MaxDropletPrecision; i < droplet.Exponent; i++ {
+		precision *= 10
+	}
+	return precision
+}
+
+// inputHours returns coin hours an output contributes when spent at the head block
+func inputHours(out core.TransactionOutput) (uint64, error) {
+	hours, err := out.GetCoins(CalculatedHour)
+	if err != nil {
+		return 0, err
+	}
+	quotient, err := util.AltcoinQuotient(CalculatedHour)
+	if err != nil {
+		return 0, err
+	}
+	return hours / quotient, nil
+}
+
+func newPlannedOutput(addr string, coins, hours uint64) (*SkycoinTransactionOutput, error) {
+	strCoins, err := droplet.ToString(coins)
+	if err != nil {
+		return nil, err
+	}
+	return &SkycoinTransactionOutput{
+		skyOut: readable.TransactionOutput{
+			Address: addr,
+			Coins:   strCoins,
+			Hours:   hours,
+		},
+	}, nil
+}
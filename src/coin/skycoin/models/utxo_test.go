@@ -0,0 +1,190 @@
+package skycoin
+
+import (
+	"testing"
+
+	"github.com/SkycoinProject/skycoin/src/cipher"
+	"github.com/SkycoinProject/skycoin/src/coin"
+	skyparams "github.com/SkycoinProject/skycoin/src/params"
+	"github.com/SkycoinProject/skycoin/src/readable"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	utxoOwnerAddr = "2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt"
+	utxoDestAddr  = "2HPiZkMTD2pB9FZ6HbCxFSXa1FGeNkLeEbP"
+)
+
+func makeUtxoWallet(outs ...*SkycoinTransactionOutput) *mocks.Wallet {
+	unspent := make([]core.TransactionOutput, len(outs))
+	for i, out := range outs {
+		unspent[i] = out
+	}
+	account := new(mocks.CryptoAccount)
+	account.On("ScanUnspentOutputs").Return(func() core.TransactionOutputIterator {
+		return NewSkycoinTransactionOutputIterator(unspent)
+	}, nil)
+	wlt := new(mocks.Wallet)
+	wlt.On("GetId").Return("utxo.wlt")
+	wlt.On("GetCryptoAccount").Return(account)
+	return wlt
+}
+
+func makeUtxo(id, coins string, hours uint64) *SkycoinTransactionOutput {
+	return &SkycoinTransactionOutput{
+		skyOut: readable.TransactionOutput{
+			Hash:    id,
+			Address: utxoOwnerAddr,
+			Coins:   coins,
+			Hours:   hours,
+		},
+		calculatedHours: hours,
+	}
+}
+
+func requirePlannedOutput(t *testing.T, out core.TransactionOutput, addr string, coins, hours uint64) {
+	outAddr, err := out.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, addr, outAddr.String())
+	val, err := out.GetCoins(Sky)
+	require.NoError(t, err)
+	require.Equal(t, coins, val)
+	val, err = out.GetCoins(CoinHour)
+	require.NoError(t, err)
+	require.Equal(t, hours, val)
+}
+
+func TestMaxTxnInputs(t *testing.T) {
+	for _, outputs := range []int{1, 5} {
+		n := maxTxnInputs(outputs)
+		txn := coin.Transaction{
+			In:   make([]cipher.SHA256, n),
+			Sigs: make([]cipher.Sig, n),
+			Out:  make([]coin.TransactionOutput, outputs),
+		}
+		size, err := txn.Size()
+		require.NoError(t, err)
+		require.True(t, size <= skyparams.UserVerifyTxn.MaxTransactionSize)
+		require.True(t, size+txnInputSize > skyparams.UserVerifyTxn.MaxTransactionSize)
+	}
+}
+
+func TestMaxTxnOutputs(t *testing.T) {
+	for _, inputs := range []int{1, 100} {
+		n := MaxTxnOutputs(inputs)
+		txn := coin.Transaction{
+			In:   make([]cipher.SHA256, inputs),
+			Sigs: make([]cipher.Sig, inputs),
+			Out:  make([]coin.TransactionOutput, n),
+		}
+		size, err := txn.Size()
+		require.NoError(t, err)
+		require.True(t, size <= skyparams.UserVerifyTxn.MaxTransactionSize)
+		require.True(t, size+txnOutputSize > skyparams.UserVerifyTxn.MaxTransactionSize)
+	}
+	require.Equal(t, uint64(1000), DropletPrecision())
+}
+
+func TestNewConsolidationPlan(t *testing.T) {
+	CleanGlobalMock()
+	dest, err := NewSkycoinAddress(utxoDestAddr)
+	require.NoError(t, err)
+	wlt := makeUtxoWallet(
+		makeUtxo("big", "5", 100),
+		makeUtxo("dust2", "0.2", 20),
+		makeUtxo("frozen", "0.05", 50),
+		makeUtxo("dust1", "0.1", 10),
+	)
+	coinControl := new(mocks.CoinControl)
+	coinControl.On("ListExcludedOutputs", "utxo.wlt").Return(map[string]core.OutputState{"frozen": core.OutputFrozen}, nil)
+	opt := NewTransferOptions()
+	opt.SetValue(core.StrCoinControl, coinControl)
+
+	plan, err := NewConsolidationPlan(wlt, 1000000, &dest, opt)
+	require.NoError(t, err)
+	require.Len(t, plan.Inputs, 2)
+	require.Equal(t, "dust1", plan.Inputs[0].GetId())
+	require.Equal(t, "dust2", plan.Inputs[1].GetId())
+	require.Equal(t, uint64(3), plan.Fee)
+	require.Nil(t, plan.Change)
+	require.Len(t, plan.Outputs, 1)
+	requirePlannedOutput(t, plan.Outputs[0], utxoDestAddr, 300000, 27)
+
+	_, err = NewConsolidationPlan(wlt, 150000, &dest, opt)
+	require.Equal(t, errors.ErrNoOutputsToConsolidate, err)
+}
+
+func TestNewSplitPlan(t *testing.T) {
+	CleanGlobalMock()
+	dest, err := NewSkycoinAddress(utxoDestAddr)
+	require.NoError(t, err)
+	wlt := makeUtxoWallet(makeUtxo("big", "5", 100), makeUtxo("dust", "0.1", 10))
+
+	// Leftover coins and hours return to the owner
+	plan, err := NewSplitPlan(wlt, "big", []uint64{1000000, 2000000}, &dest, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), plan.Fee)
+	require.Len(t, plan.Outputs, 2)
+	requirePlannedOutput(t, plan.Outputs[0], utxoDestAddr, 1000000, 18)
+	requirePlannedOutput(t, plan.Outputs[1], utxoDestAddr, 2000000, 36)
+	requirePlannedOutput(t, plan.Change, utxoOwnerAddr, 2000000, 36)
+
+	// Exact splits keep every remaining hour
+	plan, err = NewSplitPlan(wlt, "big", []uint64{1500000, 1500000, 2000000}, &dest, nil)
+	require.NoError(t, err)
+	require.Nil(t, plan.Change)
+	requirePlannedOutput(t, plan.Outputs[0], utxoDestAddr, 1500000, 27)
+	requirePlannedOutput(t, plan.Outputs[1], utxoDestAddr, 1500000, 27)
+	requirePlannedOutput(t, plan.Outputs[2], utxoDestAddr, 2000000, 36)
+
+	tests := []struct {
+		name     string
+		outputID string
+		amounts  []uint64
+		err      error
+	}{
+		{name: "unknown-output", outputID: "none", amounts: []uint64{1000000}, err: errors.ErrNotFound},
+		{name: "no-amounts", outputID: "big", err: errors.ErrInvalidSplitAmounts},
+		{name: "zero-amount", outputID: "big", amounts: []uint64{0}, err: errors.ErrInvalidSplitAmounts},
+		{name: "too-precise", outputID: "big", amounts: []uint64{1000100}, err: errors.ErrInvalidSplitAmounts},
+		{name: "exceeds-coins", outputID: "big", amounts: []uint64{3000000, 3000000}, err: errors.ErrInvalidSplitAmounts},
+		{name: "too-many-outputs", outputID: "big", amounts: make1000Amounts(1000), err: errors.ErrTxnTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSplitPlan(wlt, tt.outputID, tt.amounts, &dest, nil)
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestUtxoPlanBuild(t *testing.T) {
+	CleanGlobalMock()
+	dest, err := NewSkycoinAddress(utxoDestAddr)
+	require.NoError(t, err)
+	wlt := makeUtxoWallet(makeUtxo("big", "5", 100))
+	plan, err := NewSplitPlan(wlt, "big", []uint64{1000000}, &dest, nil)
+	require.NoError(t, err)
+
+	txn := new(mocks.Transaction)
+	wlt.On("Spend", plan.Inputs, plan.Outputs, mock.MatchedBy(func(addr core.Address) bool {
+		return addr.String() == utxoOwnerAddr
+	}), mock.MatchedBy(func(opt core.KeyValueStore) bool {
+		return opt.GetValue("CoinHoursSelectionType") == "manual" && opt.GetValue("BurnFactor") == "0.5"
+	})).Return(txn, nil)
+	ret, err := plan.Build(nil)
+	require.NoError(t, err)
+	require.Equal(t, txn, ret)
+}
+
+func make1000Amounts(n int) []uint64 {
+	amounts := make([]uint64, n)
+	for i := range amounts {
+		amounts[i] = 1000
+	}
+	return amounts
+}
@@ -2,8 +2,10 @@ package skycoin
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -21,7 +23,9 @@ import (
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	"github.com/fibercrypto/fibercryptowallet/src/errors"
 	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/fibercrypto/fibercryptowallet/src/util/bip39util"
 	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
+	"github.com/fibercrypto/fibercryptowallet/src/util/slip39util"
 )
 
 var logWallet = logging.MustGetLogger("Skycoin Wallet")
@@ -33,6 +37,10 @@ const (
 
 	walletExt             = ".wlt"
 	WalletTimestampFormat = "2006_01_02"
+	// ArchiveDirName directory inside wallets folder keeping archived wallets
+	ArchiveDirName = "archive"
+	// ArchiveTimestampFormat time prefixed to archived wallet file names
+	ArchiveTimestampFormat = "20060102T150405Z"
 
 	SignerIDLocalWallet  = "sky.local"
 	SignerIDRemoteWallet = "sky.remote"
@@ -96,8 +104,14 @@ func (wltSrv *SkycoinRemoteWallet) ListWallets() core.WalletIterator {
 }
 
 // CreateWallet instantiates a new wallet given account seed
-func (wltSrv *SkycoinRemoteWallet) CreateWallet(label string, seed string, wltType string, IsEncrypted bool, pwd core.PasswordReader, scanAddressesN int) (core.Wallet, error) {
+func (wltSrv *SkycoinRemoteWallet) CreateWallet(label string, seed string, seedPassphrase string, wltType string, IsEncrypted bool, pwd core.PasswordReader, scanAddressesN int) (core.Wallet, error) {
 	logWallet.Info("Creating wallet")
+	if seedPassphrase != "" && wltType != wallet.WalletTypeBip44 {
+		return nil, errors.ErrSeedPassphraseUnsupported
+	}
+	if err := checkSeedLanguage(seed, wltType); err != nil {
+		return nil, err
+	}
 	wlt := &RemoteWallet{} // nolint megacheck False negative
 	c, err := NewSkycoinApiClient(wltSrv.poolSection)
 	if err != nil {
@@ -118,6 +132,7 @@ func (wltSrv *SkycoinRemoteWallet) CreateWallet(label string, seed string, wltTy
 		wltOpt := api.CreateWalletOptions{}
 		wltOpt.Type = wltType
 		wltOpt.Seed = seed
+		wltOpt.SeedPassphrase = seedPassphrase
 		wltOpt.Password = password
 		wltOpt.Encrypt = true
 		wltOpt.Label = label
@@ -134,6 +149,7 @@ func (wltSrv *SkycoinRemoteWallet) CreateWallet(label string, seed string, wltTy
 		wltOpt := api.CreateWalletOptions{}
 		wltOpt.Type = wltType
 		wltOpt.Seed = seed
+		wltOpt.SeedPassphrase = seedPassphrase
 		wltOpt.Encrypt = false
 		wltOpt.Label = label
 		wltOpt.ScanN = scanAddressesN
@@ -163,12 +179,39 @@ func (wltSrv *SkycoinRemoteWallet) SupportedWalletTypes() []string {
 	}
 }
 
-func (wltSrv *SkycoinRemoteWallet) Encrypt(walletName string, pwd core.PasswordReader) {
+// DeleteWallet is not available for remote nodes , their wallet files are out of reach from this host
+func (wltSrv *SkycoinRemoteWallet) DeleteWallet(id, confirmation string, pwd core.PasswordReader) error {
+	logWallet.WithField("id", id).Warn("Deleting remote wallets is not supported")
+	return errors.ErrWalletFolderUnavailable
+}
+
+// ArchiveWallet is not available for remote nodes , their wallet files are out of reach from this host
+func (wltSrv *SkycoinRemoteWallet) ArchiveWallet(id string) (string, error) {
+	logWallet.WithField("id", id).Warn("Archiving remote wallets is not supported")
+	return "", errors.ErrWalletFolderUnavailable
+}
+
+// ListArchivedWallets returns an empty iterator , remote wallets are never archived
+func (wltSrv *SkycoinRemoteWallet) ListArchivedWallets() core.WalletIterator {
+	return NewSkycoinWalletIterator(make([]core.Wallet, 0))
+}
+
+// RestoreWallet is not available for remote nodes , which only load wallets on startup anyway
+func (wltSrv *SkycoinRemoteWallet) RestoreWallet(archiveId string) (core.Wallet, error) {
+	logWallet.WithField("archiveId", archiveId).Warn("Restoring remote wallets is not supported")
+	return nil, errors.ErrWalletFolderUnavailable
+}
+
+func (wltSrv *SkycoinRemoteWallet) Encrypt(walletName, cryptoType string, pwd core.PasswordReader) error {
 	logWallet.Info("Encrypting remote wallet")
+	// Remote nodes encrypt wallets with their default crypto type
+	if cryptoType != "" && cryptoType != string(wallet.DefaultCryptoType) {
+		return errors.ErrUnsupportedCryptoType
+	}
 	c, err := NewSkycoinApiClient(wltSrv.poolSection)
 	if err != nil {
 		logWallet.WithError(err).Error("Couldn't get API client")
-		return
+		return err
 	}
 	defer ReturnSkycoinClient(c)
 	pwdCtx := util.NewKeyValueMap()
@@ -177,23 +220,24 @@ func (wltSrv *SkycoinRemoteWallet) Encrypt(walletName string, pwd core.PasswordR
 	pwdCtx.SetValue(core.StrWalletName, walletName)
 	password, err := pwd("Enter password to encrypt wallet", pwdCtx)
 	if err != nil {
-		logWallet.WithError(err).Fatal("Something was wrong entering the password")
-		return
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
 	}
 	logWallet.Info("POST /api/v1/wallet/encrypt")
 	_, err = c.EncryptWallet(walletName, password)
 	if err != nil {
 		logWallet.WithError(err).Warn("Couldn't POST /api/v1/wallet/encrypt")
-		return
+		return err
 	}
+	return nil
 }
 
-func (wltSrv *SkycoinRemoteWallet) Decrypt(walletName string, pwd core.PasswordReader) {
+func (wltSrv *SkycoinRemoteWallet) Decrypt(walletName string, pwd core.PasswordReader) error {
 	logWallet.Info("Decrypting remote wallet")
 	c, err := NewSkycoinApiClient(wltSrv.poolSection)
 	if err != nil {
 		logWallet.WithError(err).Error("Couldn't get API client")
-		return
+		return err
 	}
 	defer ReturnSkycoinClient(c)
 	pwdCtx := util.NewKeyValueMap()
@@ -202,15 +246,53 @@ func (wltSrv *SkycoinRemoteWallet) Decrypt(walletName string, pwd core.PasswordR
 	pwdCtx.SetValue(core.StrWalletName, walletName)
 	password, err := pwd("Enter password to decrypt wallet", pwdCtx)
 	if err != nil {
-		logWallet.WithError(err).Fatal("Something was wrong entering the password")
-		return
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
 	}
 	logWallet.Info("POST /api/v1/wallet/decrypt")
 	_, err = c.DecryptWallet(walletName, password)
 	if err != nil {
 		logWallet.WithError(err).Warn("Couldn't POST /api/v1/wallet/decrypt")
-		return
+		return err
+	}
+	return nil
+}
+
+// ChangePassword is not supported by remote nodes , their API can only re-encrypt a wallet
+// by decrypting it first , which would leave it in plain text on the node should encrypting fail
+func (wltSrv *SkycoinRemoteWallet) ChangePassword(walletName, cryptoType string, oldPassword, newPassword core.PasswordReader) error {
+	logWallet.WithField("id", walletName).Warn("Changing the password of remote wallets is not supported")
+	return errors.ErrRemoteReencryptUnsupported
+}
+
+// UpgradeEncryption is not supported by remote nodes , see ChangePassword
+func (wltSrv *SkycoinRemoteWallet) UpgradeEncryption(walletName string, password core.PasswordReader) error {
+	logWallet.WithField("id", walletName).Warn("Upgrading the encryption of remote wallets is not supported")
+	return errors.ErrRemoteReencryptUnsupported
+}
+
+func (wltSrv *SkycoinRemoteWallet) CryptoType(walletName string) (string, error) {
+	c, err := NewSkycoinApiClient(wltSrv.poolSection)
+	if err != nil {
+		logWallet.WithError(err).Error("Couldn't get API client")
+		return "", err
+	}
+	defer ReturnSkycoinClient(c)
+	logWallet.Info("GET /api/v1/wallet")
+	wlt, err := c.Wallet(walletName)
+	if err != nil {
+		logWallet.WithError(err).WithField("id", walletName).Error("Couldn't GET /api/v1/wallet")
+		return "", err
 	}
+	if !wlt.Meta.Encrypted {
+		return "", nil
+	}
+	return string(wlt.Meta.CryptoType), nil
+}
+
+// SupportedCryptoTypes remote nodes encrypt wallets with their default crypto type only
+func (wltSrv *SkycoinRemoteWallet) SupportedCryptoTypes() []string {
+	return []string{string(wallet.DefaultCryptoType)}
 }
 
 func (wltSrv *SkycoinRemoteWallet) IsEncrypted(walletName string) (bool, error) {
@@ -317,7 +399,7 @@ func (wltEnv *WalletNode) GetWalletSet() core.WalletSet {
 // Implements SeedGenerator interface
 type SeedService struct{}
 
-func (seedService *SeedService) GenerateMnemonic(entropyBits int) (string, error) {
+func (seedService *SeedService) GenerateMnemonic(entropyBits int, language string) (string, error) {
 	logWallet.Info("Generating mnemonic for Seed service")
 	if entropyBits != 128 && entropyBits != 256 {
 		return "", errors.ErrInvalidWalletEntropy
@@ -328,9 +410,9 @@ func (seedService *SeedService) GenerateMnemonic(entropyBits int) (string, error
 		logWallet.WithError(err).WithField("entropyBits", entropyBits).Error("Call to bip39.NewEntropy(entropyBits) inside GenerateMnemonic failed")
 		return "", err
 	}
-	mnemonic, err := bip39.NewMnemonic(entropy)
+	mnemonic, err := bip39util.NewMnemonic(entropy, language)
 	if err != nil {
-		logWallet.WithError(err).WithField("entropy", entropy).Error("Call to bip39.NewMnemonic(entropy) inside GenerateMnemonic failed")
+		logWallet.WithError(err).WithField("language", language).Error("Call to bip39util.NewMnemonic(entropy, language) inside GenerateMnemonic failed")
 		return "", err
 	}
 	return mnemonic, nil
@@ -338,14 +420,83 @@ func (seedService *SeedService) GenerateMnemonic(entropyBits int) (string, error
 
 func (seedService *SeedService) VerifyMnemonic(seed string) (bool, error) {
 	logWallet.Info("Checking mnemonic")
-	err := bip39.ValidateMnemonic(seed)
+	err := bip39util.ValidateMnemonic(seed)
 	if err != nil {
-		logWallet.WithError(err).WithField("seed", seed).Error("Call to bip39.ValidateMnemonic(seed) inside VerifyMnemonic failed")
+		logWallet.WithError(err).Error("Call to bip39util.ValidateMnemonic(seed) inside VerifyMnemonic failed")
 		return false, err
 	}
 	return true, nil
 }
 
+func (seedService *SeedService) MnemonicLanguage(seed string) (string, error) {
+	return bip39util.DetectLanguage(seed)
+}
+
+func (seedService *SeedService) SupportedLanguages() []string {
+	return bip39util.Languages()
+}
+
+func (seedService *SeedService) CompleteWord(prefix, language string) ([]string, error) {
+	return bip39util.CompleteWord(prefix, language)
+}
+
+func (seedService *SeedService) SuggestWords(word, language string) ([]string, error) {
+	return bip39util.SuggestWords(word, language)
+}
+
+// Implements SeedSplitter interface , the BIP39 entropy is the SLIP-39 master secret
+func (seedService *SeedService) SplitMnemonic(mnemonic, passphrase string, groupThreshold int, groups []core.ShareGroup) ([][]string, error) {
+	logWallet.Info("Splitting mnemonic in shares")
+	entropy, err := bip39util.EntropyFromMnemonic(mnemonic, "")
+	if err != nil {
+		logWallet.WithError(err).Error("Call to bip39util.EntropyFromMnemonic(mnemonic) inside SplitMnemonic failed")
+		return nil, err
+	}
+	sharingGroups := make([]slip39util.Group, len(groups))
+	for i, g := range groups {
+		sharingGroups[i] = slip39util.Group{MemberThreshold: g.MemberThreshold, MemberCount: g.MemberCount}
+	}
+	opts := slip39util.SplitOptions{IterationExponent: slip39util.DefaultIterationExponent}
+	shares, err := slip39util.SplitSecret(entropy, passphrase, groupThreshold, sharingGroups, opts)
+	if err != nil {
+		logWallet.WithError(err).WithField("groupThreshold", groupThreshold).Error("Call to slip39util.SplitSecret inside SplitMnemonic failed")
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (seedService *SeedService) GenerateShares(entropyBits int, passphrase string, groupThreshold int, groups []core.ShareGroup) ([][]string, error) {
+	mnemonic, err := seedService.GenerateMnemonic(entropyBits, bip39util.DefaultLanguage)
+	if err != nil {
+		return nil, err
+	}
+	return seedService.SplitMnemonic(mnemonic, passphrase, groupThreshold, groups)
+}
+
+func (seedService *SeedService) VerifyShare(share string) (bool, error) {
+	logWallet.Info("Checking share mnemonic")
+	if _, err := slip39util.ParseShare(share); err != nil {
+		logWallet.WithError(err).Error("Call to slip39util.ParseShare(share) inside VerifyShare failed")
+		return false, err
+	}
+	return true, nil
+}
+
+func (seedService *SeedService) CombineShares(shares []string, passphrase, language string) (string, error) {
+	logWallet.Info("Recovering mnemonic from shares")
+	entropy, err := slip39util.CombineShares(shares, passphrase)
+	if err != nil {
+		logWallet.WithError(err).Error("Call to slip39util.CombineShares inside CombineShares failed")
+		return "", err
+	}
+	mnemonic, err := bip39util.NewMnemonic(entropy, language)
+	if err != nil {
+		logWallet.WithError(err).Error("Call to bip39util.NewMnemonic(entropy, language) inside CombineShares failed")
+		return "", err
+	}
+	return mnemonic, nil
+}
+
 type errorTickerInvalid struct {
 	tickerUsed string
 }
@@ -470,7 +621,7 @@ func (wlt *RemoteWallet) Transfer(destination core.TransactionOutput, options co
 		logWallet.WithError(err).Warnf("Couldn't get quotient for %s", params.SkycoinTicker)
 		return nil, err
 	}
-	txnOutput.skyOut.Coins = util.FormatCoins(amount, quot)
+	txnOutput.skyOut.Coins = formatRequestAmount(amount, quot)
 	createTxnFunc := func(txnR *api.CreateTransactionRequest) (core.Transaction, error) {
 		logWallet.Info("Creating transaction for remote wallet")
 		var req api.WalletCreateTransactionRequest
@@ -493,11 +644,30 @@ func (wlt *RemoteWallet) Transfer(destination core.TransactionOutput, options co
 		return fromTxnResponse(txnResponse), nil
 	}
 
-	return createTransaction(nil, []core.TransactionOutput{&txnOutput}, nil, nil, options, createTxnFunc)
+	excluded, err := excludedOutputs(options, wlt.Id)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []core.Address
+	if len(excluded) > 0 {
+		if addresses, err = loadedAddresses(wlt); err != nil {
+			return nil, err
+		}
+	}
+	addresses, uxOuts, err := selectInputs(wlt.poolSection, addresses, excluded)
+	if err != nil {
+		return nil, err
+	}
+	return createTransaction(addresses, []core.TransactionOutput{&txnOutput}, uxOuts, nil, options, createTxnFunc)
 }
 
 type createTxn func(*api.CreateTransactionRequest) (core.Transaction, error)
 
+// formatRequestAmount formats amounts sent to the node , which rejects digit grouping
+func formatRequestAmount(n, quotient uint64) string {
+	return strings.Replace(util.FormatCoins(n, quotient), ",", "", -1)
+}
+
 func createTransaction(from []core.Address, to, uxOut []core.TransactionOutput, change core.Address, options core.KeyValueStore, createTxnFunc createTxn) (core.Transaction, error) {
 	logWallet.Info("Creating transaction...")
 	var req api.CreateTransactionRequest
@@ -554,7 +724,7 @@ func createTransaction(from []core.Address, to, uxOut []core.TransactionOutput,
 			logWallet.WithError(err).Warn("Couldn't get Skycoin's quotient")
 			return nil, err
 		}
-		strAmount := util.FormatCoins(skyV, quotient)
+		strAmount := formatRequestAmount(skyV, quotient)
 		recv := api.Receiver{}
 		outAddr, err := out.GetAddress()
 		if err != nil {
@@ -575,7 +745,7 @@ func createTransaction(from []core.Address, to, uxOut []core.TransactionOutput,
 				return nil, err
 			}
 
-			recv.Hours = util.FormatCoins(chV, quotient)
+			recv.Hours = formatRequestAmount(chV, quotient)
 		}
 		destination = append(destination, recv)
 	}
@@ -616,7 +786,15 @@ func (wlt *RemoteWallet) SendFromAddress(from []core.Address, to []core.Transact
 		return fromTxnResponse(txnResponse), nil
 	}
 
-	return createTransaction(from, to, nil, change, options, createTxnFunc)
+	excluded, err := excludedOutputs(options, wlt.Id)
+	if err != nil {
+		return nil, err
+	}
+	from, uxOuts, err := selectInputs(wlt.poolSection, from, excluded)
+	if err != nil {
+		return nil, err
+	}
+	return createTransaction(from, to, uxOuts, change, options, createTxnFunc)
 }
 
 func (wlt *RemoteWallet) Spend(unspent, new []core.TransactionOutput, change core.Address, options core.KeyValueStore) (core.Transaction, error) {
@@ -642,6 +820,13 @@ func (wlt *RemoteWallet) Spend(unspent, new []core.TransactionOutput, change cor
 		return fromTxnResponse(txnResponse), nil
 	}
 
+	excluded, err := excludedOutputs(options, wlt.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSpendable(unspent, excluded, options); err != nil {
+		return nil, err
+	}
 	return createTransaction(nil, new, unspent, change, options, createTxnFunc)
 }
 
@@ -930,8 +1115,14 @@ func (wltSrv *SkycoinLocalWallet) GetWallet(id string) core.Wallet {
 	}
 }
 
-func (wltSrv *SkycoinLocalWallet) CreateWallet(label string, seed string, wltType string, IsEncrypted bool, pwd core.PasswordReader, scanAddressesN int) (core.Wallet, error) {
+func (wltSrv *SkycoinLocalWallet) CreateWallet(label string, seed string, seedPassphrase string, wltType string, IsEncrypted bool, pwd core.PasswordReader, scanAddressesN int) (core.Wallet, error) {
 	logWallet.Info("Creating Skycoin local wallet")
+	if seedPassphrase != "" && wltType != wallet.WalletTypeBip44 {
+		return nil, errors.ErrSeedPassphraseUnsupported
+	}
+	if err := checkSeedLanguage(seed, wltType); err != nil {
+		return nil, err
+	}
 	pwdCtx := util.NewKeyValueMap()
 	pwdCtx.SetValue(core.StrTypeName, core.TypeNameWalletSet)
 	pwdCtx.SetValue(core.StrMethodName, "CreateWallet")
@@ -945,11 +1136,12 @@ func (wltSrv *SkycoinLocalWallet) CreateWallet(label string, seed string, wltTyp
 	passwordByte := []byte(password)
 
 	opts := wallet.Options{
-		Label:    label,
-		Seed:     seed,
-		Encrypt:  IsEncrypted,
-		Type:     wltType,
-		Password: passwordByte,
+		Label:          label,
+		Seed:           seed,
+		SeedPassphrase: seedPassphrase,
+		Encrypt:        IsEncrypted,
+		Type:           wltType,
+		Password:       passwordByte,
 	}
 	wltName := wltSrv.newUnicWalletFilename()
 	var wlt wallet.Wallet
@@ -983,6 +1175,20 @@ func (wltSrv *SkycoinLocalWallet) CreateWallet(label string, seed string, wltTyp
 	}, nil
 }
 
+// checkSeedLanguage refuses BIP44 wallets for non English mnemonics ,
+// Skycoin derives their keys from English BIP39 mnemonics only
+func checkSeedLanguage(seed, wltType string) error {
+	if wltType != wallet.WalletTypeBip44 {
+		return nil
+	}
+	// Invalid mnemonics are reported when creating the wallet
+	language, err := bip39util.DetectLanguage(seed)
+	if err == nil && language != bip39util.LanguageEnglish {
+		return errors.ErrSeedLanguageUnsupported
+	}
+	return nil
+}
+
 // DefaultWalletType default wallet type
 func (wltSrv *SkycoinLocalWallet) DefaultWalletType() string {
 	return wallet.WalletTypeBip44
@@ -996,6 +1202,191 @@ func (wltSrv *SkycoinLocalWallet) SupportedWalletTypes() []string {
 	}
 }
 
+// DeleteWallet removes wallet file for good , use ArchiveWallet to keep it
+func (wltSrv *SkycoinLocalWallet) DeleteWallet(id, confirmation string, pwd core.PasswordReader) error {
+	logWallet.Info("Deleting Skycoin local wallet")
+	if err := wltSrv.checkDeleteWallet(id, confirmation, pwd); err != nil {
+		return err
+	}
+	return wltSrv.removeWallet(id)
+}
+
+// checkDeleteWallet ensures deletion of wallet id was confirmed , and the password is correct if encrypted
+func (wltSrv *SkycoinLocalWallet) checkDeleteWallet(id, confirmation string, pwd core.PasswordReader) error {
+	if confirmation != id {
+		return errors.ErrWalletDeleteNotConfirmed
+	}
+	path, err := wltSrv.walletPath(id)
+	if err != nil {
+		return err
+	}
+	wlt, err := wallet.Load(path)
+	if err != nil {
+		logWallet.WithError(err).WithField("filename", path).Error("Call to wallet.Load(filename) inside DeleteWallet failed.")
+		return err
+	}
+	if !wlt.IsEncrypted() {
+		return nil
+	}
+	pwdCtx := util.NewKeyValueMap()
+	pwdCtx.SetValue(core.StrTypeName, core.TypeNameWalletSet)
+	pwdCtx.SetValue(core.StrMethodName, "DeleteWallet")
+	pwdCtx.SetValue(core.StrWalletName, id)
+	pwdCtx.SetValue(core.StrWalletLabel, wlt.Label())
+	password, err := pwd("Enter password to delete wallet", pwdCtx)
+	if err != nil {
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
+	}
+	unlockedWallet, err := wallet.Unlock(wlt, []byte(password))
+	if err != nil {
+		logWallet.WithError(err).Error("Call to wallet.Unlock() inside DeleteWallet failed")
+		return err
+	}
+	unlockedWallet.Erase()
+	return nil
+}
+
+func (wltSrv *SkycoinLocalWallet) removeWallet(id string) error {
+	path, err := wltSrv.walletPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		logWallet.WithError(err).WithField("filename", path).Error("Couldn't remove wallet file")
+		return err
+	}
+	return nil
+}
+
+// ArchiveWallet moves wallet file to the archive folder , prefixed with current time
+func (wltSrv *SkycoinLocalWallet) ArchiveWallet(id string) (string, error) {
+	logWallet.Info("Archiving Skycoin local wallet")
+	path, err := wltSrv.walletPath(id)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", wallet.ErrWalletNotExist
+		}
+		return "", err
+	}
+	archiveDir := filepath.Join(wltSrv.walletDir, ArchiveDirName)
+	if err := os.MkdirAll(archiveDir, 0700); err != nil {
+		logWallet.WithError(err).WithField("dir", archiveDir).Error("Couldn't create archive folder")
+		return "", err
+	}
+	archiveId := time.Now().UTC().Format(ArchiveTimestampFormat) + "_" + id
+	archivePath := filepath.Join(archiveDir, archiveId)
+	if _, err := os.Stat(archivePath); err == nil {
+		return "", errors.ErrWalletExists
+	}
+	if err := os.Rename(path, archivePath); err != nil {
+		logWallet.WithError(err).WithField("filename", path).Error("Couldn't move wallet file to archive")
+		return "", err
+	}
+	return archiveId, nil
+}
+
+// ListArchivedWallets returns an iterator over wallets in the archive folder
+func (wltSrv *SkycoinLocalWallet) ListArchivedWallets() core.WalletIterator {
+	logWallet.Info("Listing archived Skycoin local wallets")
+	wallets := make([]core.Wallet, 0)
+	for _, wlt := range wltSrv.archivedWallets() {
+		wallets = append(wallets, wlt)
+	}
+	return NewSkycoinWalletIterator(wallets)
+}
+
+func (wltSrv *SkycoinLocalWallet) archivedWallets() []*LocalWallet {
+	archiveDir := filepath.Join(wltSrv.walletDir, ArchiveDirName)
+	wallets := make([]*LocalWallet, 0)
+	entries, err := ioutil.ReadDir(archiveDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logWallet.WithError(err).WithField("dirname", archiveDir).Error("Call to ioutil.ReadDir(dirname) inside ListArchivedWallets failed.")
+		}
+		return wallets
+	}
+	for _, e := range entries {
+		if !e.Mode().IsRegular() {
+			continue
+		}
+		archiveId := e.Name()
+		if _, err := parseArchiveId(archiveId); err != nil {
+			continue
+		}
+		path := filepath.Join(archiveDir, archiveId)
+		w, err := wallet.Load(path)
+		if err != nil {
+			logWallet.WithError(err).WithField("filename", path).Warn("Call to wallet.Load(filename) inside ListArchivedWallets failed.")
+			continue
+		}
+		wallets = append(wallets, &LocalWallet{
+			Id:        archiveId,
+			Label:     w.Label(),
+			Encrypted: w.IsEncrypted(),
+			Type:      w.Type(),
+			CoinType:  string(w.Coin()),
+			WalletDir: archiveDir,
+		})
+	}
+	return wallets
+}
+
+// RestoreWallet moves an archived wallet file back to the wallets folder under its original ID
+func (wltSrv *SkycoinLocalWallet) RestoreWallet(archiveId string) (core.Wallet, error) {
+	logWallet.Info("Restoring archived Skycoin local wallet")
+	id, err := parseArchiveId(archiveId)
+	if err != nil {
+		return nil, err
+	}
+	archivePath := filepath.Join(wltSrv.walletDir, ArchiveDirName, archiveId)
+	if _, err := os.Stat(archivePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.ErrInvalidArchiveId
+		}
+		return nil, err
+	}
+	path := filepath.Join(wltSrv.walletDir, id)
+	if _, err := os.Stat(path); err == nil {
+		return nil, errors.ErrWalletExists
+	}
+	if err := os.Rename(archivePath, path); err != nil {
+		logWallet.WithError(err).WithField("filename", archivePath).Error("Couldn't move wallet file out of archive")
+		return nil, err
+	}
+	wlt := wltSrv.GetWallet(id)
+	if wlt == nil {
+		return nil, wallet.ErrWalletNotExist
+	}
+	return wlt, nil
+}
+
+// walletPath resolves wallet file of id , rejecting IDs outside wallets folder
+func (wltSrv *SkycoinLocalWallet) walletPath(id string) (string, error) {
+	if id == "" || filepath.Base(id) != id || !strings.HasSuffix(id, walletExt) {
+		return "", wallet.ErrWalletNotExist
+	}
+	return filepath.Join(wltSrv.walletDir, id), nil
+}
+
+// parseArchiveId returns the original ID of an archived wallet
+func parseArchiveId(archiveId string) (string, error) {
+	if filepath.Base(archiveId) != archiveId {
+		return "", errors.ErrInvalidArchiveId
+	}
+	parts := strings.SplitN(archiveId, "_", 2)
+	if len(parts) != 2 || !strings.HasSuffix(parts[1], walletExt) {
+		return "", errors.ErrInvalidArchiveId
+	}
+	if _, err := time.Parse(ArchiveTimestampFormat, parts[0]); err != nil {
+		return "", errors.ErrInvalidArchiveId
+	}
+	return parts[1], nil
+}
+
 func (wltSrv *SkycoinLocalWallet) newUnicWalletFilename() string {
 	name := ""
 	for {
@@ -1010,18 +1401,25 @@ func (wltSrv *SkycoinLocalWallet) newUnicWalletFilename() string {
 
 }
 
-func (wltSrv *SkycoinLocalWallet) Encrypt(walletName string, password core.PasswordReader) {
+func (wltSrv *SkycoinLocalWallet) Encrypt(walletName, cryptoType string, password core.PasswordReader) error {
 	logWallet.Info("Encrypt Skycoin local wallet")
+	ct := wallet.DefaultCryptoType
+	if cryptoType != "" {
+		var err error
+		if ct, err = localCryptoType(cryptoType); err != nil {
+			return err
+		}
+	}
 	wltName := filepath.Join(wltSrv.walletDir, walletName)
 	wlt, err := wallet.Load(wltName)
 	if err != nil {
 		logWallet.WithError(err).WithField("filename", wltName).Error("Call to wallet.Load(filename) inside Encrypt failed.")
-		return
+		return err
 	}
 
 	wltLabel := wlt.Label()
 	if wlt.IsEncrypted() {
-		return
+		return wallet.ErrWalletEncrypted
 	}
 
 	pwdCtx := util.NewKeyValueMap()
@@ -1031,33 +1429,33 @@ func (wltSrv *SkycoinLocalWallet) Encrypt(walletName string, password core.Passw
 	pwdCtx.SetValue(core.StrWalletLabel, wltLabel)
 	pwd, err := password("Enter Password", pwdCtx)
 	if err != nil {
-		logWallet.WithError(err).Fatal("Something was wrong entering the password")
-		return
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
 	}
 	pwdBytes := []byte(pwd)
 
-	if err := wallet.Lock(wlt, pwdBytes, "scrypt-chacha20poly1305"); err != nil {
+	if err := wallet.Lock(wlt, pwdBytes, ct); err != nil {
 		logWallet.WithError(err).Error("Call to wallet.Lock() inside Encrypt failed")
-		return
+		return err
 	}
 
-	if err := wallet.Save(wlt, wltSrv.walletDir); err != nil {
-		logWallet.WithError(err).WithField("dir", wltSrv.walletDir).Error("Call to wallet.Save(wlt, dir) inside Encrypt failed")
-		return
+	if err := saveWalletAtomic(wlt, wltSrv.walletDir); err != nil {
+		logWallet.WithError(err).WithField("dir", wltSrv.walletDir).Error("Call to saveWalletAtomic(wlt, dir) inside Encrypt failed")
+		return err
 	}
-
+	return nil
 }
 
-func (wltSrv *SkycoinLocalWallet) Decrypt(walletName string, password core.PasswordReader) {
+func (wltSrv *SkycoinLocalWallet) Decrypt(walletName string, password core.PasswordReader) error {
 	logWallet.Info("Decrypt Skycoin local wallet")
 	wltName := filepath.Join(wltSrv.walletDir, walletName)
 	wlt, err := wallet.Load(wltName)
 	if err != nil {
 		logWallet.WithError(err).WithField("filename", wltName).Error("Call to wallet.Load(filename) inside Decrypt failed.")
-		return
+		return err
 	}
 	if !wlt.IsEncrypted() {
-		return
+		return wallet.ErrWalletNotEncrypted
 	}
 	wltLabel := wlt.Label()
 	pwdCtx := util.NewKeyValueMap()
@@ -1067,20 +1465,172 @@ func (wltSrv *SkycoinLocalWallet) Decrypt(walletName string, password core.Passw
 	pwdCtx.SetValue(core.StrWalletLabel, wltLabel)
 	pwd, err := password("Enter Password", pwdCtx)
 	if err != nil {
-		logWallet.WithError(err).Fatal("Something was wrong entering the password")
-		return
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
 	}
 	pwdBytes := []byte(pwd)
 
 	unlockedWallet, err := wallet.Unlock(wlt, pwdBytes)
 	if err != nil {
 		logWallet.WithError(err).Error("Call to wallet.Unlock() inside Decrypt failed")
-		return
+		return err
 	}
-	if err := wallet.Save(unlockedWallet, wltSrv.walletDir); err != nil {
-		logWallet.WithError(err).WithField("dir", wltSrv.walletDir).Error("Call to wallet.Save(wlt, dir) inside Decrypt failed")
-		return
+	if err := saveWalletAtomic(unlockedWallet, wltSrv.walletDir); err != nil {
+		logWallet.WithError(err).WithField("dir", wltSrv.walletDir).Error("Call to saveWalletAtomic(wlt, dir) inside Decrypt failed")
+		return err
+	}
+	return nil
+}
+
+func (wltSrv *SkycoinLocalWallet) ChangePassword(walletName, cryptoType string, oldPassword, newPassword core.PasswordReader) error {
+	logWallet.Info("Changing Skycoin local wallet password")
+	wltName := filepath.Join(wltSrv.walletDir, walletName)
+	wlt, err := wallet.Load(wltName)
+	if err != nil {
+		logWallet.WithError(err).WithField("filename", wltName).Error("Call to wallet.Load(filename) inside ChangePassword failed.")
+		return err
+	}
+	if !wlt.IsEncrypted() {
+		return wallet.ErrWalletNotEncrypted
+	}
+	ct := wlt.CryptoType()
+	if cryptoType != "" {
+		if ct, err = localCryptoType(cryptoType); err != nil {
+			return err
+		}
+	}
+
+	pwdCtx := util.NewKeyValueMap()
+	pwdCtx.SetValue(core.StrTypeName, core.TypeNameWalletStorage)
+	pwdCtx.SetValue(core.StrMethodName, "ChangePassword")
+	pwdCtx.SetValue(core.StrWalletName, wltName)
+	pwdCtx.SetValue(core.StrWalletLabel, wlt.Label())
+	oldPwd, err := oldPassword("Enter current password", pwdCtx)
+	if err != nil {
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
 	}
+	newPwd, err := newPassword("Enter new password", pwdCtx)
+	if err != nil {
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
+	}
+	return wltSrv.reencrypt(wlt, []byte(oldPwd), []byte(newPwd), ct)
+}
+
+func (wltSrv *SkycoinLocalWallet) UpgradeEncryption(walletName string, password core.PasswordReader) error {
+	logWallet.Info("Upgrading Skycoin local wallet encryption")
+	wltName := filepath.Join(wltSrv.walletDir, walletName)
+	wlt, err := wallet.Load(wltName)
+	if err != nil {
+		logWallet.WithError(err).WithField("filename", wltName).Error("Call to wallet.Load(filename) inside UpgradeEncryption failed.")
+		return err
+	}
+	if !wlt.IsEncrypted() {
+		return wallet.ErrWalletNotEncrypted
+	}
+	if !isWeakCryptoType(wlt.CryptoType()) {
+		return nil
+	}
+
+	pwdCtx := util.NewKeyValueMap()
+	pwdCtx.SetValue(core.StrTypeName, core.TypeNameWalletStorage)
+	pwdCtx.SetValue(core.StrMethodName, "UpgradeEncryption")
+	pwdCtx.SetValue(core.StrWalletName, wltName)
+	pwdCtx.SetValue(core.StrWalletLabel, wlt.Label())
+	pwd, err := password("Enter Password", pwdCtx)
+	if err != nil {
+		logWallet.WithError(err).Error("Something was wrong entering the password")
+		return err
+	}
+	return wltSrv.reencrypt(wlt, []byte(pwd), []byte(pwd), wallet.DefaultCryptoType)
+}
+
+// reencrypt unlocks wlt in memory and saves it encrypted with newPwd , the plaintext wallet is never written to disk
+func (wltSrv *SkycoinLocalWallet) reencrypt(wlt wallet.Wallet, oldPwd, newPwd []byte, ct wallet.CryptoType) error {
+	unlockedWallet, err := wallet.Unlock(wlt, oldPwd)
+	if err != nil {
+		logWallet.WithError(err).Error("Call to wallet.Unlock() inside reencrypt failed")
+		return err
+	}
+	defer unlockedWallet.Erase()
+	// Lock wipes secrets of the unlocked copy once encrypted
+	if err := wallet.Lock(unlockedWallet, newPwd, ct); err != nil {
+		logWallet.WithError(err).Error("Call to wallet.Lock() inside reencrypt failed")
+		return err
+	}
+	if err := saveWalletAtomic(unlockedWallet, wltSrv.walletDir); err != nil {
+		logWallet.WithError(err).WithField("dir", wltSrv.walletDir).Error("Call to saveWalletAtomic(wlt, dir) inside reencrypt failed")
+		return err
+	}
+	return nil
+}
+
+func (wltSrv *SkycoinLocalWallet) CryptoType(walletName string) (string, error) {
+	wltName := filepath.Join(wltSrv.walletDir, walletName)
+	wlt, err := wallet.Load(wltName)
+	if err != nil {
+		logWallet.WithError(err).WithField("filename", wltName).Error("Call to wallet.Load(filename) inside CryptoType failed.")
+		return "", err
+	}
+	if !wlt.IsEncrypted() {
+		return "", nil
+	}
+	return string(wlt.CryptoType()), nil
+}
+
+func (wltSrv *SkycoinLocalWallet) SupportedCryptoTypes() []string {
+	return []string{
+		string(wallet.CryptoTypeScryptChacha20poly1305),
+		string(wallet.CryptoTypeSha256Xor),
+	}
+}
+
+// localCryptoType validates crypto types local wallets can be encrypted with
+func localCryptoType(cryptoType string) (wallet.CryptoType, error) {
+	switch ct := wallet.CryptoType(cryptoType); ct {
+	case wallet.CryptoTypeScryptChacha20poly1305, wallet.CryptoTypeSha256Xor:
+		return ct, nil
+	}
+	return "", errors.ErrUnsupportedCryptoType
+}
+
+// isWeakCryptoType determines whether wallets encrypted with ct should be upgraded
+func isWeakCryptoType(ct wallet.CryptoType) bool {
+	return ct == wallet.CryptoTypeSha256Xor || ct == wallet.CryptoTypeScryptChacha20poly1305Insecure
+}
+
+// saveWalletAtomic writes the wallet file to a temporary file renamed over the old one ,
+// so that a failure never leaves a partially written wallet
+func saveWalletAtomic(wlt wallet.Wallet, dir string) error {
+	rw := wlt.ToReadable()
+	data, err := json.MarshalIndent(rw, "", "    ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, rw.Filename()+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, rw.Filename())); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
 }
 
 func (wltSrv *SkycoinLocalWallet) IsEncrypted(walletName string) (bool, error) {
@@ -1431,7 +1981,7 @@ func (wlt *LocalWallet) Transfer(to core.TransactionOutput, options core.KeyValu
 		logWallet.WithError(err).Warnf("Couldn't get ticker %s from TransactionOutput", params.SkycoinTicker)
 		return nil, err
 	}
-	strAmount := util.FormatCoins(amount, quotient)
+	strAmount := formatRequestAmount(amount, quotient)
 
 	var txnOutput SkycoinTransactionOutput
 	outAddr, err := to.GetAddress()
@@ -1441,18 +1991,21 @@ func (wlt *LocalWallet) Transfer(to core.TransactionOutput, options core.KeyValu
 	}
 	txnOutput.skyOut.Address = outAddr.String()
 	txnOutput.skyOut.Coins = strAmount
-	addresses := make([]core.Address, 0)
-	iterAddr, err := wlt.GetLoadedAddresses()
+	addresses, err := loadedAddresses(wlt)
 	if err != nil {
-		logWallet.WithError(err).Warn("Couldn't get loaded addresses")
 		return nil, err
 	}
-	for iterAddr.Next() {
-		addresses = append(addresses, iterAddr.Value())
+	excluded, err := excludedOutputs(options, wlt.Id)
+	if err != nil {
+		return nil, err
+	}
+	addresses, uxOuts, err := selectInputs(PoolSection, addresses, excluded)
+	if err != nil {
+		return nil, err
 	}
 
 	createTxnFunc := skyAPICreateTxn
-	return createTransaction(addresses, []core.TransactionOutput{&txnOutput}, nil, nil, options, createTxnFunc)
+	return createTransaction(addresses, []core.TransactionOutput{&txnOutput}, uxOuts, nil, options, createTxnFunc)
 }
 
 func (wlt LocalWallet) SendFromAddress(from []core.Address, to []core.TransactionOutput, change core.Address, options core.KeyValueStore) (core.Transaction, error) {
@@ -1473,7 +2026,15 @@ func (wlt LocalWallet) SendFromAddress(from []core.Address, to []core.Transactio
 
 	}
 
-	return createTransaction(from, to, nil, change, options, createTxnFunc)
+	excluded, err := excludedOutputs(options, wlt.Id)
+	if err != nil {
+		return nil, err
+	}
+	from, uxOuts, err := selectInputs(PoolSection, from, excluded)
+	if err != nil {
+		return nil, err
+	}
+	return createTransaction(from, to, uxOuts, change, options, createTxnFunc)
 
 }
 func (wlt LocalWallet) Spend(unspent, new []core.TransactionOutput, change core.Address, options core.KeyValueStore) (core.Transaction, error) {
@@ -1494,6 +2055,13 @@ func (wlt LocalWallet) Spend(unspent, new []core.TransactionOutput, change core.
 
 	}
 
+	excluded, err := excludedOutputs(options, wlt.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSpendable(unspent, excluded, options); err != nil {
+		return nil, err
+	}
 	return createTransaction(nil, new, unspent, change, options, createTxnFunc)
 }
 
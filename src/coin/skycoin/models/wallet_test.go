@@ -1,11 +1,15 @@
 package skycoin
 
 import (
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,16 +18,21 @@ import (
 	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
 	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
 	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/fibercrypto/fibercryptowallet/src/util/bip39util"
 
 	"github.com/SkycoinProject/skycoin/src/api"
 	"github.com/SkycoinProject/skycoin/src/cipher"
+	"github.com/SkycoinProject/skycoin/src/cipher/bip32"
+	"github.com/SkycoinProject/skycoin/src/cipher/bip39"
 	"github.com/SkycoinProject/skycoin/src/coin"
 	"github.com/SkycoinProject/skycoin/src/readable"
 	"github.com/SkycoinProject/skycoin/src/testutil"
 	"github.com/SkycoinProject/skycoin/src/wallet"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestTransactionFinderAddressesActivity(t *testing.T) {
@@ -110,23 +119,118 @@ func TestSkycoinRemoteWalletCreateWallet(t *testing.T) {
 		Encrypt: false,
 	}
 
+	wltOpt3 := api.CreateWalletOptions{
+		Type:           wallet.WalletTypeBip44,
+		Seed:           seed,
+		SeedPassphrase: "passphrase",
+		Label:          label,
+		ScanN:          scanN,
+		Encrypt:        false,
+	}
+
 	mockSkyApiCreateWallet(global_mock, &wltOpt1, "walletEncrypted", true)
 	mockSkyApiCreateWallet(global_mock, &wltOpt2, "walletNonEncrypted", false)
+	mockSkyApiCreateWallet(global_mock, &wltOpt3, "walletSeedPassphrase", false)
 
 	wltSrv := &SkycoinRemoteWallet{poolSection: PoolSection}
 	pwdReader := func(message string, _ core.KeyValueStore) (string, error) {
 		return "pwd", nil
 	}
 
-	wlt1, err := wltSrv.CreateWallet(label, seed, wallet.WalletTypeDeterministic, true, pwdReader, scanN)
+	wlt1, err := wltSrv.CreateWallet(label, seed, "", wallet.WalletTypeDeterministic, true, pwdReader, scanN)
 	require.NoError(t, err)
 	require.Equal(t, "walletEncrypted", wlt1.GetLabel())
 	require.Equal(t, "FiberCrypto", wlt1.GetId())
 
-	wlt2, err := wltSrv.CreateWallet(label, seed, wallet.WalletTypeDeterministic, false, pwdReader, scanN)
+	wlt2, err := wltSrv.CreateWallet(label, seed, "", wallet.WalletTypeDeterministic, false, pwdReader, scanN)
 	require.NoError(t, err)
 	require.Equal(t, "walletNonEncrypted", wlt2.GetLabel())
 	require.Equal(t, "FiberCrypto", wlt2.GetId())
+
+	wlt3, err := wltSrv.CreateWallet(label, seed, "passphrase", wallet.WalletTypeBip44, false, pwdReader, scanN)
+	require.NoError(t, err)
+	require.Equal(t, "walletSeedPassphrase", wlt3.GetLabel())
+
+	_, err = wltSrv.CreateWallet(label, seed, "passphrase", wallet.WalletTypeDeterministic, false, pwdReader, scanN)
+	require.Equal(t, errors.ErrSeedPassphraseUnsupported, err)
+}
+
+func TestSkycoinLocalWalletCreateWalletSeedPassphrase(t *testing.T) {
+	// BIP39 test vector , see https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	passphrase := "TREZOR"
+	bip39Seed := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	bip32Root := "xprv9s21ZrQH143K3h3fDYiay8mocZ3afhfULfb5GX8kCBdno77K4HiA15Tg23wpbeF1pLfs1c5SPmYHrEpTuuRhxMwvKDwqdKiGJS9XFKzUsAF"
+
+	seed, err := bip39.NewSeed(mnemonic, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, bip39Seed, hex.EncodeToString(seed))
+	root, err := bip32.NewMasterKey(seed)
+	require.NoError(t, err)
+	require.Equal(t, bip32Root, root.String())
+
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	wltSrv := &SkycoinLocalWallet{walletDir: dir}
+
+	// External addresses of m/44'/8000'/0' derived from the vector root keys
+	tests := []struct {
+		passphrase string
+		want       []string
+	}{
+		{passphrase: passphrase, want: []string{"zaGHV3FFnn98Bf6QXs3V1gPGuCNKdQPew3", "2DA27wgBuD9wvpWrfhV3g9uB4qh2sLLhgmv"}},
+		{passphrase: "", want: []string{"28RHxxgAsbCuTv5U9VgWrDGDUpoho2gbh66", "2bjtkT6qDBxhBSLsVK5TmRHfZBy35z7sMVq"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("SeedPassphrase -> %q", tt.passphrase), func(t *testing.T) {
+			wlt, err := wltSrv.CreateWallet("bip44", mnemonic, tt.passphrase, wallet.WalletTypeBip44, false, util.EmptyPassword, 0)
+			require.NoError(t, err)
+			addrs := wlt.GenAddresses(core.AccountAddress, 0, uint32(len(tt.want)), nil)
+			require.NotNil(t, addrs)
+			got := make([]string, 0)
+			for addrs.Next() {
+				got = append(got, addrs.Value().String())
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = wltSrv.CreateWallet("deterministic", mnemonic, passphrase, wallet.WalletTypeDeterministic, false, util.EmptyPassword, 0)
+	require.Equal(t, errors.ErrSeedPassphraseUnsupported, err)
+}
+
+func TestSkycoinLocalWalletCreateWalletNormalizedSeed(t *testing.T) {
+	entropy := make([]byte, 16)
+	for i := range entropy {
+		entropy[i] = byte(i * 17)
+	}
+	mnemonic, err := bip39util.NewMnemonic(entropy, bip39util.LanguageSpanish)
+	require.NoError(t, err)
+	composed, decomposed := norm.NFC.String(mnemonic), "  "+strings.Replace(norm.NFD.String(mnemonic), " ", "  ", -1)
+	require.NotEqual(t, composed, decomposed)
+
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	wltSrv := &SkycoinLocalWallet{walletDir: dir}
+
+	firstAddr := func(seed string) string {
+		wlt, err := wltSrv.CreateWallet("normalized", seed, "", wallet.WalletTypeDeterministic, false, util.EmptyPassword, 0)
+		require.NoError(t, err)
+		addrs := wlt.GenAddresses(core.AccountAddress, 0, 1, nil)
+		require.True(t, addrs.Next())
+		return addrs.Value().String()
+	}
+	// Seeds are normalized by the wallet manager before creating the wallet
+	require.Equal(t, firstAddr(bip39util.NormalizeMnemonic(composed)), firstAddr(bip39util.NormalizeMnemonic(decomposed)))
+	require.NotEqual(t, firstAddr(composed), firstAddr(decomposed))
+
+	_, err = wltSrv.CreateWallet("bip44", bip39util.NormalizeMnemonic(composed), "", wallet.WalletTypeBip44, false, util.EmptyPassword, 0)
+	require.Equal(t, errors.ErrSeedLanguageUnsupported, err)
+	remote := &SkycoinRemoteWallet{poolSection: PoolSection}
+	_, err = remote.CreateWallet("bip44", bip39util.NormalizeMnemonic(composed), "", wallet.WalletTypeBip44, false, util.EmptyPassword, 0)
+	require.Equal(t, errors.ErrSeedLanguageUnsupported, err)
 }
 
 func TestSkycoinRemoteWalletEncrypt(t *testing.T) {
@@ -138,7 +242,8 @@ func TestSkycoinRemoteWalletEncrypt(t *testing.T) {
 		return "pwd", nil
 	}
 
-	wltSrv.Encrypt("wallet", pwdReader)
+	require.NoError(t, wltSrv.Encrypt("wallet", "", pwdReader))
+	require.Equal(t, errors.ErrUnsupportedCryptoType, wltSrv.Encrypt("wallet", string(wallet.CryptoTypeSha256Xor), pwdReader))
 }
 
 func TestSkycoinRemoteWalletDecrypt(t *testing.T) {
@@ -150,7 +255,55 @@ func TestSkycoinRemoteWalletDecrypt(t *testing.T) {
 		return "pwd", nil
 	}
 
-	wltSrv.Decrypt("wallet", pwdReader)
+	require.NoError(t, wltSrv.Decrypt("wallet", pwdReader))
+}
+
+func TestSkycoinRemoteWalletChangePassword(t *testing.T) {
+	CleanGlobalMock()
+	global_mock.On("DecryptWallet", "wallet", "old").Return(&api.WalletResponse{}, nil)
+	// Decrypting first would leave the wallet in plain text on the node
+	global_mock.On("EncryptWallet", "wallet", "new").Return(nil, fmt.Errorf("failure"))
+
+	wltSrv := &SkycoinRemoteWallet{poolSection: PoolSection}
+	prompted := false
+	pwdReader := func(message string, _ core.KeyValueStore) (string, error) {
+		prompted = true
+		return "old", nil
+	}
+	require.Equal(t, errors.ErrRemoteReencryptUnsupported, wltSrv.ChangePassword("wallet", "", pwdReader, util.ConstantPassword("new")))
+	require.Equal(t, errors.ErrRemoteReencryptUnsupported, wltSrv.UpgradeEncryption("wallet", pwdReader))
+	require.False(t, prompted)
+	global_mock.AssertNotCalled(t, "DecryptWallet", "wallet", "old")
+	global_mock.AssertNotCalled(t, "EncryptWallet", "wallet", "new")
+}
+
+func TestSkycoinRemoteWalletCryptoType(t *testing.T) {
+	CleanGlobalMock()
+	global_mock.On("Wallet", "weak").Return(
+		&api.WalletResponse{
+			Meta: readable.WalletMeta{
+				Encrypted:  true,
+				CryptoType: wallet.CryptoTypeSha256Xor,
+			},
+		},
+		nil)
+	global_mock.On("Wallet", "plain").Return(
+		&api.WalletResponse{
+			Meta: readable.WalletMeta{
+				Encrypted: false,
+			},
+		},
+		nil)
+
+	wltSrv := &SkycoinRemoteWallet{poolSection: PoolSection}
+	cryptoType, err := wltSrv.CryptoType("weak")
+	require.NoError(t, err)
+	require.Equal(t, string(wallet.CryptoTypeSha256Xor), cryptoType)
+	cryptoType, err = wltSrv.CryptoType("plain")
+	require.NoError(t, err)
+	require.Empty(t, cryptoType)
+	require.Equal(t, []string{string(wallet.DefaultCryptoType)}, wltSrv.SupportedCryptoTypes())
+
 }
 
 func TestSkycoinRemoteWalletIsEncrypted(t *testing.T) {
@@ -1527,7 +1680,7 @@ func TestSkycoinLocalWalletEncrypt(t *testing.T) {
 			wlt, err := wallet.Load(filepath.Join(tt.srv.walletDir, tt.name))
 			clean := err == nil
 
-			tt.srv.Encrypt(tt.name, tt.pwd)
+			_ = tt.srv.Encrypt(tt.name, "", tt.pwd)
 			encrypted, err := tt.srv.IsEncrypted(tt.name)
 			if clean {
 				require.NoError(t, err)
@@ -1566,7 +1719,7 @@ func TestSkycoinLocalWalletDecrypt(t *testing.T) {
 			wlt, err := wallet.Load(filepath.Join(tt.srv.walletDir, tt.name))
 			clean := err == nil
 
-			tt.srv.Decrypt(tt.name, tt.pwd)
+			_ = tt.srv.Decrypt(tt.name, tt.pwd)
 			encrypted, err := tt.srv.IsEncrypted(tt.name)
 			if clean {
 				require.NoError(t, err)
@@ -1580,6 +1733,178 @@ func TestSkycoinLocalWalletDecrypt(t *testing.T) {
 	}
 }
 
+func TestSkycoinLocalWalletChangePassword(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	wltSrv := &SkycoinLocalWallet{walletDir: dir}
+	seed := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wlt, err := wltSrv.CreateWallet("wallet", seed, "", wallet.WalletTypeDeterministic, false, util.EmptyPassword, 0)
+	require.NoError(t, err)
+	name := wlt.GetId()
+	walletFile := filepath.Join(dir, name)
+
+	oldPwd := util.ConstantPassword("old")
+	newPwd := util.ConstantPassword("new")
+	require.Equal(t, wallet.ErrWalletNotEncrypted, wltSrv.ChangePassword(name, "", oldPwd, newPwd))
+	require.Equal(t, errors.ErrUnsupportedCryptoType, wltSrv.Encrypt(name, "rot13", oldPwd))
+
+	require.NoError(t, wltSrv.Encrypt(name, string(wallet.CryptoTypeSha256Xor), oldPwd))
+	require.Equal(t, wallet.ErrWalletEncrypted, wltSrv.Encrypt(name, "", oldPwd))
+	cryptoType, err := wltSrv.CryptoType(name)
+	require.NoError(t, err)
+	require.Equal(t, string(wallet.CryptoTypeSha256Xor), cryptoType)
+
+	unlock := func(password string) error {
+		w, err := wallet.Load(walletFile)
+		require.NoError(t, err)
+		require.True(t, w.IsEncrypted())
+		_, err = wallet.Unlock(w, []byte(password))
+		return err
+	}
+
+	// A wrong password leaves the wallet untouched
+	before, err := ioutil.ReadFile(walletFile)
+	require.NoError(t, err)
+	require.Error(t, wltSrv.ChangePassword(name, "", newPwd, newPwd))
+	after, err := ioutil.ReadFile(walletFile)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+
+	// The crypto type is kept unless a new one is given
+	require.NoError(t, wltSrv.ChangePassword(name, "", oldPwd, newPwd))
+	require.Error(t, unlock("old"))
+	require.NoError(t, unlock("new"))
+	cryptoType, err = wltSrv.CryptoType(name)
+	require.NoError(t, err)
+	require.Equal(t, string(wallet.CryptoTypeSha256Xor), cryptoType)
+
+	require.NoError(t, wltSrv.UpgradeEncryption(name, newPwd))
+	cryptoType, err = wltSrv.CryptoType(name)
+	require.NoError(t, err)
+	require.Equal(t, string(wallet.CryptoTypeScryptChacha20poly1305), cryptoType)
+	require.NoError(t, unlock("new"))
+	// Strong crypto types are not upgraded , so the password is not read
+	require.NoError(t, wltSrv.UpgradeEncryption(name, util.EmptyPassword))
+
+	require.NoError(t, wltSrv.ChangePassword(name, string(wallet.CryptoTypeScryptChacha20poly1305), newPwd, oldPwd))
+	require.NoError(t, unlock("old"))
+
+	// Neither the seed nor temporary files are left on disk
+	data, err := ioutil.ReadFile(walletFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "abandon")
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	require.NoError(t, wltSrv.Decrypt(name, oldPwd))
+	cryptoType, err = wltSrv.CryptoType(name)
+	require.NoError(t, err)
+	require.Empty(t, cryptoType)
+	require.Equal(t, wallet.ErrWalletNotEncrypted, wltSrv.Decrypt(name, oldPwd))
+}
+
+func TestSkycoinLocalWalletArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	wltSrv := &SkycoinLocalWallet{walletDir: dir}
+	seed := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wlt, err := wltSrv.CreateWallet("wallet", seed, "", wallet.WalletTypeDeterministic, false, util.EmptyPassword, 0)
+	require.NoError(t, err)
+	id := wlt.GetId()
+
+	_, err = wltSrv.ArchiveWallet("../" + id)
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+	archiveId, err := wltSrv.ArchiveWallet(id)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(archiveId, "_"+id))
+	require.Nil(t, wltSrv.GetWallet(id))
+	require.False(t, wltSrv.ListWallets().Next())
+	_, err = wltSrv.ArchiveWallet(id)
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+
+	it := wltSrv.ListArchivedWallets()
+	require.True(t, it.Next())
+	require.Equal(t, archiveId, it.Value().GetId())
+	require.Equal(t, "wallet", it.Value().GetLabel())
+	require.False(t, it.Next())
+
+	_, err = wltSrv.RestoreWallet(id)
+	require.Equal(t, errors.ErrInvalidArchiveId, err)
+	_, err = wltSrv.RestoreWallet("20191106T000000Z_" + id)
+	require.Equal(t, errors.ErrInvalidArchiveId, err)
+	restored, err := wltSrv.RestoreWallet(archiveId)
+	require.NoError(t, err)
+	require.Equal(t, id, restored.GetId())
+	require.Equal(t, "wallet", restored.GetLabel())
+	require.NotNil(t, wltSrv.GetWallet(id))
+	require.False(t, wltSrv.ListArchivedWallets().Next())
+
+	// Restoring does not overwrite wallets created with the same ID meanwhile
+	archiveId, err = wltSrv.ArchiveWallet(id)
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(filepath.Join(dir, ArchiveDirName, archiveId))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, id), data, 0600))
+	_, err = wltSrv.RestoreWallet(archiveId)
+	require.Equal(t, errors.ErrWalletExists, err)
+}
+
+func TestSkycoinLocalWalletDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	wltSrv := &SkycoinLocalWallet{walletDir: dir}
+	seed := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	plain, err := wltSrv.CreateWallet("plain", seed, "", wallet.WalletTypeDeterministic, false, util.EmptyPassword, 0)
+	require.NoError(t, err)
+	encrypted, err := wltSrv.CreateWallet("encrypted", seed, "", wallet.WalletTypeDeterministic, true, util.ConstantPassword("pwd"), 0)
+	require.NoError(t, err)
+
+	require.Equal(t, errors.ErrWalletDeleteNotConfirmed, wltSrv.DeleteWallet(plain.GetId(), "", util.EmptyPassword))
+	require.Equal(t, errors.ErrWalletDeleteNotConfirmed, wltSrv.DeleteWallet(plain.GetId(), encrypted.GetId(), util.EmptyPassword))
+	require.NotNil(t, wltSrv.GetWallet(plain.GetId()))
+	require.NoError(t, wltSrv.DeleteWallet(plain.GetId(), plain.GetId(), util.EmptyPassword))
+	require.Nil(t, wltSrv.GetWallet(plain.GetId()))
+
+	require.Error(t, wltSrv.DeleteWallet(encrypted.GetId(), encrypted.GetId(), util.ConstantPassword("wrong")))
+	require.NotNil(t, wltSrv.GetWallet(encrypted.GetId()))
+	require.NoError(t, wltSrv.DeleteWallet(encrypted.GetId(), encrypted.GetId(), util.ConstantPassword("pwd")))
+	require.Nil(t, wltSrv.GetWallet(encrypted.GetId()))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestSkycoinRemoteWalletArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	localSet := &SkycoinLocalWallet{walletDir: dir}
+	seed := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wlt, err := localSet.CreateWallet("remote", seed, "", wallet.WalletTypeDeterministic, false, util.EmptyPassword, 0)
+	require.NoError(t, err)
+	id := wlt.GetId()
+
+	CleanGlobalMock()
+	global_mock.On("UnloadWallet", id).Return(nil)
+
+	wltSrv := &SkycoinRemoteWallet{poolSection: PoolSection}
+	// Wallet files of remote nodes are never archived , restored nor deleted
+	_, err = wltSrv.ArchiveWallet(id)
+	require.Equal(t, errors.ErrWalletFolderUnavailable, err)
+	require.False(t, wltSrv.ListArchivedWallets().Next())
+	_, err = wltSrv.RestoreWallet(id)
+	require.Equal(t, errors.ErrWalletFolderUnavailable, err)
+	require.Equal(t, errors.ErrWalletFolderUnavailable, wltSrv.DeleteWallet(id, id, util.EmptyPassword))
+	global_mock.AssertNotCalled(t, "UnloadWallet", id)
+	_, err = os.Stat(filepath.Join(dir, id))
+	require.NoError(t, err)
+}
+
 func TestLocalWalletSetLabel(t *testing.T) {
 	newLabel := "custom-label"
 	tests := []struct {
@@ -1766,32 +2091,46 @@ func TestWalletNodeGetStorage(t *testing.T) {
 func TestSeedServiceGenerateMnemonic(t *testing.T) {
 	srv := new(SeedService)
 	tests := []struct {
-		name  string
-		bits  int
-		valid bool
+		name     string
+		bits     int
+		language string
+		valid    bool
 	}{
 		{name: "bad-entropyBits", bits: 15, valid: false},
 		{name: "128-bits", bits: 128, valid: true},
 		{name: "256-bits", bits: 256, valid: true},
+		{name: "spanish", bits: 128, language: bip39util.LanguageSpanish, valid: true},
+		{name: "japanese", bits: 256, language: bip39util.LanguageJapanese, valid: true},
+		{name: "bad-language", bits: 128, language: "klingon", valid: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := srv.GenerateMnemonic(tt.bits)
+			mnemonic, err := srv.GenerateMnemonic(tt.bits, tt.language)
 			if tt.valid {
 				require.Nil(t, err)
+				language, err := srv.MnemonicLanguage(mnemonic)
+				require.NoError(t, err)
+				if tt.language == "" {
+					require.Equal(t, bip39util.DefaultLanguage, language)
+				} else {
+					require.Equal(t, tt.language, language)
+				}
 			} else {
 				require.NotNil(t, err)
 			}
 		})
 	}
+	require.Contains(t, srv.SupportedLanguages(), bip39util.LanguageChineseSimplified)
 }
 
 func TestSeedServiceVerifyMnemonic(t *testing.T) {
 	srv := new(SeedService)
-	mnc128, err := srv.GenerateMnemonic(128)
+	mnc128, err := srv.GenerateMnemonic(128, "")
+	require.NoError(t, err)
+	mnc256, err := srv.GenerateMnemonic(256, "")
 	require.NoError(t, err)
-	mnc256, err := srv.GenerateMnemonic(256)
+	mncSpanish, err := srv.GenerateMnemonic(128, bip39util.LanguageSpanish)
 	require.NoError(t, err)
 	tests := []struct {
 		name     string
@@ -1801,6 +2140,8 @@ func TestSeedServiceVerifyMnemonic(t *testing.T) {
 		{name: "bad-mnemonic", mnemonic: "invalid-mnemonic", valid: false},
 		{name: "128-bits", mnemonic: mnc128, valid: true},
 		{name: "256-bits", mnemonic: mnc256, valid: true},
+		{name: "spanish", mnemonic: mncSpanish, valid: true},
+		{name: "spanish-nfc", mnemonic: norm.NFC.String(mncSpanish), valid: true},
 	}
 
 	for _, tt := range tests {
@@ -1817,6 +2158,95 @@ func TestSeedServiceVerifyMnemonic(t *testing.T) {
 	}
 }
 
+func TestSeedServiceWords(t *testing.T) {
+	srv := new(SeedService)
+	words, err := srv.CompleteWord("zo", bip39util.LanguageEnglish)
+	require.NoError(t, err)
+	require.Equal(t, []string{"zone", "zoo"}, words)
+	words, err = srv.SuggestWords("abandom", "")
+	require.NoError(t, err)
+	require.Equal(t, "abandon", words[0])
+	_, err = srv.SuggestWords("abandon", "klingon")
+	require.Equal(t, errors.ErrUnknownSeedLanguage, err)
+}
+
+func TestSeedServiceShares(t *testing.T) {
+	srv := new(SeedService)
+	groups := []core.ShareGroup{{MemberThreshold: 2, MemberCount: 3}, {MemberThreshold: 1, MemberCount: 1}}
+
+	for _, bits := range []int{128, 256} {
+		t.Run(fmt.Sprintf("%d-bits", bits), func(t *testing.T) {
+			mnemonic, err := srv.GenerateMnemonic(bits, "")
+			require.NoError(t, err)
+			shares, err := srv.SplitMnemonic(mnemonic, "pass", 1, groups)
+			require.NoError(t, err)
+			require.Len(t, shares, 2)
+			for _, group := range shares {
+				for _, share := range group {
+					valid, err := srv.VerifyShare(share)
+					require.NoError(t, err)
+					require.True(t, valid)
+				}
+			}
+
+			recovered, err := srv.CombineShares([]string{shares[0][2], shares[0][0]}, "pass", "")
+			require.NoError(t, err)
+			require.Equal(t, mnemonic, recovered)
+			recovered, err = srv.CombineShares(shares[1], "pass", "")
+			require.NoError(t, err)
+			require.Equal(t, mnemonic, recovered)
+
+			_, err = srv.CombineShares(shares[0][:1], "pass", "")
+			require.Equal(t, errors.ErrInsufficientShares, err)
+
+			shares, err = srv.GenerateShares(bits, "", 2, groups)
+			require.NoError(t, err)
+			recovered, err = srv.CombineShares([]string{shares[1][0], shares[0][1], shares[0][2]}, "", "")
+			require.NoError(t, err)
+			valid, err := srv.VerifyMnemonic(recovered)
+			require.NoError(t, err)
+			require.True(t, valid)
+		})
+	}
+
+	// Mistyped words are caught before recovering the seed
+	valid, err := srv.VerifyShare("duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney")
+	require.False(t, valid)
+	require.Equal(t, errors.ErrShareChecksum, err)
+	valid, err = srv.VerifyShare("duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard")
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	_, err = srv.SplitMnemonic("invalid-mnemonic", "", 1, groups)
+	require.Error(t, err)
+	_, err = srv.GenerateShares(15, "", 1, groups)
+	require.Equal(t, errors.ErrInvalidWalletEntropy, err)
+}
+
+func TestSeedServiceCombineSharesCreateWallet(t *testing.T) {
+	srv := new(SeedService)
+	mnemonic, err := srv.GenerateMnemonic(128, bip39util.LanguageSpanish)
+	require.NoError(t, err)
+	shares, err := srv.SplitMnemonic(mnemonic, "", 1, []core.ShareGroup{{MemberThreshold: 3, MemberCount: 5}})
+	require.NoError(t, err)
+	recovered, err := srv.CombineShares(shares[0][1:4], "", bip39util.LanguageSpanish)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	wltSrv := &SkycoinLocalWallet{walletDir: dir}
+
+	firstAddr := func(seed string) string {
+		wlt, err := wltSrv.CreateWallet(seed, seed, "", wallet.WalletTypeDeterministic, false, util.EmptyPassword, 0)
+		require.NoError(t, err)
+		addrs := wlt.GenAddresses(core.AccountAddress, 0, 1, nil)
+		require.True(t, addrs.Next())
+		return addrs.Value().String()
+	}
+	require.Equal(t, firstAddr(mnemonic), firstAddr(recovered))
+}
+
 func TestErrorTickerInvalidError(t *testing.T) {
 	format := " is an invalid ticker. Use " + Sky + " or " + CoinHour
 	tickers := []string{"a", "b", "c"}
@@ -1834,3 +2264,19 @@ func TestNewWalletNode(t *testing.T) {
 		require.Equal(t, addr, wn.NodeAddress)
 	}
 }
+
+func TestFormatRequestAmount(t *testing.T) {
+	tests := []struct {
+		n        uint64
+		quotient uint64
+		want     string
+	}{
+		{n: 500, quotient: 1, want: "500"},
+		{n: 1234, quotient: 1, want: "1234"},
+		{n: 1234500000, quotient: 1000000, want: "1234.5"},
+		{n: 0, quotient: 1000000, want: "0"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, formatRequestAmount(tt.n, tt.quotient))
+	}
+}
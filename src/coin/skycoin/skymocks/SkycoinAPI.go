@@ -58,6 +58,29 @@ func (_m *SkycoinAPI) BlockchainProgress() (*readable.BlockchainProgress, error)
 	return r0, r1
 }
 
+// BlocksInRangeVerbose provides a mock function with given fields: start, end
+func (_m *SkycoinAPI) BlocksInRangeVerbose(start uint64, end uint64) (*readable.BlocksVerbose, error) {
+	ret := _m.Called(start, end)
+
+	var r0 *readable.BlocksVerbose
+	if rf, ok := ret.Get(0).(func(uint64, uint64) *readable.BlocksVerbose); ok {
+		r0 = rf(start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*readable.BlocksVerbose)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, uint64) error); ok {
+		r1 = rf(start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CoinSupply provides a mock function with given fields:
 func (_m *SkycoinAPI) CoinSupply() (*api.CoinSupply, error) {
 	ret := _m.Called()
@@ -422,6 +445,43 @@ func (_m *SkycoinAPI) TransactionsVerbose(addrs []string) ([]readable.Transactio
 	return r0, r1
 }
 
+// UnconfirmedTransactionsVerbose provides a mock function with given fields: addrs
+func (_m *SkycoinAPI) UnconfirmedTransactionsVerbose(addrs []string) ([]readable.TransactionWithStatusVerbose, error) {
+	ret := _m.Called(addrs)
+
+	var r0 []readable.TransactionWithStatusVerbose
+	if rf, ok := ret.Get(0).(func([]string) []readable.TransactionWithStatusVerbose); ok {
+		r0 = rf(addrs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]readable.TransactionWithStatusVerbose)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(addrs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnloadWallet provides a mock function with given fields: id
+func (_m *SkycoinAPI) UnloadWallet(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateWallet provides a mock function with given fields: id, label
 func (_m *SkycoinAPI) UpdateWallet(id string, label string) error {
 	ret := _m.Called(id, label)
@@ -528,6 +588,29 @@ func (_m *SkycoinAPI) WalletCreateTransaction(req api.WalletCreateTransactionReq
 	return r0, r1
 }
 
+// WalletFolderName provides a mock function with given fields:
+func (_m *SkycoinAPI) WalletFolderName() (*api.WalletFolder, error) {
+	ret := _m.Called()
+
+	var r0 *api.WalletFolder
+	if rf, ok := ret.Get(0).(func() *api.WalletFolder); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.WalletFolder)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WalletSignTransaction provides a mock function with given fields: req
 func (_m *SkycoinAPI) WalletSignTransaction(req api.WalletSignTransactionRequest) (*api.CreateTransactionResponse, error) {
 	ret := _m.Called(req)
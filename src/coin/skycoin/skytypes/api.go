@@ -18,12 +18,16 @@ type SkycoinAPI interface {
 	TransactionsVerbose(addrs []string) ([]readable.TransactionWithStatusVerbose, error)
 	// UxOut Get uxout
 	UxOut(uxID string) (*readable.SpentOutput, error)
+	// UnconfirmedTransactionsVerbose Get unconfirmed transactions for addresses. Include spent input data
+	UnconfirmedTransactionsVerbose(addrs []string) ([]readable.TransactionWithStatusVerbose, error)
 	// PendingTransactionsVerbose Get unconfirmed transactions
 	PendingTransactionsVerbose() ([]readable.UnconfirmedTransactionVerbose, error)
 	// CoinSupply Determine coin supply
 	CoinSupply() (*api.CoinSupply, error)
 	// LastBlocks Get last N blocks
 	LastBlocks(n uint64) (*readable.Blocks, error)
+	// BlocksInRangeVerbose Get blocks between start and end seq , both included , with input data
+	BlocksInRangeVerbose(start, end uint64) (*readable.BlocksVerbose, error)
 	// BlockchainProgress Get blockchain progress
 	BlockchainProgress() (*readable.BlockchainProgress, error)
 	// Balance Get balance of addresses
@@ -34,6 +38,10 @@ type SkycoinAPI interface {
 	Wallet(id string) (*api.WalletResponse, error)
 	// UpdateWallet Change wallet label
 	UpdateWallet(id, label string) error
+	// UnloadWallet Stop tracking wallet
+	UnloadWallet(id string) error
+	// WalletFolderName Get wallets directory
+	WalletFolderName() (*api.WalletFolder, error)
 	// NewWalletAddress Generate new address in wallet
 	NewWalletAddress(id string, n int, password string) ([]string, error)
 	// Wallets Get wallets
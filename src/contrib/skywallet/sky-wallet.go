@@ -10,9 +10,11 @@ import (
 	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/skytypes"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	fce "github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
 	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
 	"github.com/fibercrypto/skywallet-go/src/skywallet"
 	skyWallet "github.com/fibercrypto/skywallet-go/src/skywallet"
+	"github.com/fibercrypto/skywallet-go/src/skywallet/wire"
 	"github.com/fibercrypto/skywallet-protob/go"
 	"github.com/gogo/protobuf/proto"
 	"github.com/sirupsen/logrus"
@@ -23,16 +25,43 @@ var logSkyWallet = logging.MustGetLogger("Skycoin hardware wallet")
 type SkyWallet struct {
 	wlt core.Wallet
 	dev skyWallet.Devicer
+	// pr reads the seed passphrase of devices protected with one
+	pr core.PasswordReader
 }
 
 const (
 	urnPrefix = "signer:skywallet:"
 )
 
+// answerPassphraseRequest reply to a device asking for the seed passphrase reading it with pr,
+// return the device response to the original request.
+func answerPassphraseRequest(dev skyWallet.Devicer, msg wire.Message, pr core.PasswordReader) (wire.Message, error) {
+	if msg.Kind != uint16(messages.MessageType_MessageType_PassphraseRequest) {
+		return msg, nil
+	}
+	if pr == nil {
+		logSkyWallet.Errorln("device requested a seed passphrase but there is no way to read it")
+		return wire.Message{}, fce.ErrHwPassphraseRequired
+	}
+	ctx := util.NewKeyValueMap()
+	ctx.SetValue(core.StrTypeName, core.TypeNameTxnSigner)
+	ctx.SetValue(core.StrMethodName, "PassphraseAck")
+	passphrase, err := pr("Enter the seed passphrase of the hardware wallet", ctx)
+	if err != nil {
+		logSkyWallet.WithError(err).Errorln("unable to read seed passphrase")
+		return wire.Message{}, err
+	}
+	return dev.PassphraseAck(passphrase)
+}
+
 // HwFirstAddr return the first address in the deterministic sequence if there is a configured
 // device connected, error if not device found or some thing fail.
-func HwFirstAddr(dev skyWallet.Devicer, derivationType string) (string, error) {
+// If the device is protected with a seed passphrase it is read with pr.
+func HwFirstAddr(dev skyWallet.Devicer, derivationType string, pr core.PasswordReader) (string, error) {
 	msg, err := dev.AddressGen(1, 0, false, derivationType)
+	if err == nil {
+		msg, err = answerPassphraseRequest(dev, msg, pr)
+	}
 	if err != nil {
 		logSkyWallet.WithError(err).Debugln("error getting address from device")
 		return "", fce.ErrHwUnexpected
@@ -51,7 +80,7 @@ func HwFirstAddr(dev skyWallet.Devicer, derivationType string) (string, error) {
 
 func hwMatchWallet(hw SkyWallet, wlt core.Wallet) bool {
 	checkForDerivation := func(dt string) bool {
-		firstAddr, err := HwFirstAddr(hw.dev, dt)
+		firstAddr, err := HwFirstAddr(hw.dev, dt, hw.pr)
 		if err != nil {
 			logSkyWallet.WithError(err).Errorln("unable to get first address from hw")
 			return false
@@ -91,6 +120,12 @@ func NewSkyWallet(wlt core.Wallet, dev skyWallet.Devicer) *SkyWallet {
 	}
 }
 
+// SetPassphraseReader set the reader of the seed passphrase used when
+// the device is protected with one and the caller did not provide it.
+func (sw *SkyWallet) SetPassphraseReader(pr core.PasswordReader) {
+	sw.pr = pr
+}
+
 func getAllIndexesFromTxn(txn core.Transaction) []int {
 	inputs := txn.GetInputs()
 	indexes := make([]int, len(inputs), cap(inputs))
@@ -248,7 +283,7 @@ func coin2Core(txn *coin.Transaction, fee uint64) (core.Transaction, error) {
 	return unTxn, nil
 }
 
-func (sw *SkyWallet) signTxn(txn *coin.Transaction, idxs []int, dt string) (*coin.Transaction, error) {
+func (sw *SkyWallet) signTxn(txn *coin.Transaction, idxs []int, dt string, pr core.PasswordReader) (*coin.Transaction, error) {
 	transactionInputs, err := getInputs(sw.wlt, *txn, idxs)
 	if err != nil {
 		logSkyWallet.WithError(err).Errorln("unable to get inputs")
@@ -260,6 +295,9 @@ func (sw *SkyWallet) signTxn(txn *coin.Transaction, idxs []int, dt string) (*coi
 		return nil, fce.ErrTxnSignFailure
 	}
 	msg, err := sw.dev.TransactionSign(transactionInputs, transactionOutputs, dt)
+	if err == nil {
+		msg, err = answerPassphraseRequest(sw.dev, msg, pr)
+	}
 	if err != nil {
 		logSkyWallet.WithError(err).Error("error signing transaction")
 		return nil, fce.ErrTxnSignFailure
@@ -295,7 +333,7 @@ func (sw *SkyWallet) signTxn(txn *coin.Transaction, idxs []int, dt string) (*coi
 	return txn, nil
 }
 
-func (sw SkyWallet) signTransaction(txn core.Transaction, idxs []int) (core.Transaction, error) {
+func (sw SkyWallet) signTransaction(txn core.Transaction, idxs []int, pr core.PasswordReader) (core.Transaction, error) {
 	fee, err := txn.ComputeFee(params.CoinHoursTicker)
 	if err != nil {
 		logSkyWallet.WithError(err).Errorln("unable to get fee")
@@ -310,7 +348,7 @@ func (sw SkyWallet) signTransaction(txn core.Transaction, idxs []int) (core.Tran
 	if err != nil {
 		return nil, err
 	}
-	signed, err := sw.signTxn(t, idxs, dt)
+	signed, err := sw.signTxn(t, idxs, dt, pr)
 	if err != nil {
 		logSkyWallet.WithError(err).Errorln("unable to sign transaction")
 		return nil, fce.ErrTxnSignFailure
@@ -395,7 +433,10 @@ func (sw SkyWallet) SignTransaction(txn core.Transaction, pr core.PasswordReader
 		logSkyWallet.Debugln("not inputs to sign specified, assuming all")
 		idxs = getAllIndexesFromTxn(txn)
 	}
-	signedTxn, err := sw.signTransaction(txn, idxs)
+	if pr == nil {
+		pr = sw.pr
+	}
+	signedTxn, err := sw.signTransaction(txn, idxs, pr)
 	if err != nil {
 		logSkyWallet.WithError(err).Errorln("error signing transaction with device")
 		return nil, fce.ErrTxnSignFailure
@@ -189,4 +189,46 @@ func TestGetSignerDescriptionShouldFailForInvalidMessageType(t *testing.T) {
 	// Then
 	require.Error(t, err)
 	require.Equal(t, "", devId)
-}
\ No newline at end of file
+}
+func TestHwFirstAddrShouldAnswerPassphraseRequest(t *testing.T) {
+	// Giving
+	dev := mocks.Devicer{}
+	expectedAddr := "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"
+	r := messages.ResponseSkycoinAddress{Addresses: []string{expectedAddr}}
+	rb, err := proto.Marshal(&r)
+	require.Nil(t, err)
+	dev.On("AddressGen", uint32(1), uint32(0), false, "bip44").Return(wire.Message{
+		Kind: uint16(messages.MessageType_MessageType_PassphraseRequest),
+	}, nil)
+	dev.On("PassphraseAck", "TREZOR").Return(wire.Message{
+		Kind: uint16(messages.MessageType_MessageType_ResponseSkycoinAddress),
+		Data: rb,
+	}, nil)
+	pr := func(string, core.KeyValueStore) (string, error) {
+		return "TREZOR", nil
+	}
+
+	// When
+	addr, err := HwFirstAddr(&dev, "bip44", pr)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, expectedAddr, addr)
+	dev.AssertExpectations(t)
+}
+
+func TestHwFirstAddrShouldFailWithoutPassphraseReader(t *testing.T) {
+	// Giving
+	dev := mocks.Devicer{}
+	dev.On("AddressGen", uint32(1), uint32(0), false, "bip44").Return(wire.Message{
+		Kind: uint16(messages.MessageType_MessageType_PassphraseRequest),
+	}, nil)
+
+	// When
+	addr, err := HwFirstAddr(&dev, "bip44", nil)
+
+	// Then
+	require.Error(t, err)
+	require.Equal(t, "", addr)
+	dev.AssertNotCalled(t, "PassphraseAck", "")
+}
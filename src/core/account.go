@@ -14,4 +14,20 @@ type CryptoAccount interface {
 	ListTransactions() TransactionIterator
 	// ListPendingTransactions to obtain details of transactions pending for confirmation in the memory
 	ListPendingTransactions() (TransactionIterator, error)
+	// GetBalanceBreakdown retrieves confirmed , predicted , spendable and locked funds for asset represented by ticker
+	GetBalanceBreakdown(ticker string) (*Balance, error)
+}
+
+// Balance breakdown of the funds owned by an account for a given asset
+type Balance struct {
+	// Confirmed funds in the blockchain
+	Confirmed uint64
+	// Predicted funds once pending transactions are confirmed
+	Predicted uint64
+	// Spendable confirmed funds not being spent by pending transactions
+	Spendable uint64
+	// Locked confirmed funds being spent by pending transactions
+	Locked uint64
+	// Accrued coin hours generated by confirmed funds since the last block , zero for other assets
+	Accrued uint64
 }
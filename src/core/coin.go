@@ -113,3 +113,32 @@ type Block interface {
 	// IsGenesisBlock determines whether this block starts blockchain sequence
 	IsGenesisBlock() (bool, error)
 }
+
+// TxnDirection classifies transactions relative to the wallet they belong to
+type TxnDirection uint32
+
+const (
+	// TxnDirectionAny matches transactions regardless of direction
+	TxnDirectionAny TxnDirection = iota
+	// TxnDirectionSent funds leave the wallet
+	TxnDirectionSent
+	// TxnDirectionReceived funds enter the wallet
+	TxnDirectionReceived
+	// TxnDirectionInternal funds move between addresses of the same wallet
+	TxnDirectionInternal
+)
+
+// BlockchainTransaction is implemented by transactions aware of the block they were included in
+type BlockchainTransaction interface {
+	Transaction
+	// GetBlockSeq returns the sequence number of the block including this transaction
+	GetBlockSeq() uint64
+}
+
+// BlockTxnScanner is implemented by blockchains able to look up address history incrementally
+type BlockTxnScanner interface {
+	// ScanBlockTxns lists transactions in blocks startSeq to endSeq , both included , involving any of addresses
+	ScanBlockTxns(addresses []string, startSeq, endSeq uint64) ([]Transaction, error)
+	// PendingTxns lists unconfirmed transactions involving any of addresses
+	PendingTxns(addresses []string) ([]Transaction, error)
+}
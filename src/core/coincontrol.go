@@ -0,0 +1,31 @@
+package core
+
+import "time"
+
+// OutputState coin control status of an unspent output
+type OutputState uint32
+
+const (
+	// OutputSpendable output available for coin selection
+	OutputSpendable OutputState = iota
+	// OutputFrozen output excluded from coin selection until thawed
+	OutputFrozen
+	// OutputReserved output temporarily excluded while a transaction spending it is signed
+	OutputReserved
+)
+
+// CoinControl keeps track of wallet outputs excluded from automatic coin selection
+type CoinControl interface {
+	// FreezeOutput excludes a wallet output from coin selection until thawed
+	FreezeOutput(walletID, outputID string) error
+	// ThawOutput makes a frozen output available for coin selection
+	ThawOutput(walletID, outputID string) error
+	// ReserveOutputs excludes wallet outputs from coin selection until released or ttl expires
+	ReserveOutputs(walletID string, outputIDs []string, ttl time.Duration) error
+	// ReleaseOutputs cancels the reservation of wallet outputs
+	ReleaseOutputs(walletID string, outputIDs []string) error
+	// GetOutputState returns the coin control status of a wallet output
+	GetOutputState(walletID, outputID string) (OutputState, error)
+	// ListExcludedOutputs maps wallet outputs excluded from coin selection to their status
+	ListExcludedOutputs(walletID string) (map[string]OutputState, error)
+}
@@ -0,0 +1,30 @@
+package core
+
+// Contact provides encrypt / decrypt data.
+type Contact interface {
+	GetID() uint64
+	SetID(id uint64)
+	GetAddresses() []StringAddress
+	SetAddresses([]StringAddress)
+	GetName() string
+	SetName(string)
+	// GetGroups tags the contact is filed under
+	GetGroups() []string
+	SetGroups([]string)
+	IsFavourite() bool
+	SetFavourite(bool)
+	// GetNotes free text about the contact
+	GetNotes() string
+	SetNotes(string)
+	IsValid() bool
+}
+type StringAddress interface {
+	GetValue() []byte
+	SetValue(val []byte)
+	GetCoinType() []byte
+	SetCoinType(val []byte)
+	// GetLabel purpose of the address , e.g. exchange deposit or cold storage
+	GetLabel() string
+	SetLabel(string)
+	IsValid() bool
+}
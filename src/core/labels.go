@@ -0,0 +1,46 @@
+package core
+
+import "io"
+
+const (
+	// LabelTypeTxn label bound to a transaction ID
+	LabelTypeTxn = "tx"
+	// LabelTypeAddress label bound to an address
+	LabelTypeAddress = "addr"
+	// LabelTypeOutput label bound to an output ID
+	LabelTypeOutput = "output"
+	// LabelTypeWallet label bound to a wallet ID
+	LabelTypeWallet = "xpub"
+)
+
+// Label annotates a wallet entity following BIP329 wallet label format
+type Label struct {
+	// Type of the labelled entity
+	Type string `json:"type"`
+	// Ref identifies the labelled entity
+	Ref string `json:"ref"`
+	// Label text
+	Label string `json:"label"`
+	// Origin optional key origin of the wallet the entity belongs to
+	Origin string `json:"origin,omitempty"`
+	// Spendable optional flag , only meaningful for outputs
+	Spendable *bool `json:"spendable,omitempty"`
+	// Mark optional highlight level of the entity
+	Mark int `json:"mark,omitempty"`
+}
+
+// LabelStore persists labels for addresses, transactions, outputs and wallets
+type LabelStore interface {
+	// SetLabel creates or replaces the label of an entity
+	SetLabel(label Label) error
+	// GetLabel looks up the label of an entity
+	GetLabel(labelType, ref string) (*Label, error)
+	// DeleteLabel removes the label of an entity
+	DeleteLabel(labelType, ref string) error
+	// ListLabels enumerates labels of a given type , all of them if type is empty
+	ListLabels(labelType string) ([]Label, error)
+	// ImportLabels reads BIP329 JSONL records and stores them , returning the number of labels imported
+	ImportLabels(r io.Reader) (int, error)
+	// ExportLabels writes all labels as BIP329 JSONL records
+	ExportLabels(w io.Writer) error
+}
@@ -23,6 +23,18 @@ type PEX interface {
 	BroadcastTxn(txn Transaction) error
 }
 
+// TxnTracker follows transactions after they are broadcast
+type TxnTracker interface {
+	// GetTxnStatus returns the status of a transaction , TXN_STATUS_CREATED if unknown to network peers
+	GetTxnStatus(txnID string) (TransactionStatus, error)
+	// GetTxnBlockSeq returns the sequence of the block including a transaction , errors.ErrTxnNotConfirmed if none
+	GetTxnBlockSeq(txnID string) (uint64, error)
+	// IsTxnInvalidated determines whether inputs of an encoded transaction were spent by another transaction
+	IsTxnInvalidated(txnID string, raw []byte) (bool, error)
+	// BroadcastRawTxn injects an encoded transaction for confirmation by network peers
+	BroadcastRawTxn(raw []byte) error
+}
+
 // PexNodeIterator scans nodes in a set
 type PexNodeIterator interface {
 	// Value of PEX node data instance at iterator pointer position
@@ -0,0 +1,49 @@
+package core
+
+import "time"
+
+// OutboxFlag signals broadcast transactions needing user attention
+type OutboxFlag uint32
+
+const (
+	// OutboxFlagNone transaction progressing as expected
+	OutboxFlagNone OutboxFlag = iota
+	// OutboxFlagStuck transaction pending for longer than expected
+	OutboxFlagStuck
+	// OutboxFlagInvalidated transaction inputs were spent by another transaction
+	OutboxFlagInvalidated
+)
+
+// OutboxTxn transaction recorded after being broadcast by the wallet
+type OutboxTxn struct {
+	// ID transaction hash
+	ID string `json:"id"`
+	// WalletID wallet the transaction was created for
+	WalletID string `json:"wallet_id"`
+	// CoinTicker coin the transaction was broadcast for
+	CoinTicker string `json:"coin"`
+	// Raw encoded transaction , as injected to the network
+	Raw []byte `json:"raw"`
+	// BroadcastAt time of the first broadcast
+	BroadcastAt time.Time `json:"broadcast_at"`
+	// LastBroadcastAt time of the latest broadcast
+	LastBroadcastAt time.Time `json:"last_broadcast_at"`
+	// Attempts number of times the transaction was broadcast
+	Attempts int `json:"attempts"`
+	// Status of the transaction as seen by the network
+	Status TransactionStatus `json:"status"`
+	// Flag signals problems detected while tracking the transaction
+	Flag OutboxFlag `json:"flag,omitempty"`
+}
+
+// Outbox persists transactions broadcast by the wallet until confirmed
+type Outbox interface {
+	// PutOutboxTxn creates or replaces an outbox record
+	PutOutboxTxn(txn OutboxTxn) error
+	// GetOutboxTxn looks up an outbox record by transaction ID
+	GetOutboxTxn(txnID string) (*OutboxTxn, error)
+	// DeleteOutboxTxn removes an outbox record
+	DeleteOutboxTxn(txnID string) error
+	// ListOutboxTxns enumerates outbox records , oldest broadcast first
+	ListOutboxTxns() ([]OutboxTxn, error)
+}
@@ -0,0 +1,74 @@
+package core
+
+import "time"
+
+// PaymentState progress of a payment expected to an address
+type PaymentState uint32
+
+const (
+	// PaymentAwaiting no funds sent to the address yet
+	PaymentAwaiting PaymentState = iota
+	// PaymentSeenInPool funds sent to the address are pending for confirmation
+	PaymentSeenInPool
+	// PaymentPartiallyPaid confirmed funds do not cover the expected amount yet
+	PaymentPartiallyPaid
+	// PaymentPaid confirmed funds match the expected amount
+	PaymentPaid
+	// PaymentOverpaid confirmed funds exceed the expected amount
+	PaymentOverpaid
+	// PaymentExpired payment was not completed before expiry
+	PaymentExpired
+)
+
+// IsFinal determines whether a payment in this state is no longer watched
+func (s PaymentState) IsFinal() bool {
+	return s == PaymentPaid || s == PaymentOverpaid || s == PaymentExpired
+}
+
+// PaymentWatch payment expected to an address of a wallet
+type PaymentWatch struct {
+	// ID identifies the watch
+	ID string `json:"id"`
+	// WalletID wallet owning the address
+	WalletID string `json:"wallet_id"`
+	// Address receiving the payment
+	Address string `json:"address"`
+	// Amount expected coins , in droplets
+	Amount uint64 `json:"amount"`
+	// MinHours minimum coin hours expected along with coins
+	MinHours uint64 `json:"min_hours,omitempty"`
+	// Expiry time the payment is no longer expected , never if zero
+	Expiry time.Time `json:"expiry,omitempty"`
+	// Confirmations required before funds are considered received
+	Confirmations uint64 `json:"confirmations"`
+	// CreatedAt time the watch was registered
+	CreatedAt time.Time `json:"created_at"`
+	// State of the payment
+	State PaymentState `json:"state"`
+	// Received coins with enough confirmations , in droplets
+	Received uint64 `json:"received"`
+	// ReceivedHours coin hours sent along with received coins
+	ReceivedHours uint64 `json:"received_hours"`
+	// Pending coins waiting for confirmations , in droplets
+	Pending uint64 `json:"pending"`
+}
+
+// PaymentEvent reports a watched payment changed its state
+type PaymentEvent struct {
+	// Watch updated payment watch
+	Watch PaymentWatch
+	// Previous state of the payment
+	Previous PaymentState
+}
+
+// PaymentWatchStore persists payments expected by the wallet
+type PaymentWatchStore interface {
+	// PutPaymentWatch creates or replaces a payment watch
+	PutPaymentWatch(watch PaymentWatch) error
+	// GetPaymentWatch looks up a payment watch by ID
+	GetPaymentWatch(id string) (*PaymentWatch, error)
+	// DeletePaymentWatch removes a payment watch
+	DeletePaymentWatch(id string) error
+	// ListPaymentWatches enumerates payment watches , oldest first
+	ListPaymentWatches() ([]PaymentWatch, error)
+}
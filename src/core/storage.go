@@ -23,6 +23,10 @@ const (
 	StrCoinTicker = "coin.ticker"
 	// StrSenderObject option key for object that triggered an action
 	StrSenderObject = "call.self"
+	// StrCoinControl option key for the CoinControl applied while selecting transaction inputs
+	StrCoinControl = "txn.coincontrol"
+	// StrOverrideFrozen option key allowing explicitly chosen frozen outputs to be spent
+	StrOverrideFrozen = "txn.overridefrozen"
 
 	// TypeNameAddress Address type name
 	TypeNameAddress = "Address"
@@ -60,6 +64,8 @@ const (
 	TypeNamePooledObjectFactory = "PooledObjectFactory"
 	// TypeNameSeedGenerator SeedGenerator type name
 	TypeNameSeedGenerator = "SeedGenerator"
+	// TypeNameSeedSplitter SeedSplitter type name
+	TypeNameSeedSplitter = "SeedSplitter"
 	// TypeNameTransaction Transaction type name
 	TypeNameTransaction = "Transaction"
 	// TypeNameTransactionInput TransactionInput type name
@@ -97,11 +103,26 @@ type AddressBook interface {
 	Init(secType int, password string) error
 	Authenticate(password string) error
 	ChangeSecurity(NewSecType int, oldPassword, newPassword string) error
+	// RecoverSecurity re-encrypts records left unreadable by an interrupted security change
+	RecoverSecurity(previousPassword string) (int, error)
 	GetContact(id uint64) (Contact, error)
 	ListContact() ([]Contact, error)
 	InsertContact(contact Contact) (uint64, error)
 	DeleteContact(id uint64) error
 	UpdateContact(id uint64, contact Contact) error
+	// SearchContacts lists contacts whose name matches query , best matches first
+	SearchContacts(query string) ([]Contact, error)
+	// FindContactsByPrefix lists contacts whose name starts with prefix , ignoring case
+	FindContactsByPrefix(prefix string) ([]Contact, error)
+	// LookupAddress returns the contact owning an address of a coin
+	LookupAddress(address, coinType string) (Contact, error)
+	// ListContactsByCoin lists contacts having addresses of a coin
+	ListContactsByCoin(coinType string) ([]Contact, error)
+	// ListContactsByGroup lists contacts filed under a group
+	ListContactsByGroup(group string) ([]Contact, error)
+	ListFavouriteContacts() ([]Contact, error)
+	// ListGroups lists the groups contacts are filed under
+	ListGroups() ([]string, error)
 	GetStorage() Storage
 	HasInit() bool
 	IsOpen() bool
@@ -109,7 +130,70 @@ type AddressBook interface {
 	Close() error
 }
 
+// StorageCodec encodes and decodes values kept in storage collections
+type StorageCodec interface {
+	// Encode serializes value
+	Encode(value interface{}) ([]byte, error)
+	// Decode deserializes data into value , a pointer
+	Decode(data []byte, value interface{}) error
+}
+
+// StorageItem entry visited while scanning a collection
+type StorageItem interface {
+	// Key of the entry
+	Key() []byte
+	// Decode deserializes the value of the entry into value , a pointer
+	Decode(value interface{}) error
+}
+
+// StorageTxn reads and writes storage collections within a single transaction.
+// Values are encoded with the codec of their collection , byte slices are stored as is
+type StorageTxn interface {
+	// Get decodes the value bound to key in a collection into value , reporting whether it exists
+	Get(collection string, key []byte, value interface{}) (bool, error)
+	// Put binds value to key in a collection , creating the collection if needed
+	Put(collection string, key []byte, value interface{}) error
+	// Delete removes key from a collection
+	Delete(collection string, key []byte) error
+	// NextSequence returns the next auto-increment ID of a collection
+	NextSequence(collection string) (uint64, error)
+	// Scan visits entries of a collection with keys in [start, end) in key order , nil bounds are open.
+	// The scan stops once visit returns false
+	Scan(collection string, start, end []byte, visit func(StorageItem) (bool, error)) error
+	// ScanPrefix visits entries of a collection whose keys start with prefix in key order.
+	// The scan stops once visit returns false
+	ScanPrefix(collection string, prefix []byte, visit func(StorageItem) (bool, error)) error
+	// HasCollection determines whether a collection was created
+	HasCollection(collection string) bool
+	// DropCollection removes a collection with all its entries
+	DropCollection(collection string) error
+}
+
+// CollectionStorage persists values in named collections of a single database
+type CollectionStorage interface {
+	// ViewCollections runs fn within a read only transaction
+	ViewCollections(fn func(StorageTxn) error) error
+	// UpdateCollections runs fn within a read-write transaction , committed only if fn succeeds
+	UpdateCollections(fn func(StorageTxn) error) error
+	// SetCollectionCodec chooses the codec of a collection , JSON by default
+	SetCollectionCodec(collection string, codec StorageCodec)
+	// SchemaVersion returns the version of the database schema
+	SchemaVersion() (uint64, error)
+}
+
+// StorageMigration upgrades the database schema to Version
+type StorageMigration struct {
+	// Version of the schema once applied
+	Version uint64
+	// Description of the changes
+	Description string
+	// Apply upgrades the database from the previous version
+	Apply func(tx StorageTxn) error
+}
+
+// Storage keeps address book contacts and settings , along with other collections
 type Storage interface {
+	CollectionStorage
 	InsertValue(value interface{}) (uint64, error)
 	GetValue(key uint64) (interface{}, error)
 	ListValues() (map[uint64]interface{}, error)
@@ -120,21 +204,3 @@ type Storage interface {
 	InsertConfig(map[string]string) error
 	Close() error
 }
-
-// Contact provides encrypt / decrypt data.
-type Contact interface {
-	GetID() uint64
-	SetID(id uint64)
-	GetAddresses() []StringAddress
-	SetAddresses([]StringAddress)
-	GetName() string
-	SetName(string)
-	IsValid() bool
-}
-type StringAddress interface {
-	GetValue() []byte
-	SetValue(val []byte)
-	GetCoinType() []byte
-	SetCoinType(val []byte)
-	IsValid() bool
-}
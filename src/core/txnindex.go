@@ -0,0 +1,33 @@
+package core
+
+// TxnQuery selects transactions recorded in a transaction index
+type TxnQuery struct {
+	// Addresses restricts results to transactions involving any of these wallet addresses
+	Addresses []string
+	// Since lower bound of transaction timestamp , ignored if zero
+	Since Timestamp
+	// Until upper bound of transaction timestamp , ignored if zero
+	Until Timestamp
+	// Direction of funds relative to the wallet
+	Direction TxnDirection
+}
+
+// TxnIndex persists confirmed transactions observed for each wallet
+type TxnIndex interface {
+	// IndexTxn records a confirmed transaction involving a wallet
+	IndexTxn(walletID string, txn Transaction, blockSeq uint64, direction TxnDirection, addresses []string) error
+	// HasTxn determines whether a transaction has been recorded for a wallet
+	HasTxn(walletID, txnID string) (bool, error)
+	// LastBlockSeq returns the block seq wallet history was synchronized up to
+	LastBlockSeq(walletID string) (uint64, error)
+	// SetLastBlockSeq updates the block seq wallet history was synchronized up to
+	SetLastBlockSeq(walletID string, blockSeq uint64) error
+	// AddressSeqs returns the block seq history of each wallet address was synchronized up to
+	AddressSeqs(walletID string) (map[string]uint64, error)
+	// SetAddressSeqs updates the block seq history of wallet addresses was synchronized up to
+	SetAddressSeqs(walletID string, addresses []string, blockSeq uint64) error
+	// ListTxns returns recorded wallet transactions matching query , newest first
+	ListTxns(walletID string, query TxnQuery) (TransactionIterator, error)
+	// Close releases underlying resources
+	Close() error
+}
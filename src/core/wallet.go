@@ -16,22 +16,40 @@ type WalletSet interface {
 	ListWallets() WalletIterator
 	// GetWallet to lookup wallet by ID
 	GetWallet(id string) Wallet
-	// CreateWallet instantiates a new wallet given account seed
-	CreateWallet(name string, seed string, walletType string, isEncryptrd bool, pwd PasswordReader, scanAddressesN int) (Wallet, error)
+	// CreateWallet instantiates a new wallet given account seed and an optional seed passphrase.
+	// Seed passphrase may only be set for wallet types supporting it (e.g. BIP44)
+	CreateWallet(name string, seed string, seedPassphrase string, walletType string, isEncryptrd bool, pwd PasswordReader, scanAddressesN int) (Wallet, error)
 	// DefaultWalletType default wallet type
 	DefaultWalletType() string
 	// SupportedWalletTypes list supported wallet type names
 	SupportedWalletTypes() []string
+	// DeleteWallet removes a wallet for good , confirmation must match wallet ID
+	// and password is requested for encrypted wallets
+	DeleteWallet(id, confirmation string, pwd PasswordReader) error
+	// ArchiveWallet hides wallet from the set , the archive ID returned is used to restore it
+	ArchiveWallet(id string) (string, error)
+	// ListArchivedWallets returns an iterator over archived wallets identified by archive ID
+	ListArchivedWallets() WalletIterator
+	// RestoreWallet brings an archived wallet back to the set
+	RestoreWallet(archiveId string) (Wallet, error)
 }
 
 // WalletStorage provides access to the underlying wallets data store
 type WalletStorage interface {
-	// Encrypt protects wallet data using cryptography
-	Encrypt(walletName string, password PasswordReader)
+	// Encrypt protects wallet data using cryptoType , the default one if empty
+	Encrypt(walletName, cryptoType string, password PasswordReader) error
 	// Decrypt unlocks wallet for accessing internal data
-	Decrypt(walletName string, password PasswordReader)
+	Decrypt(walletName string, password PasswordReader) error
+	// ChangePassword re-encrypts wallet data with a new password using cryptoType , the current one if empty
+	ChangePassword(walletName, cryptoType string, oldPassword, newPassword PasswordReader) error
+	// UpgradeEncryption re-encrypts wallet data protected by a weak crypto type with the default one
+	UpgradeEncryption(walletName string, password PasswordReader) error
 	// IsEncrypted queries whether wallet data is encrypted or not
 	IsEncrypted(walletName string) (bool, error)
+	// CryptoType returns the crypto type protecting wallet data , empty if not encrypted
+	CryptoType(walletName string) (string, error)
+	// SupportedCryptoTypes lists crypto types wallets can be encrypted with , the default one first
+	SupportedCryptoTypes() []string
 }
 
 // AddressType differentiates between BIP44 public external and internal change addresses
@@ -89,10 +107,36 @@ type WalletAddress interface {
 
 // SeedGenerator establishes the contract for generating BIP39-compatible mnemonics
 type SeedGenerator interface {
-	// GenerateMnemonic generates a valid BIP-39 mnemonic phrase
-	GenerateMnemonic(wordCount int) (string, error)
-	// VerifyMnemonic shall determine whether a given mnemonic phrase complies to BIP39
+	// GenerateMnemonic generates a valid BIP-39 mnemonic phrase with words in language
+	GenerateMnemonic(wordCount int, language string) (string, error)
+	// VerifyMnemonic shall determine whether a given mnemonic phrase complies to BIP39 , in any supported language
 	VerifyMnemonic(seed string) (bool, error)
+	// MnemonicLanguage detects the language of the words of a mnemonic phrase
+	MnemonicLanguage(seed string) (string, error)
+	// SupportedLanguages lists the languages of mnemonic phrases
+	SupportedLanguages() []string
+	// CompleteWord lists the words of language starting with prefix
+	CompleteWord(prefix, language string) ([]string, error)
+	// SuggestWords lists the words of language closest to a mistyped word
+	SuggestWords(word, language string) ([]string, error)
+}
+
+// ShareGroup members of a seed backup group , MemberThreshold of them recover the group share
+type ShareGroup struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// SeedSplitter establishes the contract for backing up BIP39 mnemonics as SLIP-39 share mnemonics
+type SeedSplitter interface {
+	// SplitMnemonic splits mnemonic in share mnemonics listed by group , any groupThreshold groups recover it
+	SplitMnemonic(mnemonic, passphrase string, groupThreshold int, groups []ShareGroup) ([][]string, error)
+	// GenerateShares splits a fresh mnemonic with entropyBits of entropy
+	GenerateShares(entropyBits int, passphrase string, groupThreshold int, groups []ShareGroup) ([][]string, error)
+	// VerifyShare shall determine whether a share mnemonic is well formed and its checksum matches
+	VerifyShare(share string) (bool, error)
+	// CombineShares recovers the BIP39 mnemonic split in shares , with words in the language of the split mnemonic
+	CombineShares(shares []string, passphrase, language string) (string, error)
 }
 
 // WalletEnv is the entry point to manage wallets
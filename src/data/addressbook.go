@@ -2,20 +2,13 @@ package data
 
 import (
 	"bytes"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
-	"crypto/sha512"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"github.com/SkycoinProject/skycoin/src/cipher/bip39"
+	"github.com/boltdb/bolt"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
-	"golang.org/x/crypto/bcrypt"
-	"golang.org/x/crypto/pbkdf2"
-	"io"
 	"strconv"
+	"sync"
 )
 
 const (
@@ -39,12 +32,18 @@ var (
 	errInvalidContact      = errors.New("you try to inserted a invalid contact")
 	errInvalidSecType      = errors.New("invalid security type")
 	errAddrsBookHasNotInit = errors.New("address book not has init")
+	errInvalidCipherText   = errors.New("cipher text too short")
 )
 
 // addrsBook implement AddressBook interface for boltdb database.
 type addrsBook struct {
 	storage core.Storage
 	key     []byte
+	// keys derived from key , by derivation settings
+	keys map[string][]byte
+	// index of decrypted contacts , nil until built
+	index      *contactIndex
+	indexMutex sync.Mutex
 }
 
 var logDb = logging.MustGetLogger("AddressBook Data")
@@ -58,60 +57,57 @@ func NewAddressBook(storage core.Storage) core.AddressBook {
 }
 
 // ChangeSecurity change the security type of the Address Book, this method work to change the password too.
+// Contacts and labels are re-encrypted in place within a single transaction , which only commits
+// once everything decrypts back under the new key. A failure or crash leaves the previous settings in effect.
 func (addrsBook *addrsBook) ChangeSecurity(NewSecType int, oldPassword, newPassword string) error {
 	logDb.Info("changing Address Book security")
 
 	if err := addrsBook.Authenticate(oldPassword); err != nil {
 		return err
 	}
-
-	contactsList, err := addrsBook.ListContact()
+	db, isBolt := addrsBook.GetStorage().(*boltStorage)
+	if !isBolt {
+		return errSecurityChangeUnsupported
+	}
+	oldCipher, err := addrsBook.cipher()
 	if err != nil {
 		return err
 	}
-	for e := range contactsList {
-		if err := addrsBook.DeleteContact(contactsList[e].GetID()); err != nil {
-			return err
-		}
-	}
 
-	var hash, entropy []byte
+	var newCipher *bookCipher
+	var settings map[string]string
 
 	switch NewSecType {
 	case NoSecurity, ObfuscationSecurity:
-		if err := addrsBook.insertConfig(NewSecType, hash, entropy); err != nil {
-			logDb.Error(err)
-			return err
-		}
-		break
+		newCipher, _ = newBookCipher(NewSecType, nil, nil)
 	case PasswordSecurity:
-		addrsBook.key = []byte(newPassword)
-		if entropy, err = addrsBook.genEntropy(); err != nil {
+		if newCipher, settings, err = newPasswordSecurity([]byte(newPassword)); err != nil {
 			logDb.Error(err)
 			return err
 		}
-
-		hash, err = bcrypt.GenerateFromPassword([]byte(newPassword), 12)
-		if err != nil {
-			logDb.Error(err)
-			return err
-		}
-
-		if err := addrsBook.insertConfig(NewSecType, hash, entropy); err != nil {
-			logDb.Error(err)
-			return err
-		}
-		break
 	default:
 		logDb.Error(errInvalidSecType)
 		return errInvalidSecType
 	}
-	for e := range contactsList {
-		if _, err := addrsBook.InsertContact(contactsList[e]); err != nil {
+	config := addrsBook.securityConfig(NewSecType, settings)
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		records, err := readRecords(tx, oldCipher)
+		if err != nil {
 			return err
 		}
+		// Labels are encrypted with the same key as contacts.
+		if err := writeRecords(tx, newCipher, records); err != nil {
+			return err
+		}
+		return putConfig(tx, config)
+	})
+	if err != nil {
+		logDb.Error(err)
+		return err
 	}
-
+	addrsBook.setKey([]byte(newPassword))
+	addrsBook.keys = newCipher.keys
 	return nil
 }
 
@@ -126,43 +122,33 @@ func (addrsBook *addrsBook) Init(secType int, password string) error {
 		return fmt.Errorf("address book has init")
 	}
 
-	var hash, entropy []byte
-	var err error
-
+	var settings map[string]string
 	switch secType {
 	case NoSecurity, ObfuscationSecurity:
-		if err := addrsBook.insertConfig(secType, hash, entropy); err != nil {
-			logDb.Error(err)
-			return err
-		}
 		break
 	case PasswordSecurity:
-		addrsBook.key = []byte(password)
-		if entropy, err = addrsBook.genEntropy(); err != nil {
-			logDb.Error(err)
-			return err
-		}
-
-		hash, err = bcrypt.GenerateFromPassword([]byte(password), 12)
+		c, passwordSettings, err := newPasswordSecurity([]byte(password))
 		if err != nil {
 			logDb.Error(err)
 			return err
 		}
-
-		if err := addrsBook.insertConfig(secType, hash, entropy); err != nil {
-			logDb.Error(err)
-			return err
-		}
-		break
+		settings = passwordSettings
+		addrsBook.setKey([]byte(password))
+		addrsBook.keys = c.keys
 	default:
 		logDb.Error(errInvalidSecType)
 		return errInvalidSecType
 	}
 
+	if err := addrsBook.GetStorage().InsertConfig(addrsBook.securityConfig(secType, settings)); err != nil {
+		logDb.Error(err)
+		return err
+	}
 	return nil
 }
 
 // Authenticate authentic a user in the Address Book. ( Only SecType : PasswordSecurity )
+// Encryption is upgraded to the current key derivation settings on success.
 func (addrsBook *addrsBook) Authenticate(password string) error {
 	logDb.Info("authenticate AddressBook")
 	if !addrsBook.IsOpen() {
@@ -185,12 +171,19 @@ func (addrsBook *addrsBook) Authenticate(password string) error {
 		return nil
 	}
 
-	addrsBook.key = []byte(password)
-	if err := addrsBook.verifyHash(); err != nil {
+	addrsBook.setKey([]byte(password))
+	if err := addrsBook.verifyPassword(); err != nil {
 		logDb.Error(err)
 		return err
 	}
 
+	// Address books written in the legacy format , or with a lower cost , are upgraded transparently
+	if err := addrsBook.upgradeSecurity(); err != nil {
+		logDb.WithError(err).Warn("Couldn't upgrade Address Book encryption")
+	}
+	if err := addrsBook.queryIndex(func(*contactIndex) error { return nil }); err != nil {
+		logDb.WithError(err).Warn("Couldn't index Address Book contacts")
+	}
 	return nil
 }
 
@@ -202,26 +195,24 @@ func (addrsBook *addrsBook) InsertContact(contact core.Contact) (uint64, error)
 		return 0, errInvalidContact
 	}
 
-	contactsList, err := addrsBook.ListContact()
-	if err != nil && err != errBucketEmpty {
-		return 0, err
-	}
-	for _, v := range contact.GetAddresses() {
-		if err := addrsBook.addressExists(v, contactsList); err != nil {
-			return 0, err
+	var id uint64
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		if err := idx.conflict(contact, 0); err != nil {
+			return err
 		}
-	}
-	if err := addrsBook.nameExists(contact, contactsList); err != nil {
-		return 0, err
-	}
-
-	encryptedData, err := addrsBook.encryptContact(contact.(*Contact))
-	if err != nil {
-		return 0, err
-	}
-
-	// Commit transaction before exit.
-	return addrsBook.GetStorage().InsertValue(encryptedData)
+		encryptedData, err := addrsBook.encryptContact(contact.(*Contact))
+		if err != nil {
+			return err
+		}
+		if id, err = addrsBook.GetStorage().InsertValue(encryptedData); err != nil {
+			return err
+		}
+		stored := copyContact(contact)
+		stored.SetID(id)
+		idx.put(stored)
+		return nil
+	})
+	return id, err
 }
 
 // GetContact get a contact by ID.
@@ -271,7 +262,13 @@ func (addrsBook *addrsBook) ListContact() ([]core.Contact, error) {
 // DeleteContact delete a contact from the address book by its ID.
 func (addrsBook *addrsBook) DeleteContact(id uint64) error {
 	logDb.Info("Removing a contact from AddressBook")
-	return addrsBook.GetStorage().DeleteValue(id)
+	if err := addrsBook.GetStorage().DeleteValue(id); err != nil {
+		return err
+	}
+	addrsBook.updateIndex(func(idx *contactIndex) {
+		idx.remove(id)
+	})
+	return nil
 }
 
 // UpdateContact update a contact in the address book by its ID.
@@ -281,39 +278,26 @@ func (addrsBook *addrsBook) UpdateContact(id uint64, newContact core.Contact) er
 		return errInvalidContact
 	}
 
-	var contactsList []core.Contact
-	var err error
-	if contactsList, err = addrsBook.ListContact(); err != nil {
-		return err
-	}
-	var pos int
-	for e := range contactsList {
-		if contactsList[e].GetID() == id {
-			contactsList[e] = nil
-			pos = e
-			break
-		}
-	}
-
-	for _, ncAddrs := range newContact.GetAddresses() {
-		if err := addrsBook.addressExists(ncAddrs, append(contactsList[:pos], contactsList[pos+1:]...)); err != nil {
-			return err
-		}
-	}
-
-	if err := addrsBook.nameExists(newContact, contactsList); err != nil {
-		return err
-	}
-
 	if _, ok := newContact.(*Contact); !ok {
 		return errParseContact
 	}
 
-	encryptedData, err := addrsBook.encryptContact(newContact.(*Contact))
-	if err != nil {
-		return err
-	}
-	return addrsBook.GetStorage().UpdateValue(id, encryptedData)
+	return addrsBook.queryIndex(func(idx *contactIndex) error {
+		if err := idx.conflict(newContact, id); err != nil {
+			return err
+		}
+		encryptedData, err := addrsBook.encryptContact(newContact.(*Contact))
+		if err != nil {
+			return err
+		}
+		if err := addrsBook.GetStorage().UpdateValue(id, encryptedData); err != nil {
+			return err
+		}
+		stored := copyContact(newContact)
+		stored.SetID(id)
+		idx.put(stored)
+		return nil
+	})
 }
 
 // GetPath return database path
@@ -358,96 +342,49 @@ func (addrsBook *addrsBook) GetSecType() (int, error) {
 	return strconv.Atoi(addrsBook.GetStorage().GetConfig()[SecurityType])
 }
 
-// genEntropy generate annil Entropy by a mnemonic. If mnemonic is nil,
-// it generate a random.
-func (addrsBook *addrsBook) genEntropy() ([]byte, error) {
-	mn, err := bip39.NewDefaultMnemonic()
-	if err != nil {
-		return nil, err
-	}
-	e, err := bip39.EntropyFromMnemonic(mn)
-	if err != nil {
-		return nil, err
-	}
-	return e, nil
-}
-
-// getEntropyFromConfig retrieve the Entropy parameter from the database.
-func (addrsBook *addrsBook) getEntropyFromConfig() []byte {
-	logDb.Info("Getting entropy.")
-	return []byte(addrsBook.GetStorage().GetConfig()[Entropy])
-
-}
-
-// getHashFromConfig get hash from config bucket in the database..
-func (addrsBook *addrsBook) getHashFromConfig() []byte {
-	logDb.Info("Getting hash.")
-	return []byte(addrsBook.GetStorage().GetConfig()[Hash])
-}
-
 // encryptContact encrypt a contact by the security Type.
 func (addrsBook *addrsBook) encryptContact(c *Contact) ([]byte, error) {
-	secType, err := addrsBook.GetSecType()
+	data, err := c.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	switch secType {
-	case NoSecurity:
-		return c.MarshalBinary()
-	case ObfuscationSecurity:
-		data, err := c.MarshalBinary()
-		if err != nil {
-			return nil, err
-		}
-		return []byte(base64.StdEncoding.EncodeToString(data)), nil
-	case PasswordSecurity:
-		return addrsBook.encryptAESGCM(c)
-	}
-
-	return nil, fmt.Errorf("invalid security type")
+	return addrsBook.encryptData(data)
 }
 
 // Decrypt a cipher message by the Security Type and return a Contact.
 func (addrsBook *addrsBook) decryptContact(cipherMsg []byte) (core.Contact, error) {
-	secType, err := addrsBook.GetSecType()
+	data, err := addrsBook.decryptData(cipherMsg)
 	if err != nil {
 		return nil, err
 	}
-	switch secType {
-	case NoSecurity:
-		contact := Contact{}
-		if err := contact.UnmarshalBinary(cipherMsg); err != nil {
-			return nil, err
-		}
-
-		return &contact, nil
-
-	case ObfuscationSecurity:
-		contact := Contact{}
-		data, err := base64.StdEncoding.DecodeString(string(cipherMsg))
-		if err != nil {
-			return nil, err
-		}
-		if err := contact.UnmarshalBinary(data); err != nil {
-			return nil, err
-		}
-		return &contact, nil
-	case PasswordSecurity:
-		return addrsBook.decryptAESGCM(cipherMsg)
+	contact := Contact{}
+	if err := contact.UnmarshalBinary(data); err != nil {
+		return nil, err
 	}
+	return &contact, nil
+}
 
-	return nil, errInvalidSecType
+// encryptData encrypt raw data by the security Type.
+func (addrsBook *addrsBook) encryptData(data []byte) ([]byte, error) {
+	c, err := addrsBook.cipher()
+	if err != nil {
+		return nil, err
+	}
+	return c.encrypt(data)
 }
 
-// verifyHash verify if a password match with the current AddressBook hash.
-func (addrsBook *addrsBook) verifyHash() error {
-	hash := addrsBook.getHashFromConfig()
-	return bcrypt.CompareHashAndPassword(hash, addrsBook.key)
+// decryptData decrypt a cipher message by the security Type and return raw data.
+func (addrsBook *addrsBook) decryptData(cipherMsg []byte) ([]byte, error) {
+	c, err := addrsBook.cipher()
+	if err != nil {
+		return nil, err
+	}
+	return c.decrypt(cipherMsg)
 }
 
 // addressExists search an address in the list of contacts into the AddressBook.
 // If find the address return error, else return nil.
-func (addrsBook *addrsBook) addressExists(address core.StringAddress, contacts []core.Contact) error {
+func addressExists(address core.StringAddress, contacts []core.Contact) error {
 	for _, contact := range contacts {
 		for _, addrs := range contact.GetAddresses() {
 			if bytes.Compare(addrs.GetValue(), address.GetValue()) == 0 &&
@@ -462,7 +399,7 @@ func (addrsBook *addrsBook) addressExists(address core.StringAddress, contacts [
 
 // nameExists search an name in the list of contacts into the AddressBook.
 // If find the address return error, else return nil.
-func (addrsBook *addrsBook) nameExists(newContact core.Contact, contactsList []core.Contact) error {
+func nameExists(newContact core.Contact, contactsList []core.Contact) error {
 	for _, contact := range contactsList {
 		if dataContact, ok := contact.(*Contact); ok {
 			if bytes.Compare(newContact.(*Contact).Name, dataContact.Name) == 0 {
@@ -473,66 +410,3 @@ func (addrsBook *addrsBook) nameExists(newContact core.Contact, contactsList []c
 
 	return nil
 }
-
-// insertConfig insert the configuration parameter into Config block in the database.
-func (addrsBook *addrsBook) insertConfig(secType int, hash, entropy []byte) error {
-	if err := addrsBook.GetStorage().InsertConfig(
-		map[string]string{
-			SecurityType: strconv.Itoa(secType),
-			Hash:         string(hash),
-			Entropy:      string(entropy)}); err != nil {
-		logDb.Error(err)
-		return err
-	}
-	return nil
-}
-
-// decryptAESGCM encrypt a data with AESGCM algorithm. http://golang.org/crypto/
-func (addrsBook *addrsBook) encryptAESGCM(contact *Contact) ([]byte, error) {
-	block, err := aes.NewCipher(
-		pbkdf2.Key(addrsBook.getEntropyFromConfig(), addrsBook.key, 4096, 32, sha512.New))
-	if err != nil {
-		return nil, err
-	}
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, err
-	}
-
-	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, err
-	}
-	bc, err := contact.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-
-	cipherText := aesGCM.Seal(nonce, nonce, bc, nil)
-	return cipherText, nil
-}
-
-// decryptAESGCM decrypt a data with AESGCM algorithm. http://golang.org/crypto/
-func (addrsBook *addrsBook) decryptAESGCM(cipherMsg []byte) (core.Contact, error) {
-	block, err := aes.NewCipher(pbkdf2.Key(
-		addrsBook.getEntropyFromConfig(), addrsBook.key, 4096, 32, sha512.New))
-	if err != nil {
-		return nil, err
-	}
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, err
-	}
-	var contact Contact
-	nonceSize := aesGCM.NonceSize()
-	nonce, cipherText := cipherMsg[:nonceSize], cipherMsg[nonceSize:]
-
-	data, err := aesGCM.Open(nil, nonce, cipherText, nil)
-	if err != nil {
-		return nil, err
-	}
-	if err := contact.UnmarshalBinary(data); err != nil {
-		return nil, err
-	}
-	return &contact, nil
-}
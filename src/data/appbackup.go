@@ -0,0 +1,636 @@
+package data
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+const (
+	// BackupFileWallet wallet file of a wallet directory
+	BackupFileWallet = "wallet"
+	// BackupFileArchivedWallet wallet file of the archive of a wallet directory
+	BackupFileArchivedWallet = "archived_wallet"
+	// BackupFileDatabase snapshot of a database , holding contacts , labels and other collections
+	BackupFileDatabase = "database"
+	// BackupFileSettings application settings
+	BackupFileSettings = "settings"
+
+	// appBackupVersion version of the archives written by WriteBackup
+	appBackupVersion = 1
+	// backupManifestName archive entry holding the manifest
+	backupManifestName = "manifest.json"
+	// backupWalletExt extension of the wallet files backed up
+	backupWalletExt = ".wlt"
+)
+
+// RestoreMode action taken when a backed up wallet is already present.
+type RestoreMode int
+
+const (
+	// RestoreMerge keep present wallets , database records and settings , restoring only what is missing
+	RestoreMerge RestoreMode = iota
+	// RestoreReplace overwrite present wallets , databases and settings with the backed up ones
+	RestoreReplace
+)
+
+var (
+	// Errors
+	errInvalidAppBackup   = errors.New("invalid backup")
+	errBackupCorrupted    = errors.New("backup corrupted")
+	errBackupUnsupported  = errors.New("database does not support backups")
+	errNoWalletsToRestore = errors.New("no wallet directory to restore wallets into")
+)
+
+// BackupSources application state written by WriteBackup.
+type BackupSources struct {
+	// WalletDirs local wallet directories , restored in the same order
+	WalletDirs []string
+	// ArchiveDirs archive directories of WalletDirs , by index. Empty entries are skipped
+	ArchiveDirs []string
+	// Databases opened databases by file name
+	Databases map[string]core.Storage
+	// Settings application configuration
+	Settings map[string]string
+}
+
+// BackupManifest describes the files of a backup.
+type BackupManifest struct {
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	Files     []BackupFile `json:"files"`
+}
+
+// BackupFile file held by a backup.
+type BackupFile struct {
+	// Path of the entry within the archive
+	Path string `json:"path"`
+	// Kind of file , see BackupFileWallet , BackupFileArchivedWallet , BackupFileDatabase and BackupFileSettings
+	Kind string `json:"kind"`
+	// Name of the file once restored
+	Name string `json:"name"`
+	// Source index of the wallet directory holding wallet files
+	Source int    `json:"source,omitempty"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// FirstAddress first address of wallet files , used to detect wallets already present
+	FirstAddress string `json:"first_address,omitempty"`
+}
+
+// RestoreTargets where a backup is restored.
+type RestoreTargets struct {
+	// Wallets wallet directories , backed up directories are restored in the same order.
+	// Wallets of extra backed up directories are restored into the first one
+	Wallets []RestoreWalletDir
+	// Databases opened databases by name , restored in place
+	Databases map[string]core.Storage
+	// Settings current application configuration
+	Settings map[string]string
+}
+
+// RestoreWalletDir wallet directory wallets are restored into.
+type RestoreWalletDir struct {
+	Dir string
+	// ArchiveDir holding archived wallets of Dir , archived wallets are skipped if empty
+	ArchiveDir string
+	// Env looks up wallets present in Dir
+	Env core.WalletEnv
+}
+
+// WalletConflict backed up wallet already present.
+type WalletConflict struct {
+	// File backed up wallet file
+	File BackupFile
+	// Wallet present wallet with the same first address
+	Wallet core.Wallet
+	// Dir holding the present wallet
+	Dir string
+}
+
+// RestoreReport outcome of a restore.
+type RestoreReport struct {
+	// Restored files written , as paths
+	Restored []string
+	// Replaced present files overwritten , as paths
+	Replaced []string
+	// Kept present files left untouched , as paths
+	Kept []string
+	// Settings application configuration to apply
+	Settings map[string]string
+}
+
+// Backup verified contents of a backup archive.
+type Backup struct {
+	manifest BackupManifest
+	contents map[string][]byte
+}
+
+// walletFile minimal view of a wallet file , listing its addresses.
+type walletFile struct {
+	Entries []struct {
+		Address string `json:"address"`
+	} `json:"entries"`
+}
+
+// WriteBackup writes an archive holding sources encrypted with passphrase , returning its manifest.
+func WriteBackup(w io.Writer, sources BackupSources, passphrase string) (*BackupManifest, error) {
+	manifest := BackupManifest{Version: appBackupVersion, CreatedAt: time.Now().UTC(), Files: make([]BackupFile, 0)}
+	contents := make(map[string][]byte)
+	add := func(file BackupFile, content []byte) {
+		sum := sha256.Sum256(content)
+		file.Size = int64(len(content))
+		file.SHA256 = hex.EncodeToString(sum[:])
+		manifest.Files = append(manifest.Files, file)
+		contents[file.Path] = content
+	}
+
+	addWallets := func(dir, kind, root string, source int) error {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			logDb.WithError(err).WithField("dirname", dir).Warn("Couldn't read wallet directory")
+			return err
+		}
+		for _, e := range entries {
+			if !e.Mode().IsRegular() || !strings.HasSuffix(e.Name(), backupWalletExt) {
+				continue
+			}
+			content, err := ioutil.ReadFile(filepath.Join(dir, e.Name()))
+			if err != nil {
+				return err
+			}
+			add(BackupFile{
+				Path:         path.Join(root, strconv.Itoa(source), e.Name()),
+				Kind:         kind,
+				Name:         e.Name(),
+				Source:       source,
+				FirstAddress: walletFirstAddress(content),
+			}, content)
+		}
+		return nil
+	}
+	for i, dir := range sources.WalletDirs {
+		if err := addWallets(dir, BackupFileWallet, "wallets", i); err != nil {
+			return nil, err
+		}
+	}
+	for i, dir := range sources.ArchiveDirs {
+		if dir == "" {
+			continue
+		}
+		// Wallets are archived on demand , the archive may not exist yet
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		if err := addWallets(dir, BackupFileArchivedWallet, "archives", i); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(sources.Databases))
+	for name := range sources.Databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		db, isBolt := sources.Databases[name].(*boltStorage)
+		if !isBolt {
+			return nil, errBackupUnsupported
+		}
+		var snapshot bytes.Buffer
+		if err := db.View(func(tx *bolt.Tx) error {
+			_, err := tx.WriteTo(&snapshot)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		add(BackupFile{Path: path.Join("databases", name), Kind: BackupFileDatabase, Name: name}, snapshot.Bytes())
+	}
+
+	if sources.Settings != nil {
+		content, err := json.Marshal(sources.Settings)
+		if err != nil {
+			return nil, err
+		}
+		add(BackupFile{Path: "settings.json", Kind: BackupFileSettings, Name: "settings.json"}, content)
+	}
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	writeEntry := func(name string, content []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), ModTime: manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(content)
+		return err
+	}
+	if err := writeEntry(backupManifestName, manifestData); err != nil {
+		return nil, err
+	}
+	for _, file := range manifest.Files {
+		if err := writeEntry(file.Path, contents[file.Path]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	kdf, err := newKDFParams()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := newEnvelopeCipher([]byte(passphrase), kdf, nil).encrypt(archive.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// ReadBackup decrypts an archive written by WriteBackup , verifying every file against its manifest.
+func ReadBackup(r io.Reader, passphrase string) (*Backup, error) {
+	msg, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	msg = bytes.TrimSpace(msg)
+	env, err := parseEnvelope(msg)
+	if err != nil {
+		return nil, errInvalidAppBackup
+	}
+	archive, err := newEnvelopeCipher([]byte(passphrase), env.KDF, nil).decrypt(msg)
+	if err != nil {
+		return nil, errWrongPassword
+	}
+
+	b := &Backup{contents: make(map[string][]byte)}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errBackupCorrupted
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errBackupCorrupted
+		}
+		b.contents[hdr.Name] = content
+	}
+	manifestData, hasManifest := b.contents[backupManifestName]
+	if !hasManifest || json.Unmarshal(manifestData, &b.manifest) != nil {
+		return nil, errInvalidAppBackup
+	}
+	delete(b.contents, backupManifestName)
+	if b.manifest.Version != appBackupVersion {
+		return nil, fmt.Errorf("%s: unsupported version %d", errInvalidAppBackup, b.manifest.Version)
+	}
+	if len(b.manifest.Files) != len(b.contents) {
+		return nil, errBackupCorrupted
+	}
+	for _, file := range b.manifest.Files {
+		content, exists := b.contents[file.Path]
+		sum := sha256.Sum256(content)
+		if !exists || int64(len(content)) != file.Size || hex.EncodeToString(sum[:]) != file.SHA256 {
+			return nil, fmt.Errorf("%s: %s", errBackupCorrupted, file.Path)
+		}
+		// Restored files must stay within their target directory
+		if file.Name == "" || file.Name != filepath.Base(file.Name) || file.Name == ".." {
+			return nil, fmt.Errorf("%s: %s", errBackupCorrupted, file.Path)
+		}
+	}
+	return b, nil
+}
+
+// Manifest describes the files of the backup.
+func (b *Backup) Manifest() BackupManifest {
+	return b.manifest
+}
+
+// Conflicts lists backed up wallets whose first address matches a wallet present in targets.
+func (b *Backup) Conflicts(targets RestoreTargets) []WalletConflict {
+	conflicts := make([]WalletConflict, 0)
+	for _, file := range b.manifest.Files {
+		if file.Kind != BackupFileWallet || file.FirstAddress == "" {
+			continue
+		}
+		for _, target := range targets.Wallets {
+			if target.Env == nil {
+				continue
+			}
+			if wlt, err := target.Env.LookupWallet(file.FirstAddress); err == nil {
+				conflicts = append(conflicts, WalletConflict{File: file, Wallet: wlt, Dir: target.Dir})
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// Restore writes the backed up files into targets. Wallets present with the same first address
+// are kept or replaced following mode , other wallets are added under an unused file name.
+// Databases are either replaced or merged record by record , keeping present records.
+func (b *Backup) Restore(targets RestoreTargets, mode RestoreMode) (*RestoreReport, error) {
+	report := &RestoreReport{
+		Restored: make([]string, 0),
+		Replaced: make([]string, 0),
+		Kept:     make([]string, 0),
+		Settings: make(map[string]string),
+	}
+	conflicts := make(map[string]WalletConflict)
+	for _, c := range b.Conflicts(targets) {
+		conflicts[c.File.Path] = c
+	}
+
+	for _, file := range b.manifest.Files {
+		content := b.contents[file.Path]
+		switch file.Kind {
+		case BackupFileWallet:
+			if c, isPresent := conflicts[file.Path]; isPresent {
+				dst := filepath.Join(c.Dir, c.Wallet.GetId())
+				if mode == RestoreMerge {
+					report.Kept = append(report.Kept, dst)
+					continue
+				}
+				if err := writeFileAtomic(dst, content); err != nil {
+					return report, err
+				}
+				report.Replaced = append(report.Replaced, dst)
+				continue
+			}
+			if len(targets.Wallets) == 0 {
+				return report, errNoWalletsToRestore
+			}
+			dir := targets.Wallets[0].Dir
+			if file.Source < len(targets.Wallets) {
+				dir = targets.Wallets[file.Source].Dir
+			}
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				return report, err
+			}
+			dst := unusedFileName(dir, file.Name)
+			if err := writeFileAtomic(dst, content); err != nil {
+				return report, err
+			}
+			report.Restored = append(report.Restored, dst)
+		case BackupFileArchivedWallet:
+			if len(targets.Wallets) == 0 {
+				return report, errNoWalletsToRestore
+			}
+			dir := targets.Wallets[0].ArchiveDir
+			if file.Source < len(targets.Wallets) {
+				dir = targets.Wallets[file.Source].ArchiveDir
+			}
+			if dir == "" {
+				continue
+			}
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				return report, err
+			}
+			// Archived file names are unique , being prefixed with the archiving time
+			dst := filepath.Join(dir, file.Name)
+			if _, err := os.Stat(dst); err == nil {
+				if mode == RestoreMerge {
+					report.Kept = append(report.Kept, dst)
+					continue
+				}
+				report.Replaced = append(report.Replaced, dst)
+			} else {
+				report.Restored = append(report.Restored, dst)
+			}
+			if err := writeFileAtomic(dst, content); err != nil {
+				return report, err
+			}
+		case BackupFileDatabase:
+			db, isTarget := targets.Databases[file.Name]
+			if !isTarget {
+				continue
+			}
+			dst := db.Path()
+			changed, err := restoreDatabase(db, content, mode)
+			if err != nil {
+				return report, err
+			}
+			switch {
+			case mode == RestoreReplace:
+				report.Replaced = append(report.Replaced, dst)
+			case changed:
+				report.Restored = append(report.Restored, dst)
+			default:
+				report.Kept = append(report.Kept, dst)
+			}
+		case BackupFileSettings:
+			var settings map[string]string
+			if err := json.Unmarshal(content, &settings); err != nil {
+				return report, errBackupCorrupted
+			}
+			for k, v := range targets.Settings {
+				report.Settings[k] = v
+			}
+			for k, v := range settings {
+				if _, isSet := targets.Settings[k]; isSet && mode == RestoreMerge {
+					continue
+				}
+				report.Settings[k] = v
+			}
+		}
+	}
+	return report, nil
+}
+
+// restoreDatabase writes the records of a database snapshot into storage , reporting whether any was written.
+// With RestoreMerge present records are kept. Contacts and labels are only merged if both
+// address books share security settings , since they are encrypted with their keys.
+func restoreDatabase(storage core.Storage, snapshot []byte, mode RestoreMode) (bool, error) {
+	db, isBolt := storage.(*boltStorage)
+	if !isBolt {
+		return false, errBackupUnsupported
+	}
+	tmp := db.Path() + ".restore"
+	if err := ioutil.WriteFile(tmp, snapshot, 0600); err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp)
+	src, err := bolt.Open(tmp, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		return false, errBackupCorrupted
+	}
+	defer src.Close()
+
+	current, err := db.SchemaVersion()
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	err = src.View(func(srcTx *bolt.Tx) error {
+		var version uint64
+		if bkt := srcTx.Bucket([]byte(dbMetaBkt)); bkt != nil {
+			if val := bkt.Get([]byte(metaSchemaVersion)); val != nil {
+				if err := json.Unmarshal(val, &version); err != nil {
+					return errBackupCorrupted
+				}
+			}
+		}
+		if version > current {
+			return errSchemaTooNew
+		}
+		return db.Update(func(tx *bolt.Tx) error {
+			if mode == RestoreReplace {
+				changed = true
+				return replaceBuckets(tx, srcTx)
+			}
+			var err error
+			changed, err = mergeBuckets(tx, srcTx)
+			return err
+		})
+	})
+	if err != nil || mode != RestoreReplace {
+		return changed, err
+	}
+	// Replaced databases hold the schema version of the backup
+	return changed, db.migrate(registeredMigrations())
+}
+
+// replaceBuckets overwrite every bucket of tx with those of src.
+func replaceBuckets(tx, src *bolt.Tx) error {
+	names := make([][]byte, 0)
+	if err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+		names = append(names, append([]byte(nil), name...))
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+	}
+	return src.ForEach(func(name []byte, srcBkt *bolt.Bucket) error {
+		_, err := copyRecords(tx, name, srcBkt)
+		return err
+	})
+}
+
+// mergeBuckets add records of src missing in tx , skipping the schema version.
+func mergeBuckets(tx, src *bolt.Tx) (bool, error) {
+	sameSecurity := tx.Bucket([]byte(dbConfigBkt)) == nil || sameRecords(tx.Bucket([]byte(dbConfigBkt)), src.Bucket([]byte(dbConfigBkt)))
+	changed := false
+	err := src.ForEach(func(name []byte, srcBkt *bolt.Bucket) error {
+		switch string(name) {
+		case dbMetaBkt:
+			return nil
+		case dbConfigBkt, dbAddrsBookBkt, dbLabelsBkt:
+			if !sameSecurity {
+				logDb.WithField("bucket", string(name)).Warn("Backed up address book security differs , records not merged")
+				return nil
+			}
+		}
+		added, err := copyRecords(tx, name, srcBkt)
+		changed = changed || added
+		return err
+	})
+	return changed, err
+}
+
+// copyRecords put records of src missing in bucket name of tx , reporting whether any was written.
+// The bucket sequence is raised to that of src so that restored IDs are not reused.
+func copyRecords(tx *bolt.Tx, name []byte, src *bolt.Bucket) (bool, error) {
+	dst, err := tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	err = src.ForEach(func(k, v []byte) error {
+		// Collections hold no nested buckets
+		if v == nil || dst.Get(k) != nil {
+			return nil
+		}
+		changed = true
+		return dst.Put(append([]byte(nil), k...), append([]byte(nil), v...))
+	})
+	if err != nil {
+		return false, err
+	}
+	if src.Sequence() > dst.Sequence() {
+		return changed, dst.SetSequence(src.Sequence())
+	}
+	return changed, nil
+}
+
+// sameRecords determines whether two buckets hold the same records.
+func sameRecords(a, b *bolt.Bucket) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	count := 0
+	same := true
+	_ = a.ForEach(func(k, v []byte) error {
+		count++
+		same = same && bytes.Equal(b.Get(k), v)
+		return nil
+	})
+	_ = b.ForEach(func(k, v []byte) error {
+		count--
+		return nil
+	})
+	return same && count == 0
+}
+
+// walletFirstAddress return the first address listed by a wallet file , empty if unknown.
+func walletFirstAddress(content []byte) string {
+	var wlt walletFile
+	if err := json.Unmarshal(content, &wlt); err != nil || len(wlt.Entries) == 0 {
+		return ""
+	}
+	return wlt.Entries[0].Address
+}
+
+// unusedFileName return the path of name within dir , numbered if another file has that name.
+func unusedFileName(dir, name string) string {
+	dst := filepath.Join(dir, name)
+	ext := filepath.Ext(name)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dst); os.IsNotExist(err) {
+			return dst
+		}
+		dst = filepath.Join(dir, fmt.Sprintf("%s_%d%s", strings.TrimSuffix(name, ext), i, ext))
+	}
+}
+
+// writeFileAtomic replace the contents of a file by writing a temporary file first.
+func writeFileAtomic(dst string, content []byte) error {
+	tmp := dst + ".restore"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestWallet(t *testing.T, dir, name, firstAddr string) {
+	content := []byte(`{"meta":{"label":"` + name + `"},"entries":[{"address":"` + firstAddr + `"}]}`)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), content, 0600))
+}
+
+func TestAppBackup(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir)
+	writeTestWallet(t, srcDir, "first.wlt", walletAddr1)
+	writeTestWallet(t, srcDir, "second.wlt", walletAddr2)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "notes.txt"), []byte("skipped"), 0600))
+	srcArchive := filepath.Join(srcDir, "archive")
+	require.NoError(t, os.Mkdir(srcArchive, 0700))
+	writeTestWallet(t, srcArchive, "20200102T150405Z_old.wlt", foreignAddr)
+
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+	require.NoError(t, db.UpdateCollections(func(tx core.StorageTxn) error {
+		if err := tx.Put("records", []byte("k"), testRecord{Name: "k", Value: 1}); err != nil {
+			return err
+		}
+		return tx.Put("records", []byte("extra"), testRecord{Name: "extra", Value: 3})
+	}))
+
+	var archive bytes.Buffer
+	manifest, err := WriteBackup(&archive, BackupSources{
+		WalletDirs:  []string{srcDir},
+		ArchiveDirs: []string{srcArchive},
+		Databases:   map[string]core.Storage{"data.dt": db},
+		Settings:    map[string]string{"skywallet/node": "remote", "ui/theme": "dark"},
+	}, "secret")
+	require.NoError(t, err)
+	require.Len(t, manifest.Files, 5)
+	require.NotContains(t, archive.String(), walletAddr1)
+
+	_, err = ReadBackup(bytes.NewReader(archive.Bytes()), "wrong")
+	require.Equal(t, errWrongPassword, err)
+	_, err = ReadBackup(bytes.NewReader([]byte("garbage")), "secret")
+	require.Equal(t, errInvalidAppBackup, err)
+
+	backup, err := ReadBackup(bytes.NewReader(archive.Bytes()), "secret")
+	require.NoError(t, err)
+	require.Equal(t, manifest.Files, backup.Manifest().Files)
+
+	// first.wlt is present as existing.wlt in the target directory
+	dstDir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dstDir)
+	writeTestWallet(t, dstDir, "existing.wlt", walletAddr1)
+	writeTestWallet(t, dstDir, "second.wlt", foreignAddr)
+	wlt := new(mocks.Wallet)
+	wlt.On("GetId").Return("existing.wlt")
+	env := new(mocks.WalletEnv)
+	env.On("LookupWallet", walletAddr1).Return(wlt, nil)
+	env.On("LookupWallet", walletAddr2).Return(nil, errors.New("not found"))
+	dstDB := openTxnIndex(t)
+	defer closeTxnIndex(t, dstDB)
+	require.NoError(t, dstDB.UpdateCollections(func(tx core.StorageTxn) error {
+		if err := tx.Put("records", []byte("k"), testRecord{Name: "k", Value: 2}); err != nil {
+			return err
+		}
+		return tx.Put("records", []byte("own"), testRecord{Name: "own", Value: 4})
+	}))
+	dbPath := dstDB.Path()
+	dstArchive := filepath.Join(dstDir, "archive")
+	targets := RestoreTargets{
+		Wallets:   []RestoreWalletDir{{Dir: dstDir, ArchiveDir: dstArchive, Env: env}},
+		Databases: map[string]core.Storage{"data.dt": dstDB},
+		Settings:  map[string]string{"ui/theme": "light"},
+	}
+
+	conflicts := backup.Conflicts(targets)
+	require.Len(t, conflicts, 1)
+	require.Equal(t, "first.wlt", conflicts[0].File.Name)
+	require.Equal(t, wlt, conflicts[0].Wallet)
+
+	report, err := backup.Restore(targets, RestoreMerge)
+	require.NoError(t, err)
+	archivedPath := filepath.Join(dstArchive, "20200102T150405Z_old.wlt")
+	require.Equal(t, []string{filepath.Join(dstDir, "second_1.wlt"), archivedPath, dbPath}, report.Restored)
+	require.Equal(t, []string{filepath.Join(dstDir, "existing.wlt")}, report.Kept)
+	require.Empty(t, report.Replaced)
+	require.Equal(t, map[string]string{"skywallet/node": "remote", "ui/theme": "light"}, report.Settings)
+	require.Equal(t, walletFirstAddress(readTestFile(t, filepath.Join(dstDir, "second.wlt"))), foreignAddr)
+	require.Equal(t, foreignAddr, walletFirstAddress(readTestFile(t, archivedPath)))
+
+	// Present records are kept , missing ones are added
+	records := func() map[string]int {
+		values := make(map[string]int)
+		require.NoError(t, dstDB.ViewCollections(func(tx core.StorageTxn) error {
+			return tx.Scan("records", nil, nil, func(item core.StorageItem) (bool, error) {
+				var r testRecord
+				err := item.Decode(&r)
+				values[r.Name] = r.Value
+				return true, err
+			})
+		}))
+		return values
+	}
+	require.Equal(t, map[string]int{"k": 2, "extra": 3, "own": 4}, records())
+
+	report, err = backup.Restore(targets, RestoreMerge)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dstDir, "existing.wlt"), archivedPath, dbPath}, report.Kept)
+
+	report, err = backup.Restore(targets, RestoreReplace)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dstDir, "existing.wlt"), archivedPath, dbPath}, report.Replaced)
+	require.Equal(t, "dark", report.Settings["ui/theme"])
+	require.Equal(t, walletAddr1, walletFirstAddress(readTestFile(t, filepath.Join(dstDir, "existing.wlt"))))
+	require.Equal(t, map[string]int{"k": 1, "extra": 3}, records())
+}
+
+func TestAppBackup_Databases(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+	ab := NewAddressBook(db)
+	require.NoError(t, ab.Init(NoSecurity, ""))
+	_, err := ab.InsertContact(&Contact{
+		Address: []Address{{Value: []byte(walletAddr2), Coin: []byte("SKY")}},
+		Name:    []byte("alice"),
+	})
+	require.NoError(t, err)
+	store, err := NewLabelStore(ab)
+	require.NoError(t, err)
+	require.NoError(t, store.SetLabel(core.Label{Type: core.LabelTypeAddress, Ref: walletAddr1, Label: "savings"}))
+	require.NoError(t, db.IndexTxn(testWalletID, makeTestTxn("txn1", 10, 100, foreignAddr, walletAddr1), 10, core.TxnDirectionReceived, []string{walletAddr1}))
+	require.NoError(t, db.PutOutboxTxn(core.OutboxTxn{ID: "txn2", WalletID: testWalletID}))
+	require.NoError(t, db.PutPaymentWatch(core.PaymentWatch{ID: "watch1", WalletID: testWalletID, Address: walletAddr1}))
+	require.NoError(t, db.FreezeOutput(testWalletID, "out1"))
+
+	var archive bytes.Buffer
+	_, err = WriteBackup(&archive, BackupSources{Databases: map[string]core.Storage{"data.dt": db}}, "secret")
+	require.NoError(t, err)
+	backup, err := ReadBackup(bytes.NewReader(archive.Bytes()), "secret")
+	require.NoError(t, err)
+
+	requireRestored := func(t *testing.T, dst *boltStorage) {
+		found, err := dst.HasTxn(testWalletID, "txn1")
+		require.NoError(t, err)
+		require.True(t, found)
+		outboxTxn, err := dst.GetOutboxTxn("txn2")
+		require.NoError(t, err)
+		require.NotNil(t, outboxTxn)
+		watch, err := dst.GetPaymentWatch("watch1")
+		require.NoError(t, err)
+		require.NotNil(t, watch)
+		state, err := dst.GetOutputState(testWalletID, "out1")
+		require.NoError(t, err)
+		require.Equal(t, core.OutputFrozen, state)
+	}
+	listNames := func(t *testing.T, book core.AddressBook) []string {
+		contacts, err := book.ListContact()
+		require.NoError(t, err)
+		names := make([]string, 0)
+		for _, c := range contacts {
+			names = append(names, string(c.GetName()))
+		}
+		return names
+	}
+
+	t.Run("into-empty-database", func(t *testing.T) {
+		dst := openTxnIndex(t)
+		defer closeTxnIndex(t, dst)
+		_, err := backup.Restore(RestoreTargets{Databases: map[string]core.Storage{"data.dt": dst}}, RestoreMerge)
+		require.NoError(t, err)
+		requireRestored(t, dst)
+		book := NewAddressBook(dst)
+		require.True(t, book.HasInit())
+		require.Equal(t, []string{"alice"}, listNames(t, book))
+		dstStore, err := NewLabelStore(book)
+		require.NoError(t, err)
+		label, err := dstStore.GetLabel(core.LabelTypeAddress, walletAddr1)
+		require.NoError(t, err)
+		require.Equal(t, "savings", label.Label)
+		// Restored contact IDs are not reused
+		id, err := book.InsertContact(&Contact{
+			Address: []Address{{Value: []byte("2DpeofcsamDfanrRz34qjYvskRzKqzNKMcj"), Coin: []byte("SKY")}},
+			Name:    []byte("bob"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), id)
+	})
+
+	t.Run("into-other-address-book", func(t *testing.T) {
+		dst := openTxnIndex(t)
+		defer closeTxnIndex(t, dst)
+		book := NewAddressBook(dst)
+		require.NoError(t, book.Init(PasswordSecurity, defaultPass))
+		_, err := book.InsertContact(&Contact{
+			Address: []Address{{Value: []byte("2DpeofcsamDfanrRz34qjYvskRzKqzNKMcj"), Coin: []byte("SKY")}},
+			Name:    []byte("bob"),
+		})
+		require.NoError(t, err)
+		_, err = backup.Restore(RestoreTargets{Databases: map[string]core.Storage{"data.dt": dst}}, RestoreMerge)
+		require.NoError(t, err)
+		requireRestored(t, dst)
+		// Contacts encrypted with another key are left out
+		require.Equal(t, []string{"bob"}, listNames(t, book))
+	})
+}
+
+func readTestFile(t *testing.T, path string) []byte {
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return content
+}
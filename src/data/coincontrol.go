@@ -0,0 +1,133 @@
+package data
+
+import (
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+const (
+	// Db buckets.
+	dbCoinControlBkt = "CoinControl"
+)
+
+// outputControl coin control settings recorded for an output.
+// Reservations are kept until ReservedUntil , expressed in Unix nanoseconds.
+type outputControl struct {
+	Frozen        bool  `json:"frozen,omitempty"`
+	ReservedUntil int64 `json:"reserved_until,omitempty"`
+}
+
+func (oc *outputControl) state(now time.Time) core.OutputState {
+	if oc.Frozen {
+		return core.OutputFrozen
+	}
+	if oc.ReservedUntil > now.UnixNano() {
+		return core.OutputReserved
+	}
+	return core.OutputSpendable
+}
+
+// FreezeOutput excludes a wallet output from coin selection until thawed.
+func (b *boltStorage) FreezeOutput(walletID, outputID string) error {
+	return b.updateOutputControl(walletID, []string{outputID}, func(oc *outputControl) {
+		oc.Frozen = true
+	})
+}
+
+// ThawOutput makes a frozen output available for coin selection.
+// Reservations of the output are left untouched.
+func (b *boltStorage) ThawOutput(walletID, outputID string) error {
+	return b.updateOutputControl(walletID, []string{outputID}, func(oc *outputControl) {
+		oc.Frozen = false
+	})
+}
+
+// ReserveOutputs excludes wallet outputs from coin selection until released or ttl expires.
+func (b *boltStorage) ReserveOutputs(walletID string, outputIDs []string, ttl time.Duration) error {
+	until := time.Now().Add(ttl).UnixNano()
+	return b.updateOutputControl(walletID, outputIDs, func(oc *outputControl) {
+		oc.ReservedUntil = until
+	})
+}
+
+// ReleaseOutputs cancels the reservation of wallet outputs.
+func (b *boltStorage) ReleaseOutputs(walletID string, outputIDs []string) error {
+	return b.updateOutputControl(walletID, outputIDs, func(oc *outputControl) {
+		oc.ReservedUntil = 0
+	})
+}
+
+// GetOutputState returns the coin control status of a wallet output.
+func (b *boltStorage) GetOutputState(walletID, outputID string) (core.OutputState, error) {
+	state := core.OutputSpendable
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		var oc outputControl
+		found, err := tx.Get(dbCoinControlBkt, walletKey(walletID, outputID), &oc)
+		if found {
+			state = oc.state(time.Now())
+		}
+		return err
+	})
+	if err != nil {
+		logDb.Error(err)
+	}
+	return state, err
+}
+
+// ListExcludedOutputs maps wallet outputs excluded from coin selection to their status.
+func (b *boltStorage) ListExcludedOutputs(walletID string) (map[string]core.OutputState, error) {
+	excluded := make(map[string]core.OutputState)
+	now := time.Now()
+	prefix := walletPrefix(walletID)
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		return tx.ScanPrefix(dbCoinControlBkt, prefix, func(item core.StorageItem) (bool, error) {
+			var oc outputControl
+			if err := item.Decode(&oc); err != nil {
+				return false, err
+			}
+			if state := oc.state(now); state != core.OutputSpendable {
+				excluded[string(item.Key()[len(prefix):])] = state
+			}
+			return true, nil
+		})
+	})
+	if err != nil {
+		logDb.Error(err)
+		return nil, err
+	}
+	return excluded, nil
+}
+
+// updateOutputControl applies change to the settings of wallet outputs.
+// Records of outputs neither frozen nor reserved are removed.
+func (b *boltStorage) updateOutputControl(walletID string, outputIDs []string, change func(*outputControl)) error {
+	now := time.Now()
+	err := b.UpdateCollections(func(tx core.StorageTxn) error {
+		for _, outputID := range outputIDs {
+			key := walletKey(walletID, outputID)
+			var oc outputControl
+			if _, err := tx.Get(dbCoinControlBkt, key, &oc); err != nil {
+				return err
+			}
+			change(&oc)
+			if oc.state(now) == core.OutputSpendable {
+				if err := tx.Delete(dbCoinControlBkt, key); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Put(dbCoinControlBkt, key, oc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logDb.Error(err)
+	}
+	return err
+}
+
+// Type assertions
+var _ core.CoinControl = &boltStorage{}
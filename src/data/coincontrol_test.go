@@ -0,0 +1,66 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinControl(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	excluded, err := db.ListExcludedOutputs(testWalletID)
+	require.NoError(t, err)
+	require.Empty(t, excluded)
+
+	require.NoError(t, db.FreezeOutput(testWalletID, "out1"))
+	require.NoError(t, db.ReserveOutputs(testWalletID, []string{"out2", "out3"}, time.Hour))
+	require.NoError(t, db.ReserveOutputs(testWalletID, []string{"out4"}, -time.Second))
+
+	tests := []struct {
+		name     string
+		walletID string
+		outputID string
+		state    core.OutputState
+	}{
+		{name: "frozen", walletID: testWalletID, outputID: "out1", state: core.OutputFrozen},
+		{name: "reserved", walletID: testWalletID, outputID: "out2", state: core.OutputReserved},
+		{name: "expired", walletID: testWalletID, outputID: "out4", state: core.OutputSpendable},
+		{name: "unknown-output", walletID: testWalletID, outputID: "out5", state: core.OutputSpendable},
+		{name: "other-wallet", walletID: "other.wlt", outputID: "out1", state: core.OutputSpendable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := db.GetOutputState(tt.walletID, tt.outputID)
+			require.NoError(t, err)
+			require.Equal(t, tt.state, state)
+		})
+	}
+
+	excluded, err = db.ListExcludedOutputs(testWalletID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]core.OutputState{
+		"out1": core.OutputFrozen,
+		"out2": core.OutputReserved,
+		"out3": core.OutputReserved,
+	}, excluded)
+
+	// Freeze takes precedence over reservations
+	require.NoError(t, db.ReserveOutputs(testWalletID, []string{"out1"}, time.Hour))
+	require.NoError(t, db.ReleaseOutputs(testWalletID, []string{"out1", "out2"}))
+	require.NoError(t, db.ThawOutput(testWalletID, "out3"))
+	excluded, err = db.ListExcludedOutputs(testWalletID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]core.OutputState{
+		"out1": core.OutputFrozen,
+		"out3": core.OutputReserved,
+	}, excluded)
+
+	require.NoError(t, db.ThawOutput(testWalletID, "out1"))
+	state, err := db.GetOutputState(testWalletID, "out1")
+	require.NoError(t, err)
+	require.Equal(t, core.OutputSpendable, state)
+}
@@ -0,0 +1,234 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/boltdb/bolt"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+const (
+	// walletKeySep separates the wallet ID from the record ID in keys of collections shared by wallets
+	walletKeySep = 0
+)
+
+var (
+	// errReadOnlyTxn write attempted within ViewCollections
+	errReadOnlyTxn = errors.New("database: read only transaction")
+)
+
+// walletPrefix common prefix of the keys of wallet records.
+func walletPrefix(walletID string) []byte {
+	return append([]byte(walletID), walletKeySep)
+}
+
+// walletKey key of a wallet record within collections shared by wallets.
+func walletKey(walletID, id string) []byte {
+	return append(walletPrefix(walletID), id...)
+}
+
+// jsonCodec encodes collection values as JSON.
+type jsonCodec struct{}
+
+// Encode serializes value as JSON.
+func (jsonCodec) Encode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode deserializes JSON data into value.
+func (jsonCodec) Decode(data []byte, value interface{}) error {
+	return json.Unmarshal(data, value)
+}
+
+// ViewCollections runs fn within a read only transaction.
+func (b *boltStorage) ViewCollections(fn func(core.StorageTxn) error) error {
+	return b.View(func(tx *bolt.Tx) error {
+		return fn(&boltTxn{tx: tx, db: b})
+	})
+}
+
+// UpdateCollections runs fn within a read-write transaction , committed only if fn succeeds.
+func (b *boltStorage) UpdateCollections(fn func(core.StorageTxn) error) error {
+	return b.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTxn{tx: tx, db: b})
+	})
+}
+
+// SetCollectionCodec chooses the codec of a collection , a nil codec restores JSON.
+func (b *boltStorage) SetCollectionCodec(collection string, codec core.StorageCodec) {
+	b.codecsMutex.Lock()
+	defer b.codecsMutex.Unlock()
+	if codec == nil {
+		delete(b.codecs, collection)
+		return
+	}
+	b.codecs[collection] = codec
+}
+
+func (b *boltStorage) codec(collection string) core.StorageCodec {
+	b.codecsMutex.RLock()
+	defer b.codecsMutex.RUnlock()
+	if codec, isSet := b.codecs[collection]; isSet {
+		return codec
+	}
+	return jsonCodec{}
+}
+
+// boltTxn implements core.StorageTxn mapping collections to top level buckets.
+type boltTxn struct {
+	tx *bolt.Tx
+	db *boltStorage
+}
+
+func (t *boltTxn) encode(collection string, value interface{}) ([]byte, error) {
+	if raw, isRaw := value.([]byte); isRaw {
+		return raw, nil
+	}
+	return t.db.codec(collection).Encode(value)
+}
+
+func (t *boltTxn) decode(collection string, data []byte, value interface{}) error {
+	if raw, isRaw := value.(*[]byte); isRaw {
+		*raw = append([]byte(nil), data...)
+		return nil
+	}
+	return t.db.codec(collection).Decode(data, value)
+}
+
+// writable return the bucket of a collection , created if missing.
+func (t *boltTxn) writable(collection string) (*bolt.Bucket, error) {
+	if !t.tx.Writable() {
+		return nil, errReadOnlyTxn
+	}
+	return t.tx.CreateBucketIfNotExists([]byte(collection))
+}
+
+// Get decodes the value bound to key in a collection into value , reporting whether it exists.
+func (t *boltTxn) Get(collection string, key []byte, value interface{}) (bool, error) {
+	bkt := t.tx.Bucket([]byte(collection))
+	if bkt == nil {
+		return false, nil
+	}
+	data := bkt.Get(key)
+	if data == nil {
+		return false, nil
+	}
+	return true, t.decode(collection, data, value)
+}
+
+// Put binds value to key in a collection , creating the collection if needed.
+func (t *boltTxn) Put(collection string, key []byte, value interface{}) error {
+	bkt, err := t.writable(collection)
+	if err != nil {
+		return err
+	}
+	data, err := t.encode(collection, value)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(key, data)
+}
+
+// Delete removes key from a collection.
+func (t *boltTxn) Delete(collection string, key []byte) error {
+	if !t.tx.Writable() {
+		return errReadOnlyTxn
+	}
+	bkt := t.tx.Bucket([]byte(collection))
+	if bkt == nil {
+		return nil
+	}
+	return bkt.Delete(key)
+}
+
+// NextSequence returns the next auto-increment ID of a collection.
+func (t *boltTxn) NextSequence(collection string) (uint64, error) {
+	bkt, err := t.writable(collection)
+	if err != nil {
+		return 0, err
+	}
+	return bkt.NextSequence()
+}
+
+// Scan visits entries of a collection with keys in [start, end) in key order , nil bounds are open.
+func (t *boltTxn) Scan(collection string, start, end []byte, visit func(core.StorageItem) (bool, error)) error {
+	return t.scan(collection, start, func(k []byte) bool {
+		return end == nil || bytes.Compare(k, end) < 0
+	}, visit)
+}
+
+// ScanPrefix visits entries of a collection whose keys start with prefix in key order.
+func (t *boltTxn) ScanPrefix(collection string, prefix []byte, visit func(core.StorageItem) (bool, error)) error {
+	return t.scan(collection, prefix, func(k []byte) bool {
+		return bytes.HasPrefix(k, prefix)
+	}, visit)
+}
+
+func (t *boltTxn) scan(collection string, start []byte, inRange func([]byte) bool, visit func(core.StorageItem) (bool, error)) error {
+	bkt := t.tx.Bucket([]byte(collection))
+	if bkt == nil {
+		return nil
+	}
+	c := bkt.Cursor()
+	var k, v []byte
+	if start == nil {
+		k, v = c.First()
+	} else {
+		k, v = c.Seek(start)
+	}
+	for ; k != nil && inRange(k); k, v = c.Next() {
+		// Nested buckets are not collection entries
+		if v == nil {
+			continue
+		}
+		next, err := visit(&boltItem{txn: t, collection: collection, key: k, value: v})
+		if err != nil || !next {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasCollection determines whether a collection was created.
+func (t *boltTxn) HasCollection(collection string) bool {
+	return t.tx.Bucket([]byte(collection)) != nil
+}
+
+// DropCollection removes a collection with all its entries.
+func (t *boltTxn) DropCollection(collection string) error {
+	if !t.tx.Writable() {
+		return errReadOnlyTxn
+	}
+	if t.tx.Bucket([]byte(collection)) == nil {
+		return nil
+	}
+	return t.tx.DeleteBucket([]byte(collection))
+}
+
+// boltItem implements core.StorageItem , only valid during the scan visiting it.
+type boltItem struct {
+	txn        *boltTxn
+	collection string
+	key        []byte
+	value      []byte
+}
+
+// Key of the entry.
+func (it *boltItem) Key() []byte {
+	return append([]byte(nil), it.key...)
+}
+
+// Decode deserializes the value of the entry into value.
+func (it *boltItem) Decode(value interface{}) error {
+	return it.txn.decode(it.collection, it.value, value)
+}
+
+// Type assertions
+var (
+	_ core.CollectionStorage = &boltStorage{}
+	_ core.StorageTxn        = &boltTxn{}
+	_ core.StorageItem       = &boltItem{}
+	_ core.StorageCodec      = jsonCodec{}
+)
@@ -0,0 +1,164 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/stretchr/testify/require"
+)
+
+type testRecord struct {
+	Name  string
+	Value int
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Encode(value interface{}) ([]byte, error) {
+	return []byte(strings.ToUpper(value.(string))), nil
+}
+
+func (upperCodec) Decode(data []byte, value interface{}) error {
+	*value.(*string) = string(data)
+	return nil
+}
+
+func scanKeys(t *testing.T, db *boltStorage, scan func(tx core.StorageTxn, visit func(core.StorageItem) (bool, error)) error) []string {
+	keys := make([]string, 0)
+	require.NoError(t, db.ViewCollections(func(tx core.StorageTxn) error {
+		return scan(tx, func(item core.StorageItem) (bool, error) {
+			keys = append(keys, string(item.Key()))
+			return len(keys) < 3, nil
+		})
+	}))
+	return keys
+}
+
+func TestBoltStorage_Collections(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	require.NoError(t, db.UpdateCollections(func(tx core.StorageTxn) error {
+		for _, k := range []string{"a1", "a2", "b1", "b2", "b3", "c1"} {
+			if err := tx.Put("records", []byte(k), testRecord{Name: k, Value: len(k)}); err != nil {
+				return err
+			}
+		}
+		return tx.Put("raw", []byte("k"), []byte("raw value"))
+	}))
+
+	require.NoError(t, db.ViewCollections(func(tx core.StorageTxn) error {
+		var r testRecord
+		found, err := tx.Get("records", []byte("b2"), &r)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, testRecord{Name: "b2", Value: 2}, r)
+		found, err = tx.Get("records", []byte("zz"), &r)
+		require.NoError(t, err)
+		require.False(t, found)
+		found, err = tx.Get("missing", []byte("a1"), &r)
+		require.NoError(t, err)
+		require.False(t, found)
+		require.False(t, tx.HasCollection("missing"))
+
+		var raw []byte
+		_, err = tx.Get("raw", []byte("k"), &raw)
+		require.NoError(t, err)
+		require.Equal(t, "raw value", string(raw))
+		// Writes are rejected in read only transactions
+		require.Equal(t, errReadOnlyTxn, tx.Put("records", []byte("x"), testRecord{}))
+		return nil
+	}))
+
+	require.Equal(t, []string{"a2", "b1", "b2"}, scanKeys(t, db, func(tx core.StorageTxn, visit func(core.StorageItem) (bool, error)) error {
+		return tx.Scan("records", []byte("a2"), nil, visit)
+	}))
+	require.Equal(t, []string{"a1", "a2"}, scanKeys(t, db, func(tx core.StorageTxn, visit func(core.StorageItem) (bool, error)) error {
+		return tx.Scan("records", nil, []byte("b1"), visit)
+	}))
+	require.Equal(t, []string{"b1", "b2", "b3"}, scanKeys(t, db, func(tx core.StorageTxn, visit func(core.StorageItem) (bool, error)) error {
+		return tx.ScanPrefix("records", []byte("b"), visit)
+	}))
+	require.Empty(t, scanKeys(t, db, func(tx core.StorageTxn, visit func(core.StorageItem) (bool, error)) error {
+		return tx.ScanPrefix("missing", nil, visit)
+	}))
+
+	// Failed transactions leave collections untouched
+	errAbort := errors.New("abort")
+	err := db.UpdateCollections(func(tx core.StorageTxn) error {
+		require.NoError(t, tx.Delete("records", []byte("a1")))
+		require.NoError(t, tx.DropCollection("raw"))
+		return errAbort
+	})
+	require.Equal(t, errAbort, err)
+	require.NoError(t, db.ViewCollections(func(tx core.StorageTxn) error {
+		var r testRecord
+		found, err := tx.Get("records", []byte("a1"), &r)
+		require.True(t, found)
+		require.True(t, tx.HasCollection("raw"))
+		return err
+	}))
+
+	db.SetCollectionCodec("upper", upperCodec{})
+	require.NoError(t, db.UpdateCollections(func(tx core.StorageTxn) error {
+		id, err := tx.NextSequence("upper")
+		require.Equal(t, uint64(1), id)
+		require.NoError(t, err)
+		return tx.Put("upper", []byte("k"), "value")
+	}))
+	require.NoError(t, db.ViewCollections(func(tx core.StorageTxn) error {
+		var v string
+		_, err := tx.Get("upper", []byte("k"), &v)
+		require.Equal(t, "VALUE", v)
+		return err
+	}))
+}
+
+func TestBoltStorage_Migrate(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	version, err := db.SchemaVersion()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), version)
+
+	applied := make([]uint64, 0)
+	migration := func(version uint64, err error) core.StorageMigration {
+		return core.StorageMigration{
+			Version:     version,
+			Description: "test",
+			Apply: func(tx core.StorageTxn) error {
+				applied = append(applied, version)
+				if err := tx.Put("migrated", []byte{byte(version)}, version); err != nil {
+					return err
+				}
+				return err
+			},
+		}
+	}
+	require.NoError(t, db.migrate([]core.StorageMigration{migration(1, nil), migration(2, nil)}))
+	require.Equal(t, []uint64{1, 2}, applied)
+	version, err = db.SchemaVersion()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), version)
+
+	// Applied migrations are not run again , a failed one is rolled back
+	require.Error(t, db.migrate([]core.StorageMigration{migration(1, nil), migration(2, nil), migration(3, errors.New("failed"))}))
+	require.Equal(t, []uint64{1, 2, 3}, applied)
+	version, err = db.SchemaVersion()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), version)
+	require.NoError(t, db.ViewCollections(func(tx core.StorageTxn) error {
+		var v json.Number
+		found, err := tx.Get("migrated", []byte{3}, &v)
+		require.False(t, found)
+		return err
+	}))
+
+	require.Equal(t, errSchemaTooNew, db.migrate([]core.StorageMigration{migration(1, nil)}))
+	require.Equal(t, errSchemaTooNew, db.migrate(nil))
+	require.Equal(t, errMigrationVersion, RegisterMigration(core.StorageMigration{Version: 0}))
+}
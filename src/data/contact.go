@@ -12,12 +12,19 @@ type Contact struct {
 	ID      uint64
 	Address []Address
 	Name    []byte
+	// Groups tags the contact is filed under
+	Groups    []string `json:",omitempty"`
+	Favourite bool     `json:",omitempty"`
+	// Notes free text about the contact
+	Notes string `json:",omitempty"`
 }
 
 // Address is the relation of an address and his coin type.
 type Address struct {
 	Value []byte
 	Coin  []byte
+	// Label purpose of the address , e.g. exchange deposit or cold storage
+	Label string `json:",omitempty"`
 }
 
 // MarshalBinary encodes a user to binary format.
@@ -71,6 +78,46 @@ func (c *Contact) SetName(newName string) {
 	c.Name = []byte(newName)
 }
 
+// GetGroups return the tags the contact is filed under.
+func (c *Contact) GetGroups() []string {
+	return c.Groups
+}
+
+// SetGroups set the tags of the contact. Surrounding spaces are trimmed , empty and repeated tags are dropped.
+func (c *Contact) SetGroups(groups []string) {
+	c.Groups = nil
+	seen := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		g = strings.TrimSpace(g)
+		key := strings.ToLower(g)
+		if _, isSeen := seen[key]; isSeen || g == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		c.Groups = append(c.Groups, g)
+	}
+}
+
+// IsFavourite return true if the contact is marked as favourite.
+func (c *Contact) IsFavourite() bool {
+	return c.Favourite
+}
+
+// SetFavourite mark or unmark the contact as favourite.
+func (c *Contact) SetFavourite(favourite bool) {
+	c.Favourite = favourite
+}
+
+// GetNotes return free text about the contact.
+func (c *Contact) GetNotes() string {
+	return c.Notes
+}
+
+// SetNotes set free text about the contact.
+func (c *Contact) SetNotes(notes string) {
+	c.Notes = notes
+}
+
 func (c *Contact) IsValid() bool {
 	if strings.ReplaceAll(c.GetName(), " ", "") == "" {
 		return false
@@ -106,6 +153,16 @@ func (ad *Address) SetCoinType(coinType []byte) {
 	ad.Coin = coinType
 }
 
+// GetLabel get the purpose of the address.
+func (ad *Address) GetLabel() string {
+	return ad.Label
+}
+
+// SetLabel set the purpose of the address.
+func (ad *Address) SetLabel(label string) {
+	ad.Label = label
+}
+
 func (ad *Address) IsValid() bool {
 	if _, err := util.AddressFromString(string(ad.GetValue()),
 		string(ad.GetCoinType())); err != nil {
@@ -0,0 +1,439 @@
+package data
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+// Ranks of fuzzy name matches , lower is better
+const (
+	matchExact = iota
+	matchPrefix
+	matchWordPrefix
+	matchSubstring
+	matchSubsequence
+	// matchTypos plus the edit distance to the closest name prefix
+	matchTypos
+)
+
+// contactIndex in-memory index of decrypted contacts , by ID , name and address.
+type contactIndex struct {
+	contacts map[uint64]*Contact
+	// names contacts sorted by lower case name
+	names []indexedName
+	// owners ID of the contact owning each address , see addressKey
+	owners map[string]uint64
+}
+
+type indexedName struct {
+	key string
+	id  uint64
+}
+
+// newContactIndex index a list of contacts.
+func newContactIndex(contacts []core.Contact) *contactIndex {
+	idx := &contactIndex{
+		contacts: make(map[uint64]*Contact, len(contacts)),
+		owners:   make(map[string]uint64),
+	}
+	for _, c := range contacts {
+		idx.put(c)
+	}
+	return idx
+}
+
+// addressKey index key of an address , tickers are not case sensitive.
+func addressKey(address, coinType string) string {
+	return strings.ToUpper(coinType) + "\x00" + address
+}
+
+// copyContact return a data.Contact holding a copy of contact.
+func copyContact(contact core.Contact) *Contact {
+	c := &Contact{
+		ID:        contact.GetID(),
+		Name:      []byte(contact.GetName()),
+		Groups:    append([]string(nil), contact.GetGroups()...),
+		Favourite: contact.IsFavourite(),
+		Notes:     contact.GetNotes(),
+	}
+	for _, sa := range contact.GetAddresses() {
+		c.Address = append(c.Address, Address{
+			Value: append([]byte(nil), sa.GetValue()...),
+			Coin:  append([]byte(nil), sa.GetCoinType()...),
+			Label: sa.GetLabel(),
+		})
+	}
+	return c
+}
+
+// put add or replace a contact.
+func (idx *contactIndex) put(contact core.Contact) {
+	idx.remove(contact.GetID())
+	c := copyContact(contact)
+	idx.contacts[c.ID] = c
+	for _, addr := range c.Address {
+		idx.owners[addressKey(string(addr.Value), string(addr.Coin))] = c.ID
+	}
+	entry := indexedName{key: strings.ToLower(c.GetName()), id: c.ID}
+	pos := sort.Search(len(idx.names), func(i int) bool {
+		return idx.names[i].key >= entry.key
+	})
+	idx.names = append(idx.names, indexedName{})
+	copy(idx.names[pos+1:], idx.names[pos:])
+	idx.names[pos] = entry
+}
+
+// remove a contact , if indexed.
+func (idx *contactIndex) remove(id uint64) {
+	c, exists := idx.contacts[id]
+	if !exists {
+		return
+	}
+	delete(idx.contacts, id)
+	for _, addr := range c.Address {
+		key := addressKey(string(addr.Value), string(addr.Coin))
+		if idx.owners[key] == id {
+			delete(idx.owners, key)
+		}
+	}
+	for i := range idx.names {
+		if idx.names[i].id == id {
+			idx.names = append(idx.names[:i], idx.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// get return a copy of a contact , nil if not indexed.
+func (idx *contactIndex) get(id uint64) core.Contact {
+	if c, exists := idx.contacts[id]; exists {
+		return copyContact(c)
+	}
+	return nil
+}
+
+// owner return the contact owning an address , nil if none does.
+func (idx *contactIndex) owner(address, coinType string) core.Contact {
+	if id, exists := idx.owners[addressKey(address, coinType)]; exists {
+		return idx.get(id)
+	}
+	return nil
+}
+
+// byPrefix list contacts whose name starts with prefix , ignoring case , sorted by name.
+func (idx *contactIndex) byPrefix(prefix string) []core.Contact {
+	prefix = strings.ToLower(prefix)
+	contacts := make([]core.Contact, 0)
+	pos := sort.Search(len(idx.names), func(i int) bool {
+		return idx.names[i].key >= prefix
+	})
+	for ; pos < len(idx.names) && strings.HasPrefix(idx.names[pos].key, prefix); pos++ {
+		contacts = append(contacts, idx.get(idx.names[pos].id))
+	}
+	return contacts
+}
+
+// byCoin list contacts having addresses of a coin , sorted by name.
+func (idx *contactIndex) byCoin(coinType string) []core.Contact {
+	contacts := make([]core.Contact, 0)
+	for _, n := range idx.names {
+		for _, addr := range idx.contacts[n.id].Address {
+			if strings.EqualFold(string(addr.Coin), coinType) {
+				contacts = append(contacts, idx.get(n.id))
+				break
+			}
+		}
+	}
+	return contacts
+}
+
+// byGroup list contacts filed under a group , ignoring case , sorted by name.
+func (idx *contactIndex) byGroup(group string) []core.Contact {
+	contacts := make([]core.Contact, 0)
+	for _, n := range idx.names {
+		for _, g := range idx.contacts[n.id].Groups {
+			if strings.EqualFold(g, group) {
+				contacts = append(contacts, idx.get(n.id))
+				break
+			}
+		}
+	}
+	return contacts
+}
+
+// favourites list contacts marked as favourite , sorted by name.
+func (idx *contactIndex) favourites() []core.Contact {
+	contacts := make([]core.Contact, 0)
+	for _, n := range idx.names {
+		if idx.contacts[n.id].Favourite {
+			contacts = append(contacts, idx.get(n.id))
+		}
+	}
+	return contacts
+}
+
+// groups list the groups contacts are filed under , sorted ignoring case.
+func (idx *contactIndex) groups() []string {
+	byKey := make(map[string]string)
+	for _, c := range idx.contacts {
+		for _, g := range c.Groups {
+			if _, exists := byKey[strings.ToLower(g)]; !exists {
+				byKey[strings.ToLower(g)] = g
+			}
+		}
+	}
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	groups := make([]string, 0, len(keys))
+	for _, k := range keys {
+		groups = append(groups, byKey[k])
+	}
+	return groups
+}
+
+// search list contacts whose name matches query , best matches first.
+// Names match if they contain the query , contain its letters in order , or
+// start with a few typos of it.
+func (idx *contactIndex) search(query string) []core.Contact {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return idx.byPrefix("")
+	}
+	type match struct {
+		rank int
+		pos  int
+	}
+	matches := make([]match, 0)
+	for i, n := range idx.names {
+		if rank, ok := matchName(n.key, query); ok {
+			matches = append(matches, match{rank: rank, pos: i})
+		}
+	}
+	// Names are already sorted , so ties keep that order
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+	contacts := make([]core.Contact, 0, len(matches))
+	for _, m := range matches {
+		contacts = append(contacts, idx.get(idx.names[m.pos].id))
+	}
+	return contacts
+}
+
+// conflict return the error of nameExists or addressExists if contact , stored with ID id ,
+// would share its name or an address with another contact.
+func (idx *contactIndex) conflict(contact core.Contact, id uint64) error {
+	for _, sa := range contact.GetAddresses() {
+		if owner := idx.owner(string(sa.GetValue()), string(sa.GetCoinType())); owner != nil && owner.GetID() != id {
+			if err := addressExists(sa, []core.Contact{owner}); err != nil {
+				return err
+			}
+		}
+	}
+	key := strings.ToLower(contact.GetName())
+	pos := sort.Search(len(idx.names), func(i int) bool {
+		return idx.names[i].key >= key
+	})
+	for ; pos < len(idx.names) && idx.names[pos].key == key; pos++ {
+		if idx.names[pos].id == id {
+			continue
+		}
+		if err := nameExists(contact, []core.Contact{idx.contacts[idx.names[pos].id]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchName rank how well a lower case name matches a lower case query.
+func matchName(name, query string) (int, bool) {
+	switch {
+	case name == query:
+		return matchExact, true
+	case strings.HasPrefix(name, query):
+		return matchPrefix, true
+	case strings.Contains(name, " "+query):
+		return matchWordPrefix, true
+	case strings.Contains(name, query):
+		return matchSubstring, true
+	case isSubsequence(name, query):
+		return matchSubsequence, true
+	}
+	maxTypos := 1
+	if utf8.RuneCountInString(query) > 4 {
+		maxTypos = 2
+	}
+	nameRunes, queryRunes := []rune(name), []rune(query)
+	if len(nameRunes) > len(queryRunes) {
+		nameRunes = nameRunes[:len(queryRunes)]
+	}
+	if d := editDistance(nameRunes, queryRunes); d <= maxTypos {
+		return matchTypos + d, true
+	}
+	return 0, false
+}
+
+// isSubsequence return true if s holds the runes of sub in order.
+func isSubsequence(s, sub string) bool {
+	for _, r := range s {
+		if len(sub) == 0 {
+			break
+		}
+		if first, size := utf8.DecodeRuneInString(sub); r == first {
+			sub = sub[size:]
+		}
+	}
+	return len(sub) == 0
+}
+
+// editDistance Levenshtein distance of two strings.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// loadIndex return the index of the address book , building it unless up to date.
+// Must be called with indexMutex held.
+func (addrsBook *addrsBook) loadIndex() (*contactIndex, error) {
+	if addrsBook.index != nil {
+		return addrsBook.index, nil
+	}
+	contacts, err := addrsBook.ListContact()
+	if err != nil && err != errBucketEmpty {
+		return nil, err
+	}
+	addrsBook.index = newContactIndex(contacts)
+	return addrsBook.index, nil
+}
+
+// resetIndex drop the index , to be rebuilt on next use.
+func (addrsBook *addrsBook) resetIndex() {
+	addrsBook.indexMutex.Lock()
+	defer addrsBook.indexMutex.Unlock()
+	addrsBook.index = nil
+}
+
+// updateIndex apply f to the index if already built.
+func (addrsBook *addrsBook) updateIndex(f func(idx *contactIndex)) {
+	addrsBook.indexMutex.Lock()
+	defer addrsBook.indexMutex.Unlock()
+	if addrsBook.index != nil {
+		f(addrsBook.index)
+	}
+}
+
+// queryIndex run f on the index , building it if needed.
+func (addrsBook *addrsBook) queryIndex(f func(idx *contactIndex) error) error {
+	addrsBook.indexMutex.Lock()
+	defer addrsBook.indexMutex.Unlock()
+	idx, err := addrsBook.loadIndex()
+	if err != nil {
+		return err
+	}
+	return f(idx)
+}
+
+// SearchContacts list contacts whose name matches query , best matches first. Names match
+// ignoring case if they contain the query , contain its letters in order or start with a few typos of it.
+func (addrsBook *addrsBook) SearchContacts(query string) ([]core.Contact, error) {
+	var contacts []core.Contact
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		contacts = idx.search(query)
+		return nil
+	})
+	return contacts, err
+}
+
+// FindContactsByPrefix list contacts whose name starts with prefix ignoring case , sorted by name.
+func (addrsBook *addrsBook) FindContactsByPrefix(prefix string) ([]core.Contact, error) {
+	var contacts []core.Contact
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		contacts = idx.byPrefix(prefix)
+		return nil
+	})
+	return contacts, err
+}
+
+// LookupAddress return the contact owning an address of a coin.
+func (addrsBook *addrsBook) LookupAddress(address, coinType string) (core.Contact, error) {
+	var contact core.Contact
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		if contact = idx.owner(address, coinType); contact == nil {
+			return errors.ErrContactNotFound
+		}
+		return nil
+	})
+	return contact, err
+}
+
+// ListContactsByCoin list contacts having addresses of a coin , sorted by name.
+func (addrsBook *addrsBook) ListContactsByCoin(coinType string) ([]core.Contact, error) {
+	var contacts []core.Contact
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		contacts = idx.byCoin(coinType)
+		return nil
+	})
+	return contacts, err
+}
+
+// ListContactsByGroup list contacts filed under a group ignoring case , sorted by name.
+func (addrsBook *addrsBook) ListContactsByGroup(group string) ([]core.Contact, error) {
+	var contacts []core.Contact
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		contacts = idx.byGroup(group)
+		return nil
+	})
+	return contacts, err
+}
+
+// ListFavouriteContacts list contacts marked as favourite , sorted by name.
+func (addrsBook *addrsBook) ListFavouriteContacts() ([]core.Contact, error) {
+	var contacts []core.Contact
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		contacts = idx.favourites()
+		return nil
+	})
+	return contacts, err
+}
+
+// ListGroups list the groups contacts are filed under , sorted ignoring case.
+func (addrsBook *addrsBook) ListGroups() ([]string, error) {
+	var groups []string
+	err := addrsBook.queryIndex(func(idx *contactIndex) error {
+		groups = idx.groups()
+		return nil
+	})
+	return groups, err
+}
@@ -0,0 +1,155 @@
+package data
+
+import (
+	"testing"
+
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+)
+
+func contactNames(contacts []core.Contact) []string {
+	names := make([]string, 0, len(contacts))
+	for _, c := range contacts {
+		names = append(names, c.GetName())
+	}
+	return names
+}
+
+func TestContactIndex_Search(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+	for _, c := range []*Contact{
+		{Name: []byte("Alice"), Address: []Address{{Value: []byte(importAddr1), Coin: []byte("SKY")}}},
+		{Name: []byte("Alicia Keys"), Address: []Address{{Value: []byte(importAddr2), Coin: []byte("SKY")}}},
+		{Name: []byte("Mallory")},
+		{Name: []byte("Bob Malice")},
+	} {
+		_, err := ab.InsertContact(c)
+		require.NoError(t, err)
+	}
+
+	contacts, err := ab.FindContactsByPrefix("ALI")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Alicia Keys"}, contactNames(contacts))
+	contacts, err = ab.SearchContacts("alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Bob Malice", "Alicia Keys"}, contactNames(contacts))
+	contacts, err = ab.SearchContacts("keys")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alicia Keys"}, contactNames(contacts))
+	contacts, err = ab.SearchContacts("mlry")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Mallory"}, contactNames(contacts))
+	contacts, err = ab.SearchContacts("Malory")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Mallory"}, contactNames(contacts))
+	contacts, err = ab.SearchContacts("zed")
+	require.NoError(t, err)
+	require.Empty(t, contacts)
+
+	contacts, err = ab.ListContactsByCoin("sky")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Alicia Keys"}, contactNames(contacts))
+	contact, err := ab.LookupAddress(importAddr2, "sky")
+	require.NoError(t, err)
+	require.Equal(t, "Alicia Keys", contact.GetName())
+	_, err = ab.LookupAddress(importAddr2, "BTC")
+	require.Equal(t, errors.ErrContactNotFound, err)
+}
+
+func TestContactIndex_KeptUpToDate(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+	id, err := ab.InsertContact(&Contact{
+		Name:    []byte("Alice"),
+		Address: []Address{{Value: []byte(importAddr1), Coin: []byte("SKY")}},
+	})
+	require.NoError(t, err)
+
+	// Built after authentication
+	book := ab.(*addrsBook)
+	book.resetIndex()
+	require.NoError(t, ab.Authenticate(defaultPass))
+	require.NotNil(t, book.index)
+	require.Len(t, book.index.contacts, 1)
+
+	_, err = ab.InsertContact(&Contact{
+		Name:    []byte("Eve"),
+		Address: []Address{{Value: []byte(importAddr1), Coin: []byte("SKY")}},
+	})
+	require.Error(t, err)
+	_, err = ab.InsertContact(&Contact{Name: []byte("Alice")})
+	require.Error(t, err)
+
+	require.NoError(t, ab.UpdateContact(id, &Contact{
+		Name:    []byte("Alice Smith"),
+		Address: []Address{{Value: []byte(importAddr3), Coin: []byte("SKY")}},
+	}))
+	_, err = ab.LookupAddress(importAddr1, "SKY")
+	require.Equal(t, errors.ErrContactNotFound, err)
+	contact, err := ab.LookupAddress(importAddr3, "SKY")
+	require.NoError(t, err)
+	require.Equal(t, "Alice Smith", contact.GetName())
+	require.Equal(t, id, contact.GetID())
+
+	// Contacts returned are copies
+	contact.SetName("Mallory")
+	contacts, err := ab.FindContactsByPrefix("alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice Smith"}, contactNames(contacts))
+
+	require.NoError(t, ab.DeleteContact(id))
+	contacts, err = ab.SearchContacts("alice")
+	require.NoError(t, err)
+	require.Empty(t, contacts)
+	_, err = ab.InsertContact(&Contact{
+		Name:    []byte("Eve"),
+		Address: []Address{{Value: []byte(importAddr3), Coin: []byte("SKY")}},
+	})
+	require.NoError(t, err)
+
+	// Locked books are not indexed
+	require.Error(t, ab.Authenticate("wrong"))
+	_, err = ab.SearchContacts("eve")
+	require.Error(t, err)
+}
+
+func TestContact_Details(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	// Records written before contact details decode unchanged
+	var legacy Contact
+	require.NoError(t, legacy.UnmarshalBinary([]byte(`{"ID":0,"Address":[{"Value":"MkRwZW9mY3NhbURmYW5yUnozNHFqWXZza1J6S3F6TktNY2o=","Coin":"U0tZ"}],"Name":"QWxpY2U="}`)))
+	require.Equal(t, "Alice", legacy.GetName())
+	require.Equal(t, importAddr1, string(legacy.Address[0].Value))
+	require.Empty(t, legacy.GetGroups())
+	data, err := legacy.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, `{"ID":0,"Address":[{"Value":"MkRwZW9mY3NhbURmYW5yUnozNHFqWXZza1J6S3F6TktNY2o=","Coin":"U0tZ"}],"Name":"QWxpY2U="}`, string(data))
+
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+	alice := &Contact{Name: []byte("Alice"), Favourite: true}
+	alice.SetGroups([]string{" Friends ", "friends", "", "Work"})
+	require.Equal(t, []string{"Friends", "Work"}, alice.GetGroups())
+	_, err = ab.InsertContact(alice)
+	require.NoError(t, err)
+	bob := &Contact{Name: []byte("Bob")}
+	bob.SetGroups([]string{"work"})
+	_, err = ab.InsertContact(bob)
+	require.NoError(t, err)
+
+	contacts, err := ab.ListContactsByGroup("WORK")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Bob"}, contactNames(contacts))
+	contacts, err = ab.ListFavouriteContacts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice"}, contactNames(contacts))
+	groups, err := ab.ListGroups()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Friends", "Work"}, groups)
+}
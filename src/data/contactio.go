@@ -0,0 +1,608 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+)
+
+const (
+	// ContactFormatBackup versioned JSON backup encrypted with its own passphrase
+	ContactFormatBackup = "backup"
+	// ContactFormatCSV CSV with name , coin ticker and address columns , one row per address
+	ContactFormatCSV = "csv"
+	// ContactFormatVCard vCard with a custom property per crypto address
+	ContactFormatVCard = "vcf"
+
+	// VCardAddressProperty vCard property holding a crypto address , its TYPE parameter is the coin ticker
+	// and its LABEL parameter the purpose of the address
+	VCardAddressProperty = "X-CRYPTO-ADDRESS"
+	// VCardFavouriteProperty vCard property set to TRUE for favourite contacts
+	VCardFavouriteProperty = "X-FAVOURITE"
+
+	// contactBackupVersion version of the backup contents written by ExportContacts
+	contactBackupVersion = 1
+)
+
+// ImportMode action taken when an imported contact has the name of a stored one.
+type ImportMode int
+
+const (
+	// ImportMerge add the imported addresses to the stored contact
+	ImportMerge ImportMode = iota
+	// ImportSkip keep the stored contact untouched
+	ImportSkip
+	// ImportOverwrite replace the addresses of the stored contact by the imported ones
+	ImportOverwrite
+)
+
+var (
+	// Errors
+	errUnknownContactFormat = errors.New("unknown contacts format")
+	errInvalidBackup        = errors.New("invalid contacts backup")
+	errContactWithoutName   = errors.New("contact without name")
+	errMalformedContactRow  = errors.New("expected name, coin and address columns")
+
+	// csvContactHeader columns of CSV exports
+	csvContactHeader = []string{"name", "coin", "address"}
+)
+
+// ImportRejection row of an imported file that was not stored.
+type ImportRejection struct {
+	// Line record number in CSV files , line number in vCard files and contact number in backups , counting from 1
+	Line    int
+	Name    string
+	Coin    string
+	Address string
+	Err     error
+}
+
+// ImportReport outcome of a contacts import.
+type ImportReport struct {
+	Imported    int
+	Merged      int
+	Overwritten int
+	Skipped     int
+	Rejected    []ImportRejection
+}
+
+// contactBackup plain contents of a contacts backup.
+type contactBackup struct {
+	Version  int                   `json:"version"`
+	Contacts []contactBackupRecord `json:"contacts"`
+}
+
+type contactBackupRecord struct {
+	Name      string                 `json:"name"`
+	Addresses []contactBackupAddress `json:"addresses"`
+	Groups    []string               `json:"groups,omitempty"`
+	Favourite bool                   `json:"favourite,omitempty"`
+	Notes     string                 `json:"notes,omitempty"`
+}
+
+type contactBackupAddress struct {
+	Coin    string `json:"coin"`
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+}
+
+// importedContact contact read from an imported file.
+type importedContact struct {
+	line      int
+	name      string
+	addresses []importedAddress
+	groups    []string
+	favourite bool
+	notes     string
+	// err reason the row can not be imported at all
+	err error
+}
+
+type importedAddress struct {
+	line  int
+	coin  string
+	value string
+	label string
+}
+
+// ExportContacts writes every contact of book in format. Backups are encrypted with passphrase ,
+// which is ignored by other formats.
+func ExportContacts(book core.AddressBook, w io.Writer, format, passphrase string) error {
+	contacts, err := book.ListContact()
+	if err != nil && err != errBucketEmpty {
+		return err
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].GetName() < contacts[j].GetName()
+	})
+	switch format {
+	case ContactFormatBackup:
+		return writeContactBackup(w, contacts, passphrase)
+	case ContactFormatCSV:
+		return writeContactsCSV(w, contacts)
+	case ContactFormatVCard:
+		return writeContactsVCard(w, contacts)
+	}
+	return errUnknownContactFormat
+}
+
+// ImportContacts reads contacts in format and stores them in book. Addresses are validated by the
+// plugin of their coin and rejected if owned by another contact , contacts named like a stored one
+// are handled according to mode. Rejected rows are listed in the report , an error is returned
+// only if the file can not be read.
+func ImportContacts(book core.AddressBook, r io.Reader, format string, mode ImportMode, passphrase string) (*ImportReport, error) {
+	var entries []importedContact
+	var err error
+	switch format {
+	case ContactFormatBackup:
+		entries, err = readContactBackup(r, passphrase)
+	case ContactFormatCSV:
+		entries, err = readContactsCSV(r)
+	case ContactFormatVCard:
+		entries, err = readContactsVCard(r)
+	default:
+		err = errUnknownContactFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := book.ListContact()
+	if err != nil && err != errBucketEmpty {
+		return nil, err
+	}
+	report := &ImportReport{}
+	for _, entry := range entries {
+		contacts = importContact(book, contacts, entry, mode, report)
+	}
+	return report, nil
+}
+
+// importContact stores an imported contact , returning the updated list of stored contacts.
+func importContact(book core.AddressBook, contacts []core.Contact, entry importedContact, mode ImportMode, report *ImportReport) []core.Contact {
+	reject := func(line int, addr importedAddress, err error) {
+		report.Rejected = append(report.Rejected, ImportRejection{
+			Line:    line,
+			Name:    entry.name,
+			Coin:    addr.coin,
+			Address: addr.value,
+			Err:     err,
+		})
+	}
+	if entry.err != nil {
+		reject(entry.line, importedAddress{}, entry.err)
+		return contacts
+	}
+	if entry.name == "" {
+		for _, addr := range entry.addresses {
+			reject(addr.line, addr, errContactWithoutName)
+		}
+		if len(entry.addresses) == 0 {
+			reject(entry.line, importedAddress{}, errContactWithoutName)
+		}
+		return contacts
+	}
+
+	pos := -1
+	candidate := &Contact{Name: []byte(entry.name)}
+	if err := nameExists(candidate, contacts); err != nil {
+		for i := range contacts {
+			if contacts[i].GetName() == entry.name {
+				pos = i
+			}
+		}
+	}
+	if pos >= 0 && mode == ImportSkip {
+		report.Skipped++
+		return contacts
+	}
+	others := make([]core.Contact, 0, len(contacts))
+	for i := range contacts {
+		if i != pos {
+			others = append(others, contacts[i])
+		}
+	}
+
+	accepted := make([]Address, 0, len(entry.addresses))
+	for _, addr := range entry.addresses {
+		sa := Address{Value: []byte(addr.value), Coin: []byte(addr.coin), Label: addr.label}
+		if _, err := util.AddressFromString(addr.value, addr.coin); err != nil {
+			reject(addr.line, addr, err)
+			continue
+		}
+		if err := addressExists(&sa, others); err != nil {
+			reject(addr.line, addr, err)
+			continue
+		}
+		if err := addressExists(&sa, []core.Contact{&Contact{Address: accepted}}); err != nil {
+			// Listed twice for this contact
+			continue
+		}
+		accepted = append(accepted, sa)
+	}
+	if len(entry.addresses) > 0 && len(accepted) == 0 {
+		return contacts
+	}
+
+	if pos < 0 {
+		candidate.Address = accepted
+		candidate.SetGroups(entry.groups)
+		candidate.SetFavourite(entry.favourite)
+		candidate.SetNotes(entry.notes)
+		id, err := book.InsertContact(candidate)
+		if err != nil {
+			reject(entry.line, importedAddress{}, err)
+			return contacts
+		}
+		candidate.SetID(id)
+		report.Imported++
+		return append(contacts, candidate)
+	}
+
+	stored := contacts[pos]
+	updated := &Contact{ID: stored.GetID(), Name: []byte(stored.GetName())}
+	if mode == ImportMerge {
+		// Stored details win , imported ones fill the gaps
+		merged := copyContact(stored)
+		merged.SetGroups(append(merged.Groups, entry.groups...))
+		merged.SetFavourite(merged.Favourite || entry.favourite)
+		if merged.Notes == "" {
+			merged.SetNotes(entry.notes)
+		}
+		for i := range accepted {
+			if addressExists(&accepted[i], []core.Contact{merged}) == nil {
+				merged.Address = append(merged.Address, accepted[i])
+			}
+		}
+		if reflect.DeepEqual(merged, copyContact(stored)) {
+			return contacts
+		}
+		updated = merged
+	} else {
+		updated.Address = accepted
+		updated.SetGroups(entry.groups)
+		updated.SetFavourite(entry.favourite)
+		updated.SetNotes(entry.notes)
+	}
+	if err := book.UpdateContact(updated.ID, updated); err != nil {
+		reject(entry.line, importedAddress{}, err)
+		return contacts
+	}
+	if mode == ImportMerge {
+		report.Merged++
+	} else {
+		report.Overwritten++
+	}
+	contacts[pos] = updated
+	return contacts
+}
+
+// newImportedAddress normalize an address read from an imported file.
+func newImportedAddress(line int, coin, value string) importedAddress {
+	return importedAddress{
+		line:  line,
+		coin:  strings.ToUpper(strings.TrimSpace(coin)),
+		value: strings.TrimSpace(value),
+	}
+}
+
+func writeContactBackup(w io.Writer, contacts []core.Contact, passphrase string) error {
+	backup := contactBackup{Version: contactBackupVersion, Contacts: make([]contactBackupRecord, 0, len(contacts))}
+	for _, c := range contacts {
+		record := contactBackupRecord{
+			Name:      c.GetName(),
+			Addresses: make([]contactBackupAddress, 0),
+			Groups:    c.GetGroups(),
+			Favourite: c.IsFavourite(),
+			Notes:     c.GetNotes(),
+		}
+		for _, sa := range c.GetAddresses() {
+			record.Addresses = append(record.Addresses, contactBackupAddress{
+				Coin:    string(sa.GetCoinType()),
+				Address: string(sa.GetValue()),
+				Label:   sa.GetLabel(),
+			})
+		}
+		backup.Contacts = append(backup.Contacts, record)
+	}
+	data, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+	kdf, err := newKDFParams()
+	if err != nil {
+		return err
+	}
+	msg, err := newEnvelopeCipher([]byte(passphrase), kdf, nil).encrypt(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(msg)
+	return err
+}
+
+func readContactBackup(r io.Reader, passphrase string) ([]importedContact, error) {
+	msg, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	env, err := parseEnvelope(bytes.TrimSpace(msg))
+	if err != nil {
+		return nil, err
+	}
+	c := newEnvelopeCipher([]byte(passphrase), env.KDF, nil)
+	data, err := c.decrypt(bytes.TrimSpace(msg))
+	if err != nil {
+		return nil, errWrongPassword
+	}
+	var backup contactBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, errInvalidBackup
+	}
+	if backup.Version != contactBackupVersion {
+		return nil, fmt.Errorf("%s: unsupported version %d", errInvalidBackup, backup.Version)
+	}
+	entries := make([]importedContact, 0, len(backup.Contacts))
+	for i, record := range backup.Contacts {
+		entry := importedContact{
+			line:      i + 1,
+			name:      strings.TrimSpace(record.Name),
+			groups:    record.Groups,
+			favourite: record.Favourite,
+			notes:     record.Notes,
+		}
+		for _, addr := range record.Addresses {
+			imported := newImportedAddress(i+1, addr.Coin, addr.Address)
+			imported.label = addr.Label
+			entry.addresses = append(entry.addresses, imported)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func writeContactsCSV(w io.Writer, contacts []core.Contact) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvContactHeader); err != nil {
+		return err
+	}
+	for _, c := range contacts {
+		for _, sa := range c.GetAddresses() {
+			if err := cw.Write([]string{c.GetName(), string(sa.GetCoinType()), string(sa.GetValue())}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readContactsCSV group rows by contact name , the header row is optional.
+func readContactsCSV(r io.Reader) ([]importedContact, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]importedContact, 0)
+	byName := make(map[string]int)
+	for i, record := range records {
+		line := i + 1
+		if i == 0 && isCSVContactHeader(record) {
+			continue
+		}
+		if len(record) != len(csvContactHeader) {
+			entries = append(entries, importedContact{line: line, err: errMalformedContactRow})
+			continue
+		}
+		name := strings.TrimSpace(record[0])
+		addr := newImportedAddress(line, record[1], record[2])
+		if pos, exists := byName[name]; exists && name != "" {
+			entries[pos].addresses = append(entries[pos].addresses, addr)
+			continue
+		}
+		byName[name] = len(entries)
+		entries = append(entries, importedContact{line: line, name: name, addresses: []importedAddress{addr}})
+	}
+	return entries, nil
+}
+
+func isCSVContactHeader(record []string) bool {
+	if len(record) != len(csvContactHeader) {
+		return false
+	}
+	for i := range record {
+		if !strings.EqualFold(strings.TrimSpace(record[i]), csvContactHeader[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeContactsVCard(w io.Writer, contacts []core.Contact) error {
+	bw := bufio.NewWriter(w)
+	for _, c := range contacts {
+		fmt.Fprint(bw, "BEGIN:VCARD\r\nVERSION:4.0\r\n")
+		fmt.Fprintf(bw, "FN:%s\r\n", escapeVCard(c.GetName()))
+		if groups := c.GetGroups(); len(groups) > 0 {
+			escaped := make([]string, 0, len(groups))
+			for _, g := range groups {
+				escaped = append(escaped, escapeVCard(g))
+			}
+			fmt.Fprintf(bw, "CATEGORIES:%s\r\n", strings.Join(escaped, ","))
+		}
+		if c.GetNotes() != "" {
+			fmt.Fprintf(bw, "NOTE:%s\r\n", escapeVCard(c.GetNotes()))
+		}
+		if c.IsFavourite() {
+			fmt.Fprintf(bw, "%s:TRUE\r\n", VCardFavouriteProperty)
+		}
+		for _, sa := range c.GetAddresses() {
+			label := ""
+			if sa.GetLabel() != "" {
+				label = ";LABEL=" + quoteVCardParam(sa.GetLabel())
+			}
+			fmt.Fprintf(bw, "%s;TYPE=%s%s:%s\r\n", VCardAddressProperty,
+				escapeVCard(string(sa.GetCoinType())), label, escapeVCard(string(sa.GetValue())))
+		}
+		fmt.Fprint(bw, "END:VCARD\r\n")
+	}
+	return bw.Flush()
+}
+
+// readContactsVCard read the formatted name and crypto addresses of every card , other properties are ignored.
+func readContactsVCard(r io.Reader) ([]importedContact, error) {
+	lines, err := unfoldVCard(r)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]importedContact, 0)
+	var card *importedContact
+	for _, l := range lines {
+		params, value, ok := splitVCardLine(l.text)
+		if !ok {
+			continue
+		}
+		// Drop group prefixes as in item1.X-CRYPTO-ADDRESS
+		name := strings.ToUpper(params[0][strings.LastIndex(params[0], ".")+1:])
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			card = &importedContact{line: l.number}
+		case card == nil:
+			continue
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			entries = append(entries, *card)
+			card = nil
+		case name == "FN":
+			card.name = strings.TrimSpace(unescapeVCard(value))
+		case name == "CATEGORIES":
+			card.groups = append(card.groups, splitVCardList(value)...)
+		case name == "NOTE":
+			card.notes = unescapeVCard(value)
+		case name == VCardFavouriteProperty:
+			card.favourite = strings.EqualFold(value, "TRUE")
+		case name == VCardAddressProperty:
+			var coin, label string
+			for _, p := range params[1:] {
+				kv := strings.SplitN(p, "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+				switch strings.ToUpper(kv[0]) {
+				case "TYPE":
+					coin = unescapeVCard(strings.Trim(kv[1], `"`))
+				case "LABEL":
+					label = strings.Trim(kv[1], `"`)
+				}
+			}
+			addr := newImportedAddress(l.number, coin, unescapeVCard(value))
+			addr.label = label
+			card.addresses = append(card.addresses, addr)
+		}
+	}
+	if card != nil {
+		return nil, errors.New("unterminated vCard")
+	}
+	return entries, nil
+}
+
+type vCardLine struct {
+	number int
+	text   string
+}
+
+// unfoldVCard join continuation lines , numbered after the line they start on.
+func unfoldVCard(r io.Reader) ([]vCardLine, error) {
+	lines := make([]vCardLine, 0)
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lines = append(lines, vCardLine{number: number, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+var vCardEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+func escapeVCard(s string) string {
+	return vCardEscaper.Replace(s)
+}
+
+// splitVCardLine split a content line into its name and parameters , and its value.
+// Separators within quoted parameter values are ignored.
+func splitVCardLine(text string) ([]string, string, bool) {
+	params := make([]string, 0, 2)
+	start, quoted := 0, false
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '"':
+			quoted = !quoted
+		case quoted:
+		case text[i] == ';':
+			params = append(params, text[start:i])
+			start = i + 1
+		case text[i] == ':':
+			return append(params, text[start:i]), text[i+1:], true
+		}
+	}
+	return nil, "", false
+}
+
+// quoteVCardParam quote a parameter value , dropping the characters parameters can not hold.
+func quoteVCardParam(s string) string {
+	return `"` + strings.NewReplacer(`"`, "", "\r", "", "\n", " ").Replace(s) + `"`
+}
+
+// splitVCardList split a comma separated value , honouring escaped commas.
+func splitVCardList(s string) []string {
+	items := make([]string, 0)
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			items = append(items, unescapeVCard(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, unescapeVCard(s[start:]))
+}
+
+func unescapeVCard(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
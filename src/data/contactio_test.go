@@ -0,0 +1,203 @@
+package data
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	importAddr1 = "2DpeofcsamDfanrRz34qjYvskRzKqzNKMcj"
+	importAddr2 = "25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ"
+	importAddr3 = "2TFC2Ktc6Y3UAUqo7WGA55X6mqoKZRaFp9s"
+)
+
+// contactSummary lists contacts as name: addresses , sorted by name
+func contactSummary(t *testing.T, ab core.AddressBook) []string {
+	contacts, err := ab.ListContact()
+	require.NoError(t, err)
+	summary := make([]string, 0, len(contacts))
+	for _, c := range contacts {
+		addrs := make([]string, 0)
+		for _, sa := range c.GetAddresses() {
+			addrs = append(addrs, string(sa.GetCoinType())+"/"+string(sa.GetValue()))
+		}
+		summary = append(summary, c.GetName()+": "+strings.Join(addrs, " "))
+	}
+	sort.Strings(summary)
+	return summary
+}
+
+func TestImportContacts_CSV(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+	_, err := ab.InsertContact(&Contact{
+		Name:    []byte("Alice"),
+		Address: []Address{{Value: []byte(importAddr1), Coin: []byte("SKY")}},
+	})
+	require.NoError(t, err)
+
+	csvData := "Name,Coin,Address\n" +
+		"Bob,sky," + importAddr2 + "\n" +
+		"Bob,SKY,not-an-address\n" +
+		"Carol,SKY," + importAddr1 + "\n" +
+		"Dave,SKY\n" +
+		"Alice,SKY," + importAddr3 + "\n"
+	report, err := ImportContacts(ab, strings.NewReader(csvData), ContactFormatCSV, ImportSkip, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Imported)
+	require.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Rejected, 3)
+	lines := []int{report.Rejected[0].Line, report.Rejected[1].Line, report.Rejected[2].Line}
+	require.Equal(t, []int{3, 4, 5}, lines)
+	require.Equal(t, "Carol", report.Rejected[1].Name)
+	require.Equal(t, errMalformedContactRow, report.Rejected[2].Err)
+	require.Equal(t, []string{
+		"Alice: SKY/" + importAddr1,
+		"Bob: SKY/" + importAddr2,
+	}, contactSummary(t, ab))
+
+	report, err = ImportContacts(ab, strings.NewReader("Alice,SKY,"+importAddr3+"\n"), ContactFormatCSV, ImportMerge, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Merged)
+	require.Equal(t, "Alice: SKY/"+importAddr1+" SKY/"+importAddr3, contactSummary(t, ab)[0])
+
+	// Addresses of the overwritten contact itself are not reported as taken
+	report, err = ImportContacts(ab, strings.NewReader("Alice,SKY,"+importAddr3+"\n"), ContactFormatCSV, ImportOverwrite, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Overwritten)
+	require.Empty(t, report.Rejected)
+	require.Equal(t, "Alice: SKY/"+importAddr3, contactSummary(t, ab)[0])
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportContacts(ab, &buf, ContactFormatCSV, ""))
+	require.Equal(t, "name,coin,address\nAlice,SKY,"+importAddr3+"\nBob,SKY,"+importAddr2+"\n", buf.String())
+}
+
+func TestImportContacts_RoundTrip(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	withKDFParams(t, 1<<10, 8, 1, func() {
+		src := InitAddrsBook(t)
+		defer CloseTest(t, src)
+		_, err := src.InsertContact(&Contact{
+			Name: []byte("Smith, John; Jr."),
+			Address: []Address{
+				{Value: []byte(importAddr1), Coin: []byte("SKY"), Label: `exchange "deposit": main; spot`},
+				{Value: []byte(importAddr2), Coin: []byte("SKY")},
+			},
+			Groups:    []string{"Family", "Work, remote"},
+			Favourite: true,
+			Notes:     "Met at the conference;\nprefers SKY",
+		})
+		require.NoError(t, err)
+		_, err = src.InsertContact(&Contact{Name: []byte("Nobody")})
+		require.NoError(t, err)
+		want := contactSummary(t, src)
+
+		for _, format := range []string{ContactFormatBackup, ContactFormatCSV, ContactFormatVCard} {
+			t.Run(format, func(t *testing.T) {
+				var buf bytes.Buffer
+				require.NoError(t, ExportContacts(src, &buf, format, "backup-pass"))
+				dst := InitAddrsBook(t)
+				defer CloseTest(t, dst)
+				if format == ContactFormatBackup {
+					require.False(t, bytes.Contains(buf.Bytes(), []byte(importAddr1)))
+					_, err := ImportContacts(dst, bytes.NewReader(buf.Bytes()), format, ImportMerge, "wrong")
+					require.Equal(t, errWrongPassword, err)
+				}
+				report, err := ImportContacts(dst, &buf, format, ImportMerge, "backup-pass")
+				require.NoError(t, err)
+				require.Empty(t, report.Rejected)
+				if format == ContactFormatCSV {
+					// CSV rows carry addresses , contacts without any are not exported
+					require.Equal(t, want[1:], contactSummary(t, dst))
+					return
+				}
+				require.Equal(t, want, contactSummary(t, dst))
+				contacts, err := dst.FindContactsByPrefix("smith")
+				require.NoError(t, err)
+				require.Len(t, contacts, 1)
+				require.Equal(t, []string{"Family", "Work, remote"}, contacts[0].GetGroups())
+				require.True(t, contacts[0].IsFavourite())
+				require.Equal(t, "Met at the conference;\nprefers SKY", contacts[0].GetNotes())
+				labels := make(map[string]string)
+				for _, sa := range contacts[0].GetAddresses() {
+					labels[string(sa.GetValue())] = sa.GetLabel()
+				}
+				wantLabel := `exchange "deposit": main; spot`
+				if format == ContactFormatVCard {
+					// Parameters can not hold quotes
+					wantLabel = `exchange deposit: main; spot`
+				}
+				require.Equal(t, map[string]string{importAddr1: wantLabel, importAddr2: ""}, labels)
+			})
+		}
+	})
+}
+
+func TestImportContacts_VCard(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+	vcf := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"N:Doe;Jane;;;\r\n" +
+		"FN:Jane\r\n" +
+		"  Doe\r\n" +
+		"TEL;TYPE=CELL:+1 555 0100\r\n" +
+		"item1.X-CRYPTO-ADDRESS;TYPE=sky:" + importAddr1 + "\r\n" +
+		"X-CRYPTO-ADDRESS;TYPE=BTC:" + importAddr2 + "\r\n" +
+		"END:VCARD\r\n"
+	report, err := ImportContacts(ab, strings.NewReader(vcf), ContactFormatVCard, ImportMerge, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Imported)
+	require.Len(t, report.Rejected, 1)
+	require.Equal(t, 8, report.Rejected[0].Line)
+	require.Equal(t, "BTC", report.Rejected[0].Coin)
+	require.Equal(t, []string{"Jane Doe: SKY/" + importAddr1}, contactSummary(t, ab))
+
+	_, err = ImportContacts(ab, strings.NewReader("BEGIN:VCARD\r\nFN:Open\r\n"), ContactFormatVCard, ImportMerge, "")
+	require.Error(t, err)
+	_, err = ImportContacts(ab, strings.NewReader(""), "xml", ImportMerge, "")
+	require.Equal(t, errUnknownContactFormat, err)
+}
+
+func TestImportContacts_MergeDetails(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+	id, err := ab.InsertContact(&Contact{
+		Name:    []byte("Alice"),
+		Address: []Address{{Value: []byte(importAddr1), Coin: []byte("SKY"), Label: "cold storage"}},
+		Groups:  []string{"Friends"},
+		Notes:   "stored",
+	})
+	require.NoError(t, err)
+	vcf := "BEGIN:VCARD\r\nFN:Alice\r\nCATEGORIES:friends,Work\r\nNOTE:imported\r\nX-FAVOURITE:TRUE\r\n" +
+		"X-CRYPTO-ADDRESS;TYPE=SKY;LABEL=hot:" + importAddr1 + "\r\nEND:VCARD\r\n"
+	report, err := ImportContacts(ab, strings.NewReader(vcf), ContactFormatVCard, ImportMerge, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Merged)
+	contact, err := ab.GetContact(id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Friends", "Work"}, contact.GetGroups())
+	require.True(t, contact.IsFavourite())
+	require.Equal(t, "stored", contact.GetNotes())
+	require.Equal(t, "cold storage", contact.GetAddresses()[0].GetLabel())
+
+	report, err = ImportContacts(ab, strings.NewReader(vcf), ContactFormatVCard, ImportOverwrite, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Overwritten)
+	contact, err = ab.GetContact(id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"friends", "Work"}, contact.GetGroups())
+	require.Equal(t, "imported", contact.GetNotes())
+	require.Equal(t, "hot", contact.GetAddresses()[0].GetLabel())
+}
@@ -0,0 +1,118 @@
+package data
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+)
+
+// ContactResolver turns contacts of an address book into addresses of a coin , and back.
+// Stored addresses are validated by the plugin bound to their coin , invalid ones are ignored.
+type ContactResolver struct {
+	book core.AddressBook
+}
+
+// NewContactResolver create a resolver looking up contacts of an address book.
+func NewContactResolver(book core.AddressBook) *ContactResolver {
+	return &ContactResolver{book: book}
+}
+
+// FindContact looks up a contact by ID or name , names match ignoring case and surrounding spaces.
+func (r *ContactResolver) FindContact(nameOrID string) (core.Contact, error) {
+	key := strings.TrimSpace(nameOrID)
+	if id, err := strconv.ParseUint(key, 10, 64); err == nil {
+		if c, err := r.book.GetContact(id); err == nil {
+			return c, nil
+		}
+	}
+	contacts, err := r.book.FindContactsByPrefix(key)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contacts {
+		if strings.EqualFold(strings.TrimSpace(c.GetName()), key) {
+			return c, nil
+		}
+	}
+	return nil, errors.ErrContactNotFound
+}
+
+// ContactAddresses lists addresses of a contact for the coin represented by ticker.
+func (r *ContactResolver) ContactAddresses(nameOrID, ticker string) ([]core.Address, error) {
+	contact, err := r.FindContact(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	addrs := coinAddresses(contact, ticker)
+	if len(addrs) == 0 {
+		return nil, errors.ErrContactNoAddress
+	}
+	return addrs, nil
+}
+
+// Resolve returns the address to pay a contact in the coin represented by ticker.
+// ErrAmbiguousContact is returned if the contact has several addresses for that coin ,
+// then the caller should choose one of those listed by ContactAddresses.
+func (r *ContactResolver) Resolve(nameOrID, ticker string) (core.Address, error) {
+	addrs, err := r.ContactAddresses(nameOrID, ticker)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) > 1 {
+		return nil, errors.ErrAmbiguousContact
+	}
+	return addrs[0], nil
+}
+
+// ContactNames maps addresses of the coin represented by ticker to the name of the contact owning them.
+func (r *ContactResolver) ContactNames(ticker string) (map[string]string, error) {
+	contacts, err := r.book.ListContactsByCoin(ticker)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for _, c := range contacts {
+		for _, addr := range coinAddresses(c, ticker) {
+			names[addr.String()] = c.GetName()
+		}
+	}
+	return names, nil
+}
+
+// ContactName returns the name of the contact owning an address of the coin represented by ticker ,
+// looked up in the contact index. It is empty if no contact owns the address.
+func (r *ContactResolver) ContactName(address, ticker string) (string, error) {
+	contact, err := r.book.LookupAddress(address, ticker)
+	if err == errors.ErrContactNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return contact.GetName(), nil
+}
+
+// LookupAddress returns the contact owning an address of the coin represented by ticker.
+func (r *ContactResolver) LookupAddress(address, ticker string) (core.Contact, error) {
+	return r.book.LookupAddress(address, ticker)
+}
+
+// coinAddresses returns the valid addresses of a contact for the coin represented by ticker.
+func coinAddresses(contact core.Contact, ticker string) []core.Address {
+	addrs := make([]core.Address, 0)
+	for _, sa := range contact.GetAddresses() {
+		if !strings.EqualFold(string(sa.GetCoinType()), ticker) {
+			continue
+		}
+		addr, err := util.AddressFromString(string(sa.GetValue()), ticker)
+		if err != nil {
+			logDb.WithError(err).Warn("Ignoring invalid contact address")
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
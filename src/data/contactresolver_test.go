@@ -0,0 +1,69 @@
+package data
+
+import (
+	"strconv"
+	"testing"
+
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactResolver(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+	aliceID, err := ab.InsertContact(&Contact{
+		Name:    []byte("Alice"),
+		Address: []Address{{Value: []byte("2DpeofcsamDfanrRz34qjYvskRzKqzNKMcj"), Coin: []byte("SKY")}},
+	})
+	require.NoError(t, err)
+	_, err = ab.InsertContact(&Contact{
+		Name: []byte("Bob"),
+		Address: []Address{
+			{Value: []byte("25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ"), Coin: []byte("SKY")},
+			{Value: []byte("2TFC2Ktc6Y3UAUqo7WGA55X6mqoKZRaFp9s"), Coin: []byte("SKY")},
+		},
+	})
+	require.NoError(t, err)
+	r := NewContactResolver(ab)
+
+	addr, err := r.Resolve(" alice ", "SKY")
+	require.NoError(t, err)
+	require.Equal(t, "2DpeofcsamDfanrRz34qjYvskRzKqzNKMcj", addr.String())
+	addr, err = r.Resolve(strconv.FormatUint(aliceID, 10), "SKY")
+	require.NoError(t, err)
+	require.Equal(t, "2DpeofcsamDfanrRz34qjYvskRzKqzNKMcj", addr.String())
+
+	_, err = r.Resolve("Bob", "SKY")
+	require.Equal(t, errors.ErrAmbiguousContact, err)
+	addrs, err := r.ContactAddresses("Bob", "SKY")
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+	require.Equal(t, "25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ", addrs[0].String())
+
+	_, err = r.Resolve("Carol", "SKY")
+	require.Equal(t, errors.ErrContactNotFound, err)
+	_, err = r.Resolve("Alice", "BTC")
+	require.Equal(t, errors.ErrContactNoAddress, err)
+
+	names, err := r.ContactNames("SKY")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"2DpeofcsamDfanrRz34qjYvskRzKqzNKMcj": "Alice",
+		"25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ": "Bob",
+		"2TFC2Ktc6Y3UAUqo7WGA55X6mqoKZRaFp9s": "Bob",
+	}, names)
+	contact, err := r.LookupAddress("2TFC2Ktc6Y3UAUqo7WGA55X6mqoKZRaFp9s", "SKY")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", contact.GetName())
+	_, err = r.LookupAddress("2LRUs2MFEhCpDfSHaNjCtzjz8TJjuTK98s5", "SKY")
+	require.Equal(t, errors.ErrContactNotFound, err)
+	name, err := r.ContactName("25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ", "SKY")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", name)
+	name, err = r.ContactName("2LRUs2MFEhCpDfSHaNjCtzjz8TJjuTK98s5", "SKY")
+	require.NoError(t, err)
+	require.Empty(t, name)
+}
@@ -3,13 +3,19 @@ package data
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"time"
+
 	"github.com/SkycoinProject/skycoin/src/visor/dbutil"
 	"github.com/boltdb/bolt"
-	"time"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
 )
 
+// boltStorage keeps named collections in top level buckets of a bolt database.
 type boltStorage struct {
 	*bolt.DB
+	codecs      map[string]core.StorageCodec
+	codecsMutex sync.RWMutex
 }
 
 const (
@@ -23,9 +29,12 @@ var (
 	errDatabaseNotOpen = errors.New("database not open")
 	errBucketEmpty     = errors.New("database: bucket are empty")
 	errValEmpty        = errors.New(" database: result are empty")
+	// errTxnHistoryUnavailable address history could not be retrieved from the node
+	errTxnHistoryUnavailable = errors.New("transaction history unavailable")
 )
 
 // GetBoltStorage generate a new instance of boltStorage by path.
+// Registered migrations newer than the database schema are applied before returning.
 func GetBoltStorage(path string) (*boltStorage, error) {
 	db, err := bolt.Open(path, 0600,
 		&bolt.Options{
@@ -37,188 +46,156 @@ func GetBoltStorage(path string) (*boltStorage, error) {
 		return nil, err
 	}
 
-	return &boltStorage{db}, nil
+	b := &boltStorage{DB: db, codecs: make(map[string]core.StorageCodec)}
+	if err := b.migrate(registeredMigrations()); err != nil {
+		logDb.Error(err)
+		if err := db.Close(); err != nil {
+			logDb.Error(err)
+		}
+		return nil, err
+	}
+	return b, nil
 }
 
 // GetConfig Returns the config bucket content.
 func (b *boltStorage) GetConfig() map[string]string {
-	tx, err := b.Begin(false)
+	var confMap map[string]string
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		if !tx.HasCollection(dbConfigBkt) {
+			return nil
+		}
+		confMap = make(map[string]string, 0)
+		return tx.Scan(dbConfigBkt, nil, nil, func(item core.StorageItem) (bool, error) {
+			var v []byte
+			if err := item.Decode(&v); err != nil {
+				return false, err
+			}
+			confMap[string(item.Key())] = string(v)
+			return true, nil
+		})
+	})
 	if err != nil {
 		logDb.Error(err)
 		return nil
 	}
-	defer func() {
-		if err := tx.Rollback(); err != nil {
-			logDb.Fatal(err)
-		}
-	}()
-
-	if conf := tx.Bucket([]byte(dbConfigBkt)); conf != nil {
-		confMap := make(map[string]string, 0)
-		if err := conf.ForEach(func(k, v []byte) error {
-			confMap[string(k)] = string(v)
-			return nil
-		}); err != nil {
-			logDb.Error(err)
-			return nil
-		}
-		return confMap
-	}
-	return nil
+	return confMap
 }
 
 // InsertConfig set into config bucket the config parameters (securityType, hash, entropy)
 func (b *boltStorage) InsertConfig(options map[string]string) error {
-	// Start a writeable transaction.
-	tx, err := b.Begin(true)
-	if err != nil {
-		logDb.Error(err)
-		return err
-	}
-
-	defer func() {
-		if err := tx.Rollback(); err != nil && err != bolt.ErrTxClosed {
-			// Start a writeable transaction.
-			logDb.Fatal(err)
+	err := b.UpdateCollections(func(tx core.StorageTxn) error {
+		for k, v := range options {
+			if err := tx.Put(dbConfigBkt, []byte(k), []byte(v)); err != nil {
+				return err
+			}
 		}
-	}()
-
-	bkt, err := tx.CreateBucketIfNotExists([]byte(dbConfigBkt))
+		return nil
+	})
 	if err != nil {
 		logDb.Error(err)
-		return err
-	}
-	for k, v := range options {
-		if err := bkt.Put([]byte(k), []byte(v)); err != nil {
-			logDb.Error(err)
-			return err
-		}
 	}
-	return tx.Commit()
+	return err
 }
 
 // InsertValue insert a value in AddressBook bucket.
 func (b *boltStorage) InsertValue(value interface{}) (uint64, error) {
-	// Start a writeable transaction.
-	tx, err := b.Begin(true)
-	if err != nil {
-		logDb.Error(err)
-		return 0, err
-	}
 	element, ok := value.([]byte)
 	if !ok {
 		err := errValueNoMatch(element, []byte{})
 		logDb.Error(err)
 		return 0, err
 	}
-	defer func() {
-		if err := tx.Rollback(); err != nil && err != bolt.ErrTxClosed {
-			logDb.Fatal(err)
+	var id uint64
+	err := b.UpdateCollections(func(tx core.StorageTxn) error {
+		// The sequence is an auto-incrementing integer that is transactionally safe.
+		var err error
+		if id, err = tx.NextSequence(dbAddrsBookBkt); err != nil {
+			return err
 		}
-	}()
-
-	bkt, err := tx.CreateBucketIfNotExists([]byte(dbAddrsBookBkt))
-	if err != nil {
-		logDb.Fatal(err)
-		return 0, err
-	}
-	// The sequence is an auto-incrementing integer that is transactionally safe.
-	id, err := bkt.NextSequence()
-
+		return tx.Put(dbAddrsBookBkt, dbutil.Itob(id), element)
+	})
 	if err != nil {
-		logDb.Fatal(err)
-		return 0, err
-	}
-
-	if err := bkt.Put(dbutil.Itob(id), element); err != nil {
-		logDb.Fatal(err)
+		logDb.Error(err)
 		return 0, err
 	}
-	return id, tx.Commit()
+	return id, nil
 }
 
 // GetValue get a value from the AddressBook bucket.
 func (b *boltStorage) GetValue(key uint64) (interface{}, error) {
-	tx, err := b.Begin(false)
-	if err != nil {
-		logDb.Error(err)
-		return nil, err
-	}
-	defer func() {
-		if err := tx.Rollback(); err != nil {
-			logDb.Fatal(err)
+	var result []byte
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		if !tx.HasCollection(dbAddrsBookBkt) {
+			return errBucketEmpty
 		}
-	}()
-	if addrsBookBkt := tx.Bucket([]byte(dbAddrsBookBkt)); addrsBookBkt != nil {
-		result := addrsBookBkt.Get(dbutil.Itob(key))
-		if result == nil {
-			return nil, errValEmpty
+		found, err := tx.Get(dbAddrsBookBkt, dbutil.Itob(key), &result)
+		if err == nil && !found {
+			return errValEmpty
 		}
-		return result, nil
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return nil, errBucketEmpty
+	return result, nil
 }
 
 // ListValues returns all values from AddressBook bucket.
 func (b *boltStorage) ListValues() (map[uint64]interface{}, error) {
-	tx, err := b.Begin(false)
-	if err != nil {
-		logDb.Error(err)
-		return nil, err
-	}
-	defer func() {
-		if err := tx.Rollback(); err != nil {
-			logDb.Fatal(err)
+	resultsMap := make(map[uint64]interface{}, 0)
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		if !tx.HasCollection(dbAddrsBookBkt) {
+			return errBucketEmpty
 		}
-	}()
-
-	if addrsBookBkt := tx.Bucket([]byte(dbAddrsBookBkt)); addrsBookBkt != nil {
-		resultsMap := make(map[uint64]interface{}, 0)
-		if err := addrsBookBkt.ForEach(func(k, v []byte) error {
-			resultsMap[dbutil.Btoi(k)] = v
-			return nil
-		}); err != nil {
+		return tx.Scan(dbAddrsBookBkt, nil, nil, func(item core.StorageItem) (bool, error) {
+			var v []byte
+			if err := item.Decode(&v); err != nil {
+				return false, err
+			}
+			resultsMap[dbutil.Btoi(item.Key())] = v
+			return true, nil
+		})
+	})
+	if err != nil {
+		if err != errBucketEmpty {
 			logDb.Error(err)
-			return nil, err
 		}
-		return resultsMap, nil
+		return nil, err
 	}
-	return nil, errBucketEmpty
+	return resultsMap, nil
 }
 
 // DeleteValue remove a value from the AddressBook bucket by its id.
 func (b *boltStorage) DeleteValue(key uint64) error {
-	return b.Update(func(tx *bolt.Tx) error {
-		bkt := tx.Bucket([]byte(dbAddrsBookBkt))
-		if bkt == nil {
+	return b.UpdateCollections(func(tx core.StorageTxn) error {
+		if !tx.HasCollection(dbAddrsBookBkt) {
 			return errBucketEmpty
 		}
-
-		if val := bkt.Get(dbutil.Itob(key)); val == nil {
-			return errValEmpty
+		var val []byte
+		if found, err := tx.Get(dbAddrsBookBkt, dbutil.Itob(key), &val); err != nil || !found {
+			if err == nil {
+				err = errValEmpty
+			}
+			return err
 		}
-
-		return bkt.Delete(dbutil.Itob(key))
+		return tx.Delete(dbAddrsBookBkt, dbutil.Itob(key))
 	})
-
 }
 
 // UpdateValue update a element into the AddressBook bucket by its id.
 func (b *boltStorage) UpdateValue(key uint64, newVal interface{}) error {
-	return b.Update(func(tx *bolt.Tx) error {
-		bkt := tx.Bucket([]byte(dbAddrsBookBkt))
-		if bkt == nil {
+	element, ok := newVal.([]byte)
+	if !ok {
+		err := errValueNoMatch(element, []byte{})
+		logDb.Error(err)
+		return err
+	}
+	return b.UpdateCollections(func(tx core.StorageTxn) error {
+		if !tx.HasCollection(dbAddrsBookBkt) {
 			logDb.Error(errBucketEmpty)
 			return errBucketEmpty
 		}
-		element, ok := newVal.([]byte)
-		if !ok {
-			err := errValueNoMatch(element, []byte{})
-			logDb.Error(err)
-			return err
-		}
-		return bkt.Put(dbutil.Itob(key), newVal.([]byte))
+		return tx.Put(dbAddrsBookBkt, dbutil.Itob(key), element)
 	})
 }
 
@@ -0,0 +1,209 @@
+package data
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/SkycoinProject/skycoin/src/cipher/scrypt"
+	fcParams "github.com/fibercrypto/fibercryptowallet/src/params"
+)
+
+const (
+	// KDF key derivation settings of PasswordSecurity , including the salt
+	KDF = "kdf"
+	// Check known value sealed with the password key , used to verify passwords
+	Check = "check"
+	// PrevKDF key derivation settings in use before the last security change
+	PrevKDF = "prevKdf"
+	// PrevCheck password check in use before the last security change
+	PrevCheck = "prevCheck"
+
+	// KDFScrypt scrypt key derivation function
+	KDFScrypt = "scrypt"
+	// CipherAES256GCM AES-256 in Galois/Counter Mode
+	CipherAES256GCM = "aes-256-gcm"
+
+	// envelopeVersion version of the envelope format written by this package
+	envelopeVersion = 1
+	// envelopeKeyLen length of the keys sealing envelopes
+	envelopeKeyLen = 32
+	// envelopeSaltLen length of the salts of new keys
+	envelopeSaltLen = 32
+	// maxScryptN highest scrypt cost accepted , bounds the work an envelope may demand
+	maxScryptN = 1 << 22
+	// maxScryptR highest scrypt block size accepted
+	maxScryptR = 32
+	// maxScryptP highest scrypt parallelization accepted
+	maxScryptP = 16
+	// maxScryptMemory most memory in bytes scrypt may use to derive a key
+	maxScryptMemory = 1 << 30
+)
+
+var (
+	// Errors
+	errInvalidKDFParams  = errors.New("invalid key derivation parameters")
+	errUnsupportedKDF    = errors.New("unsupported key derivation function")
+	errUnsupportedCipher = errors.New("unsupported envelope cipher")
+	errInvalidEnvelope   = errors.New("invalid envelope")
+	errWrongPassword     = errors.New("wrong password")
+
+	// envelopeMagic prefix telling envelopes apart from records written in the legacy format
+	envelopeMagic = []byte("FCWE")
+	// checkPlaintext value sealed in the password check
+	checkPlaintext = []byte("fibercryptowallet address book")
+
+	// defaultKDF settings deriving keys from new passwords
+	defaultKDF = KDFParams{Name: KDFScrypt, N: fcParams.AddrsBookScryptN, R: fcParams.AddrsBookScryptR, P: fcParams.AddrsBookScryptP}
+	// kdfMutex guards defaultKDF
+	kdfMutex sync.RWMutex
+)
+
+// KDFParams key derivation function and cost parameters of a password key.
+type KDFParams struct {
+	Name string `json:"name"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt,omitempty"`
+}
+
+// SetKDFParams set the scrypt cost of keys derived from new passwords. Password protected
+// address books derived with a lower cost are upgraded on their next successful authentication.
+func SetKDFParams(n, r, p int) error {
+	kdf := KDFParams{Name: KDFScrypt, N: n, R: r, P: p}
+	if err := kdf.validate(); err != nil {
+		return err
+	}
+	kdfMutex.Lock()
+	defer kdfMutex.Unlock()
+	defaultKDF = kdf
+	return nil
+}
+
+// GetKDFParams return the scrypt cost of keys derived from new passwords.
+func GetKDFParams() (n, r, p int) {
+	kdf := currentKDF()
+	return kdf.N, kdf.R, kdf.P
+}
+
+// currentKDF return the settings deriving keys from new passwords.
+func currentKDF() KDFParams {
+	kdfMutex.RLock()
+	defer kdfMutex.RUnlock()
+	return defaultKDF
+}
+
+// validate fail unless params can be used to derive a key.
+func (params KDFParams) validate() error {
+	if params.Name != KDFScrypt {
+		return errUnsupportedKDF
+	}
+	if params.N <= 1 || params.N > maxScryptN || params.N&(params.N-1) != 0 ||
+		params.R <= 0 || params.R > maxScryptR || params.P <= 0 || params.P > maxScryptP {
+		return errInvalidKDFParams
+	}
+	// scrypt allocates 128 * r * (N + p) bytes
+	if 128*uint64(params.R)*(uint64(params.N)+uint64(params.P)) > maxScryptMemory {
+		return errInvalidKDFParams
+	}
+	return nil
+}
+
+// deriveKey derive the key of a password.
+func (params KDFParams) deriveKey(password []byte) ([]byte, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return scrypt.Key(password, params.Salt, params.N, params.R, params.P, envelopeKeyLen)
+}
+
+// weakerThan return true if keys derived with params are cheaper to brute force than with other.
+func (params KDFParams) weakerThan(other KDFParams) bool {
+	return params.Name != other.Name || params.N < other.N || params.R < other.R || params.P < other.P
+}
+
+// id identify the key derived with params.
+func (params KDFParams) id() string {
+	return params.Name + ":" + strconv.Itoa(params.N) + ":" + strconv.Itoa(params.R) + ":" +
+		strconv.Itoa(params.P) + ":" + string(params.Salt)
+}
+
+// newKDFParams return the default key derivation settings with a fresh salt.
+func newKDFParams() (KDFParams, error) {
+	params := currentKDF()
+	params.Salt = make([]byte, envelopeSaltLen)
+	if _, err := io.ReadFull(rand.Reader, params.Salt); err != nil {
+		return KDFParams{}, err
+	}
+	return params, nil
+}
+
+// parseKDFParams decode key derivation settings stored in the config.
+func parseKDFParams(value string) (*KDFParams, error) {
+	var params KDFParams
+	if err := json.Unmarshal([]byte(value), &params); err != nil {
+		return nil, err
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+// envelope versioned container of encrypted data , recording how its key was derived.
+type envelope struct {
+	Version int       `json:"v"`
+	KDF     KDFParams `json:"kdf"`
+	Cipher  string    `json:"cipher"`
+	// Data nonce followed by the sealed data
+	Data []byte `json:"data"`
+}
+
+// isEnvelope return true if msg was written in the envelope format.
+func isEnvelope(msg []byte) bool {
+	return bytes.HasPrefix(msg, envelopeMagic)
+}
+
+// sealEnvelope encrypt data with a key derived by params.
+func sealEnvelope(key []byte, params KDFParams, data []byte) ([]byte, error) {
+	sealed, err := encryptAESGCM(key, data)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := json.Marshal(envelope{
+		Version: envelopeVersion,
+		KDF:     params,
+		Cipher:  CipherAES256GCM,
+		Data:    sealed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), envelopeMagic...), msg...), nil
+}
+
+// parseEnvelope decode an envelope , failing on versions and ciphers this package can not open.
+func parseEnvelope(msg []byte) (*envelope, error) {
+	if !isEnvelope(msg) {
+		return nil, errInvalidEnvelope
+	}
+	var env envelope
+	if err := json.Unmarshal(msg[len(envelopeMagic):], &env); err != nil {
+		return nil, errInvalidEnvelope
+	}
+	if env.Version != envelopeVersion {
+		return nil, errInvalidEnvelope
+	}
+	if env.Cipher != CipherAES256GCM {
+		return nil, errUnsupportedCipher
+	}
+	if err := env.KDF.validate(); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
@@ -0,0 +1,182 @@
+package data
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/SkycoinProject/skycoin/src/visor/dbutil"
+	"github.com/boltdb/bolt"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// legacyEntropy salt of the legacy key in tests
+const legacyEntropy = "legacy-entropy-0"
+
+// openLegacyBook create an address book in the format written before envelopes , protected with defaultPass
+func openLegacyBook(t *testing.T) (core.AddressBook, *bookCipher) {
+	db, err := GetBoltStorage(GetFilePath(t))
+	require.NoError(t, err)
+	hash, err := bcrypt.GenerateFromPassword([]byte(defaultPass), bcrypt.MinCost)
+	require.NoError(t, err)
+	require.NoError(t, db.InsertConfig(map[string]string{
+		SecurityType: strconv.Itoa(PasswordSecurity),
+		Hash:         string(hash),
+		Entropy:      legacyEntropy,
+	}))
+	legacy, err := newBookCipher(PasswordSecurity, []byte(defaultPass), []byte(legacyEntropy))
+	require.NoError(t, err)
+	return NewAddressBook(db), legacy
+}
+
+// withKDFParams run f with the scrypt cost of new keys set to n , r , p
+func withKDFParams(t *testing.T, n, r, p int, f func()) {
+	oldN, oldR, oldP := GetKDFParams()
+	require.NoError(t, SetKDFParams(n, r, p))
+	defer func() {
+		require.NoError(t, SetKDFParams(oldN, oldR, oldP))
+	}()
+	f()
+}
+
+func TestBookCipher_LegacyFormat(t *testing.T) {
+	// Sealed by the PBKDF2 and AES-GCM scheme used before envelopes
+	msg, err := hex.DecodeString("051b6e196f24be94c0293065cac7f58fa4bac76a2bd707fdac02d4b6af9cfb6df56eb688026c2ec8425081430dca15ea405d74382f2d")
+	require.NoError(t, err)
+	legacy, err := newBookCipher(PasswordSecurity, []byte(defaultPass), []byte(legacyEntropy))
+	require.NoError(t, err)
+	data, err := legacy.decrypt(msg)
+	require.NoError(t, err)
+	require.Equal(t, "legacy address book record", string(data))
+
+	wrong, err := newBookCipher(PasswordSecurity, []byte("wrong"), []byte(legacyEntropy))
+	require.NoError(t, err)
+	_, err = wrong.decrypt(msg)
+	require.Error(t, err)
+
+	// Envelope ciphers do not accept legacy messages
+	kdf, err := newKDFParams()
+	require.NoError(t, err)
+	_, err = newEnvelopeCipher([]byte(defaultPass), kdf, nil).decrypt(msg)
+	require.Equal(t, errInvalidEnvelope, err)
+}
+
+func TestBookCipher_Envelope(t *testing.T) {
+	withKDFParams(t, 1<<10, 8, 1, func() {
+		kdf, err := newKDFParams()
+		require.NoError(t, err)
+		c := newEnvelopeCipher([]byte(defaultPass), kdf, nil)
+		msg, err := c.encrypt([]byte("record"))
+		require.NoError(t, err)
+		require.True(t, isEnvelope(msg))
+		env, err := parseEnvelope(msg)
+		require.NoError(t, err)
+		require.Equal(t, envelopeVersion, env.Version)
+		require.Equal(t, CipherAES256GCM, env.Cipher)
+		require.Equal(t, kdf, env.KDF)
+
+		// Envelopes are opened with the key derivation they record
+		other, err := newKDFParams()
+		require.NoError(t, err)
+		data, err := newEnvelopeCipher([]byte(defaultPass), other, nil).decrypt(msg)
+		require.NoError(t, err)
+		require.Equal(t, "record", string(data))
+		_, err = newEnvelopeCipher([]byte("wrong"), kdf, nil).decrypt(msg)
+		require.Error(t, err)
+
+		env.Cipher = "rot13"
+		tampered, err := json.Marshal(env)
+		require.NoError(t, err)
+		_, err = c.decrypt(append([]byte("FCWE"), tampered...))
+		require.Equal(t, errUnsupportedCipher, err)
+	})
+	require.Equal(t, errInvalidKDFParams, SetKDFParams(1000, 8, 1))
+	require.Equal(t, errInvalidKDFParams, SetKDFParams(1<<10, 0, 1))
+}
+
+func TestKDFParams_Limits(t *testing.T) {
+	// Costs demanding too much work or memory are rejected
+	require.Equal(t, errInvalidKDFParams, SetKDFParams(1<<10, maxScryptR+1, 1))
+	require.Equal(t, errInvalidKDFParams, SetKDFParams(1<<10, 8, maxScryptP+1))
+	require.Equal(t, errInvalidKDFParams, SetKDFParams(maxScryptN, 8, 1))
+	n, r, p := GetKDFParams()
+	require.Equal(t, []int{1 << 15, 8, 1}, []int{n, r, p})
+
+	// Envelopes recording such costs are not opened
+	_, err := parseKDFParams(`{"name":"scrypt","n":1024,"r":1048576,"p":1}`)
+	require.Equal(t, errInvalidKDFParams, err)
+	_, err = parseKDFParams(`{"name":"scrypt","n":1024,"r":8,"p":1073741823}`)
+	require.Equal(t, errInvalidKDFParams, err)
+	kdf, err := parseKDFParams(`{"name":"scrypt","n":524288,"r":8,"p":16}`)
+	require.NoError(t, err)
+	require.Equal(t, 1<<19, kdf.N)
+}
+
+func TestAuthenticate_UpgradesLegacyBook(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	withKDFParams(t, 1<<10, 8, 1, func() {
+		ab, legacy := openLegacyBook(t)
+		defer CloseTest(t, ab)
+		id := putRawContact(t, ab, legacy, &Contact{
+			Address: []Address{{Value: []byte(foreignAddr), Coin: []byte("SKY")}},
+			Name:    []byte("contact_test1"),
+		})
+		label := core.Label{Type: core.LabelTypeAddress, Ref: foreignAddr, Label: "Shop"}
+		key, err := legacy.labelKey(label.Type, label.Ref)
+		require.NoError(t, err)
+		data, err := json.Marshal(label)
+		require.NoError(t, err)
+		value, err := legacy.encrypt(data)
+		require.NoError(t, err)
+		db := ab.GetStorage().(*boltStorage)
+		require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+			bkt, err := tx.CreateBucketIfNotExists([]byte(dbLabelsBkt))
+			if err != nil {
+				return err
+			}
+			return bkt.Put(key, value)
+		}))
+
+		require.Error(t, ab.Authenticate("wrong"))
+		require.Empty(t, ab.GetStorage().GetConfig()[KDF])
+		require.NoError(t, ab.Authenticate(defaultPass))
+		config := ab.GetStorage().GetConfig()
+		require.NotEmpty(t, config[KDF])
+		require.NotEmpty(t, config[Check])
+		require.Empty(t, config[Hash])
+		require.Equal(t, legacyEntropy, config[PrevEntropy])
+
+		var raw []byte
+		require.NoError(t, db.View(func(tx *bolt.Tx) error {
+			raw = append(raw, tx.Bucket([]byte(dbAddrsBookBkt)).Get(dbutil.Itob(id))...)
+			return nil
+		}))
+		require.True(t, isEnvelope(raw))
+		contact, err := ab.GetContact(id)
+		require.NoError(t, err)
+		require.Equal(t, "contact_test1", contact.GetName())
+		store, err := NewLabelStore(ab)
+		require.NoError(t, err)
+		got, err := store.GetLabel(label.Type, label.Ref)
+		require.NoError(t, err)
+		require.Equal(t, &label, got)
+
+		// Raising the cost upgrades the book on the next authentication
+		withKDFParams(t, 1<<11, 8, 1, func() {
+			require.NoError(t, ab.Authenticate(defaultPass))
+		})
+		kdf, err := parseKDFParams(ab.GetStorage().GetConfig()[KDF])
+		require.NoError(t, err)
+		require.Equal(t, 1<<11, kdf.N)
+		require.Error(t, ab.Authenticate("wrong"))
+		require.NoError(t, ab.Authenticate(defaultPass))
+		contact, err = ab.GetContact(id)
+		require.NoError(t, err)
+		require.Equal(t, "contact_test1", contact.GetName())
+	})
+}
@@ -0,0 +1,304 @@
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+const (
+	// Db buckets.
+	dbLabelsBkt = "Labels"
+)
+
+var (
+	// Errors
+	errLabelStoreLocked      = errors.New("label store is locked , authenticate the address book first")
+	errLabelStoreUnsupported = errors.New("label store requires a bolt address book")
+	errInvalidLabel          = errors.New("invalid label")
+)
+
+// bip329Types label types defined by BIP329
+var bip329Types = map[string]struct{}{
+	core.LabelTypeTxn:     {},
+	core.LabelTypeAddress: {},
+	"pubkey":              {},
+	"input":               {},
+	core.LabelTypeOutput:  {},
+	core.LabelTypeWallet:  {},
+}
+
+// labelStore implements LabelStore on top of the address book database.
+// Labels share the address book security settings , with PasswordSecurity
+// both lookup keys and values are unreadable without the password.
+type labelStore struct {
+	book *addrsBook
+	db   *boltStorage
+}
+
+// NewLabelStore create a label store sharing storage and security with an address book.
+func NewLabelStore(book core.AddressBook) (core.LabelStore, error) {
+	ab, isAddrsBook := book.(*addrsBook)
+	if !isAddrsBook {
+		return nil, errLabelStoreUnsupported
+	}
+	db, isBolt := ab.GetStorage().(*boltStorage)
+	if !isBolt {
+		return nil, errLabelStoreUnsupported
+	}
+	return &labelStore{book: ab, db: db}, nil
+}
+
+// SetLabel creates or replaces the label of an entity.
+func (ls *labelStore) SetLabel(label core.Label) error {
+	if err := validateLabel(label); err != nil {
+		return err
+	}
+	key, value, err := ls.book.encodeLabel(label)
+	if err != nil {
+		logDb.WithError(err).Error("Couldn't encode label")
+		return err
+	}
+	return ls.db.UpdateCollections(func(tx core.StorageTxn) error {
+		return tx.Put(dbLabelsBkt, key, value)
+	})
+}
+
+// GetLabel looks up the label of an entity , nil if it is not labelled.
+func (ls *labelStore) GetLabel(labelType, ref string) (*core.Label, error) {
+	key, err := ls.book.labelKey(labelType, ref)
+	if err != nil {
+		return nil, err
+	}
+	var value []byte
+	err = ls.db.ViewCollections(func(tx core.StorageTxn) error {
+		_, err := tx.Get(dbLabelsBkt, key, &value)
+		return err
+	})
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return ls.book.decodeLabel(value)
+}
+
+// DeleteLabel removes the label of an entity.
+func (ls *labelStore) DeleteLabel(labelType, ref string) error {
+	key, err := ls.book.labelKey(labelType, ref)
+	if err != nil {
+		return err
+	}
+	return ls.db.UpdateCollections(func(tx core.StorageTxn) error {
+		return tx.Delete(dbLabelsBkt, key)
+	})
+}
+
+// ListLabels enumerates labels of a given type sorted by reference , all of them if type is empty.
+func (ls *labelStore) ListLabels(labelType string) ([]core.Label, error) {
+	labels, err := ls.book.listLabels()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]core.Label, 0, len(labels))
+	for _, label := range labels {
+		if labelType == "" || label.Type == labelType {
+			filtered = append(filtered, label)
+		}
+	}
+	return filtered, nil
+}
+
+// ImportLabels reads BIP329 JSONL records and stores them , returning the number of labels imported.
+// Records of an existing entity replace the current label. Nothing is stored if any record is invalid.
+func (ls *labelStore) ImportLabels(r io.Reader) (int, error) {
+	labels := make([]core.Label, 0)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var label core.Label
+		if err := json.Unmarshal([]byte(line), &label); err != nil {
+			return 0, fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		if err := validateLabel(label); err != nil {
+			return 0, fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		labels = append(labels, label)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if err := ls.book.putLabels(labels, false); err != nil {
+		logDb.WithError(err).Error("Couldn't import labels")
+		return 0, err
+	}
+	return len(labels), nil
+}
+
+// ExportLabels writes all labels as BIP329 JSONL records.
+func (ls *labelStore) ExportLabels(w io.Writer) error {
+	labels, err := ls.book.listLabels()
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	for _, label := range labels {
+		if err := encoder.Encode(label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateLabel(label core.Label) error {
+	if _, isKnown := bip329Types[label.Type]; !isKnown {
+		return fmt.Errorf("%s: unknown type %q", errInvalidLabel, label.Type)
+	}
+	if label.Ref == "" {
+		return fmt.Errorf("%s: empty reference", errInvalidLabel)
+	}
+	return nil
+}
+
+// labelStorage return the bolt database holding labels , nil if labels are unsupported.
+func (addrsBook *addrsBook) labelStorage() *boltStorage {
+	db, _ := addrsBook.GetStorage().(*boltStorage)
+	return db
+}
+
+// checkLabelsAccess fail unless labels can be read and written with the current credentials.
+func (addrsBook *addrsBook) checkLabelsAccess() (int, error) {
+	if !addrsBook.HasInit() {
+		return 0, errAddrsBookHasNotInit
+	}
+	secType, err := addrsBook.GetSecType()
+	if err != nil {
+		return 0, err
+	}
+	if secType == PasswordSecurity && addrsBook.key == nil {
+		return 0, errLabelStoreLocked
+	}
+	return secType, nil
+}
+
+// labelKey bucket key of a label. Keys are hashed with PasswordSecurity to hide the labelled entities.
+func (addrsBook *addrsBook) labelKey(labelType, ref string) ([]byte, error) {
+	if _, err := addrsBook.checkLabelsAccess(); err != nil {
+		return nil, err
+	}
+	c, err := addrsBook.cipher()
+	if err != nil {
+		return nil, err
+	}
+	return c.labelKey(labelType, ref)
+}
+
+// encodeLabel return the bucket key and value of a label.
+func (addrsBook *addrsBook) encodeLabel(label core.Label) ([]byte, []byte, error) {
+	key, err := addrsBook.labelKey(label.Type, label.Ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(label)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := addrsBook.encryptData(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+// decodeLabel decrypt a bucket value into a label.
+func (addrsBook *addrsBook) decodeLabel(value []byte) (*core.Label, error) {
+	data, err := addrsBook.decryptData(value)
+	if err != nil {
+		return nil, err
+	}
+	var label core.Label
+	if err := json.Unmarshal(data, &label); err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// listLabels decrypt every stored label , sorted by type and reference.
+func (addrsBook *addrsBook) listLabels() ([]core.Label, error) {
+	if _, err := addrsBook.checkLabelsAccess(); err != nil {
+		return nil, err
+	}
+	labels := make([]core.Label, 0)
+	db := addrsBook.labelStorage()
+	if db == nil {
+		return labels, nil
+	}
+	values := make([][]byte, 0)
+	err := db.ViewCollections(func(tx core.StorageTxn) error {
+		return tx.Scan(dbLabelsBkt, nil, nil, func(item core.StorageItem) (bool, error) {
+			var value []byte
+			if err := item.Decode(&value); err != nil {
+				return false, err
+			}
+			values = append(values, value)
+			return true, nil
+		})
+	})
+	if err != nil {
+		logDb.Error(err)
+		return nil, err
+	}
+	for _, value := range values {
+		label, err := addrsBook.decodeLabel(value)
+		if err != nil {
+			logDb.WithError(err).Error("Couldn't decode label")
+			return nil, err
+		}
+		labels = append(labels, *label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Type != labels[j].Type {
+			return labels[i].Type < labels[j].Type
+		}
+		return labels[i].Ref < labels[j].Ref
+	})
+	return labels, nil
+}
+
+// putLabels store labels in a single transaction , discarding previous labels if replace is set.
+func (addrsBook *addrsBook) putLabels(labels []core.Label, replace bool) error {
+	db := addrsBook.labelStorage()
+	if db == nil {
+		return nil
+	}
+	keys := make([][]byte, len(labels))
+	values := make([][]byte, len(labels))
+	for i, label := range labels {
+		var err error
+		if keys[i], values[i], err = addrsBook.encodeLabel(label); err != nil {
+			return err
+		}
+	}
+	return db.UpdateCollections(func(tx core.StorageTxn) error {
+		if replace {
+			if err := tx.DropCollection(dbLabelsBkt); err != nil {
+				return err
+			}
+		}
+		for i := range keys {
+			if err := tx.Put(dbLabelsBkt, keys[i], values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
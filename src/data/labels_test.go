@@ -0,0 +1,154 @@
+package data
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+)
+
+func openLabelStore(t *testing.T, secType int) (core.AddressBook, core.LabelStore) {
+	db, err := GetBoltStorage(GetFilePath(t))
+	require.NoError(t, err)
+	ab := NewAddressBook(db)
+	require.NoError(t, ab.Init(secType, defaultPass))
+	store, err := NewLabelStore(ab)
+	require.NoError(t, err)
+	return ab, store
+}
+
+func TestLabelStore_SetLabel(t *testing.T) {
+	spendable := false
+	tests := []struct {
+		name    string
+		secType int
+	}{
+		{name: "no-security", secType: NoSecurity},
+		{name: "obfuscation", secType: ObfuscationSecurity},
+		{name: "password", secType: PasswordSecurity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ab, store := openLabelStore(t, tt.secType)
+			defer CloseTest(t, ab)
+
+			label := core.Label{Type: core.LabelTypeAddress, Ref: walletAddr1, Label: "Savings", Mark: 2}
+			require.NoError(t, store.SetLabel(label))
+			require.NoError(t, store.SetLabel(core.Label{Type: core.LabelTypeOutput, Ref: "out1", Label: "dust", Spendable: &spendable}))
+
+			got, err := store.GetLabel(core.LabelTypeAddress, walletAddr1)
+			require.NoError(t, err)
+			require.Equal(t, &label, got)
+
+			label.Label = "Cold storage"
+			require.NoError(t, store.SetLabel(label))
+			got, err = store.GetLabel(core.LabelTypeAddress, walletAddr1)
+			require.NoError(t, err)
+			require.Equal(t, "Cold storage", got.Label)
+
+			got, err = store.GetLabel(core.LabelTypeTxn, walletAddr1)
+			require.NoError(t, err)
+			require.Nil(t, got)
+
+			labels, err := store.ListLabels(core.LabelTypeOutput)
+			require.NoError(t, err)
+			require.Len(t, labels, 1)
+			require.False(t, *labels[0].Spendable)
+
+			require.NoError(t, store.DeleteLabel(core.LabelTypeAddress, walletAddr1))
+			labels, err = store.ListLabels("")
+			require.NoError(t, err)
+			require.Len(t, labels, 1)
+
+			require.Error(t, store.SetLabel(core.Label{Type: "unknown", Ref: "x"}))
+			require.Error(t, store.SetLabel(core.Label{Type: core.LabelTypeTxn}))
+		})
+	}
+}
+
+func TestLabelStore_Encrypted(t *testing.T) {
+	ab, store := openLabelStore(t, PasswordSecurity)
+	defer CloseTest(t, ab)
+	require.NoError(t, store.SetLabel(core.Label{Type: core.LabelTypeAddress, Ref: walletAddr1, Label: "Savings"}))
+
+	db := ab.GetStorage().(*boltStorage)
+	raw := make([]byte, 0)
+	require.NoError(t, db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(dbLabelsBkt)).ForEach(func(k, v []byte) error {
+			raw = append(append(raw, k...), v...)
+			return nil
+		})
+	}))
+	require.NotContains(t, string(raw), walletAddr1)
+	require.NotContains(t, string(raw), "Savings")
+
+	// Locked store
+	locked := NewAddressBook(db)
+	lockedStore, err := NewLabelStore(locked)
+	require.NoError(t, err)
+	_, err = lockedStore.GetLabel(core.LabelTypeAddress, walletAddr1)
+	require.Equal(t, errLabelStoreLocked, err)
+	_, err = lockedStore.ListLabels("")
+	require.Equal(t, errLabelStoreLocked, err)
+}
+
+func TestLabelStore_ImportExport(t *testing.T) {
+	ab, store := openLabelStore(t, PasswordSecurity)
+	defer CloseTest(t, ab)
+
+	input := `{"type":"tx","ref":"f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd","label":"Rent"}
+
+{"type":"addr","ref":"` + walletAddr1 + `","label":"Savings","origin":"wpkh([d34db33f/84'/0'/0'])"}
+{"type":"output","ref":"out1","label":"dust","spendable":false}
+`
+	n, err := store.ImportLabels(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	var buf bytes.Buffer
+	require.NoError(t, store.ExportLabels(&buf))
+	require.Equal(t, `{"type":"addr","ref":"`+walletAddr1+`","label":"Savings","origin":"wpkh([d34db33f/84'/0'/0'])"}
+{"type":"output","ref":"out1","label":"dust","spendable":false}
+{"type":"tx","ref":"f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd","label":"Rent"}
+`, buf.String())
+
+	// Invalid records abort the whole import
+	_, err = store.ImportLabels(strings.NewReader(`{"type":"tx","ref":"a","label":"ok"}
+{"type":"tx","label":"no ref"}`))
+	require.Error(t, err)
+	got, err := store.GetLabel(core.LabelTypeTxn, "a")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestLabelStore_ChangeSecurity(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab, store := openLabelStore(t, PasswordSecurity)
+	defer CloseTest(t, ab)
+	_, err := ab.InsertContact(&Contact{
+		Address: []Address{{Value: []byte(foreignAddr), Coin: []byte("SKY")}},
+		Name:    []byte("contact_test1"),
+	})
+	require.NoError(t, err)
+	label := core.Label{Type: core.LabelTypeWallet, Ref: testWalletID, Label: "Main"}
+	require.NoError(t, store.SetLabel(label))
+
+	for _, change := range []struct {
+		secType       int
+		old, password string
+	}{
+		{PasswordSecurity, defaultPass, "new-password"},
+		{ObfuscationSecurity, "new-password", ""},
+		{PasswordSecurity, "", defaultPass},
+	} {
+		require.NoError(t, ab.ChangeSecurity(change.secType, change.old, change.password))
+		got, err := store.GetLabel(core.LabelTypeWallet, testWalletID)
+		require.NoError(t, err)
+		require.Equal(t, &label, got)
+	}
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+const (
+	// Db buckets.
+	dbMetaBkt = "Meta"
+
+	// metaSchemaVersion key of the schema version in the meta collection
+	metaSchemaVersion = "schema.version"
+)
+
+var (
+	// Errors
+	errMigrationVersion = errors.New("database: migration version must be positive and unique")
+	errSchemaTooNew     = errors.New("database: schema version is newer than supported")
+
+	migrations      []core.StorageMigration
+	migrationsMutex sync.Mutex
+)
+
+// RegisterMigration adds a migration applied to databases opened afterwards by GetBoltStorage.
+// Migrations run in version order , each in its own transaction.
+func RegisterMigration(migration core.StorageMigration) error {
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+	if migration.Version == 0 || migration.Apply == nil {
+		return errMigrationVersion
+	}
+	for _, m := range migrations {
+		if m.Version == migration.Version {
+			return errMigrationVersion
+		}
+	}
+	migrations = append(migrations, migration)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return nil
+}
+
+// registeredMigrations return a copy of the registered migrations , in version order.
+func registeredMigrations() []core.StorageMigration {
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+	return append([]core.StorageMigration(nil), migrations...)
+}
+
+// SchemaVersion returns the version of the database schema , zero for databases never migrated.
+func (b *boltStorage) SchemaVersion() (uint64, error) {
+	var version uint64
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		_, err := tx.Get(dbMetaBkt, []byte(metaSchemaVersion), &version)
+		return err
+	})
+	return version, err
+}
+
+// migrate applies the migrations newer than the schema version , sorted by version.
+// Each migration commits along with the schema version it upgrades to.
+func (b *boltStorage) migrate(pending []core.StorageMigration) error {
+	current, err := b.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	var latest uint64
+	if len(pending) > 0 {
+		latest = pending[len(pending)-1].Version
+	}
+	if current > latest {
+		return errSchemaTooNew
+	}
+	for _, m := range pending {
+		if m.Version <= current {
+			continue
+		}
+		logDb.Infof("Migrating database schema to version %d: %s", m.Version, m.Description)
+		err := b.UpdateCollections(func(tx core.StorageTxn) error {
+			if err := m.Apply(tx); err != nil {
+				return err
+			}
+			return tx.Put(dbMetaBkt, []byte(metaSchemaVersion), m.Version)
+		})
+		if err != nil {
+			return fmt.Errorf("migration to schema version %d failed: %v", m.Version, err)
+		}
+		current = m.Version
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package data
+
+import (
+	"sort"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+const (
+	// Db buckets.
+	dbOutboxBkt = "Outbox"
+)
+
+// PutOutboxTxn creates or replaces an outbox record.
+func (b *boltStorage) PutOutboxTxn(txn core.OutboxTxn) error {
+	err := b.UpdateCollections(func(tx core.StorageTxn) error {
+		return tx.Put(dbOutboxBkt, []byte(txn.ID), txn)
+	})
+	if err != nil {
+		logDb.Error(err)
+	}
+	return err
+}
+
+// GetOutboxTxn looks up an outbox record by transaction ID , nil if not recorded.
+func (b *boltStorage) GetOutboxTxn(txnID string) (*core.OutboxTxn, error) {
+	var txn *core.OutboxTxn
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		var stored core.OutboxTxn
+		found, err := tx.Get(dbOutboxBkt, []byte(txnID), &stored)
+		if found {
+			txn = &stored
+		}
+		return err
+	})
+	if err != nil {
+		logDb.Error(err)
+		return nil, err
+	}
+	return txn, nil
+}
+
+// DeleteOutboxTxn removes an outbox record.
+func (b *boltStorage) DeleteOutboxTxn(txnID string) error {
+	err := b.UpdateCollections(func(tx core.StorageTxn) error {
+		return tx.Delete(dbOutboxBkt, []byte(txnID))
+	})
+	if err != nil {
+		logDb.Error(err)
+	}
+	return err
+}
+
+// ListOutboxTxns enumerates outbox records , oldest broadcast first.
+func (b *boltStorage) ListOutboxTxns() ([]core.OutboxTxn, error) {
+	txns := make([]core.OutboxTxn, 0)
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		return tx.Scan(dbOutboxBkt, nil, nil, func(item core.StorageItem) (bool, error) {
+			var txn core.OutboxTxn
+			if err := item.Decode(&txn); err != nil {
+				return false, err
+			}
+			txns = append(txns, txn)
+			return true, nil
+		})
+	})
+	if err != nil {
+		logDb.Error(err)
+		return nil, err
+	}
+	sort.SliceStable(txns, func(i, j int) bool {
+		return txns[i].BroadcastAt.Before(txns[j].BroadcastAt)
+	})
+	return txns, nil
+}
+
+// Type assertions
+var _ core.Outbox = &boltStorage{}
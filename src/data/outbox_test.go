@@ -0,0 +1,161 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltStorage_Outbox(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	txn, err := db.GetOutboxTxn("txn1")
+	require.NoError(t, err)
+	require.Nil(t, txn)
+
+	now := time.Now().UTC()
+	txn1 := core.OutboxTxn{ID: "txn1", WalletID: testWalletID, CoinTicker: "SKY", Raw: []byte{1, 2}, BroadcastAt: now, LastBroadcastAt: now, Attempts: 1, Status: core.TXN_STATUS_PENDING}
+	txn2 := core.OutboxTxn{ID: "txn2", WalletID: testWalletID, CoinTicker: "SKY", Raw: []byte{3}, BroadcastAt: now.Add(-time.Hour), LastBroadcastAt: now, Attempts: 2, Status: core.TXN_STATUS_PENDING}
+	require.NoError(t, db.PutOutboxTxn(txn1))
+	require.NoError(t, db.PutOutboxTxn(txn2))
+
+	txn, err = db.GetOutboxTxn("txn1")
+	require.NoError(t, err)
+	require.Equal(t, txn1.Raw, txn.Raw)
+	require.True(t, txn1.BroadcastAt.Equal(txn.BroadcastAt))
+	require.Equal(t, txn1.Attempts, txn.Attempts)
+
+	txns, err := db.ListOutboxTxns()
+	require.NoError(t, err)
+	require.Len(t, txns, 2)
+	require.Equal(t, "txn2", txns[0].ID)
+	require.Equal(t, "txn1", txns[1].ID)
+
+	require.NoError(t, db.DeleteOutboxTxn("txn2"))
+	txns, err = db.ListOutboxTxns()
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	require.Equal(t, "txn1", txns[0].ID)
+}
+
+func TestSyncOutbox(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	now := time.Now().UTC()
+	policy := OutboxPolicy{RebroadcastInterval: time.Minute, StuckAfter: time.Hour}
+	record := func(id, ticker string, age, sinceBroadcast time.Duration) core.OutboxTxn {
+		return core.OutboxTxn{
+			ID:              id,
+			WalletID:        testWalletID,
+			CoinTicker:      ticker,
+			Raw:             []byte(id),
+			BroadcastAt:     now.Add(-age),
+			LastBroadcastAt: now.Add(-sinceBroadcast),
+			Attempts:        1,
+			Status:          core.TXN_STATUS_PENDING,
+		}
+	}
+	for _, txn := range []core.OutboxTxn{
+		record("confirmed", "SKY", 2*time.Hour, 2*time.Hour),
+		record("recent", "SKY", time.Second, time.Second),
+		record("due", "SKY", 10*time.Minute, 2*time.Minute),
+		record("stuck", "SKY", 2*time.Hour, 2*time.Minute),
+		record("dropped", "SKY", 10*time.Minute, 2*time.Minute),
+		record("double-spent", "SKY", 10*time.Minute, 2*time.Minute),
+		record("other-coin", "BTC", 2*time.Hour, 2*time.Hour),
+	} {
+		require.NoError(t, db.PutOutboxTxn(txn))
+	}
+
+	tracker := new(mocks.TxnTracker)
+	tracker.On("GetTxnStatus", "confirmed").Return(core.TXN_STATUS_CONFIRMED, nil)
+	tracker.On("GetTxnStatus", "recent").Return(core.TXN_STATUS_PENDING, nil)
+	tracker.On("GetTxnStatus", "due").Return(core.TXN_STATUS_PENDING, nil)
+	tracker.On("GetTxnStatus", "stuck").Return(core.TXN_STATUS_PENDING, nil)
+	tracker.On("GetTxnStatus", "dropped").Return(core.TXN_STATUS_CREATED, nil)
+	tracker.On("GetTxnStatus", "double-spent").Return(core.TXN_STATUS_CREATED, nil)
+	tracker.On("IsTxnInvalidated", "dropped", []byte("dropped")).Return(false, nil)
+	tracker.On("IsTxnInvalidated", "double-spent", []byte("double-spent")).Return(true, nil)
+	tracker.On("BroadcastRawTxn", []byte("due")).Return(nil)
+	tracker.On("BroadcastRawTxn", []byte("stuck")).Return(nil)
+	tracker.On("BroadcastRawTxn", []byte("dropped")).Return(nil)
+
+	changed, err := SyncOutbox(db, "SKY", tracker, policy, now)
+	require.NoError(t, err)
+	changedIDs := make([]string, 0)
+	for _, txn := range changed {
+		changedIDs = append(changedIDs, txn.ID)
+	}
+	require.Equal(t, []string{"confirmed", "stuck", "double-spent"}, changedIDs)
+
+	tests := []struct {
+		name     string
+		status   core.TransactionStatus
+		flag     core.OutboxFlag
+		attempts int
+	}{
+		{name: "confirmed", status: core.TXN_STATUS_CONFIRMED, flag: core.OutboxFlagNone, attempts: 1},
+		{name: "recent", status: core.TXN_STATUS_PENDING, flag: core.OutboxFlagNone, attempts: 1},
+		{name: "due", status: core.TXN_STATUS_PENDING, flag: core.OutboxFlagNone, attempts: 2},
+		{name: "stuck", status: core.TXN_STATUS_PENDING, flag: core.OutboxFlagStuck, attempts: 2},
+		{name: "dropped", status: core.TXN_STATUS_PENDING, flag: core.OutboxFlagNone, attempts: 2},
+		{name: "double-spent", status: core.TXN_STATUS_PENDING, flag: core.OutboxFlagInvalidated, attempts: 1},
+		{name: "other-coin", status: core.TXN_STATUS_PENDING, flag: core.OutboxFlagNone, attempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn, err := db.GetOutboxTxn(tt.name)
+			require.NoError(t, err)
+			require.Equal(t, tt.status, txn.Status)
+			require.Equal(t, tt.flag, txn.Flag)
+			require.Equal(t, tt.attempts, txn.Attempts)
+		})
+	}
+	tracker.AssertNotCalled(t, "GetTxnStatus", "other-coin")
+
+	// Settled transactions are no longer queried
+	tracker = new(mocks.TxnTracker)
+	for _, id := range []string{"recent", "due", "stuck", "dropped"} {
+		tracker.On("GetTxnStatus", id).Return(core.TXN_STATUS_PENDING, nil)
+	}
+	_, err = SyncOutbox(db, "SKY", tracker, policy, now)
+	require.NoError(t, err)
+	tracker.AssertNotCalled(t, "GetTxnStatus", "confirmed")
+	tracker.AssertNotCalled(t, "GetTxnStatus", "double-spent")
+}
+
+func TestSendOutboxTxn(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	now := time.Now().UTC()
+	tracker := new(mocks.TxnTracker)
+	tracker.On("BroadcastRawTxn", []byte("sent")).Return(func(raw []byte) error {
+		// Transaction is tracked before it reaches the network
+		txn, err := db.GetOutboxTxn("sent")
+		require.NoError(t, err)
+		require.NotNil(t, txn)
+		require.Equal(t, 0, txn.Attempts)
+		return nil
+	})
+	tracker.On("BroadcastRawTxn", []byte("rejected")).Return(errors.ErrInvalidTxn)
+
+	require.NoError(t, SendOutboxTxn(db, tracker, core.OutboxTxn{ID: "sent", WalletID: testWalletID, CoinTicker: "SKY", Raw: []byte("sent")}, now))
+	txn, err := db.GetOutboxTxn("sent")
+	require.NoError(t, err)
+	require.Equal(t, 1, txn.Attempts)
+	require.Equal(t, core.TXN_STATUS_PENDING, txn.Status)
+	require.True(t, now.Equal(txn.BroadcastAt))
+
+	err = SendOutboxTxn(db, tracker, core.OutboxTxn{ID: "rejected", CoinTicker: "SKY", Raw: []byte("rejected")}, now)
+	require.Equal(t, errors.ErrInvalidTxn, err)
+	txn, err = db.GetOutboxTxn("rejected")
+	require.NoError(t, err)
+	require.Nil(t, txn)
+}
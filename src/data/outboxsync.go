@@ -0,0 +1,93 @@
+package data
+
+import (
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+// OutboxPolicy schedule applied while tracking broadcast transactions
+type OutboxPolicy struct {
+	// RebroadcastInterval minimum time elapsed between broadcasts of a pending transaction
+	RebroadcastInterval time.Duration
+	// StuckAfter time since the first broadcast after which a pending transaction is flagged as stuck
+	StuckAfter time.Duration
+}
+
+// SendOutboxTxn records a signed transaction in the outbox and then broadcasts it , so that it is
+// tracked and broadcast again by SyncOutbox should the wallet stop in between.
+// The record is removed if the network rejects the transaction.
+func SendOutboxTxn(outbox core.Outbox, tracker core.TxnTracker, txn core.OutboxTxn, now time.Time) error {
+	txn.BroadcastAt, txn.LastBroadcastAt = now, now
+	txn.Attempts = 0
+	txn.Status, txn.Flag = core.TXN_STATUS_PENDING, core.OutboxFlagNone
+	if err := outbox.PutOutboxTxn(txn); err != nil {
+		return err
+	}
+	if err := tracker.BroadcastRawTxn(txn.Raw); err != nil {
+		if errDel := outbox.DeleteOutboxTxn(txn.ID); errDel != nil {
+			logDb.WithError(errDel).Warn("Couldn't remove rejected transaction from outbox ", txn.ID)
+		}
+		return err
+	}
+	txn.Attempts = 1
+	return outbox.PutOutboxTxn(txn)
+}
+
+// SyncOutbox updates the status of outbox transactions broadcast for a coin.
+// Transactions still pending are broadcast again once policy.RebroadcastInterval elapsed.
+// Those unknown to the network whose inputs were spent elsewhere are flagged as invalidated
+// and no longer tracked. Records whose status or flag changed are returned.
+func SyncOutbox(outbox core.Outbox, ticker string, tracker core.TxnTracker, policy OutboxPolicy, now time.Time) ([]core.OutboxTxn, error) {
+	txns, err := outbox.ListOutboxTxns()
+	if err != nil {
+		return nil, err
+	}
+	changed := make([]core.OutboxTxn, 0)
+	for _, txn := range txns {
+		if txn.CoinTicker != ticker || txn.Status == core.TXN_STATUS_CONFIRMED || txn.Flag == core.OutboxFlagInvalidated {
+			continue
+		}
+		status, err := tracker.GetTxnStatus(txn.ID)
+		if err != nil {
+			logDb.WithError(err).Warn("Couldn't get status of transaction ", txn.ID)
+			return nil, err
+		}
+		prevStatus, prevFlag := txn.Status, txn.Flag
+		switch status {
+		case core.TXN_STATUS_CONFIRMED:
+			txn.Status, txn.Flag = status, core.OutboxFlagNone
+		case core.TXN_STATUS_PENDING:
+			txn.Status = status
+		default:
+			invalidated, err := tracker.IsTxnInvalidated(txn.ID, txn.Raw)
+			if err != nil {
+				logDb.WithError(err).Warn("Couldn't check inputs of transaction ", txn.ID)
+				return nil, err
+			}
+			if invalidated {
+				txn.Flag = core.OutboxFlagInvalidated
+			}
+		}
+		if txn.Status != core.TXN_STATUS_CONFIRMED && txn.Flag != core.OutboxFlagInvalidated {
+			if now.Sub(txn.BroadcastAt) >= policy.StuckAfter {
+				txn.Flag = core.OutboxFlagStuck
+			}
+			if now.Sub(txn.LastBroadcastAt) >= policy.RebroadcastInterval {
+				if err := tracker.BroadcastRawTxn(txn.Raw); err != nil {
+					logDb.WithError(err).Warn("Couldn't rebroadcast transaction ", txn.ID)
+				} else {
+					txn.LastBroadcastAt = now
+					txn.Attempts++
+				}
+			}
+		}
+		if err := outbox.PutOutboxTxn(txn); err != nil {
+			return nil, err
+		}
+		if txn.Status != prevStatus || txn.Flag != prevFlag {
+			changed = append(changed, txn)
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,218 @@
+package data
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+)
+
+// WatchPayment registers a payment expected to an address of wlt.
+// If watch.Address is empty the next unused wallet address is allocated , generating it if needed.
+func WatchPayment(store core.PaymentWatchStore, wlt core.Wallet, watch core.PaymentWatch, pwd core.PasswordReader, now time.Time) (*core.PaymentWatch, error) {
+	if watch.Amount == 0 {
+		return nil, errors.ErrInvalidValue
+	}
+	if watch.Address == "" {
+		addr, err := NextUnusedAddress(store, wlt, pwd)
+		if err != nil {
+			return nil, err
+		}
+		watch.Address = addr.String()
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	watch.ID = hex.EncodeToString(id)
+	watch.WalletID = wlt.GetId()
+	watch.CreatedAt = now
+	watch.State = core.PaymentAwaiting
+	watch.Received, watch.ReceivedHours, watch.Pending = 0, 0, 0
+	if err := store.PutPaymentWatch(watch); err != nil {
+		return nil, err
+	}
+	return &watch, nil
+}
+
+// NextUnusedAddress returns the first account address of wlt neither involved in transactions
+// nor watched for payments. A new address is generated once every loaded address has been used.
+func NextUnusedAddress(store core.PaymentWatchStore, wlt core.Wallet, pwd core.PasswordReader) (core.Address, error) {
+	watches, err := store.ListPaymentWatches()
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[string]struct{}, len(watches))
+	for _, watch := range watches {
+		watched[watch.Address] = struct{}{}
+	}
+	addrIter, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		logDb.WithError(err).Warn("Couldn't get loaded addresses")
+		return nil, err
+	}
+	var loaded uint32
+	for addrIter.Next() {
+		loaded++
+		addr := addrIter.Value()
+		if _, isWatched := watched[addr.String()]; isWatched {
+			continue
+		}
+		txnIter := addr.GetCryptoAccount().ListTransactions()
+		if txnIter == nil {
+			return nil, errTxnHistoryUnavailable
+		}
+		if !txnIter.Next() {
+			return addr, nil
+		}
+	}
+	newIter := wlt.GenAddresses(core.AccountAddress, loaded, 1, pwd)
+	if newIter == nil || !newIter.Next() {
+		return nil, errors.ErrNoMoreElements
+	}
+	return newIter.Value(), nil
+}
+
+// SyncPaymentWatches updates watched payments not settled yet after funds sent to their addresses.
+// Funds count as received once buried under the required confirmations , at least one.
+// Events are returned for every payment whose state changed.
+func SyncPaymentWatches(store core.PaymentWatchStore, wallets core.WalletSet, bc core.BlockchainStatus, now time.Time) ([]core.PaymentEvent, error) {
+	watches, err := store.ListPaymentWatches()
+	if err != nil {
+		return nil, err
+	}
+	headSeq, err := bc.GetNumberOfBlocks()
+	if err != nil {
+		logDb.WithError(err).Warn("Couldn't get number of blocks")
+		return nil, err
+	}
+	accounts := make(map[string]core.CryptoAccount)
+	loadedWallets := make(map[string]struct{})
+	events := make([]core.PaymentEvent, 0)
+	for _, watch := range watches {
+		if watch.State.IsFinal() {
+			continue
+		}
+		if _, isLoaded := loadedWallets[watch.WalletID]; !isLoaded {
+			if err := loadWalletAccounts(wallets, watch.WalletID, accounts); err != nil {
+				return nil, err
+			}
+			loadedWallets[watch.WalletID] = struct{}{}
+		}
+		account, isKnown := accounts[watch.Address]
+		if !isKnown {
+			logDb.WithField("address", watch.Address).Warn("Watched address not found in wallet")
+			continue
+		}
+		previous := watch.State
+		if err := evalPaymentWatch(&watch, account, headSeq, now); err != nil {
+			return nil, err
+		}
+		if err := store.PutPaymentWatch(watch); err != nil {
+			return nil, err
+		}
+		if watch.State != previous {
+			events = append(events, core.PaymentEvent{Watch: watch, Previous: previous})
+		}
+	}
+	return events, nil
+}
+
+// loadWalletAccounts maps loaded addresses of a wallet to their crypto accounts
+func loadWalletAccounts(wallets core.WalletSet, walletID string, accounts map[string]core.CryptoAccount) error {
+	wlt := wallets.GetWallet(walletID)
+	if wlt == nil {
+		logDb.WithField("id", walletID).Warn("Couldn't load wallet")
+		return nil
+	}
+	addrIter, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		logDb.WithError(err).Warn("Couldn't get loaded addresses")
+		return err
+	}
+	for addrIter.Next() {
+		accounts[addrIter.Value().String()] = addrIter.Value().GetCryptoAccount()
+	}
+	return nil
+}
+
+// isAfterWatch tells whether a transaction may pay a watch , i.e. it was not made before the watch was created.
+// Transactions without timestamp are assumed to be recent.
+func isAfterWatch(txn core.Transaction, watch *core.PaymentWatch) bool {
+	ts := uint64(txn.GetTimestamp())
+	return ts == 0 || watch.CreatedAt.IsZero() || ts >= uint64(watch.CreatedAt.Unix())
+}
+
+// evalPaymentWatch computes funds sent to a watched address since it was created and the resulting payment state.
+// Pending funds take precedence over partially confirmed ones so that payments on their way are reported.
+func evalPaymentWatch(watch *core.PaymentWatch, account core.CryptoAccount, headSeq uint64, now time.Time) error {
+	txnIter := account.ListTransactions()
+	if txnIter == nil {
+		logDb.Warn("Couldn't get transaction iterator")
+		return errTxnHistoryUnavailable
+	}
+	required := watch.Confirmations
+	if required == 0 {
+		required = 1
+	}
+	watch.Received, watch.ReceivedHours, watch.Pending = 0, 0, 0
+	seen := make(map[string]struct{})
+	for txnIter.Next() {
+		txn := txnIter.Value()
+		if _, isSeen := seen[txn.GetId()]; isSeen {
+			continue
+		}
+		seen[txn.GetId()] = struct{}{}
+		if !isAfterWatch(txn, watch) {
+			continue
+		}
+		isReceived := util.TxnConfirmations(txn, headSeq) >= required
+		for _, out := range txn.GetOutputs() {
+			addr, err := out.GetAddress()
+			if err != nil {
+				return err
+			}
+			if addr.String() != watch.Address {
+				continue
+			}
+			coins, err := out.GetCoins(params.SkycoinTicker)
+			if err != nil {
+				return err
+			}
+			if !isReceived {
+				watch.Pending += coins
+				continue
+			}
+			hours, err := out.GetCoins(params.CoinHoursTicker)
+			if err != nil {
+				return err
+			}
+			watch.Received += coins
+			watch.ReceivedHours += hours
+		}
+	}
+
+	switch {
+	case watch.Received > 0 && watch.Received >= watch.Amount && watch.ReceivedHours >= watch.MinHours:
+		if watch.Received > watch.Amount {
+			watch.State = core.PaymentOverpaid
+		} else {
+			watch.State = core.PaymentPaid
+		}
+		return nil
+	case watch.Pending > 0:
+		watch.State = core.PaymentSeenInPool
+	case watch.Received > 0:
+		watch.State = core.PaymentPartiallyPaid
+	default:
+		watch.State = core.PaymentAwaiting
+	}
+	if !watch.Expiry.IsZero() && !now.Before(watch.Expiry) {
+		watch.State = core.PaymentExpired
+	}
+	return nil
+}
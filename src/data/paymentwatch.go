@@ -0,0 +1,78 @@
+package data
+
+import (
+	"sort"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+const (
+	// Db buckets.
+	dbPaymentWatchesBkt = "PaymentWatches"
+)
+
+// PutPaymentWatch creates or replaces a payment watch.
+func (b *boltStorage) PutPaymentWatch(watch core.PaymentWatch) error {
+	err := b.UpdateCollections(func(tx core.StorageTxn) error {
+		return tx.Put(dbPaymentWatchesBkt, []byte(watch.ID), watch)
+	})
+	if err != nil {
+		logDb.Error(err)
+	}
+	return err
+}
+
+// GetPaymentWatch looks up a payment watch by ID , nil if not registered.
+func (b *boltStorage) GetPaymentWatch(id string) (*core.PaymentWatch, error) {
+	var watch *core.PaymentWatch
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		var stored core.PaymentWatch
+		found, err := tx.Get(dbPaymentWatchesBkt, []byte(id), &stored)
+		if found {
+			watch = &stored
+		}
+		return err
+	})
+	if err != nil {
+		logDb.Error(err)
+		return nil, err
+	}
+	return watch, nil
+}
+
+// DeletePaymentWatch removes a payment watch.
+func (b *boltStorage) DeletePaymentWatch(id string) error {
+	err := b.UpdateCollections(func(tx core.StorageTxn) error {
+		return tx.Delete(dbPaymentWatchesBkt, []byte(id))
+	})
+	if err != nil {
+		logDb.Error(err)
+	}
+	return err
+}
+
+// ListPaymentWatches enumerates payment watches , oldest first.
+func (b *boltStorage) ListPaymentWatches() ([]core.PaymentWatch, error) {
+	watches := make([]core.PaymentWatch, 0)
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		return tx.Scan(dbPaymentWatchesBkt, nil, nil, func(item core.StorageItem) (bool, error) {
+			var watch core.PaymentWatch
+			if err := item.Decode(&watch); err != nil {
+				return false, err
+			}
+			watches = append(watches, watch)
+			return true, nil
+		})
+	})
+	if err != nil {
+		logDb.Error(err)
+		return nil, err
+	}
+	sort.SliceStable(watches, func(i, j int) bool {
+		return watches[i].CreatedAt.Before(watches[j].CreatedAt)
+	})
+	return watches, nil
+}
+
+// Type assertions
+var _ core.PaymentWatchStore = &boltStorage{}
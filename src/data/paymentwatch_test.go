@@ -0,0 +1,158 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltStorage_PaymentWatches(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	watch, err := db.GetPaymentWatch("w1")
+	require.NoError(t, err)
+	require.Nil(t, watch)
+
+	now := time.Now().UTC()
+	w1 := core.PaymentWatch{ID: "w1", WalletID: testWalletID, Address: walletAddr1, Amount: 1000000, CreatedAt: now}
+	w2 := core.PaymentWatch{ID: "w2", WalletID: testWalletID, Address: walletAddr2, Amount: 2000000, CreatedAt: now.Add(-time.Hour), Expiry: now}
+	require.NoError(t, db.PutPaymentWatch(w1))
+	require.NoError(t, db.PutPaymentWatch(w2))
+
+	watch, err = db.GetPaymentWatch("w2")
+	require.NoError(t, err)
+	require.Equal(t, w2.Address, watch.Address)
+	require.Equal(t, w2.Amount, watch.Amount)
+	require.True(t, w2.Expiry.Equal(watch.Expiry))
+
+	watches, err := db.ListPaymentWatches()
+	require.NoError(t, err)
+	require.Len(t, watches, 2)
+	require.Equal(t, "w2", watches[0].ID)
+	require.Equal(t, "w1", watches[1].ID)
+
+	require.NoError(t, db.DeletePaymentWatch("w2"))
+	watches, err = db.ListPaymentWatches()
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	require.Equal(t, "w1", watches[0].ID)
+}
+
+func TestNextUnusedAddress(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	// Addresses without history are allocated unless already watched
+	wlt := mockWalletWithHistory(nil)
+	addr, err := NextUnusedAddress(db, wlt, nil)
+	require.NoError(t, err)
+	require.Equal(t, walletAddr1, addr.String())
+	require.NoError(t, db.PutPaymentWatch(core.PaymentWatch{ID: "w1", Address: walletAddr1}))
+	addr, err = NextUnusedAddress(db, wlt, nil)
+	require.NoError(t, err)
+	require.Equal(t, walletAddr2, addr.String())
+
+	// New address is generated once all of them have been used
+	wlt = mockWalletWithHistory([]core.Transaction{makeTestTxn("txn1", 1, 100, foreignAddr, walletAddr2)})
+	newAddr := new(mocks.Address)
+	newAddr.On("String").Return(foreignAddr)
+	wlt.On("GenAddresses", core.AccountAddress, uint32(2), uint32(1), mock.Anything).Return(
+		skycoin.NewSkycoinAddressIterator([]core.Address{newAddr}))
+	addr, err = NextUnusedAddress(db, wlt, nil)
+	require.NoError(t, err)
+	require.Equal(t, foreignAddr, addr.String())
+	wlt.AssertCalled(t, "GenAddresses", core.AccountAddress, uint32(2), uint32(1), mock.Anything)
+}
+
+func TestSyncPaymentWatches(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	now := time.Now().UTC()
+	for _, watch := range []core.PaymentWatch{
+		{ID: "paid", Address: walletAddr1, Amount: 2000000, MinHours: 5},
+		{ID: "overpaid", Address: walletAddr1, Amount: 1000000},
+		{ID: "low-hours", Address: walletAddr1, Amount: 2000000, MinHours: 6},
+		{ID: "partial", Address: walletAddr1, Amount: 3000000},
+		{ID: "in-pool", Address: walletAddr2, Amount: 2000000, Confirmations: 3},
+		{ID: "expired", Address: walletAddr2, Amount: 2000000, Confirmations: 3, Expiry: now.Add(-time.Minute)},
+		{ID: "awaiting", Address: foreignAddr, Amount: 2000000},
+		{ID: "settled", Address: walletAddr2, Amount: 1, State: core.PaymentPaid},
+	} {
+		watch.WalletID = testWalletID
+		watch.CreatedAt = time.Unix(100, 0).UTC()
+		require.NoError(t, db.PutPaymentWatch(watch))
+	}
+
+	wlt := mockWalletWithHistory([]core.Transaction{
+		makeTestTxn("txn1", 1, 100, foreignAddr, walletAddr1),
+		makeTestTxn("txn2", 4, 400, foreignAddr, walletAddr2),
+	})
+	wallets := new(mocks.WalletSet)
+	wallets.On("GetWallet", testWalletID).Return(wlt)
+	bc := new(mocks.BlockchainStatus)
+	bc.On("GetNumberOfBlocks").Return(uint64(4), nil)
+
+	events, err := SyncPaymentWatches(db, wallets, bc, now)
+	require.NoError(t, err)
+	states := make(map[string]core.PaymentState)
+	for _, event := range events {
+		require.Equal(t, core.PaymentAwaiting, event.Previous)
+		states[event.Watch.ID] = event.Watch.State
+	}
+	require.Equal(t, map[string]core.PaymentState{
+		"paid":      core.PaymentPaid,
+		"overpaid":  core.PaymentOverpaid,
+		"low-hours": core.PaymentPartiallyPaid,
+		"partial":   core.PaymentPartiallyPaid,
+		"in-pool":   core.PaymentSeenInPool,
+		"expired":   core.PaymentExpired,
+	}, states)
+
+	watch, err := db.GetPaymentWatch("partial")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2000000), watch.Received)
+	require.Equal(t, uint64(5), watch.ReceivedHours)
+	watch, err = db.GetPaymentWatch("in-pool")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), watch.Received)
+	require.Equal(t, uint64(2000000), watch.Pending)
+
+	// Unchanged states are not reported again
+	events, err = SyncPaymentWatches(db, wallets, bc, now)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestSyncPaymentWatchesSinceCreation(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	watch := core.PaymentWatch{ID: "w1", WalletID: testWalletID, Address: walletAddr1, Amount: 4000000, Confirmations: 2, CreatedAt: time.Unix(200, 0).UTC()}
+	require.NoError(t, db.PutPaymentWatch(watch))
+
+	// Funds sent before the watch was created are ignored
+	wlt := mockWalletWithHistory([]core.Transaction{
+		makeTestTxn("old", 1, 100, foreignAddr, walletAddr1),
+		makeTestTxn("txn1", 2, 300, foreignAddr, walletAddr1),
+		makeTestTxn("txn2", 4, 400, foreignAddr, walletAddr1),
+	})
+	wallets := new(mocks.WalletSet)
+	wallets.On("GetWallet", testWalletID).Return(wlt)
+	bc := new(mocks.BlockchainStatus)
+	bc.On("GetNumberOfBlocks").Return(uint64(4), nil)
+
+	// Partially confirmed payment with more funds on their way is reported as pending
+	events, err := SyncPaymentWatches(db, wallets, bc, time.Now())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, core.PaymentSeenInPool, events[0].Watch.State)
+	require.Equal(t, uint64(2000000), events[0].Watch.Received)
+	require.Equal(t, uint64(2000000), events[0].Watch.Pending)
+}
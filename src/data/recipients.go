@@ -0,0 +1,119 @@
+package data
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+// RecentRecipient address funds were sent to from a wallet , matched to the contact owning it.
+type RecentRecipient struct {
+	// Address funds were sent to
+	Address string
+	// Contact owning the address , nil if none does or the address book is locked
+	Contact core.Contact
+	// Label of the contact address , empty if not owned by any contact
+	Label string
+	// LastSent timestamp of the latest transaction paying the address
+	LastSent core.Timestamp
+	// TxnID latest transaction paying the address
+	TxnID string
+	// Count of transactions paying the address
+	Count int
+}
+
+// RecentRecipients lists addresses paid by transactions sent from wallets , latest first.
+// Outputs to ownAddrs or back to the addresses spent are change and not listed.
+// Recipients are looked up in book , which may be nil. At most limit recipients are returned
+// if limit is positive.
+func RecentRecipients(index core.TxnIndex, book core.AddressBook, walletIDs []string, ownAddrs map[string]struct{}, limit int) ([]*RecentRecipient, error) {
+	byAddress := make(map[string]*RecentRecipient)
+	tickers := make(map[string][]string)
+	for _, wltID := range walletIDs {
+		it, err := index.ListTxns(wltID, core.TxnQuery{Direction: core.TxnDirectionSent})
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			txn := it.Value()
+			spent := make(map[string]struct{})
+			for _, in := range txn.GetInputs() {
+				out, err := in.GetSpentOutput()
+				if err != nil {
+					return nil, err
+				}
+				addr, err := out.GetAddress()
+				if err != nil {
+					return nil, err
+				}
+				spent[addr.String()] = struct{}{}
+			}
+			paid := make(map[string]struct{})
+			for _, out := range txn.GetOutputs() {
+				addr, err := out.GetAddress()
+				if err != nil {
+					return nil, err
+				}
+				value := addr.String()
+				_, isOwn := ownAddrs[value]
+				_, isChange := spent[value]
+				_, isPaid := paid[value]
+				if isOwn || isChange || isPaid {
+					continue
+				}
+				paid[value] = struct{}{}
+				r, exists := byAddress[value]
+				if !exists {
+					r = &RecentRecipient{Address: value}
+					byAddress[value] = r
+					tickers[value] = out.SupportedAssets()
+				}
+				r.Count++
+				if !exists || txn.GetTimestamp() > r.LastSent {
+					r.LastSent = txn.GetTimestamp()
+					r.TxnID = txn.GetId()
+				}
+			}
+		}
+	}
+
+	recipients := make([]*RecentRecipient, 0, len(byAddress))
+	for _, r := range byAddress {
+		recipients = append(recipients, r)
+	}
+	sort.Slice(recipients, func(i, j int) bool {
+		if recipients[i].LastSent != recipients[j].LastSent {
+			return recipients[i].LastSent > recipients[j].LastSent
+		}
+		return recipients[i].Address < recipients[j].Address
+	})
+	if limit > 0 && len(recipients) > limit {
+		recipients = recipients[:limit]
+	}
+	if book != nil {
+		for _, r := range recipients {
+			matchRecipient(book, r, tickers[r.Address])
+		}
+	}
+	return recipients, nil
+}
+
+// matchRecipient looks up the contact owning the address of a recipient ,
+// trying the tickers of assets sent to it.
+func matchRecipient(book core.AddressBook, r *RecentRecipient, tickers []string) {
+	for _, ticker := range tickers {
+		contact, err := book.LookupAddress(r.Address, ticker)
+		if err != nil {
+			continue
+		}
+		r.Contact = contact
+		for _, sa := range contact.GetAddresses() {
+			if string(sa.GetValue()) == r.Address && strings.EqualFold(string(sa.GetCoinType()), ticker) {
+				r.Label = sa.GetLabel()
+				break
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"testing"
+
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentRecipients(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+	ab := InitAddrsBook(t)
+	defer CloseTest(t, ab)
+
+	sent := func(id string, seq, ts uint64, to ...string) {
+		txn := makeTestTxn(id, seq, ts, walletAddr1, walletAddr1)
+		for _, addr := range to {
+			txn.Outputs = append(txn.Outputs, &txnOutRecord{
+				ID:      id + "-" + addr,
+				Address: addr,
+				Coins:   map[string]uint64{skycoin.Sky: 1000000},
+			})
+		}
+		require.NoError(t, db.IndexTxn(testWalletID, txn, seq, core.TxnDirectionSent, []string{walletAddr1}))
+	}
+	sent("txn1", 10, 1000, foreignAddr)
+	sent("txn2", 11, 2000, importAddr1, walletAddr2)
+	sent("txn3", 12, 3000, foreignAddr, foreignAddr)
+	received := makeTestTxn("txn4", 13, 4000, importAddr2, walletAddr1)
+	require.NoError(t, db.IndexTxn(testWalletID, received, 13, core.TxnDirectionReceived, []string{walletAddr1}))
+
+	_, err := ab.InsertContact(&Contact{
+		Name:    []byte("Alice"),
+		Address: []Address{{Value: []byte(importAddr1), Coin: []byte("SKY"), Label: "exchange deposit"}},
+	})
+	require.NoError(t, err)
+
+	own := map[string]struct{}{walletAddr2: {}}
+	recipients, err := RecentRecipients(db, ab, []string{testWalletID}, own, 0)
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+	require.Equal(t, foreignAddr, recipients[0].Address)
+	require.Equal(t, "txn3", recipients[0].TxnID)
+	require.Equal(t, core.Timestamp(3000), recipients[0].LastSent)
+	require.Equal(t, 2, recipients[0].Count)
+	require.Nil(t, recipients[0].Contact)
+	require.Equal(t, importAddr1, recipients[1].Address)
+	require.Equal(t, "Alice", recipients[1].Contact.GetName())
+	require.Equal(t, "exchange deposit", recipients[1].Label)
+
+	recipients, err = RecentRecipients(db, nil, []string{testWalletID}, own, 1)
+	require.NoError(t, err)
+	require.Len(t, recipients, 1)
+	require.Equal(t, foreignAddr, recipients[0].Address)
+}
@@ -0,0 +1,603 @@
+package data
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/boltdb/bolt"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// PrevSecurityType security type in use before the last security change
+	PrevSecurityType = "prevSecType"
+	// PrevHash password hash in use before the last security change
+	PrevHash = "prevHash"
+	// PrevEntropy entropy in use before the last security change
+	PrevEntropy = "prevEntropy"
+)
+
+var (
+	// Errors
+	errSecurityChangeUnsupported = errors.New("security changes require a bolt address book")
+	errVerificationFailed        = errors.New("re-encrypted data does not match the original")
+	errUnreadableRecords         = errors.New("some records cannot be decrypted , recover them before changing security")
+	errUnrecoverableRecords      = errors.New("some records could not be decrypted with any known key")
+)
+
+// bookCipher encrypts address book records under a security type.
+// With PasswordSecurity records are sealed in envelopes , unless kdf is nil which keeps the legacy
+// format of AES-GCM under a PBKDF2 key salted with entropy.
+type bookCipher struct {
+	secType  int
+	password []byte
+	// entropy salt of the legacy key
+	entropy []byte
+	// kdf derivation of the key sealing new envelopes
+	kdf *KDFParams
+	// keys derived so far , by derivation settings
+	keys map[string][]byte
+}
+
+// newBookCipher return the cipher of a security type writing the legacy format , keyed by a password and an entropy.
+func newBookCipher(secType int, password, entropy []byte) (*bookCipher, error) {
+	switch secType {
+	case NoSecurity, ObfuscationSecurity, PasswordSecurity:
+		return &bookCipher{secType: secType, password: password, entropy: entropy}, nil
+	}
+	return nil, errInvalidSecType
+}
+
+// newEnvelopeCipher return a PasswordSecurity cipher sealing envelopes with a key derived by kdf.
+// Derived keys are cached in keys , which may be nil.
+func newEnvelopeCipher(password []byte, kdf KDFParams, keys map[string][]byte) *bookCipher {
+	return &bookCipher{secType: PasswordSecurity, password: password, kdf: &kdf, keys: keys}
+}
+
+// deriveKey return the key derived from the password by kdf , or the legacy key if kdf is nil.
+func (c *bookCipher) deriveKey(kdf *KDFParams) ([]byte, error) {
+	id := "pbkdf2:" + string(c.entropy)
+	if kdf != nil {
+		id = kdf.id()
+	}
+	if key, cached := c.keys[id]; cached {
+		return key, nil
+	}
+	var key []byte
+	if kdf == nil {
+		key = pbkdf2.Key(c.entropy, c.password, 4096, 32, sha512.New)
+	} else {
+		var err error
+		if key, err = kdf.deriveKey(c.password); err != nil {
+			return nil, err
+		}
+	}
+	if c.keys == nil {
+		c.keys = make(map[string][]byte)
+	}
+	c.keys[id] = key
+	return key, nil
+}
+
+// encrypt raw data.
+func (c *bookCipher) encrypt(data []byte) ([]byte, error) {
+	switch c.secType {
+	case NoSecurity:
+		return data, nil
+	case ObfuscationSecurity:
+		return []byte(base64.StdEncoding.EncodeToString(data)), nil
+	case PasswordSecurity:
+		key, err := c.deriveKey(c.kdf)
+		if err != nil {
+			return nil, err
+		}
+		if c.kdf == nil {
+			return encryptAESGCM(key, data)
+		}
+		return sealEnvelope(key, *c.kdf, data)
+	}
+	return nil, errInvalidSecType
+}
+
+// decrypt a cipher message into raw data.
+// Envelopes are opened with the key derivation they record , legacy messages only by legacy ciphers.
+func (c *bookCipher) decrypt(cipherMsg []byte) ([]byte, error) {
+	switch c.secType {
+	case NoSecurity:
+		return cipherMsg, nil
+	case ObfuscationSecurity:
+		return base64.StdEncoding.DecodeString(string(cipherMsg))
+	case PasswordSecurity:
+		if c.kdf == nil {
+			key, err := c.deriveKey(nil)
+			if err != nil {
+				return nil, err
+			}
+			return decryptAESGCM(key, cipherMsg)
+		}
+		env, err := parseEnvelope(cipherMsg)
+		if err != nil {
+			return nil, err
+		}
+		key, err := c.deriveKey(&env.KDF)
+		if err != nil {
+			return nil, err
+		}
+		return decryptAESGCM(key, env.Data)
+	}
+	return nil, errInvalidSecType
+}
+
+// labelKey bucket key of a label. Keys are hashed with PasswordSecurity to hide the labelled entities.
+func (c *bookCipher) labelKey(labelType, ref string) ([]byte, error) {
+	key := []byte(labelType + "\x00" + ref)
+	if c.secType != PasswordSecurity {
+		return key, nil
+	}
+	secret, err := c.deriveKey(c.kdf)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, secret)
+	if _, err := mac.Write(key); err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(mac.Sum(nil))), nil
+}
+
+// verifyCheck fail unless check , a password check from the config , opens with the cipher.
+func (c *bookCipher) verifyCheck(check string) error {
+	data, err := c.decrypt([]byte(check))
+	if err != nil || !bytes.Equal(data, checkPlaintext) {
+		return errWrongPassword
+	}
+	return nil
+}
+
+// cipher return the cipher of the current security settings and credentials.
+func (addrsBook *addrsBook) cipher() (*bookCipher, error) {
+	secType, err := addrsBook.GetSecType()
+	if err != nil {
+		return nil, err
+	}
+	if addrsBook.keys == nil {
+		addrsBook.keys = make(map[string][]byte)
+	}
+	config := addrsBook.GetStorage().GetConfig()
+	if secType == PasswordSecurity && config[KDF] != "" {
+		kdf, err := parseKDFParams(config[KDF])
+		if err != nil {
+			return nil, err
+		}
+		return newEnvelopeCipher(addrsBook.key, *kdf, addrsBook.keys), nil
+	}
+	c, err := newBookCipher(secType, addrsBook.key, []byte(config[Entropy]))
+	if err != nil {
+		return nil, err
+	}
+	c.keys = addrsBook.keys
+	return c, nil
+}
+
+// setKey replace the credentials , forgetting keys derived from the previous ones.
+func (addrsBook *addrsBook) setKey(password []byte) {
+	addrsBook.key = password
+	addrsBook.keys = nil
+	addrsBook.resetIndex()
+}
+
+// newPasswordSecurity return an envelope cipher keyed by password with the default key derivation ,
+// and the config parameters describing it.
+func newPasswordSecurity(password []byte) (*bookCipher, map[string]string, error) {
+	kdf, err := newKDFParams()
+	if err != nil {
+		return nil, nil, err
+	}
+	c := newEnvelopeCipher(password, kdf, nil)
+	check, err := c.encrypt(checkPlaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	kdfData, err := json.Marshal(kdf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, map[string]string{KDF: string(kdfData), Check: string(check)}, nil
+}
+
+// verifyPassword verify the current credentials , opening the password check or comparing the legacy hash.
+func (addrsBook *addrsBook) verifyPassword() error {
+	config := addrsBook.GetStorage().GetConfig()
+	if config[Check] == "" {
+		return bcrypt.CompareHashAndPassword([]byte(config[Hash]), addrsBook.key)
+	}
+	c, err := addrsBook.cipher()
+	if err != nil {
+		return err
+	}
+	return c.verifyCheck(config[Check])
+}
+
+// upgradeSecurity re-encrypt a password protected address book with the default key derivation
+// if written in the legacy format or derived with a lower cost. Like ChangeSecurity it runs in a
+// single transaction , a failure leaves the address book as it was.
+func (addrsBook *addrsBook) upgradeSecurity() error {
+	config := addrsBook.GetStorage().GetConfig()
+	if config[KDF] != "" {
+		if kdf, err := parseKDFParams(config[KDF]); err == nil && !kdf.weakerThan(currentKDF()) {
+			return nil
+		}
+	}
+	db, isBolt := addrsBook.GetStorage().(*boltStorage)
+	if !isBolt {
+		return errSecurityChangeUnsupported
+	}
+	logDb.Info("upgrading Address Book encryption")
+	oldCipher, err := addrsBook.cipher()
+	if err != nil {
+		return err
+	}
+	newCipher, settings, err := newPasswordSecurity(addrsBook.key)
+	if err != nil {
+		return err
+	}
+	newConfig := addrsBook.securityConfig(PasswordSecurity, settings)
+	err = db.Update(func(tx *bolt.Tx) error {
+		records, err := readRecords(tx, oldCipher)
+		if err != nil {
+			return err
+		}
+		if err := writeRecords(tx, newCipher, records); err != nil {
+			return err
+		}
+		return putConfig(tx, newConfig)
+	})
+	if err != nil {
+		return err
+	}
+	addrsBook.keys = newCipher.keys
+	return nil
+}
+
+// bookRecords plain contents of the address book , keyed as in the database.
+type bookRecords struct {
+	contacts map[string][]byte
+	labels   []core.Label
+}
+
+// readRecords decrypt every contact and label stored in the database , failing if any of them is unreadable.
+func readRecords(tx *bolt.Tx, c *bookCipher) (*bookRecords, error) {
+	records := &bookRecords{contacts: make(map[string][]byte)}
+	if bkt := tx.Bucket([]byte(dbAddrsBookBkt)); bkt != nil {
+		err := bkt.ForEach(func(k, v []byte) error {
+			data, _, err := decodeRecord(c, v, false)
+			if err != nil {
+				return errUnreadableRecords
+			}
+			records.contacts[string(k)] = data
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if bkt := tx.Bucket([]byte(dbLabelsBkt)); bkt != nil {
+		err := bkt.ForEach(func(k, v []byte) error {
+			_, label, err := decodeRecord(c, v, true)
+			if err != nil {
+				return errUnreadableRecords
+			}
+			records.labels = append(records.labels, *label)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// writeRecords encrypt records with c replacing the current contents of the database ,
+// then decrypt them back to verify nothing was lost before the transaction commits.
+func writeRecords(tx *bolt.Tx, c *bookCipher, records *bookRecords) error {
+	contacts, err := tx.CreateBucketIfNotExists([]byte(dbAddrsBookBkt))
+	if err != nil {
+		return err
+	}
+	for k, data := range records.contacts {
+		value, err := c.encrypt(data)
+		if err != nil {
+			return err
+		}
+		if err := contacts.Put([]byte(k), value); err != nil {
+			return err
+		}
+	}
+	if tx.Bucket([]byte(dbLabelsBkt)) != nil {
+		if err := tx.DeleteBucket([]byte(dbLabelsBkt)); err != nil {
+			return err
+		}
+	}
+	labels, err := tx.CreateBucketIfNotExists([]byte(dbLabelsBkt))
+	if err != nil {
+		return err
+	}
+	labelData := make(map[string][]byte, len(records.labels))
+	for _, label := range records.labels {
+		key, err := c.labelKey(label.Type, label.Ref)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(label)
+		if err != nil {
+			return err
+		}
+		value, err := c.encrypt(data)
+		if err != nil {
+			return err
+		}
+		if err := labels.Put(key, value); err != nil {
+			return err
+		}
+		labelData[string(key)] = data
+	}
+
+	// Verify
+	for k, data := range records.contacts {
+		if got, err := c.decrypt(contacts.Get([]byte(k))); err != nil || !bytes.Equal(got, data) {
+			return errVerificationFailed
+		}
+	}
+	for k, data := range labelData {
+		if got, err := c.decrypt(labels.Get([]byte(k))); err != nil || !bytes.Equal(got, data) {
+			return errVerificationFailed
+		}
+	}
+	return nil
+}
+
+// putConfig set config parameters within a transaction.
+func putConfig(tx *bolt.Tx, options map[string]string) error {
+	bkt, err := tx.CreateBucketIfNotExists([]byte(dbConfigBkt))
+	if err != nil {
+		return err
+	}
+	for k, v := range options {
+		if err := bkt.Put([]byte(k), []byte(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// securityConfig return the config parameters of new security settings , completed by settings
+// describing its keys. Current settings are kept as previous ones so records left behind by an
+// interrupted change can be recovered.
+func (addrsBook *addrsBook) securityConfig(secType int, settings map[string]string) map[string]string {
+	current := addrsBook.GetStorage().GetConfig()
+	config := map[string]string{
+		SecurityType:     strconv.Itoa(secType),
+		Hash:             "",
+		Entropy:          "",
+		KDF:              "",
+		Check:            "",
+		PrevSecurityType: current[SecurityType],
+		PrevHash:         current[Hash],
+		PrevEntropy:      current[Entropy],
+		PrevKDF:          current[KDF],
+		PrevCheck:        current[Check],
+	}
+	for k, v := range settings {
+		config[k] = v
+	}
+	return config
+}
+
+// previousCiphers return the ciphers records may be encrypted with after an interrupted security change ,
+// the settings in use before the last change and the unprotected formats.
+func (addrsBook *addrsBook) previousCiphers(previousPassword string) []*bookCipher {
+	config := addrsBook.GetStorage().GetConfig()
+	ciphers := make([]*bookCipher, 0, 3)
+	if prevSecType, err := strconv.Atoi(config[PrevSecurityType]); err == nil {
+		if c := previousCipher(config, prevSecType, []byte(previousPassword)); c != nil {
+			ciphers = append(ciphers, c)
+		}
+	}
+	for _, secType := range []int{ObfuscationSecurity, NoSecurity} {
+		c, _ := newBookCipher(secType, nil, nil)
+		ciphers = append(ciphers, c)
+	}
+	return ciphers
+}
+
+// previousCipher return the cipher of the settings in use before the last security change ,
+// nil unless password matches them.
+func previousCipher(config map[string]string, prevSecType int, password []byte) *bookCipher {
+	if prevSecType == PasswordSecurity && config[PrevKDF] != "" {
+		kdf, err := parseKDFParams(config[PrevKDF])
+		if err != nil {
+			return nil
+		}
+		c := newEnvelopeCipher(password, *kdf, nil)
+		if c.verifyCheck(config[PrevCheck]) != nil {
+			return nil
+		}
+		return c
+	}
+	if prevSecType == PasswordSecurity &&
+		bcrypt.CompareHashAndPassword([]byte(config[PrevHash]), password) != nil {
+		return nil
+	}
+	c, err := newBookCipher(prevSecType, password, []byte(config[PrevEntropy]))
+	if err != nil {
+		return nil
+	}
+	return c
+}
+
+// decodeRecord decrypt a stored contact or label , failing unless it decodes to a valid record.
+func decodeRecord(c *bookCipher, value []byte, isLabel bool) ([]byte, *core.Label, error) {
+	data, err := c.decrypt(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isLabel {
+		var label core.Label
+		if err := json.Unmarshal(data, &label); err != nil {
+			return nil, nil, err
+		}
+		if err := validateLabel(label); err != nil {
+			return nil, nil, err
+		}
+		return data, &label, nil
+	}
+	var contact Contact
+	if err := contact.UnmarshalBinary(data); err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
+
+// RecoverSecurity re-encrypts with the current key any record left unreadable by an interrupted
+// security change. Records are decrypted with the security settings in use before the last change ,
+// using previousPassword , or with the unprotected formats. Returns the number of recovered records.
+// Records that still cannot be decrypted are kept untouched and reported with an error.
+func (addrsBook *addrsBook) RecoverSecurity(previousPassword string) (int, error) {
+	logDb.Info("recovering Address Book records")
+	db, isBolt := addrsBook.GetStorage().(*boltStorage)
+	if !isBolt {
+		return 0, errSecurityChangeUnsupported
+	}
+	if _, err := addrsBook.checkLabelsAccess(); err != nil {
+		return 0, err
+	}
+	current, err := addrsBook.cipher()
+	if err != nil {
+		return 0, err
+	}
+	candidates := addrsBook.previousCiphers(previousPassword)
+
+	var recovered, remaining int
+	err = db.Update(func(tx *bolt.Tx) error {
+		recovered, remaining = 0, 0
+		for _, bktName := range []string{dbAddrsBookBkt, dbLabelsBkt} {
+			bkt := tx.Bucket([]byte(bktName))
+			if bkt == nil {
+				continue
+			}
+			isLabel := bktName == dbLabelsBkt
+			unreadable := make(map[string][]byte)
+			if err := bkt.ForEach(func(k, v []byte) error {
+				if _, _, err := decodeRecord(current, v, isLabel); err != nil {
+					unreadable[string(k)] = append([]byte(nil), v...)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			for k, v := range unreadable {
+				n, err := recoverRecord(bkt, current, candidates, []byte(k), v, isLabel)
+				if err != nil {
+					return err
+				}
+				recovered += n
+				remaining += 1 - n
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logDb.Error(err)
+		return 0, err
+	}
+	if recovered > 0 {
+		addrsBook.resetIndex()
+	}
+	if remaining > 0 {
+		return recovered, errUnrecoverableRecords
+	}
+	return recovered, nil
+}
+
+// recoverRecord re-encrypt with current a record readable by any of candidates , returning 1 if recovered.
+func recoverRecord(bkt *bolt.Bucket, current *bookCipher, candidates []*bookCipher, key, value []byte, isLabel bool) (int, error) {
+	for _, c := range candidates {
+		data, label, err := decodeRecord(c, value, isLabel)
+		if err != nil {
+			continue
+		}
+		newKey := key
+		if isLabel {
+			if newKey, err = current.labelKey(label.Type, label.Ref); err != nil {
+				return 0, err
+			}
+			if err := bkt.Delete(key); err != nil {
+				return 0, err
+			}
+		}
+		newValue, err := current.encrypt(data)
+		if err != nil {
+			return 0, err
+		}
+		if err := bkt.Put(newKey, newValue); err != nil {
+			return 0, err
+		}
+		if got, err := current.decrypt(bkt.Get(newKey)); err != nil || !bytes.Equal(got, data) {
+			return 0, errVerificationFailed
+		}
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// encryptAESGCM encrypt a data with AESGCM algorithm. http://golang.org/crypto/
+func encryptAESGCM(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aesGCM, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	cipherText := aesGCM.Seal(nonce, nonce, data, nil)
+	return cipherText, nil
+}
+
+// decryptAESGCM decrypt a data with AESGCM algorithm. http://golang.org/crypto/
+func decryptAESGCM(key, cipherMsg []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aesGCM, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := aesGCM.NonceSize()
+	if len(cipherMsg) < nonceSize {
+		return nil, errInvalidCipherText
+	}
+	nonce, cipherText := cipherMsg[:nonceSize], cipherMsg[nonceSize:]
+
+	return aesGCM.Open(nil, nonce, cipherText, nil)
+}
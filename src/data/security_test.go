@@ -0,0 +1,122 @@
+package data
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/SkycoinProject/skycoin/src/visor/dbutil"
+	"github.com/boltdb/bolt"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/stretchr/testify/require"
+)
+
+// putRawContact stores a contact encrypted with c , bypassing the address book
+func putRawContact(t *testing.T, ab core.AddressBook, c *bookCipher, contact *Contact) uint64 {
+	data, err := contact.MarshalBinary()
+	require.NoError(t, err)
+	value, err := c.encrypt(data)
+	require.NoError(t, err)
+	var id uint64
+	require.NoError(t, ab.GetStorage().(*boltStorage).Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists([]byte(dbAddrsBookBkt))
+		if err != nil {
+			return err
+		}
+		if id, err = bkt.NextSequence(); err != nil {
+			return err
+		}
+		return bkt.Put(dbutil.Itob(id), value)
+	}))
+	return id
+}
+
+func TestChangeSecurity_Atomic(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab, store := openLabelStore(t, PasswordSecurity)
+	defer CloseTest(t, ab)
+	_, err := ab.InsertContact(&Contact{
+		Address: []Address{{Value: []byte(foreignAddr), Coin: []byte("SKY")}},
+		Name:    []byte("contact_test1"),
+	})
+	require.NoError(t, err)
+	label := core.Label{Type: core.LabelTypeAddress, Ref: foreignAddr, Label: "Shop"}
+	require.NoError(t, store.SetLabel(label))
+	config := ab.GetStorage().GetConfig()
+
+	// A record encrypted under an unknown key aborts the change , leaving everything as it was
+	stray, err := newBookCipher(PasswordSecurity, []byte("unknown"), []byte("entropy"))
+	require.NoError(t, err)
+	putRawContact(t, ab, stray, &Contact{Name: []byte("stray")})
+	require.Equal(t, errUnreadableRecords, ab.ChangeSecurity(ObfuscationSecurity, defaultPass, ""))
+	require.Equal(t, config, ab.GetStorage().GetConfig())
+	require.NoError(t, ab.Authenticate(defaultPass))
+	got, err := store.GetLabel(core.LabelTypeAddress, foreignAddr)
+	require.NoError(t, err)
+	require.Equal(t, &label, got)
+}
+
+func TestRecoverSecurity(t *testing.T) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	ab, store := openLabelStore(t, PasswordSecurity)
+	defer CloseTest(t, ab)
+	_, err := ab.InsertContact(&Contact{
+		Address: []Address{{Value: []byte(foreignAddr), Coin: []byte("SKY")}},
+		Name:    []byte("contact_test1"),
+	})
+	require.NoError(t, err)
+	oldKDF := ab.GetStorage().GetConfig()[KDF]
+	require.NoError(t, ab.ChangeSecurity(PasswordSecurity, defaultPass, "new-password"))
+	require.Equal(t, oldKDF, ab.GetStorage().GetConfig()[PrevKDF])
+
+	// Records left behind under the previous key and in plain format
+	kdf, err := parseKDFParams(oldKDF)
+	require.NoError(t, err)
+	putRawContact(t, ab, newEnvelopeCipher([]byte(defaultPass), *kdf, nil), &Contact{Name: []byte("contact_test2")})
+	plain, err := newBookCipher(ObfuscationSecurity, nil, nil)
+	require.NoError(t, err)
+	putRawContact(t, ab, plain, &Contact{Name: []byte("contact_test3")})
+	_, err = ab.ListContact()
+	require.Error(t, err)
+
+	n, err := ab.RecoverSecurity("wrong-password")
+	require.Equal(t, errUnrecoverableRecords, err)
+	require.Equal(t, 1, n)
+	n, err = ab.RecoverSecurity(defaultPass)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = ab.RecoverSecurity(defaultPass)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	contacts, err := ab.ListContact()
+	require.NoError(t, err)
+	names := make([]string, 0, len(contacts))
+	for _, c := range contacts {
+		names = append(names, c.GetName())
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{"contact_test1", "contact_test2", "contact_test3"}, names)
+	require.NoError(t, ab.ChangeSecurity(NoSecurity, "new-password", ""))
+
+	// Labels are rekeyed when recovered
+	label := core.Label{Type: core.LabelTypeWallet, Ref: testWalletID, Label: "Main"}
+	require.NoError(t, ab.ChangeSecurity(PasswordSecurity, "", defaultPass))
+	data, err := json.Marshal(label)
+	require.NoError(t, err)
+	require.NoError(t, ab.GetStorage().(*boltStorage).Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists([]byte(dbLabelsBkt))
+		if err != nil {
+			return err
+		}
+		return bkt.Put([]byte("legacy"), data)
+	}))
+	n, err = ab.RecoverSecurity("")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	got, err := store.GetLabel(core.LabelTypeWallet, testWalletID)
+	require.NoError(t, err)
+	require.Equal(t, &label, got)
+}
@@ -0,0 +1,415 @@
+package data
+
+import (
+	"sort"
+
+	"github.com/SkycoinProject/skycoin/src/visor/dbutil"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+)
+
+const (
+	// Db buckets.
+	dbTxnIndexBkt = "TxnIndex"
+	dbTxnSyncBkt  = "TxnSync"
+	dbAddrSyncBkt = "AddressSync"
+)
+
+// IndexTxn records a confirmed transaction involving a wallet.
+// Transactions already recorded for the wallet are left untouched.
+func (b *boltStorage) IndexTxn(walletID string, txn core.Transaction, blockSeq uint64, direction core.TxnDirection, addresses []string) error {
+	record, err := newTxnRecord(txn, blockSeq, direction, addresses)
+	if err != nil {
+		logDb.WithError(err).Error("Couldn't take transaction snapshot")
+		return err
+	}
+	err = b.UpdateCollections(func(tx core.StorageTxn) error {
+		key := walletKey(walletID, record.ID)
+		var value []byte
+		if found, err := tx.Get(dbTxnIndexBkt, key, &value); err != nil || found {
+			return err
+		}
+		return tx.Put(dbTxnIndexBkt, key, record)
+	})
+	if err != nil {
+		logDb.Error(err)
+	}
+	return err
+}
+
+// HasTxn determines whether a transaction has been recorded for a wallet.
+func (b *boltStorage) HasTxn(walletID, txnID string) (bool, error) {
+	found := false
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		var value []byte
+		var err error
+		found, err = tx.Get(dbTxnIndexBkt, walletKey(walletID, txnID), &value)
+		return err
+	})
+	return found, err
+}
+
+// LastBlockSeq returns the block seq wallet history was synchronized up to.
+func (b *boltStorage) LastBlockSeq(walletID string) (uint64, error) {
+	var seq uint64
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		var value []byte
+		found, err := tx.Get(dbTxnSyncBkt, []byte(walletID), &value)
+		if found {
+			seq = dbutil.Btoi(value)
+		}
+		return err
+	})
+	return seq, err
+}
+
+// SetLastBlockSeq updates the block seq wallet history was synchronized up to.
+func (b *boltStorage) SetLastBlockSeq(walletID string, blockSeq uint64) error {
+	return b.UpdateCollections(func(tx core.StorageTxn) error {
+		return tx.Put(dbTxnSyncBkt, []byte(walletID), dbutil.Itob(blockSeq))
+	})
+}
+
+// AddressSeqs returns the block seq history of each wallet address was synchronized up to.
+func (b *boltStorage) AddressSeqs(walletID string) (map[string]uint64, error) {
+	seqs := make(map[string]uint64)
+	prefix := walletPrefix(walletID)
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		return tx.ScanPrefix(dbAddrSyncBkt, prefix, func(item core.StorageItem) (bool, error) {
+			var value []byte
+			if err := item.Decode(&value); err != nil {
+				return false, err
+			}
+			seqs[string(item.Key()[len(prefix):])] = dbutil.Btoi(value)
+			return true, nil
+		})
+	})
+	return seqs, err
+}
+
+// SetAddressSeqs updates the block seq history of wallet addresses was synchronized up to.
+func (b *boltStorage) SetAddressSeqs(walletID string, addresses []string, blockSeq uint64) error {
+	return b.UpdateCollections(func(tx core.StorageTxn) error {
+		for _, addr := range addresses {
+			if err := tx.Put(dbAddrSyncBkt, walletKey(walletID, addr), dbutil.Itob(blockSeq)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListTxns returns recorded wallet transactions matching query , newest first.
+func (b *boltStorage) ListTxns(walletID string, query core.TxnQuery) (core.TransactionIterator, error) {
+	records := make([]*txnRecord, 0)
+	err := b.ViewCollections(func(tx core.StorageTxn) error {
+		return tx.ScanPrefix(dbTxnIndexBkt, walletPrefix(walletID), func(item core.StorageItem) (bool, error) {
+			var record txnRecord
+			if err := item.Decode(&record); err != nil {
+				return false, err
+			}
+			if record.matches(query) {
+				records = append(records, &record)
+			}
+			return true, nil
+		})
+	})
+	if err != nil {
+		logDb.Error(err)
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].BlockSeq == records[j].BlockSeq {
+			return records[i].Timestamp > records[j].Timestamp
+		}
+		return records[i].BlockSeq > records[j].BlockSeq
+	})
+	txns := make([]core.Transaction, len(records))
+	for i, record := range records {
+		txns[i] = record
+	}
+	return newTxnRecordIterator(txns), nil
+}
+
+// txnRecord is the snapshot of a confirmed transaction kept in the index.
+// It implements core.Transaction so that cached history can be rendered
+// without querying the node.
+type txnRecord struct {
+	ID        string            `json:"id"`
+	BlockSeq  uint64            `json:"seq"`
+	Timestamp uint64            `json:"timestamp"`
+	Direction uint32            `json:"direction"`
+	Addresses []string          `json:"addresses"`
+	Assets    []string          `json:"assets"`
+	Fees      map[string]uint64 `json:"fees"`
+	Inputs    []*txnInputRecord `json:"inputs"`
+	Outputs   []*txnOutRecord   `json:"outputs"`
+}
+
+type txnInputRecord struct {
+	ID    string            `json:"id"`
+	Coins map[string]uint64 `json:"coins"`
+	Spent *txnOutRecord     `json:"spent"`
+}
+
+type txnOutRecord struct {
+	ID      string            `json:"id"`
+	Address string            `json:"address"`
+	Coins   map[string]uint64 `json:"coins"`
+}
+
+func newTxnRecord(txn core.Transaction, blockSeq uint64, direction core.TxnDirection, addresses []string) (*txnRecord, error) {
+	record := &txnRecord{
+		ID:        txn.GetId(),
+		BlockSeq:  blockSeq,
+		Timestamp: uint64(txn.GetTimestamp()),
+		Direction: uint32(direction),
+		Addresses: addresses,
+		Assets:    txn.SupportedAssets(),
+		Fees:      make(map[string]uint64),
+	}
+	for _, ticker := range record.Assets {
+		if fee, err := txn.ComputeFee(ticker); err == nil {
+			record.Fees[ticker] = fee
+		}
+	}
+	for _, in := range txn.GetInputs() {
+		spent, err := in.GetSpentOutput()
+		if err != nil {
+			return nil, err
+		}
+		spentRecord, err := newTxnOutRecord(spent)
+		if err != nil {
+			return nil, err
+		}
+		record.Inputs = append(record.Inputs, &txnInputRecord{
+			ID:    in.GetId(),
+			Coins: coinsOf(in.SupportedAssets(), in.GetCoins),
+			Spent: spentRecord,
+		})
+	}
+	for _, out := range txn.GetOutputs() {
+		outRecord, err := newTxnOutRecord(out)
+		if err != nil {
+			return nil, err
+		}
+		record.Outputs = append(record.Outputs, outRecord)
+	}
+	return record, nil
+}
+
+func newTxnOutRecord(out core.TransactionOutput) (*txnOutRecord, error) {
+	addr, err := out.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	return &txnOutRecord{
+		ID:      out.GetId(),
+		Address: addr.String(),
+		Coins:   coinsOf(out.SupportedAssets(), out.GetCoins),
+	}, nil
+}
+
+func coinsOf(tickers []string, getCoins func(string) (uint64, error)) map[string]uint64 {
+	coins := make(map[string]uint64, len(tickers))
+	for _, ticker := range tickers {
+		if val, err := getCoins(ticker); err == nil {
+			coins[ticker] = val
+		}
+	}
+	return coins
+}
+
+func (r *txnRecord) matches(query core.TxnQuery) bool {
+	if query.Since != 0 && r.Timestamp < uint64(query.Since) {
+		return false
+	}
+	if query.Until != 0 && r.Timestamp > uint64(query.Until) {
+		return false
+	}
+	if query.Direction != core.TxnDirectionAny && core.TxnDirection(r.Direction) != query.Direction {
+		return false
+	}
+	if len(query.Addresses) == 0 {
+		return true
+	}
+	for _, addr := range r.Addresses {
+		if util.StringInList(addr, query.Addresses) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedAssets crypto assets involved in this transaction
+func (r *txnRecord) SupportedAssets() []string {
+	return r.Assets
+}
+
+// GetTimestamp at the moment of creation
+func (r *txnRecord) GetTimestamp() core.Timestamp {
+	return core.Timestamp(r.Timestamp)
+}
+
+// GetStatus indexed transactions are always confirmed
+func (r *txnRecord) GetStatus() core.TransactionStatus {
+	return core.TXN_STATUS_CONFIRMED
+}
+
+// GetInputs to list transaction inputs for spent transactions
+func (r *txnRecord) GetInputs() []core.TransactionInput {
+	inputs := make([]core.TransactionInput, len(r.Inputs))
+	for i, in := range r.Inputs {
+		inputs[i] = in
+	}
+	return inputs
+}
+
+// GetOutputs to list transaction outputs for coins distributed to participants
+func (r *txnRecord) GetOutputs() []core.TransactionOutput {
+	outputs := make([]core.TransactionOutput, len(r.Outputs))
+	for i, out := range r.Outputs {
+		outputs[i] = out
+	}
+	return outputs
+}
+
+// GetId to retrieve transaction ID
+func (r *txnRecord) GetId() string {
+	return r.ID
+}
+
+// GetBlockSeq returns the sequence number of the block including this transaction
+func (r *txnRecord) GetBlockSeq() uint64 {
+	return r.BlockSeq
+}
+
+// GetDirection of funds relative to the wallet the transaction was indexed for
+func (r *txnRecord) GetDirection() core.TxnDirection {
+	return core.TxnDirection(r.Direction)
+}
+
+// ComputeFee returns the fee recorded for the asset represented by ticker
+func (r *txnRecord) ComputeFee(ticker string) (uint64, error) {
+	if fee, hasFee := r.Fees[ticker]; hasFee {
+		return fee, nil
+	}
+	return 0, errors.ErrInvalidAltcoinTicker
+}
+
+// VerifyUnsigned confirmed transactions are signed
+func (r *txnRecord) VerifyUnsigned() error {
+	return errors.ErrInvalidTxn
+}
+
+// VerifySigned confirmed transactions were verified by the network
+func (r *txnRecord) VerifySigned() error {
+	return nil
+}
+
+// IsFullySigned confirmed transactions are fully signed
+func (r *txnRecord) IsFullySigned() (bool, error) {
+	return true, nil
+}
+
+// GetId provides transaction input ID
+func (in *txnInputRecord) GetId() string {
+	return in.ID
+}
+
+// GetSpentOutput returns the recorded output spent by this input
+func (in *txnInputRecord) GetSpentOutput() (core.TransactionOutput, error) {
+	if in.Spent == nil {
+		return nil, errors.ErrNilValue
+	}
+	return in.Spent, nil
+}
+
+// GetCoins looks up coins for asset represented by ticker spent by this input
+func (in *txnInputRecord) GetCoins(ticker string) (uint64, error) {
+	if coins, hasCoins := in.Coins[ticker]; hasCoins {
+		return coins, nil
+	}
+	return 0, errors.ErrInvalidAltcoinTicker
+}
+
+// SupportedAssets enumerates tickers of crypto assets supported by this input
+func (in *txnInputRecord) SupportedAssets() []string {
+	return sortedTickers(in.Coins)
+}
+
+// GetId provides transaction output ID
+func (out *txnOutRecord) GetId() string {
+	return out.ID
+}
+
+// IsSpent is unknown for recorded outputs , hence reported as unspent
+func (out *txnOutRecord) IsSpent() bool {
+	return false
+}
+
+// GetAddress returns the address of the party receiving funds
+func (out *txnOutRecord) GetAddress() (core.Address, error) {
+	addr := util.NewGenericAddress(out.Address)
+	return &addr, nil
+}
+
+// GetCoins looks up coins for asset represented by ticker transferred in this output
+func (out *txnOutRecord) GetCoins(ticker string) (uint64, error) {
+	if coins, hasCoins := out.Coins[ticker]; hasCoins {
+		return coins, nil
+	}
+	return 0, errors.ErrInvalidAltcoinTicker
+}
+
+// SupportedAssets enumerates tickers of crypto assets supported by this output
+func (out *txnOutRecord) SupportedAssets() []string {
+	return sortedTickers(out.Coins)
+}
+
+func sortedTickers(coins map[string]uint64) []string {
+	tickers := make([]string, 0, len(coins))
+	for t := range coins {
+		tickers = append(tickers, t)
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+type txnRecordIterator struct {
+	current int
+	txns    []core.Transaction
+}
+
+func newTxnRecordIterator(txns []core.Transaction) *txnRecordIterator {
+	return &txnRecordIterator{current: -1, txns: txns}
+}
+
+// Value of transaction at iterator pointer position
+func (it *txnRecordIterator) Value() core.Transaction {
+	return it.txns[it.current]
+}
+
+// Next discards current value and moves iteration pointer up to next item
+func (it *txnRecordIterator) Next() bool {
+	if it.HasNext() {
+		it.current++
+		return true
+	}
+	return false
+}
+
+// HasNext may be used to query whether more items are to be expected in the sequence
+func (it *txnRecordIterator) HasNext() bool {
+	return (it.current + 1) < len(it.txns)
+}
+
+// Type assertions
+var (
+	_ core.TxnIndex              = &boltStorage{}
+	_ core.BlockchainTransaction = &txnRecord{}
+	_ core.TransactionInput      = &txnInputRecord{}
+	_ core.TransactionOutput     = &txnOutRecord{}
+	_ core.TransactionIterator   = &txnRecordIterator{}
+)
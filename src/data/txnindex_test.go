@@ -0,0 +1,311 @@
+package data
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	walletAddr1  = "9BSEAEE3XGtQ2X43BCT2XCYgheGLQQigEG"
+	walletAddr2  = "25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ"
+	foreignAddr  = "2TFC2Ktc6Y3UAUqo7WGA55X6mqoKZRaFp9s"
+	testWalletID = "test.wlt"
+)
+
+func makeTestTxn(id string, seq, ts uint64, from, to string) *txnRecord {
+	return &txnRecord{
+		ID:        id,
+		BlockSeq:  seq,
+		Timestamp: ts,
+		Assets:    []string{skycoin.Sky, skycoin.CoinHour},
+		Fees:      map[string]uint64{skycoin.Sky: 0, skycoin.CoinHour: 5},
+		Inputs: []*txnInputRecord{
+			{
+				ID:    id + "-in",
+				Coins: map[string]uint64{skycoin.Sky: 2000000, skycoin.CoinHour: 10},
+				Spent: &txnOutRecord{
+					ID:      id + "-spent",
+					Address: from,
+					Coins:   map[string]uint64{skycoin.Sky: 2000000, skycoin.CoinHour: 10},
+				},
+			},
+		},
+		Outputs: []*txnOutRecord{
+			{
+				ID:      id + "-out",
+				Address: to,
+				Coins:   map[string]uint64{skycoin.Sky: 2000000, skycoin.CoinHour: 5},
+			},
+		},
+	}
+}
+
+func openTxnIndex(t *testing.T) *boltStorage {
+	db, err := GetBoltStorage(GetFilePath(t))
+	require.NoError(t, err)
+	return db
+}
+
+func closeTxnIndex(t *testing.T, db *boltStorage) {
+	path := db.Path()
+	require.NoError(t, db.Close())
+	require.NoError(t, os.Remove(path))
+}
+
+func TestBoltStorage_IndexTxn(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	txn := makeTestTxn("txn1", 10, 1000, foreignAddr, walletAddr1)
+	require.NoError(t, db.IndexTxn(testWalletID, txn, 10, core.TxnDirectionReceived, []string{walletAddr1}))
+	// Indexing twice keeps a single record
+	require.NoError(t, db.IndexTxn(testWalletID, txn, 10, core.TxnDirectionReceived, []string{walletAddr1}))
+
+	has, err := db.HasTxn(testWalletID, "txn1")
+	require.NoError(t, err)
+	require.True(t, has)
+	has, err = db.HasTxn("other.wlt", "txn1")
+	require.NoError(t, err)
+	require.False(t, has)
+
+	it, err := db.ListTxns(testWalletID, core.TxnQuery{})
+	require.NoError(t, err)
+	require.True(t, it.Next())
+	got := it.Value()
+	require.False(t, it.Next())
+
+	require.Equal(t, "txn1", got.GetId())
+	require.Equal(t, core.Timestamp(1000), got.GetTimestamp())
+	require.Equal(t, core.TXN_STATUS_CONFIRMED, got.GetStatus())
+	require.Equal(t, uint64(10), got.(core.BlockchainTransaction).GetBlockSeq())
+	fee, err := got.ComputeFee(skycoin.CoinHour)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), fee)
+	_, err = got.ComputeFee(skycoin.CalculatedHour)
+	require.Error(t, err)
+
+	require.Len(t, got.GetInputs(), 1)
+	spent, err := got.GetInputs()[0].GetSpentOutput()
+	require.NoError(t, err)
+	addr, err := spent.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, foreignAddr, addr.String())
+	coins, err := got.GetInputs()[0].GetCoins(skycoin.Sky)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2000000), coins)
+
+	require.Len(t, got.GetOutputs(), 1)
+	addr, err = got.GetOutputs()[0].GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, walletAddr1, addr.String())
+	coins, err = got.GetOutputs()[0].GetCoins(skycoin.CoinHour)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), coins)
+}
+
+func TestBoltStorage_ListTxns(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	require.NoError(t, db.IndexTxn(testWalletID, makeTestTxn("txn1", 1, 100, foreignAddr, walletAddr1),
+		1, core.TxnDirectionReceived, []string{walletAddr1}))
+	require.NoError(t, db.IndexTxn(testWalletID, makeTestTxn("txn2", 2, 200, walletAddr1, foreignAddr),
+		2, core.TxnDirectionSent, []string{walletAddr1}))
+	require.NoError(t, db.IndexTxn(testWalletID, makeTestTxn("txn3", 3, 300, walletAddr1, walletAddr2),
+		3, core.TxnDirectionInternal, []string{walletAddr1, walletAddr2}))
+
+	tests := []struct {
+		name  string
+		query core.TxnQuery
+		want  []string
+	}{
+		{name: "all", query: core.TxnQuery{}, want: []string{"txn3", "txn2", "txn1"}},
+		{name: "since", query: core.TxnQuery{Since: 200}, want: []string{"txn3", "txn2"}},
+		{name: "until", query: core.TxnQuery{Until: 200}, want: []string{"txn2", "txn1"}},
+		{name: "range", query: core.TxnQuery{Since: 150, Until: 250}, want: []string{"txn2"}},
+		{name: "sent", query: core.TxnQuery{Direction: core.TxnDirectionSent}, want: []string{"txn2"}},
+		{name: "received", query: core.TxnQuery{Direction: core.TxnDirectionReceived}, want: []string{"txn1"}},
+		{name: "address", query: core.TxnQuery{Addresses: []string{walletAddr2}}, want: []string{"txn3"}},
+		{name: "unknown-address", query: core.TxnQuery{Addresses: []string{foreignAddr}}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it, err := db.ListTxns(testWalletID, tt.query)
+			require.NoError(t, err)
+			got := make([]string, 0)
+			for it.Next() {
+				got = append(got, it.Value().GetId())
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	it, err := db.ListTxns("empty.wlt", core.TxnQuery{})
+	require.NoError(t, err)
+	require.False(t, it.HasNext())
+}
+
+func mockWalletWithHistory(txns []core.Transaction) *mocks.Wallet {
+	account := new(mocks.CryptoAccount)
+	account.On("ListTransactions").Return(func() core.TransactionIterator {
+		return skycoin.NewSkycoinTransactionIterator(txns)
+	})
+	addrs := make([]core.Address, 0)
+	for _, a := range []string{walletAddr1, walletAddr2} {
+		addr := new(mocks.Address)
+		addr.On("String").Return(a)
+		addr.On("GetCryptoAccount").Return(account)
+		addrs = append(addrs, addr)
+	}
+	wlt := new(mocks.Wallet)
+	wlt.On("GetId").Return(testWalletID)
+	wlt.On("GetLoadedAddresses").Return(func() core.AddressIterator {
+		return skycoin.NewSkycoinAddressIterator(addrs)
+	}, nil)
+	return wlt
+}
+
+func TestSyncWalletTxns(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	txns := []core.Transaction{
+		makeTestTxn("txn1", 1, 100, foreignAddr, walletAddr1),
+		makeTestTxn("txn2", 2, 200, walletAddr1, foreignAddr),
+		makeTestTxn("txn3", 3, 300, walletAddr1, walletAddr2),
+	}
+	wlt := mockWalletWithHistory(txns)
+	bc := new(mocks.BlockchainStatus)
+	bc.On("GetNumberOfBlocks").Return(uint64(3), nil).Once()
+
+	newTxns, err := SyncWalletTxns(db, wlt, bc)
+	require.NoError(t, err)
+	require.Len(t, newTxns, 3)
+	seq, err := db.LastBlockSeq(testWalletID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), seq)
+
+	for id, direction := range map[string]core.TxnDirection{
+		"txn1": core.TxnDirectionReceived,
+		"txn2": core.TxnDirectionSent,
+		"txn3": core.TxnDirectionInternal,
+	} {
+		it, err := db.ListTxns(testWalletID, core.TxnQuery{Direction: direction})
+		require.NoError(t, err)
+		require.True(t, it.Next())
+		require.Equal(t, id, it.Value().GetId())
+	}
+
+	// No new blocks , node is not queried
+	bc.On("GetNumberOfBlocks").Return(uint64(3), nil).Once()
+	newTxns, err = SyncWalletTxns(db, wlt, bc)
+	require.NoError(t, err)
+	require.Empty(t, newTxns)
+	wlt.AssertNumberOfCalls(t, "GetLoadedAddresses", 1)
+
+	// New block , only unseen transactions are reported
+	txns = append(txns, makeTestTxn("txn4", 4, 400, foreignAddr, walletAddr2))
+	wlt = mockWalletWithHistory(txns)
+	bc.On("GetNumberOfBlocks").Return(uint64(4), nil).Once()
+	newTxns, err = SyncWalletTxns(db, wlt, bc)
+	require.NoError(t, err)
+	require.Len(t, newTxns, 1)
+	require.Equal(t, "txn4", newTxns[0].GetId())
+	bc.AssertExpectations(t)
+	mock.AssertExpectationsForObjects(t, wlt)
+}
+
+// scanningBlockchain serves address history block by block
+type scanningBlockchain struct {
+	*mocks.BlockchainStatus
+	blocks  map[uint64][]core.Transaction
+	pending []core.Transaction
+	scanned [][2]uint64
+}
+
+func (bc *scanningBlockchain) ScanBlockTxns(addresses []string, startSeq, endSeq uint64) ([]core.Transaction, error) {
+	bc.scanned = append(bc.scanned, [2]uint64{startSeq, endSeq})
+	txns := make([]core.Transaction, 0)
+	for seq := startSeq; seq <= endSeq; seq++ {
+		txns = append(txns, bc.blocks[seq]...)
+	}
+	return txns, nil
+}
+
+func (bc *scanningBlockchain) PendingTxns(addresses []string) ([]core.Transaction, error) {
+	return bc.pending, nil
+}
+
+func TestSyncWalletTxnsIncremental(t *testing.T) {
+	db := openTxnIndex(t)
+	defer closeTxnIndex(t, db)
+
+	txns := []core.Transaction{
+		makeTestTxn("txn1", 1, 100, foreignAddr, walletAddr1),
+		makeTestTxn("txn2", 2, 200, walletAddr1, foreignAddr),
+	}
+	wlt := mockWalletWithHistory(txns)
+	bc := &scanningBlockchain{
+		BlockchainStatus: new(mocks.BlockchainStatus),
+		blocks:           make(map[uint64][]core.Transaction),
+	}
+
+	// First synchronization downloads the whole history of every address
+	bc.On("GetNumberOfBlocks").Return(uint64(2), nil).Once()
+	newTxns, err := SyncWalletTxns(db, wlt, bc)
+	require.NoError(t, err)
+	require.Len(t, newTxns, 2)
+	require.Empty(t, bc.scanned)
+	seqs, err := db.AddressSeqs(testWalletID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{walletAddr1: 2, walletAddr2: 2}, seqs)
+
+	// Later only new blocks are scanned , the address history is not downloaded again
+	bc.blocks[3] = []core.Transaction{makeTestTxn("txn3", 3, 300, foreignAddr, walletAddr2)}
+	bc.blocks[4] = []core.Transaction{makeTestTxn("txn4", 4, 400, walletAddr2, foreignAddr)}
+	// Transactions only served by address history would show up if it were downloaded
+	wlt = mockWalletWithHistory(append(txns, makeTestTxn("unscanned", 3, 300, foreignAddr, walletAddr1)))
+	bc.On("GetNumberOfBlocks").Return(uint64(4), nil).Once()
+	newTxns, err = SyncWalletTxns(db, wlt, bc)
+	require.NoError(t, err)
+	require.Len(t, newTxns, 2)
+	require.Equal(t, "txn3", newTxns[0].GetId())
+	require.Equal(t, "txn4", newTxns[1].GetId())
+	require.Equal(t, [][2]uint64{{3, 4}}, bc.scanned)
+
+	// No new blocks , only pending transactions are looked up
+	bc.pending = []core.Transaction{&pendingTxn{makeTestTxn("txn5", 0, 500, walletAddr1, foreignAddr)}}
+	bc.On("GetNumberOfBlocks").Return(uint64(4), nil).Once()
+	newTxns, err = SyncWalletTxns(db, wlt, bc)
+	require.NoError(t, err)
+	require.Len(t, newTxns, 1)
+	require.Equal(t, "txn5", newTxns[0].GetId())
+	require.Len(t, bc.scanned, 1)
+	has, err := db.HasTxn(testWalletID, "txn5")
+	require.NoError(t, err)
+	require.False(t, has)
+
+	it, err := db.ListTxns(testWalletID, core.TxnQuery{})
+	require.NoError(t, err)
+	ids := make([]string, 0)
+	for it.Next() {
+		ids = append(ids, it.Value().GetId())
+	}
+	require.Equal(t, []string{"txn4", "txn3", "txn2", "txn1"}, ids)
+	bc.AssertExpectations(t)
+}
+
+// pendingTxn is a transaction not included in any block yet
+type pendingTxn struct {
+	*txnRecord
+}
+
+func (txn *pendingTxn) GetStatus() core.TransactionStatus {
+	return core.TXN_STATUS_PENDING
+}
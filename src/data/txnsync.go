@@ -0,0 +1,211 @@
+package data
+
+import (
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+// maxScanBlocks largest number of blocks scanned to bring an address history up to date ,
+// addresses lagging further behind have their whole history downloaded instead
+const maxScanBlocks = 1000
+
+// SyncWalletTxns brings the transaction index of a wallet up to date with the blockchain.
+// Addresses are synchronized from the last block seen for each of them , scanning only new blocks
+// if the blockchain implements core.BlockTxnScanner and downloading the whole address history otherwise.
+// Transactions seen for the first time are returned, including those still pending.
+func SyncWalletTxns(index core.TxnIndex, wlt core.Wallet, bc core.BlockchainStatus) ([]core.Transaction, error) {
+	height, err := bc.GetNumberOfBlocks()
+	if err != nil {
+		logDb.WithError(err).Warn("Couldn't get number of blocks")
+		return nil, err
+	}
+	scanner, canScan := bc.(core.BlockTxnScanner)
+	lastSeq, err := index.LastBlockSeq(wlt.GetId())
+	if err != nil {
+		return nil, err
+	}
+	// Without scanner pending transactions are only looked up along with new blocks
+	if !canScan && lastSeq != 0 && height <= lastSeq {
+		return nil, nil
+	}
+
+	addrIter, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		logDb.WithError(err).Warn("Couldn't get loaded addresses")
+		return nil, err
+	}
+	addrSeqs, err := index.AddressSeqs(wlt.GetId())
+	if err != nil {
+		return nil, err
+	}
+	syncer := &txnSyncer{
+		index:       index,
+		walletID:    wlt.GetId(),
+		walletAddrs: make(map[string]struct{}),
+		height:      height,
+		seen:        make(map[string]struct{}),
+		newTxns:     make([]core.Transaction, 0),
+	}
+	allAddrs := make([]string, 0)
+	outdated := make([]core.Address, 0)
+	scanAddrs := make([]string, 0)
+	var scanFrom uint64
+	for addrIter.Next() {
+		addr := addrIter.Value()
+		syncer.walletAddrs[addr.String()] = struct{}{}
+		allAddrs = append(allAddrs, addr.String())
+		seq := addrSeqs[addr.String()]
+		switch {
+		case seq >= height:
+		case canScan && seq != 0 && height-seq <= maxScanBlocks:
+			if len(scanAddrs) == 0 || seq+1 < scanFrom {
+				scanFrom = seq + 1
+			}
+			scanAddrs = append(scanAddrs, addr.String())
+		default:
+			outdated = append(outdated, addr)
+		}
+	}
+
+	for _, addr := range outdated {
+		txnIter := addr.GetCryptoAccount().ListTransactions()
+		if txnIter == nil {
+			logDb.Warn("Couldn't get transaction iterator")
+			return nil, errTxnHistoryUnavailable
+		}
+		for txnIter.Next() {
+			if err := syncer.add(txnIter.Value()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(scanAddrs) != 0 {
+		txns, err := scanner.ScanBlockTxns(scanAddrs, scanFrom, height)
+		if err != nil {
+			logDb.WithError(err).Warn("Couldn't scan blocks for address history")
+			return nil, err
+		}
+		for _, txn := range txns {
+			if err := syncer.add(txn); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if canScan && len(allAddrs) != 0 {
+		txns, err := scanner.PendingTxns(allAddrs)
+		if err != nil {
+			logDb.WithError(err).Warn("Couldn't get pending transactions")
+			return nil, err
+		}
+		for _, txn := range txns {
+			if err := syncer.add(txn); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	synced := scanAddrs
+	for _, addr := range outdated {
+		synced = append(synced, addr.String())
+	}
+	if len(synced) != 0 {
+		if err := index.SetAddressSeqs(wlt.GetId(), synced, height); err != nil {
+			return nil, err
+		}
+	}
+	if err := index.SetLastBlockSeq(wlt.GetId(), height); err != nil {
+		return nil, err
+	}
+	return syncer.newTxns, nil
+}
+
+// txnSyncer records transactions found while synchronizing a wallet history
+type txnSyncer struct {
+	index       core.TxnIndex
+	walletID    string
+	walletAddrs map[string]struct{}
+	height      uint64
+	seen        map[string]struct{}
+	newTxns     []core.Transaction
+}
+
+// add indexes a confirmed transaction not recorded yet , pending transactions are only reported
+func (s *txnSyncer) add(txn core.Transaction) error {
+	if _, isSeen := s.seen[txn.GetId()]; isSeen {
+		return nil
+	}
+	s.seen[txn.GetId()] = struct{}{}
+	if txn.GetStatus() != core.TXN_STATUS_CONFIRMED {
+		s.newTxns = append(s.newTxns, txn)
+		return nil
+	}
+	indexed, err := s.index.HasTxn(s.walletID, txn.GetId())
+	if err != nil {
+		return err
+	}
+	if indexed {
+		return nil
+	}
+	direction, involved, err := classifyTxn(txn, s.walletAddrs)
+	if err != nil {
+		logDb.WithError(err).Warn("Couldn't classify transaction ", txn.GetId())
+		return err
+	}
+	blockSeq := s.height
+	if bcTxn, isBcTxn := txn.(core.BlockchainTransaction); isBcTxn {
+		blockSeq = bcTxn.GetBlockSeq()
+	}
+	if err := s.index.IndexTxn(s.walletID, txn, blockSeq, direction, involved); err != nil {
+		return err
+	}
+	s.newTxns = append(s.newTxns, txn)
+	return nil
+}
+
+// classifyTxn determines the direction of a transaction relative to a set of wallet addresses
+// and lists the wallet addresses it involves
+func classifyTxn(txn core.Transaction, walletAddrs map[string]struct{}) (core.TxnDirection, []string, error) {
+	involved := make([]string, 0)
+	added := make(map[string]struct{})
+	addInvolved := func(addr string) bool {
+		if _, isOwn := walletAddrs[addr]; !isOwn {
+			return false
+		}
+		if _, isAdded := added[addr]; !isAdded {
+			added[addr] = struct{}{}
+			involved = append(involved, addr)
+		}
+		return true
+	}
+
+	sent := false
+	for _, in := range txn.GetInputs() {
+		out, err := in.GetSpentOutput()
+		if err != nil {
+			return core.TxnDirectionAny, nil, err
+		}
+		addr, err := out.GetAddress()
+		if err != nil {
+			return core.TxnDirectionAny, nil, err
+		}
+		if addInvolved(addr.String()) {
+			sent = true
+		}
+	}
+	internal := true
+	for _, out := range txn.GetOutputs() {
+		addr, err := out.GetAddress()
+		if err != nil {
+			return core.TxnDirectionAny, nil, err
+		}
+		if !addInvolved(addr.String()) {
+			internal = false
+		}
+	}
+	if !sent {
+		return core.TxnDirectionReceived, involved, nil
+	}
+	if internal {
+		return core.TxnDirectionInternal, involved, nil
+	}
+	return core.TxnDirectionSent, involved, nil
+}
@@ -70,8 +70,80 @@ var (
 	ErrKeyNotFound = errors.New("Key not found")
 	// ErrHwUnexpected unexpected error with sky-hw
 	ErrHwUnexpected = errors.New("Unexpected error with device")
+	// ErrHwPassphraseRequired device requested a seed passphrase that could not be read
+	ErrHwPassphraseRequired = errors.New("Hardware wallet requires a seed passphrase")
 	// ErrHwSignTransactionCanceled cancelled by user in the physical device
 	ErrHwSignTransactionCanceled = errors.New("Sign transaction with hardware wallet has been canceled")
 	// ErrNilValue object should not be null
 	ErrNilValue = errors.New("Object should not be null")
+	// ErrOutputExcluded output frozen or reserved by coin control
+	ErrOutputExcluded = errors.New("Output excluded by coin control")
+	// ErrNoSpendableOutputs every output available for spending is excluded by coin control
+	ErrNoSpendableOutputs = errors.New("No spendable outputs available")
+	// ErrNoOutputsToConsolidate less than two spendable outputs hold coins below the threshold
+	ErrNoOutputsToConsolidate = errors.New("Not enough outputs to consolidate")
+	// ErrInvalidSplitAmounts split amounts are zero , too precise or exceed output coins
+	ErrInvalidSplitAmounts = errors.New("Invalid split amounts")
+	// ErrInsufficientCoinHours not enough coin hours to pay transaction fee
+	ErrInsufficientCoinHours = errors.New("Insufficient coin hours to pay transaction fee")
+	// ErrTxnTooLarge transaction exceeds the maximum size accepted by the node
+	ErrTxnTooLarge = errors.New("Transaction exceeds maximum size")
+	// ErrTxnNotConfirmed transaction not included in any block yet
+	ErrTxnNotConfirmed = errors.New("Transaction not confirmed")
+	// ErrConfirmationTimeout transaction did not reach the expected confirmations in time
+	ErrConfirmationTimeout = errors.New("Timeout waiting for transaction confirmations")
+	// ErrInvalidPaymentURI payment request URI is malformed or refers to an unknown coin
+	ErrInvalidPaymentURI = errors.New("Invalid payment request URI")
+	// ErrInvalidAmountPrecision amount has more decimal places than supported by the coin
+	ErrInvalidAmountPrecision = errors.New("Amount exceeds coin precision")
+	// ErrQRPayloadTooLong payload does not fit in the largest QR code
+	ErrQRPayloadTooLong = errors.New("Payload too long for QR code")
+	// ErrInvalidPayoutFile batch payout file is malformed
+	ErrInvalidPayoutFile = errors.New("Invalid payout file")
+	// ErrPayoutHoursMismatch coin hours set for some payouts of a batch but not for all of them
+	ErrPayoutHoursMismatch = errors.New("Coin hours must be set for every payout or none")
+	// ErrNoValidPayouts every row of a batch payout was rejected
+	ErrNoValidPayouts = errors.New("No valid payouts in batch")
+	// ErrContactNotFound no contact in the address book matches the given name or ID
+	ErrContactNotFound = errors.New("Contact not found")
+	// ErrContactNoAddress contact has no valid address for the requested coin
+	ErrContactNoAddress = errors.New("Contact has no address for this coin")
+	// ErrAmbiguousContact contact has several addresses for the requested coin
+	ErrAmbiguousContact = errors.New("Contact has several addresses for this coin")
+	// ErrSeedPassphraseUnsupported seed passphrase given for a wallet type not supporting it
+	ErrSeedPassphraseUnsupported = errors.New("Seed passphrase is supported by BIP44 wallets only")
+	// ErrInvalidShare share mnemonic with unknown words , bad length or invalid parameters
+	ErrInvalidShare = errors.New("Invalid share mnemonic")
+	// ErrShareChecksum share mnemonic checksum does not match , e.g. a mistyped word
+	ErrShareChecksum = errors.New("Invalid share mnemonic checksum")
+	// ErrMismatchedShares shares do not belong to the same secret or are repeated
+	ErrMismatchedShares = errors.New("Shares do not belong to the same set")
+	// ErrInsufficientShares not enough groups or members to recover the secret
+	ErrInsufficientShares = errors.New("Not enough shares to recover the secret")
+	// ErrExtraShares more groups or group members than their thresholds were given
+	ErrExtraShares = errors.New("Too many shares , give exactly the threshold of groups and members")
+	// ErrShareDigest recovered secret does not match its digest
+	ErrShareDigest = errors.New("Invalid shares , secret digest does not match")
+	// ErrInvalidSharing group or member thresholds and counts out of range
+	ErrInvalidSharing = errors.New("Invalid share thresholds or counts")
+	// ErrInvalidSecretLength secret to split must have an even length of at least 128 bits
+	ErrInvalidSecretLength = errors.New("Invalid secret length")
+	// ErrInvalidSharePassphrase share passphrase must be printable ASCII
+	ErrInvalidSharePassphrase = errors.New("Share passphrase must be printable ASCII")
+	// ErrUnknownSeedLanguage language without BIP39 wordlist or mnemonic not matching any of them
+	ErrUnknownSeedLanguage = errors.New("Unknown seed language")
+	// ErrSeedLanguageUnsupported BIP44 wallets derive their keys from English mnemonics only
+	ErrSeedLanguageUnsupported = errors.New("BIP44 wallets only support English seeds")
+	// ErrUnsupportedCryptoType wallet can not be encrypted with the requested crypto type
+	ErrUnsupportedCryptoType = errors.New("Unsupported wallet crypto type")
+	// ErrRemoteReencryptUnsupported remote nodes can not re-encrypt wallets without decrypting them first
+	ErrRemoteReencryptUnsupported = errors.New("Changing the encryption of remote wallets is not supported")
+	// ErrWalletDeleteNotConfirmed confirmation token does not match the wallet to delete
+	ErrWalletDeleteNotConfirmed = errors.New("Wallet deletion not confirmed")
+	// ErrInvalidArchiveId archive ID does not refer to an archived wallet
+	ErrInvalidArchiveId = errors.New("Invalid archived wallet ID")
+	// ErrWalletExists a wallet with the same ID is already in the set
+	ErrWalletExists = errors.New("Wallet already exists")
+	// ErrWalletFolderUnavailable wallet files of remote node are not reachable from this host
+	ErrWalletFolderUnavailable = errors.New("Wallet folder not available")
 )
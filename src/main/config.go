@@ -15,6 +15,12 @@ const (
 	RemoteWallet
 	DataRefreshTimeoutKey = "lifeTime"
 	DataUpdateTimeKey     = "updateTime"
+	// KDFOptionName global option holding the scrypt cost of address book keys
+	KDFOptionName = "kdf"
+	// KDFCostKey , KDFBlockSizeKey and KDFParallelKey scrypt N , r and p in the KDF option
+	KDFCostKey      = "n"
+	KDFBlockSizeKey = "r"
+	KDFParallelKey  = "p"
 )
 
 var (
@@ -46,7 +52,18 @@ func init() {
 	}
 	cacheOpt := NewOption("cache", []string{}, false, string(cacheBytes))
 
-	_ = confManager.RegisterSection("global", []*Option{cacheOpt})
+	kdf := map[string]string{
+		KDFCostKey:      strconv.Itoa(params.AddrsBookScryptN),
+		KDFBlockSizeKey: strconv.Itoa(params.AddrsBookScryptR),
+		KDFParallelKey:  strconv.Itoa(params.AddrsBookScryptP),
+	}
+	kdfBytes, err := json.Marshal(kdf)
+	if err != nil {
+		return
+	}
+	kdfOpt := NewOption(KDFOptionName, []string{}, false, string(kdfBytes))
+
+	_ = confManager.RegisterSection("global", []*Option{cacheOpt, kdfOpt})
 }
 
 type ConfigManager struct {
@@ -58,6 +75,28 @@ func (cm *ConfigManager) GetSections() []string {
 	return cm.setting.ChildGroups()
 }
 
+// ExportSettings return every setting by its full key , e.g. to back them up
+func (cm *ConfigManager) ExportSettings() map[string]string {
+	settings := make(map[string]string)
+	for _, key := range cm.setting.AllKeys() {
+		settings[key] = cm.setting.Value(key, qtcore.NewQVariant()).ToString()
+	}
+	return settings
+}
+
+// ImportSettings replace every setting by those in settings , indexed by full key
+func (cm *ConfigManager) ImportSettings(settings map[string]string) {
+	defer cm.setting.Sync()
+	for _, key := range cm.setting.AllKeys() {
+		if _, isSet := settings[key]; !isSet {
+			cm.setting.Remove(key)
+		}
+	}
+	for key, value := range settings {
+		cm.setting.SetValue(key, qtcore.NewQVariant1(value))
+	}
+}
+
 func (cm *ConfigManager) GetSectionManager(section string) *SectionManager {
 	sectionM, ok := cm.sections[section]
 	if !ok {
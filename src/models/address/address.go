@@ -16,6 +16,7 @@ const (
 	Address = int(core.Qt__UserRole) + 1<<iota
 	AddressSky
 	AddressCoinHours
+	AddressContact
 )
 
 type AddressDetails struct {
@@ -24,6 +25,7 @@ type AddressDetails struct {
 	_ string `property:"address"`
 	_ string `property:"addressSky"`
 	_ string `property:"addressCoinHours"`
+	_ string `property:"contact"`
 }
 
 type AddressList struct {
@@ -44,6 +46,7 @@ func (al *AddressList) init() {
 		Address:          core.NewQByteArray2("address", -1),
 		AddressSky:       core.NewQByteArray2("addressSky", -1),
 		AddressCoinHours: core.NewQByteArray2("addressCoinHours", -1),
+		AddressContact:   core.NewQByteArray2("contact", -1),
 	})
 
 	al.ConnectRowCount(al.rowCount)
@@ -95,6 +98,10 @@ func (al *AddressList) data(index *core.QModelIndex, role int) *core.QVariant {
 		{
 			return core.NewQVariant1(address.AddressSky())
 		}
+	case AddressContact:
+		{
+			return core.NewQVariant1(address.Contact())
+		}
 	default:
 		{
 			return core.NewQVariant()
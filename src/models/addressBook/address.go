@@ -11,6 +11,7 @@ import (
 const (
 	Value = int(qtcore.Qt__UserRole + (iota + 1))
 	CoinType
+	Label
 )
 
 func init() { AddrsBkAddressModel_QmlRegisterType2("AddrsBookManager", 1, 0, "AddrsBkAddressModel") }
@@ -19,6 +20,7 @@ type QAddress struct {
 	qtcore.QObject
 	_ string `property:"value"`
 	_ string `property:"coinType"`
+	_ string `property:"label"`
 }
 
 type AddrsBkAddressModel struct {
@@ -32,6 +34,7 @@ func (m *AddrsBkAddressModel) init() {
 	m.SetRoles(map[int]*qtcore.QByteArray{
 		Value:    qtcore.NewQByteArray2("value", -1),
 		CoinType: qtcore.NewQByteArray2("coinType", -1),
+		Label:    qtcore.NewQByteArray2("label", -1),
 	})
 	qml.QQmlEngine_SetObjectOwnership(m, qml.QQmlEngine__CppOwnership)
 	m.ConnectRowCount(m.rowCount)
@@ -70,6 +73,10 @@ func (m *AddrsBkAddressModel) data(index *qtcore.QModelIndex, role int) *qtcore.
 		{
 			return qtcore.NewQVariant1(address.CoinType())
 		}
+	case Label:
+		{
+			return qtcore.NewQVariant1(address.Label())
+		}
 	default:
 		{
 			return qtcore.NewQVariant()
@@ -86,7 +93,15 @@ func fromAddressToQAddress(addresses []core.StringAddress) []*QAddress {
 		qa := NewQAddress(nil)
 		qa.SetCoinType(string(addrs.GetCoinType()))
 		qa.SetValue(string(addrs.GetValue()))
+		qa.SetLabel(addrs.GetLabel())
 		qAddresses = append(qAddresses, qa)
 	}
 	return qAddresses
 }
+
+// newQAddressModel returns a model listing addresses.
+func newQAddressModel(addresses []core.StringAddress) *AddrsBkAddressModel {
+	am := NewAddrsBkAddressModel(nil)
+	am.SetAddress(fromAddressToQAddress(addresses))
+	return am
+}
@@ -1,10 +1,15 @@
 package addressBook
 
 import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
 	"github.com/SkycoinProject/skycoin/src/util/file"
 	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	"github.com/fibercrypto/fibercryptowallet/src/data"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
 	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
 	qtcore "github.com/therecipe/qt/core"
 	"github.com/therecipe/qt/qml"
@@ -16,35 +21,56 @@ const (
 	Name = int(qtcore.Qt__UserRole + (iota + 1))
 	Address
 	ID
+	Groups
+	Favourite
+	Notes
 )
 
 var addrsBook core.AddressBook
+var labelStore core.LabelStore
+
+// storage application database holding the address book , nil unless open
+var storage core.Storage
 var logAddressBook = logging.MustGetLogger("Address Book Model")
 
-func init() { AddrsBookModel_QmlRegisterType2("AddrsBookManager", 1, 0, "AddrsBookModel") }
+func init() {
+	AddrsBookModel_QmlRegisterType2("AddrsBookManager", 1, 0, "AddrsBookModel")
+	QContactImportReport_QmlRegisterType2("AddrsBookManager", 1, 0, "QContactImportReport")
+}
 
 var addresses = make([]core.StringAddress, 0)
 
 type AddrsBookModel struct {
 	qtcore.QAbstractListModel
 
-	_ map[int]*qtcore.QByteArray                                 `property:"roles"`
-	_ []*QContact                                                `property:"contacts"`
-	_ int                                                        `property:"count"`
-	_ func()                                                     `constructor:"init"`
-	_ func(row int, id uint64)                                   `slot:"removeContact,auto"`
-	_ func(row int, id uint64, name string)                      `slot:"editContact,auto"`
-	_ func(name string)                                          `slot:"newContact"`
-	_ func()                                                     `slot:"loadContacts"`
-	_ func(int, string)                                          `slot:"initAddrsBook"`
-	_ func() int                                                 `slot:"getSecType"`
-	_ func(password string) bool                                 `slot:"authenticate"`
-	_ func() bool                                                `slot:"hasInit"`
-	_ func(value, coinType string)                               `slot:"addAddress"`
-	_ func(newSecType int, oldPassword, newPassword string) bool `slot:"changeSecType"`
-	_ func(value string) bool                                    `slot:"addressIsValid"`
-	_ func(row int, name string) bool                            `slot:"nameExist"`
-	_ func(row int, address string, coinType string) bool        `slot:"addressExist"`
+	_ map[int]*qtcore.QByteArray                                                   `property:"roles"`
+	_ []*QContact                                                                  `property:"contacts"`
+	_ int                                                                          `property:"count"`
+	_ func()                                                                       `constructor:"init"`
+	_ func(row int, id uint64)                                                     `slot:"removeContact,auto"`
+	_ func(row int, id uint64, name string)                                        `slot:"editContact,auto"`
+	_ func(name string)                                                            `slot:"newContact"`
+	_ func()                                                                       `slot:"loadContacts"`
+	_ func(query string)                                                           `slot:"searchContacts"`
+	_ func(int, string)                                                            `slot:"initAddrsBook"`
+	_ func() int                                                                   `slot:"getSecType"`
+	_ func(password string) bool                                                   `slot:"authenticate"`
+	_ func() bool                                                                  `slot:"hasInit"`
+	_ func(value, coinType string)                                                 `slot:"addAddress"`
+	_ func(newSecType int, oldPassword, newPassword string) bool                   `slot:"changeSecType"`
+	_ func(previousPassword string) int                                            `slot:"recoverSecurity"`
+	_ func(value string) bool                                                      `slot:"addressIsValid"`
+	_ func(row int, name string) bool                                              `slot:"nameExist"`
+	_ func(row int, address string, coinType string) bool                          `slot:"addressExist"`
+	_ func(path string) int                                                        `slot:"importLabels"`
+	_ func(path string) bool                                                       `slot:"exportLabels"`
+	_ func(path, format string, mode int, passphrase string) *QContactImportReport `slot:"importContacts"`
+	_ func(path, format, passphrase string) bool                                   `slot:"exportContacts"`
+	_ func(row int, id uint64, groups []string, favourite bool, notes string) bool `slot:"setContactDetails"`
+	_ func(row int, id uint64, address, coinType, label string) bool               `slot:"setAddressLabel"`
+	_ func(group string)                                                           `slot:"loadGroup"`
+	_ func()                                                                       `slot:"loadFavourites"`
+	_ func() []string                                                              `slot:"listGroups"`
 }
 
 type QContact struct {
@@ -52,14 +78,20 @@ type QContact struct {
 	_ uint64              `property:"id"`
 	_ string              `property:"name"`
 	_ AddrsBkAddressModel `property:"address"`
+	_ []string            `property:"groups"`
+	_ bool                `property:"favourite"`
+	_ string              `property:"notes"`
 }
 
 func (abm *AddrsBookModel) init() {
 	logAddressBook.Info("Init addressBook model")
 	abm.SetRoles(map[int]*qtcore.QByteArray{
-		Name:    qtcore.NewQByteArray2("name", -1),
-		Address: qtcore.NewQByteArray2("address", -1),
-		ID:      qtcore.NewQByteArray2("id", -1),
+		Name:      qtcore.NewQByteArray2("name", -1),
+		Address:   qtcore.NewQByteArray2("address", -1),
+		ID:        qtcore.NewQByteArray2("id", -1),
+		Groups:    qtcore.NewQByteArray2("groups", -1),
+		Favourite: qtcore.NewQByteArray2("favourite", -1),
+		Notes:     qtcore.NewQByteArray2("notes", -1),
 	})
 	qml.QQmlEngine_SetObjectOwnership(abm, qml.QQmlEngine__CppOwnership)
 	abm.ConnectRowCount(abm.rowCount)
@@ -73,20 +105,127 @@ func (abm *AddrsBookModel) init() {
 	abm.ConnectAddressExist(abm.addressExist)
 	abm.ConnectNameExist(abm.nameExist)
 	abm.ConnectLoadContacts(abm.loadContacts)
+	abm.ConnectSearchContacts(abm.searchContacts)
 	abm.ConnectInitAddrsBook(abm.initAddrsBook)
 	// abm.ConnectEditContact(abm.editContact)
 	// abm.ConnectRemoveContact(abm.removeContact)
 	abm.ConnectHasInit(abm.hasInit)
 	abm.ConnectChangeSecType(abm.changeSecType)
+	abm.ConnectRecoverSecurity(abm.recoverSecurity)
 	abm.ConnectAddAddress(abm.addAddress)
+	abm.ConnectImportLabels(abm.importLabels)
+	abm.ConnectExportLabels(abm.exportLabels)
+	abm.ConnectImportContacts(abm.importContacts)
+	abm.ConnectExportContacts(abm.exportContacts)
+	abm.ConnectSetContactDetails(abm.setContactDetails)
+	abm.ConnectSetAddressLabel(abm.setAddressLabel)
+	abm.ConnectLoadGroup(abm.loadGroup)
+	abm.ConnectLoadFavourites(abm.loadFavourites)
+	abm.ConnectListGroups(abm.listGroups)
+	openAddrsBook()
+}
+
+// openAddrsBook opens the address book database unless already open.
+func openAddrsBook() {
 	if addrsBook == nil {
+		applyKDFSettings()
 		db, err := data.GetBoltStorage(getConfigFileDir())
 		if err != nil {
 			logAddressBook.Error(err)
+		} else {
+			storage = db
 		}
 		addrsBook = data.NewAddressBook(db)
 	}
+}
+
+// applyKDFSettings sets the scrypt cost of new address book keys from the global settings.
+// The default cost is kept if settings are missing or invalid.
+func applyKDFSettings() {
+	sm := local.GetConfigManager().GetSectionManager("global")
+	if sm == nil {
+		return
+	}
+	value, err := sm.GetValue(local.KDFOptionName, nil)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't get key derivation settings")
+		return
+	}
+	kdf := make(map[string]string)
+	if err := json.Unmarshal([]byte(value), &kdf); err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't parse key derivation settings")
+		return
+	}
+	cost := make([]int, 0, 3)
+	for _, key := range []string{local.KDFCostKey, local.KDFBlockSizeKey, local.KDFParallelKey} {
+		n, err := strconv.Atoi(kdf[key])
+		if err != nil {
+			logAddressBook.WithError(err).Warn("Couldn't parse key derivation settings")
+			return
+		}
+		cost = append(cost, n)
+	}
+	if err := data.SetKDFParams(cost[0], cost[1], cost[2]); err != nil {
+		logAddressBook.WithError(err).Warn("Ignoring invalid key derivation settings")
+	}
+}
+
+// GetLabelStore returns the labels store sharing security with the address book.
+// Returns nil if it is not available.
+func GetLabelStore() core.LabelStore {
+	if labelStore == nil {
+		openAddrsBook()
+		store, err := data.NewLabelStore(addrsBook)
+		if err != nil {
+			logAddressBook.WithError(err).Warn("Couldn't open labels store")
+			return nil
+		}
+		labelStore = store
+	}
+	return labelStore
+}
+
+// GetAddrsBook returns the address book shared by the models.
+func GetAddrsBook() core.AddressBook {
+	openAddrsBook()
+	return addrsBook
+}
+
+// GetStorage returns the application database shared by the models , nil if it could not be opened.
+// Contacts , labels , history , outbox , payments and coin control are collections of this database.
+func GetStorage() core.Storage {
+	openAddrsBook()
+	return storage
+}
+
+// ReloadAddrsBook lets restore rewrite the application database in place and reloads the address book ,
+// whose security settings may have changed. restore gets nil if the database could not be opened.
+func ReloadAddrsBook(restore func(storage core.Storage) error) error {
+	openAddrsBook()
+	defer func() {
+		if storage != nil {
+			addrsBook = data.NewAddressBook(storage)
+			labelStore = nil
+		}
+	}()
+	return restore(storage)
+}
+
+// GetContactResolver returns a resolver looking up contacts of the address book.
+func GetContactResolver() *data.ContactResolver {
+	openAddrsBook()
+	return data.NewContactResolver(addrsBook)
+}
 
+// ContactNames maps addresses of the coin represented by ticker to contact names ,
+// empty if the address book is locked.
+func ContactNames(ticker string) map[string]string {
+	names, err := GetContactResolver().ContactNames(ticker)
+	if err != nil {
+		logAddressBook.WithError(err).Debug("Couldn't load contact names")
+		return map[string]string{}
+	}
+	return names
 }
 
 func (abm *AddrsBookModel) rowCount(*qtcore.QModelIndex) int {
@@ -116,6 +255,18 @@ func (abm *AddrsBookModel) data(index *qtcore.QModelIndex, role int) *qtcore.QVa
 		{
 			return qtcore.NewQVariant1(contact.Id())
 		}
+	case Groups:
+		{
+			return qtcore.NewQVariant1(contact.Groups())
+		}
+	case Favourite:
+		{
+			return qtcore.NewQVariant1(contact.IsFavourite())
+		}
+	case Notes:
+		{
+			return qtcore.NewQVariant1(contact.Notes())
+		}
 	default:
 		return qtcore.NewQVariant()
 	}
@@ -165,14 +316,15 @@ func (abm *AddrsBookModel) editContact(row int, id uint64, name string) {
 	}
 	qc := NewQContact(nil)
 	qc.SetName(name)
-	qa := fromAddressToQAddress(addresses)
-	am := NewAddrsBkAddressModel(nil)
-	am.SetAddress(qa)
-	qc.SetAddress(am)
 	qc.SetId(id)
 	var c = data.Contact{}
 	c.SetAddresses(addresses)
 	c.SetName(name)
+	keepContactDetails(id, &c)
+	qc.SetGroups(c.GetGroups())
+	qc.SetFavourite(c.IsFavourite())
+	qc.SetNotes(c.GetNotes())
+	qc.SetAddress(newQAddressModel(c.GetAddresses()))
 	if err := addrsBook.UpdateContact(id, &c); err != nil {
 		logAddressBook.Error(err)
 		return
@@ -216,6 +368,15 @@ func (abm *AddrsBookModel) loadContacts() {
 	}
 }
 
+// searchContacts shows the contacts whose name matches query , best matches first. All contacts if query is empty.
+func (abm *AddrsBookModel) searchContacts(query string) {
+	contacts, err := addrsBook.SearchContacts(query)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't search contacts")
+	}
+	abm.showContacts(contacts)
+}
+
 func (abm *AddrsBookModel) getSecType() int {
 	secType, err := addrsBook.GetSecType()
 	if err != nil {
@@ -236,10 +397,7 @@ func (abm *AddrsBookModel) authenticate(password string) bool {
 func (abm *AddrsBookModel) newContact(name string) {
 	qc := NewQContact(nil)
 	qc.SetName(name)
-	qa := fromAddressToQAddress(addresses)
-	am := NewAddrsBkAddressModel(nil)
-	am.SetAddress(qa)
-	qc.SetAddress(am)
+	qc.SetAddress(newQAddressModel(addresses))
 	var contact data.Contact
 	contact.SetName(name)
 	contact.SetAddresses(addresses)
@@ -283,9 +441,10 @@ func fromContactToQContact(contacts []core.Contact) []*QContact {
 		qc.SetName(c.GetName())
 		logAddressBook.Info(c.GetID())
 		qc.SetId(c.GetID())
-		qAddressModel := NewAddrsBkAddressModel(nil)
-		qAddressModel.SetAddress(fromAddressToQAddress(c.GetAddresses()))
-		qc.SetAddress(qAddressModel)
+		qc.SetAddress(newQAddressModel(c.GetAddresses()))
+		qc.SetGroups(c.GetGroups())
+		qc.SetFavourite(c.IsFavourite())
+		qc.SetNotes(c.GetNotes())
 		qContacts = append(qContacts, qc)
 	}
 	return qContacts
@@ -337,3 +496,173 @@ func (abm *AddrsBookModel) changeSecType(secType int, oldPassword string, newPas
 	}
 	return true
 }
+
+// recoverSecurity re-encrypts records left unreadable by an interrupted security change ,
+// returning the number of records recovered or -1 if some of them could not be recovered.
+func (abm *AddrsBookModel) recoverSecurity(previousPassword string) int {
+	n, err := addrsBook.RecoverSecurity(previousPassword)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't recover address book records")
+		return -1
+	}
+	if n > 0 {
+		abm.loadContacts()
+	}
+	return n
+}
+
+// importLabels loads labels from a BIP329 JSONL file , returning the number of labels imported or -1 on failure.
+func (*AddrsBookModel) importLabels(path string) int {
+	store := GetLabelStore()
+	if store == nil {
+		return -1
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't open labels file")
+		return -1
+	}
+	defer f.Close()
+	n, err := store.ImportLabels(f)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't import labels")
+		return -1
+	}
+	return n
+}
+
+// exportLabels saves every label to a BIP329 JSONL file.
+func (*AddrsBookModel) exportLabels(path string) bool {
+	store := GetLabelStore()
+	if store == nil {
+		return false
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't create labels file")
+		return false
+	}
+	if err := store.ExportLabels(f); err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't export labels")
+		_ = f.Close()
+		return false
+	}
+	// Written data may be lost if closing fails
+	if err := f.Close(); err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't write labels file")
+		return false
+	}
+	return true
+}
+
+// keepContactDetails copies into c the details of the stored contact id ,
+// and the labels of the addresses c keeps.
+func keepContactDetails(id uint64, c *data.Contact) {
+	stored, err := addrsBook.GetContact(id)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't load contact details")
+		return
+	}
+	c.SetGroups(stored.GetGroups())
+	c.SetFavourite(stored.IsFavourite())
+	c.SetNotes(stored.GetNotes())
+	for i := range c.Address {
+		for _, sa := range stored.GetAddresses() {
+			if bytes.Equal(sa.GetValue(), c.Address[i].Value) && bytes.Equal(sa.GetCoinType(), c.Address[i].Coin) {
+				c.Address[i].SetLabel(sa.GetLabel())
+				break
+			}
+		}
+	}
+}
+
+// updateContact applies edit to the stored contact id , then refreshes row.
+func (abm *AddrsBookModel) updateContact(row int, id uint64, edit func(c core.Contact) bool) bool {
+	if row < 0 || row >= abm.Count() {
+		return false
+	}
+	c, err := addrsBook.GetContact(id)
+	if err != nil {
+		logAddressBook.Error(err)
+		return false
+	}
+	if !edit(c) {
+		return false
+	}
+	if err := addrsBook.UpdateContact(id, c); err != nil {
+		logAddressBook.Error(err)
+		return false
+	}
+	qc := abm.Contacts()[row]
+	qc.SetAddress(newQAddressModel(c.GetAddresses()))
+	qc.SetGroups(c.GetGroups())
+	qc.SetFavourite(c.IsFavourite())
+	qc.SetNotes(c.GetNotes())
+	index := abm.Index(row, 0, qtcore.NewQModelIndex())
+	abm.DataChanged(index, index, []int{Address, Groups, Favourite, Notes})
+	return true
+}
+
+// setContactDetails files a contact under groups , marks it as favourite and sets notes about it.
+func (abm *AddrsBookModel) setContactDetails(row int, id uint64, groups []string, favourite bool, notes string) bool {
+	return abm.updateContact(row, id, func(c core.Contact) bool {
+		c.SetGroups(groups)
+		c.SetFavourite(favourite)
+		c.SetNotes(notes)
+		return true
+	})
+}
+
+// setAddressLabel describes the purpose of an address of a contact , e.g. exchange deposit or cold storage.
+func (abm *AddrsBookModel) setAddressLabel(row int, id uint64, address, coinType, label string) bool {
+	return abm.updateContact(row, id, func(c core.Contact) bool {
+		for _, sa := range c.GetAddresses() {
+			if string(sa.GetValue()) == address && string(sa.GetCoinType()) == coinType {
+				sa.SetLabel(label)
+				return true
+			}
+		}
+		logAddressBook.Warn("Contact address not found")
+		return false
+	})
+}
+
+// showContacts replaces the contacts shown.
+func (abm *AddrsBookModel) showContacts(contacts []core.Contact) {
+	qContacts := fromContactToQContact(contacts)
+	for _, c := range qContacts {
+		qml.QQmlEngine_SetObjectOwnership(c, qml.QQmlEngine__CppOwnership)
+	}
+	abm.BeginResetModel()
+	abm.SetContacts(qContacts)
+	abm.SetCount(len(qContacts))
+	abm.EndResetModel()
+}
+
+// loadGroup shows the contacts filed under group.
+func (abm *AddrsBookModel) loadGroup(group string) {
+	contacts, err := addrsBook.ListContactsByGroup(group)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't list contacts of group")
+	}
+	abm.showContacts(contacts)
+}
+
+// loadFavourites shows the contacts marked as favourite.
+func (abm *AddrsBookModel) loadFavourites() {
+	contacts, err := addrsBook.ListFavouriteContacts()
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't list favourite contacts")
+	}
+	abm.showContacts(contacts)
+}
+
+// listGroups lists the groups contacts are filed under.
+func (*AddrsBookModel) listGroups() []string {
+	groups, err := addrsBook.ListGroups()
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't list contact groups")
+		return []string{}
+	}
+	return groups
+}
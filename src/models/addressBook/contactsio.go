@@ -0,0 +1,71 @@
+package addressBook
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fibercrypto/fibercryptowallet/src/data"
+	qtcore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+// QContactImportReport outcome of a contacts import
+type QContactImportReport struct {
+	qtcore.QObject
+
+	_ int      `property:"imported"`
+	_ int      `property:"merged"`
+	_ int      `property:"overwritten"`
+	_ int      `property:"skipped"`
+	_ []string `property:"rejected"`
+}
+
+// importContacts loads contacts from a backup , CSV or vCard file , see data.ContactFormatBackup and siblings.
+// Mode is one of data.ImportMerge , data.ImportSkip or data.ImportOverwrite. Returns nil on failure.
+func (abm *AddrsBookModel) importContacts(path, format string, mode int, passphrase string) *QContactImportReport {
+	f, err := os.Open(path)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't open contacts file")
+		return nil
+	}
+	defer f.Close()
+	report, err := data.ImportContacts(addrsBook, f, format, data.ImportMode(mode), passphrase)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't import contacts")
+		return nil
+	}
+	abm.loadContacts()
+
+	qReport := NewQContactImportReport(nil)
+	qml.QQmlEngine_SetObjectOwnership(qReport, qml.QQmlEngine__CppOwnership)
+	qReport.SetImported(report.Imported)
+	qReport.SetMerged(report.Merged)
+	qReport.SetOverwritten(report.Overwritten)
+	qReport.SetSkipped(report.Skipped)
+	rejected := make([]string, 0, len(report.Rejected))
+	for _, r := range report.Rejected {
+		rejected = append(rejected, fmt.Sprintf("%d: %s %s %s: %s", r.Line, r.Name, r.Coin, r.Address, r.Err))
+	}
+	qReport.SetRejected(rejected)
+	return qReport
+}
+
+// exportContacts writes every contact as a backup encrypted with passphrase , CSV or vCard file
+func (*AddrsBookModel) exportContacts(path, format, passphrase string) bool {
+	f, err := os.Create(path)
+	if err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't create contacts file")
+		return false
+	}
+	if err := data.ExportContacts(addrsBook, f, format, passphrase); err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't export contacts")
+		_ = f.Close()
+		return false
+	}
+	// Written data may be lost if closing fails
+	if err := f.Close(); err != nil {
+		logAddressBook.WithError(err).Warn("Couldn't write contacts file")
+		return false
+	}
+	return true
+}
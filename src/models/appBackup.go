@@ -0,0 +1,178 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/config"
+	sky "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/data"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+const (
+	// appDatabaseBackupName name of the application database within backups
+	appDatabaseBackupName = "data.dt"
+
+	// BackupRestoreMerge keep present wallets , database records and settings
+	BackupRestoreMerge = int(data.RestoreMerge)
+	// BackupRestoreReplace overwrite present wallets , databases and settings
+	BackupRestoreReplace = int(data.RestoreReplace)
+)
+
+// QBackupInfo contents of a backup checked before restoring it
+type QBackupInfo struct {
+	qtCore.QObject
+
+	_ bool     `property:"valid"`
+	_ string   `property:"error"`
+	_ string   `property:"createdAt"`
+	_ int      `property:"wallets"`
+	_ []string `property:"conflicts"`
+}
+
+// localWalletDirs returns the directories of local wallet sources
+func localWalletDirs() []string {
+	dirs := make([]string, 0)
+	sources, err := config.GetWalletSources()
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't load wallet sources")
+		return dirs
+	}
+	for _, src := range sources {
+		if src.Tp == string(config.LocalWallet) {
+			dirs = append(dirs, src.Source)
+		}
+	}
+	return dirs
+}
+
+// localArchiveDirs returns the archive directories of local wallet sources , by index
+func localArchiveDirs(walletDirs []string) []string {
+	dirs := make([]string, len(walletDirs))
+	for i, dir := range walletDirs {
+		dirs[i] = filepath.Join(dir, sky.ArchiveDirName)
+	}
+	return dirs
+}
+
+// backupRestoreTargets returns where backups are restored
+func backupRestoreTargets() data.RestoreTargets {
+	targets := data.RestoreTargets{
+		Wallets:  make([]data.RestoreWalletDir, 0),
+		Settings: local.GetConfigManager().ExportSettings(),
+	}
+	walletDirs := localWalletDirs()
+	archiveDirs := localArchiveDirs(walletDirs)
+	for i, dir := range walletDirs {
+		targets.Wallets = append(targets.Wallets, data.RestoreWalletDir{
+			Dir:        dir,
+			ArchiveDir: archiveDirs[i],
+			Env:        sky.NewWalletDirectory(dir),
+		})
+	}
+	return targets
+}
+
+// readBackup decrypts the backup stored at path
+func readBackup(path, passphrase string) (*data.Backup, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return data.ReadBackup(f, passphrase)
+}
+
+// createBackup saves wallets , archived wallets , the application database and settings
+// to an archive encrypted with passphrase
+func (walletM *WalletManager) createBackup(path, passphrase string) bool {
+	walletDirs := localWalletDirs()
+	sources := data.BackupSources{
+		WalletDirs:  walletDirs,
+		ArchiveDirs: localArchiveDirs(walletDirs),
+		Databases:   make(map[string]core.Storage),
+		Settings:    local.GetConfigManager().ExportSettings(),
+	}
+	if db := addressBook.GetStorage(); db != nil {
+		sources.Databases[appDatabaseBackupName] = db
+	}
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't create backup file")
+		return false
+	}
+	if _, err = data.WriteBackup(f, sources, passphrase); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't write backup")
+	}
+	if errClose := f.Close(); err == nil && errClose != nil {
+		err = errClose
+		logWalletManager.WithError(err).Warn("Couldn't write backup")
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return false
+	}
+	return true
+}
+
+// checkBackup verifies a backup , listing the wallets already present
+func (walletM *WalletManager) checkBackup(path, passphrase string) *QBackupInfo {
+	info := NewQBackupInfo(nil)
+	qml.QQmlEngine_SetObjectOwnership(info, qml.QQmlEngine__CppOwnership)
+	backup, err := readBackup(path, passphrase)
+	if err != nil {
+		info.SetValid(false)
+		info.SetError(err.Error())
+		return info
+	}
+	manifest := backup.Manifest()
+	wallets := 0
+	for _, file := range manifest.Files {
+		if file.Kind == data.BackupFileWallet {
+			wallets++
+		}
+	}
+	conflicts := make([]string, 0)
+	for _, c := range backup.Conflicts(backupRestoreTargets()) {
+		conflicts = append(conflicts, c.Wallet.GetLabel())
+	}
+	info.SetValid(true)
+	info.SetCreatedAt(manifest.CreatedAt.Local().Format(time.RFC3339))
+	info.SetWallets(wallets)
+	info.SetConflicts(conflicts)
+	return info
+}
+
+// restoreBackup restores a backup , mode chooses whether present wallets are merged or replaced
+func (walletM *WalletManager) restoreBackup(path, passphrase string, mode int) bool {
+	backup, err := readBackup(path, passphrase)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't read backup")
+		return false
+	}
+	targets := backupRestoreTargets()
+	var report *data.RestoreReport
+	err = addressBook.ReloadAddrsBook(func(db core.Storage) error {
+		if db != nil {
+			targets.Databases = map[string]core.Storage{appDatabaseBackupName: db}
+		}
+		report, err = backup.Restore(targets, data.RestoreMode(mode))
+		return err
+	})
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't restore backup")
+		return false
+	}
+	local.GetConfigManager().ImportSettings(report.Settings)
+	logWalletManager.Infof("Backup restored: %d files restored , %d replaced , %d kept",
+		len(report.Restored), len(report.Replaced), len(report.Kept))
+	walletM.updateWalletEnvs()
+	walletM.updateWallets()
+	return true
+}
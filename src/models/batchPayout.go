@@ -0,0 +1,221 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	sky "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/fibercrypto/fibercryptowallet/src/util/payutil"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+// payoutInputsReserve inputs each batch transaction may spend , the remaining size is left to outputs.
+// Transactions needing more inputs are split.
+const payoutInputsReserve = 32
+
+// QBatchPayout preview of the transactions paying recipients listed in a file
+type QBatchPayout struct {
+	qtCore.QObject
+
+	_ int    `property:"recipientCount"`
+	_ int    `property:"rejectedCount"`
+	_ int    `property:"txnCount"`
+	_ string `property:"totalSky"`
+	_ string `property:"totalCoinHours"`
+	_ string `property:"fee"`
+
+	wltId string
+	batch *payutil.BatchPayout
+}
+
+// QPayoutResult outcome of a row of a batch payout file
+type QPayoutResult struct {
+	qtCore.QObject
+
+	_ int    `property:"line"`
+	_ string `property:"address"`
+	_ string `property:"label"`
+	_ string `property:"status"`
+	_ int    `property:"txn"`
+	_ string `property:"transactionId"`
+	_ string `property:"error"`
+}
+
+// previewBatchPayout reads recipients from a CSV or JSON file and creates the unsigned transactions paying them.
+// Coin hours are selected automatically unless set for every row of the file.
+func (walletM *WalletManager) previewBatchPayout(wltId, path, burnFactor string) *QBatchPayout {
+	logWalletManager.Info("Planning batch payout")
+	wlt := walletM.WalletEnv.GetWalletSet().GetWallet(wltId)
+	if wlt == nil {
+		logWalletManager.WithField("id", wltId).Warn("Couldn't load wallet")
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't open payout file")
+		return nil
+	}
+	defer f.Close()
+	format := payutil.FormatCSV
+	if strings.EqualFold(filepath.Ext(path), "."+payutil.FormatJSON) {
+		format = payutil.FormatJSON
+	}
+	rows, err := payutil.ReadPayouts(f, format)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't read payout file")
+		return nil
+	}
+	// One output is left for change
+	maxOutputs := sky.MaxTxnOutputs(payoutInputsReserve) - 1
+	batch, err := payutil.NewBatchPayout(rows, params.SkycoinTicker, maxOutputs, sky.DropletPrecision())
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't plan batch payout")
+		return nil
+	}
+	batch.MaxInputs = payoutInputsReserve
+	opt := util.NewKeyValueMap()
+	opt.SetValue("BurnFactor", burnFactor)
+	walletM.setCoinControlOptions(opt)
+	if err := batch.Build(wlt, opt); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't create batch payout transactions")
+		return nil
+	}
+	return newQBatchPayout(batch, wltId)
+}
+
+// executeBatchPayout signs and broadcasts every transaction of a batch payout , true if all of them were sent
+func (walletM *WalletManager) executeBatchPayout(qBatch *QBatchPayout, source, password string) bool {
+	logWalletManager.Info("Executing batch payout")
+	if qBatch == nil || qBatch.batch == nil {
+		logWalletManager.Warn("Invalid batch payout")
+		return false
+	}
+	wlt := walletM.WalletEnv.GetWalletSet().GetWallet(qBatch.wltId)
+	if wlt == nil {
+		logWalletManager.WithField("id", qBatch.wltId).Warn("Couldn't load wallet")
+		return false
+	}
+	signer, err := util.LookupSignServiceForWallet(wlt, core.UID(source))
+	if err != nil {
+		logWalletManager.WithError(err).Warnf("No signer %s for wallet %v", source, wlt)
+		return false
+	}
+	if signerUid, err := signer.GetSignerUID(); err == nil && wlt.GetId() == string(signerUid) {
+		// NOTE the signer is the wallet it self
+		signer = nil
+	}
+	pwd := util.ConstantPassword(password)
+	// NOTE: No easy way to get plain passwords in memory
+	password = ""
+	if err := qBatch.batch.Execute(wlt, signer, pwd, walletM.coinControl, walletM.sendOutboxTxn); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't execute batch payout")
+		return false
+	}
+	isComplete := true
+	sent := make(map[int]bool, len(qBatch.batch.Txns))
+	for _, res := range qBatch.batch.Results {
+		switch res.Status {
+		case payutil.PayoutBroadcast:
+			sent[res.Txn] = true
+		case payutil.PayoutFailed:
+			isComplete = false
+		}
+	}
+	logWalletManager.WithField("transactions", len(sent)).Info("Batch payout broadcast")
+	return isComplete
+}
+
+// getBatchPayoutResults lists the outcome of every row of the payout file
+func (walletM *WalletManager) getBatchPayoutResults(qBatch *QBatchPayout) []*QPayoutResult {
+	if qBatch == nil || qBatch.batch == nil {
+		return nil
+	}
+	results := make([]*QPayoutResult, 0, len(qBatch.batch.Results))
+	for _, res := range qBatch.batch.Results {
+		qRes := NewQPayoutResult(nil)
+		qml.QQmlEngine_SetObjectOwnership(qRes, qml.QQmlEngine__CppOwnership)
+		qRes.SetLine(res.Line)
+		qRes.SetAddress(res.Address)
+		qRes.SetLabel(res.Label)
+		qRes.SetStatus(res.Status.String())
+		qRes.SetTxn(res.Txn)
+		qRes.SetTransactionId(res.TxnID)
+		if res.Err != nil {
+			qRes.SetError(res.Err.Error())
+		}
+		results = append(results, qRes)
+	}
+	return results
+}
+
+// saveBatchPayoutReport writes the outcome of every row of the payout file as CSV
+func (walletM *WalletManager) saveBatchPayoutReport(qBatch *QBatchPayout, path string) bool {
+	if qBatch == nil || qBatch.batch == nil {
+		logWalletManager.Warn("Invalid batch payout")
+		return false
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't create payout report")
+		return false
+	}
+	if err := payutil.WriteReportCSV(f, qBatch.batch.Results); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't write payout report")
+		_ = f.Close()
+		return false
+	}
+	// Written data may be lost if closing fails
+	if err := f.Close(); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't write payout report")
+		return false
+	}
+	return true
+}
+
+func newQBatchPayout(batch *payutil.BatchPayout, wltId string) *QBatchPayout {
+	qBatch := NewQBatchPayout(nil)
+	qml.QQmlEngine_SetObjectOwnership(qBatch, qml.QQmlEngine__CppOwnership)
+	qBatch.wltId = wltId
+	qBatch.batch = batch
+	recipients := make(map[string]struct{})
+	for _, chunk := range batch.Chunks {
+		for _, p := range chunk {
+			recipients[p.Address] = struct{}{}
+		}
+	}
+	rejected := 0
+	for _, res := range batch.Results {
+		if res.Status == payutil.PayoutRejected {
+			rejected++
+		}
+	}
+	qBatch.SetRecipientCount(len(recipients))
+	qBatch.SetRejectedCount(rejected)
+	qBatch.SetTxnCount(len(batch.Txns))
+	coins, _ := batch.Total()
+	var hours, fee uint64
+	for _, txn := range batch.Txns {
+		for _, out := range txn.GetOutputs() {
+			addr, err := out.GetAddress()
+			if err != nil {
+				continue
+			}
+			if _, isRecipient := recipients[addr.String()]; isRecipient {
+				h, _ := out.GetCoins(sky.CoinHour)
+				hours += h
+			}
+		}
+		if f, err := txn.ComputeFee(sky.CoinHour); err == nil {
+			fee += f
+		}
+	}
+	qBatch.SetTotalSky(formatPlanCoins(coins, sky.Sky))
+	qBatch.SetTotalCoinHours(formatPlanCoins(hours, sky.CoinHour))
+	qBatch.SetFee(formatPlanCoins(fee, sky.CoinHour))
+	return qBatch
+}
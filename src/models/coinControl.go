@@ -0,0 +1,150 @@
+package models
+
+import (
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
+)
+
+// outputReservationTTL time outputs stay reserved after signing a transaction spending them
+const outputReservationTTL = 10 * time.Minute
+
+// openCoinControl returns coin control settings kept in the application database , nil if not available
+func openCoinControl() core.CoinControl {
+	coinControl, isCoinControl := addressBook.GetStorage().(core.CoinControl)
+	if !isCoinControl {
+		logWalletManager.Error("Couldn't open coin control database")
+		return nil
+	}
+	return coinControl
+}
+
+// setCoinControlOptions binds coin control settings to transaction options
+func (walletM *WalletManager) setCoinControlOptions(opt core.KeyValueStore) {
+	if walletM.coinControl != nil {
+		opt.SetValue(core.StrCoinControl, walletM.coinControl)
+	}
+	opt.SetValue(core.StrOverrideFrozen, walletM.IsOverrideFrozen())
+}
+
+func (walletM *WalletManager) freezeOutput(wltId, outputId string) bool {
+	if walletM.coinControl == nil {
+		return false
+	}
+	if err := walletM.coinControl.FreezeOutput(wltId, outputId); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't freeze output")
+		return false
+	}
+	walletM.refreshOutputState(outputId)
+	return true
+}
+
+func (walletM *WalletManager) thawOutput(wltId, outputId string) bool {
+	if walletM.coinControl == nil {
+		return false
+	}
+	if err := walletM.coinControl.ThawOutput(wltId, outputId); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't thaw output")
+		return false
+	}
+	walletM.refreshOutputState(outputId)
+	return true
+}
+
+func (walletM *WalletManager) releaseOutputs(wltId string, outputIds []string) bool {
+	if walletM.coinControl == nil {
+		return false
+	}
+	if err := walletM.coinControl.ReleaseOutputs(wltId, outputIds); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't release outputs")
+		return false
+	}
+	for _, outputId := range outputIds {
+		walletM.refreshOutputState(outputId)
+	}
+	return true
+}
+
+func (walletM *WalletManager) outputState(wltId, outputId string) int {
+	if walletM.coinControl == nil {
+		return int(core.OutputSpendable)
+	}
+	state, err := walletM.coinControl.GetOutputState(wltId, outputId)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't get output state")
+		return int(core.OutputSpendable)
+	}
+	return int(state)
+}
+
+func (walletM *WalletManager) setOutputLabel(outputId, label string) bool {
+	store := addressBook.GetLabelStore()
+	if store == nil {
+		logWalletManager.Warn("Labels are not available")
+		walletM.LabelsNotSaved()
+		return false
+	}
+	var err error
+	if label == "" {
+		err = store.DeleteLabel(core.LabelTypeOutput, outputId)
+	} else {
+		err = store.SetLabel(core.Label{Type: core.LabelTypeOutput, Ref: outputId, Label: label})
+	}
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't save output label")
+		walletM.LabelsNotSaved()
+		return false
+	}
+	walletM.outputsByAddressMutex.Lock()
+	defer walletM.outputsByAddressMutex.Unlock()
+	for _, outs := range walletM.outputsByAddress {
+		for _, qout := range outs {
+			if qout.OutputID() == outputId {
+				qout.SetLabel(label)
+			}
+		}
+	}
+	return true
+}
+
+// refreshOutputState updates the state of cached outputs after coin control changes
+func (walletM *WalletManager) refreshOutputState(outputId string) {
+	walletM.outputsByAddressMutex.Lock()
+	defer walletM.outputsByAddressMutex.Unlock()
+	for _, outs := range walletM.outputsByAddress {
+		for _, qout := range outs {
+			if qout.OutputID() == outputId {
+				qout.SetState(walletM.outputState(qout.WalletOwner(), outputId))
+			}
+		}
+	}
+}
+
+// reserveInputs excludes outputs spent by a transaction from coin selection while it is signed.
+// Reserved outputs are returned grouped by wallet.
+func (walletM *WalletManager) reserveInputs(txn core.Transaction, wltByAddr map[string]core.Wallet) map[string][]string {
+	reserved := make(map[string][]string)
+	if walletM.coinControl == nil {
+		return reserved
+	}
+	for _, in := range txn.GetInputs() {
+		out, err := in.GetSpentOutput()
+		if err != nil {
+			continue
+		}
+		outAddr, err := out.GetAddress()
+		if err != nil {
+			continue
+		}
+		if wlt, isKnown := wltByAddr[outAddr.String()]; isKnown {
+			reserved[wlt.GetId()] = append(reserved[wlt.GetId()], in.GetId())
+		}
+	}
+	for wltId, outputIds := range reserved {
+		if err := walletM.coinControl.ReserveOutputs(wltId, outputIds, outputReservationTTL); err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't reserve outputs")
+		}
+	}
+	return reserved
+}
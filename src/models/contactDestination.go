@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
+)
+
+// resolveContact lists the SKY addresses of a contact given its name or ID , so the user can choose one if there are several
+func (walletM *WalletManager) resolveContact(contact string) []string {
+	addrs, err := addressBook.GetContactResolver().ContactAddresses(contact, params.SkycoinTicker)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't resolve contact")
+		return nil
+	}
+	values := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		values = append(values, addr.String())
+	}
+	return values
+}
+
+// sendToContact creates a transaction paying the only SKY address of a contact given its name or ID
+func (walletM *WalletManager) sendToContact(wltId, contact, amount string) *QTransaction {
+	addr, err := addressBook.GetContactResolver().Resolve(contact, params.SkycoinTicker)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't resolve contact")
+		return nil
+	}
+	return walletM.sendTo(wltId, addr.String(), amount)
+}
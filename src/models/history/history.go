@@ -45,6 +45,8 @@ func (hm *TransactionList) init() {
 		transactions.Addresses:       core.NewQByteArray2("addresses", -1),
 		transactions.Inputs:          core.NewQByteArray2("inputs", -1),
 		transactions.Outputs:         core.NewQByteArray2("outputs", -1),
+		transactions.Label:           core.NewQByteArray2("label", -1),
+		transactions.Confirmations:   core.NewQByteArray2("confirmations", -1),
 	})
 
 	hm.ConnectRowCount(hm.rowCount)
@@ -126,6 +128,14 @@ func (hm *TransactionList) data(index *core.QModelIndex, role int) *core.QVarian
 		{
 			return core.NewQVariant1(transaction.Outputs())
 		}
+	case transactions.Label:
+		{
+			return core.NewQVariant1(transaction.Label())
+		}
+	case transactions.Confirmations:
+		{
+			return core.NewQVariant1(transaction.Confirmations())
+		}
 	default:
 		{
 			return core.NewQVariant()
@@ -1,6 +1,7 @@
 package history
 
 import (
+	"os"
 	"time"
 
 	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
@@ -10,13 +11,17 @@ import (
 
 	coin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/data"
+	fcParams "github.com/fibercrypto/fibercryptowallet/src/params"
 	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
 
 	"github.com/fibercrypto/fibercryptowallet/src/models"
 	"github.com/fibercrypto/fibercryptowallet/src/models/address"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
 	"github.com/fibercrypto/fibercryptowallet/src/models/transactions"
 
 	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/fibercrypto/fibercryptowallet/src/util/historyutil"
 
 	qtCore "github.com/therecipe/qt/core"
 )
@@ -38,6 +43,7 @@ const (
 type HistoryManager struct {
 	qtCore.QObject
 	walletEnv       core.WalletEnv
+	blockchain      core.BlockchainStatus
 	newTxn          map[string][]core.Transaction
 	txnFinded       map[string]struct{}
 	filters         []string
@@ -50,6 +56,7 @@ type HistoryManager struct {
 	end             chan bool
 	_               func()                                    `constructor:"init"`
 	_               func()                                    `signal:"newTransactions"`
+	_               func()                                    `signal:"labelsNotSaved"`
 	_               func() []*transactions.TransactionDetails `slot:"getTransactions"`
 	_               func() []*transactions.TransactionDetails `slot:"getTransactionsWithFilters"`
 	_               func() []*transactions.TransactionDetails `slot:"getNewTransactions"`
@@ -57,6 +64,20 @@ type HistoryManager struct {
 	_               func(string)                              `slot:"addFilter"`
 	_               func(string)                              `slot:"removeFilter"`
 	_               func()                                    `slot:"update"`
+	_               func(path, format string) bool            `slot:"exportTransactions"`
+	_               string                                    `property:"nextCursor"`
+
+	// queryTransactions returns a page of transactions matching the given criteria.
+	// Zero dates and contact ID , empty strings and negative status select any value
+	_ func(since, until *qtCore.QDateTime, direction int, minAmount, maxAmount, counterparty string, contactID uint64, label string, status int, cursor string, limit int) []*transactions.TransactionDetails `slot:"queryTransactions"`
+	// setTransactionLabel attaches a note to a transaction , an empty label removes it
+	_ func(txnID, label string) bool `slot:"setTransactionLabel"`
+	// balanceSeries samples the balance of a wallet at points instants between since and until
+	_ func(wltID string, since, until *qtCore.QDateTime, points int) []*QBalancePoint `slot:"balanceSeries"`
+	// recentRecipients lists at most limit addresses recently paid from known wallets , matched to contacts
+	_ func(limit int) []*QRecentRecipient `slot:"recentRecipients"`
+
+	lastQuery historyutil.Query
 }
 
 func (hm *HistoryManager) init() {
@@ -67,13 +88,20 @@ func (hm *HistoryManager) init() {
 	hm.ConnectAddFilter(hm.addFilter)
 	hm.ConnectRemoveFilter(hm.removeFilter)
 	hm.ConnectUpdate(hm.updateTxns)
+	hm.ConnectQueryTransactions(hm.queryTransactions)
+	hm.ConnectExportTransactions(hm.exportTransactions)
+	hm.ConnectSetTransactionLabel(hm.setTransactionLabel)
+	hm.ConnectBalanceSeries(hm.balanceSeries)
+	hm.ConnectRecentRecipients(hm.recentRecipients)
 	hm.walletEnv = models.GetWalletEnv()
+	hm.blockchain = coin.NewSkycoinBlockchain(fcParams.DataRefreshTimeout * uint64(time.Second))
 
 	hm.txnForAddresses = make(map[string][]core.Transaction, 0)
 	hm.newTxn = make(map[string][]core.Transaction, 0)
 	uptimeTicker := time.NewTicker(10 * time.Second)
 	historyManager = hm
 	hm.txnFinded = make(map[string]struct{}, 0)
+	hm.loadIndexedTxns()
 	go func() {
 		for {
 			select {
@@ -117,84 +145,170 @@ func (hm *HistoryManager) updateTxns() {
 	defer hm.mutexForUpdate.Unlock()
 	logHistoryManager.Info("Getting transactions of Addresses")
 	hm.addresses = hm.getAddressesWithWallets()
+	txnIndex := getTxnIndex()
+	if txnIndex == nil {
+		logHistoryManager.Warn("Transaction index not available , querying the node")
+	}
 	wltIterator := hm.walletEnv.GetWalletSet().ListWallets()
 	if wltIterator == nil {
 		logHistoryManager.WithError(nil).Warn("Couldn't get transactions of Addresses")
 		return
 	}
 	for wltIterator.Next() {
-		logHistoryManager.Debug("Getting addresses history for wallet ", wltIterator.Value().GetId())
-		addressIterator, err := wltIterator.Value().GetLoadedAddresses()
+		var txns []core.Transaction
+		var err error
+		if txnIndex == nil {
+			logHistoryManager.Debug("Getting addresses history for wallet ", wltIterator.Value().GetId())
+			txns, err = listWalletTxns(wltIterator.Value())
+		} else {
+			logHistoryManager.Debug("Synchronizing history for wallet ", wltIterator.Value().GetId())
+			txns, err = data.SyncWalletTxns(txnIndex, wltIterator.Value(), hm.blockchain)
+		}
 		if err != nil {
-			logHistoryManager.Warn("Couldn't get address iterator")
+			logHistoryManager.WithError(err).Warn("Couldn't synchronize wallet history")
+			continue
+		}
+		for _, txn := range txns {
+			if _, exist := hm.txnFinded[txn.GetId()]; exist {
+				continue
+			}
+			txnAddrs, err := hm.walletAddressesOfTxn(txn)
+			if err != nil {
+				logHistoryManager.WithError(err).Warn("Couldn't get transaction addresses")
+				continue
+			}
+			hm.mutexForNew.Lock()
+			for _, addr := range txnAddrs {
+				hm.newTxn[addr] = append(hm.newTxn[addr], txn)
+			}
+			hm.mutexForNew.Unlock()
+			hm.NewTransactions()
+			hm.txnFinded[txn.GetId()] = struct{}{}
+		}
+	}
+}
+
+// listWalletTxns asks the node for the transactions of every loaded address of wlt ,
+// the whole history is fetched each time since there is no index to resume from
+func listWalletTxns(wlt core.Wallet) ([]core.Transaction, error) {
+	addressIterator, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		return nil, err
+	}
+	txns := make([]core.Transaction, 0)
+	for addressIterator.Next() {
+		txnsIterator := addressIterator.Value().GetCryptoAccount().ListTransactions()
+		if txnsIterator == nil {
+			logHistoryManager.Warn("Couldn't get transaction iterator")
 			continue
 		}
+		for txnsIterator.Next() {
+			txns = append(txns, txnsIterator.Value())
+		}
+	}
+	return txns, nil
+}
 
-		for addressIterator.Next() {
-			txnsIterator := addressIterator.Value().GetCryptoAccount().ListTransactions()
-			if txnsIterator == nil {
-				logHistoryManager.Warn("Couldn't get transaction iterator")
+// loadIndexedTxns populates history with transactions recorded in the local index
+func (hm *HistoryManager) loadIndexedTxns() {
+	hm.addresses = hm.getAddressesWithWallets()
+	txnIndex := getTxnIndex()
+	if txnIndex == nil {
+		return
+	}
+	wltIterator := hm.walletEnv.GetWalletSet().ListWallets()
+	if wltIterator == nil {
+		logHistoryManager.WithError(nil).Warn("Couldn't load indexed transactions")
+		return
+	}
+	hm.mutexForAll.Lock()
+	defer hm.mutexForAll.Unlock()
+	for wltIterator.Next() {
+		txnsIterator, err := txnIndex.ListTxns(wltIterator.Value().GetId(), core.TxnQuery{})
+		if err != nil {
+			logHistoryManager.WithError(err).Warn("Couldn't list indexed transactions")
+			continue
+		}
+		for txnsIterator.Next() {
+			txn := txnsIterator.Value()
+			if _, exist := hm.txnFinded[txn.GetId()]; exist {
 				continue
 			}
-			for txnsIterator.Next() {
-				corrupted := false
-				if _, exist := hm.txnFinded[txnsIterator.Value().GetId()]; !exist {
-					for _, in := range txnsIterator.Value().GetInputs() {
-						out, err := in.GetSpentOutput()
-						if err != nil {
-							corrupted = true
-							break
-						}
-						outAddr, err := out.GetAddress()
-						if err != nil {
-							corrupted = true
-							break
-						}
-						if _, exist := hm.addresses[outAddr.String()]; exist {
-							hm.mutexForNew.Lock()
-							_, exist2 := hm.newTxn[outAddr.String()]
-							if exist2 {
-								hm.newTxn[outAddr.String()] = append(hm.newTxn[outAddr.String()], txnsIterator.Value())
-							} else {
-								hm.newTxn[outAddr.String()] = []core.Transaction{txnsIterator.Value()}
-							}
-							hm.mutexForNew.Unlock()
-						}
-					}
-					if corrupted {
-						continue
-					}
-					for _, out := range txnsIterator.Value().GetOutputs() {
-						outAddr, err := out.GetAddress()
-						if err != nil {
-							logHistoryManager.WithError(err).Warn("Couldn't get address")
-							corrupted = true
-							break
-						}
-						if _, exist := hm.addresses[outAddr.String()]; exist {
-							hm.mutexForNew.Lock()
-							_, exist2 := hm.newTxn[outAddr.String()]
-							if exist2 {
-								hm.newTxn[outAddr.String()] = append(hm.newTxn[outAddr.String()], txnsIterator.Value())
-							} else {
-								hm.newTxn[outAddr.String()] = []core.Transaction{txnsIterator.Value()}
-							}
-							hm.mutexForNew.Unlock()
-						}
-					}
-					if corrupted {
-						continue
-					}
-					hm.NewTransactions()
-					hm.txnFinded[txnsIterator.Value().GetId()] = struct{}{}
-				}
+			txnAddrs, err := hm.walletAddressesOfTxn(txn)
+			if err != nil {
+				logHistoryManager.WithError(err).Warn("Couldn't get transaction addresses")
+				continue
 			}
+			for _, addr := range txnAddrs {
+				hm.txnForAddresses[addr] = append(hm.txnForAddresses[addr], txn)
+			}
+			hm.txnFinded[txn.GetId()] = struct{}{}
+		}
+	}
+}
 
+// walletAddressesOfTxn lists known wallet addresses sending or receiving funds in a transaction
+func (hm *HistoryManager) walletAddressesOfTxn(txn core.Transaction) ([]string, error) {
+	txnAddrs := make([]string, 0)
+	for _, in := range txn.GetInputs() {
+		out, err := in.GetSpentOutput()
+		if err != nil {
+			return nil, err
 		}
+		outAddr, err := out.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		if _, exist := hm.addresses[outAddr.String()]; exist {
+			txnAddrs = append(txnAddrs, outAddr.String())
+		}
+	}
+	for _, out := range txn.GetOutputs() {
+		outAddr, err := out.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		if _, exist := hm.addresses[outAddr.String()]; exist {
+			txnAddrs = append(txnAddrs, outAddr.String())
+		}
+	}
+	return txnAddrs, nil
+}
+
+// getTxnIndex returns wallet history kept in the application database , nil if not available
+func getTxnIndex() core.TxnIndex {
+	txnIndex, _ := addressBook.GetStorage().(core.TxnIndex)
+	return txnIndex
+}
+
+// TxnContext holds what describing transactions needs , looked up once per history refresh
+type TxnContext struct {
+	// Labels maps transaction IDs to their labels
+	Labels map[string]string
+	// HeadSeq is the chain height confirmations are counted against , zero if unknown
+	HeadSeq uint64
+	// Contacts maps addresses to the names of the contacts owning them
+	Contacts map[string]string
+}
+
+// newTxnContext looks up the transaction labels , the chain height and the contact names ,
+// leaving them empty if the address book is locked or the node can't be reached
+func (hm *HistoryManager) newTxnContext() *TxnContext {
+	ctx := &TxnContext{Contacts: addressBook.ContactNames(params.SkycoinTicker)}
+	if headSeq, err := hm.blockchain.GetNumberOfBlocks(); err == nil {
+		ctx.HeadSeq = headSeq
+	} else {
+		logHistoryManager.WithError(err).Warn("Couldn't get number of blocks")
 	}
+	if store := addressBook.GetLabelStore(); store != nil {
+		// Locked labels leave transactions unlabelled
+		ctx.Labels, _ = historyutil.TxnLabels(store)
+	}
+	return ctx
 }
 
 func (hm *HistoryManager) getTransactions() []*transactions.TransactionDetails {
+	ctx := hm.newTxnContext()
 	hm.mutexForAll.Lock()
 
 	txnsForReturn := make([]*transactions.TransactionDetails, 0)
@@ -202,7 +316,7 @@ func (hm *HistoryManager) getTransactions() []*transactions.TransactionDetails {
 	for _, txns := range hm.txnForAddresses {
 		for _, txn := range txns {
 			if _, exist := added[txn.GetId()]; !exist {
-				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses)
+				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses, ctx)
 				if err != nil {
 					logHistoryManager.WithError(err).Warn("Couldn't convert transaction")
 				}
@@ -212,12 +326,13 @@ func (hm *HistoryManager) getTransactions() []*transactions.TransactionDetails {
 		}
 	}
 	hm.mutexForAll.Unlock()
-	newTxns := hm.getNewTransactions()
+	newTxns := hm.newTransactions(ctx)
 	txnsForReturn = append(txnsForReturn, newTxns...)
 	return txnsForReturn
 }
 
 func (hm *HistoryManager) getTransansactionsWithFilters() []*transactions.TransactionDetails {
+	ctx := hm.newTxnContext()
 	hm.mutexForAll.Lock()
 
 	txnsForReturn := make([]*transactions.TransactionDetails, 0)
@@ -225,7 +340,7 @@ func (hm *HistoryManager) getTransansactionsWithFilters() []*transactions.Transa
 	for _, addr := range hm.filters {
 		for _, txn := range hm.txnForAddresses[addr] {
 			if _, exist := added[txn.GetId()]; !exist {
-				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses)
+				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses, ctx)
 				if err != nil {
 					logHistoryManager.WithError(err).Warn("Couldn't convert transaction")
 				}
@@ -235,12 +350,16 @@ func (hm *HistoryManager) getTransansactionsWithFilters() []*transactions.Transa
 		}
 	}
 	defer hm.mutexForAll.Unlock()
-	newTxns := hm.getNewTransactionsWithFilters()
+	newTxns := hm.newTransactionsWithFilters(ctx)
 	txnsForReturn = append(txnsForReturn, newTxns...)
 	return txnsForReturn
 }
 
 func (hm *HistoryManager) getNewTransactions() []*transactions.TransactionDetails {
+	return hm.newTransactions(hm.newTxnContext())
+}
+
+func (hm *HistoryManager) newTransactions(ctx *TxnContext) []*transactions.TransactionDetails {
 	hm.mutexForNew.Lock()
 	defer hm.mutexForNew.Unlock()
 	txnsForReturn := make([]*transactions.TransactionDetails, 0)
@@ -248,7 +367,7 @@ func (hm *HistoryManager) getNewTransactions() []*transactions.TransactionDetail
 	for addr, _ := range hm.newTxn {
 		for _, txn := range hm.newTxn[addr] {
 			if _, exist := added[txn.GetId()]; !exist {
-				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses)
+				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses, ctx)
 				if err != nil {
 					logHistoryManager.WithError(err).Warn("Couldn't convert transaction")
 				}
@@ -270,6 +389,10 @@ func (hm *HistoryManager) getNewTransactions() []*transactions.TransactionDetail
 }
 
 func (hm *HistoryManager) getNewTransactionsWithFilters() []*transactions.TransactionDetails {
+	return hm.newTransactionsWithFilters(hm.newTxnContext())
+}
+
+func (hm *HistoryManager) newTransactionsWithFilters(ctx *TxnContext) []*transactions.TransactionDetails {
 	hm.mutexForNew.Lock()
 	defer hm.mutexForNew.Unlock()
 	txnsForReturn := make([]*transactions.TransactionDetails, 0)
@@ -277,7 +400,7 @@ func (hm *HistoryManager) getNewTransactionsWithFilters() []*transactions.Transa
 	for _, addr := range hm.filters {
 		for _, txn := range hm.newTxn[addr] {
 			if _, exist := added[txn.GetId()]; !exist {
-				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses)
+				txnDetail, err := TransactionDetailsFromCoreTxn(txn, hm.addresses, ctx)
 				if err != nil {
 					logHistoryManager.WithError(err).Warn("Couldn't convert transaction")
 				}
@@ -299,6 +422,163 @@ func (hm *HistoryManager) getNewTransactionsWithFilters() []*transactions.Transa
 	return txnsForReturn
 }
 
+// knownTxns lists every transaction in history , including those not yet returned as new
+func (hm *HistoryManager) knownTxns() []core.Transaction {
+	txns := make([]core.Transaction, 0)
+	hm.mutexForAll.Lock()
+	for _, addrTxns := range hm.txnForAddresses {
+		txns = append(txns, addrTxns...)
+	}
+	hm.mutexForAll.Unlock()
+	hm.mutexForNew.Lock()
+	for _, addrTxns := range hm.newTxn {
+		txns = append(txns, addrTxns...)
+	}
+	hm.mutexForNew.Unlock()
+	return txns
+}
+
+// historyEntries returns the history entries matching query , ignoring pagination
+func (hm *HistoryManager) historyEntries(query historyutil.Query) ([]*historyutil.Entry, error) {
+	entries, err := hm.labelledEntries(hm.newTxnContext())
+	if err != nil {
+		return nil, err
+	}
+	query.Cursor, query.Limit = "", 0
+	page, err := query.Run(entries)
+	if err != nil {
+		return nil, err
+	}
+	return page.Entries, nil
+}
+
+// labelledEntries describes every known transaction including its label and confirmations
+func (hm *HistoryManager) labelledEntries(ctx *TxnContext) ([]*historyutil.Entry, error) {
+	entries, err := historyutil.NewEntries(hm.knownTxns(), hm.addresses)
+	if err != nil {
+		return nil, err
+	}
+	historyutil.AttachConfirmations(entries, ctx.HeadSeq)
+	historyutil.AttachLabels(entries, ctx.Labels)
+	historyutil.AttachContacts(entries, ctx.Contacts)
+	return entries, nil
+}
+
+func (hm *HistoryManager) queryTransactions(since, until *qtCore.QDateTime, direction int, minAmount, maxAmount, counterparty string, contactID uint64, label string, status int, cursor string, limit int) []*transactions.TransactionDetails {
+	logHistoryManager.Info("Querying transactions")
+	query := historyutil.Query{
+		Direction: core.TxnDirection(direction),
+		Label:     label,
+		Cursor:    cursor,
+		Limit:     limit,
+	}
+	if since != nil && since.IsValid() {
+		query.Since = core.Timestamp(since.ToSecsSinceEpoch())
+	}
+	if until != nil && until.IsValid() {
+		query.Until = core.Timestamp(until.ToSecsSinceEpoch())
+	}
+	var err error
+	if minAmount != "" {
+		if query.MinAmount, err = util.GetCoinValue(minAmount, params.SkycoinTicker); err != nil {
+			logHistoryManager.WithError(err).Warn("Invalid minimum amount")
+			return nil
+		}
+	}
+	if maxAmount != "" {
+		if query.MaxAmount, err = util.GetCoinValue(maxAmount, params.SkycoinTicker); err != nil {
+			logHistoryManager.WithError(err).Warn("Invalid maximum amount")
+			return nil
+		}
+	}
+	if counterparty != "" {
+		query.Counterparties = []string{counterparty}
+	}
+	if contactID != 0 {
+		if query.Contact, err = addressBook.GetAddrsBook().GetContact(contactID); err != nil {
+			logHistoryManager.WithError(err).Warn("Couldn't get contact")
+			return nil
+		}
+	}
+	switch status {
+	case transactions.TransactionStatusConfirmed:
+		query.Statuses = []core.TransactionStatus{core.TXN_STATUS_CONFIRMED}
+	case transactions.TransactionStatusPending:
+		query.Statuses = []core.TransactionStatus{core.TXN_STATUS_PENDING}
+	}
+
+	ctx := hm.newTxnContext()
+	entries, err := hm.labelledEntries(ctx)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't describe transactions")
+		return nil
+	}
+	page, err := query.Run(entries)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't query transactions")
+		return nil
+	}
+	hm.lastQuery = query
+	hm.SetNextCursor(page.NextCursor)
+	txnsForReturn := make([]*transactions.TransactionDetails, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		txnDetail, err := TransactionDetailsFromCoreTxn(entry.Txn, hm.addresses, ctx)
+		if err != nil {
+			logHistoryManager.WithError(err).Warn("Couldn't convert transaction")
+			continue
+		}
+		txnsForReturn = append(txnsForReturn, txnDetail)
+	}
+	return txnsForReturn
+}
+
+// exportTransactions writes every transaction matching the last query to a CSV or JSON file
+func (hm *HistoryManager) exportTransactions(path, format string) bool {
+	logHistoryManager.Info("Exporting transactions")
+	entries, err := hm.historyEntries(hm.lastQuery)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't query transactions")
+		return false
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't create export file")
+		return false
+	}
+	if err := historyutil.Export(f, format, entries); err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't export transactions")
+		_ = f.Close()
+		return false
+	}
+	// Written data may be lost if closing fails
+	if err := f.Close(); err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't write export file")
+		return false
+	}
+	return true
+}
+
+func (hm *HistoryManager) setTransactionLabel(txnID, label string) bool {
+	store := addressBook.GetLabelStore()
+	if store == nil {
+		logHistoryManager.Warn("Labels are not available")
+		hm.LabelsNotSaved()
+		return false
+	}
+	var err error
+	if label == "" {
+		err = store.DeleteLabel(core.LabelTypeTxn, txnID)
+	} else {
+		err = store.SetLabel(core.Label{Type: core.LabelTypeTxn, Ref: txnID, Label: label})
+	}
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't save transaction label")
+		hm.LabelsNotSaved()
+		return false
+	}
+	return true
+}
+
 func (hm *HistoryManager) addFilter(addr string) {
 	logHistoryManager.Info("Add filter")
 	alreadyIs := false
@@ -346,7 +626,7 @@ func (hm *HistoryManager) getAddressesWithWallets() map[string]string {
 	return response
 }
 
-func TransactionDetailsFromCoreTxn(txn core.Transaction, addresses map[string]string) (*transactions.TransactionDetails, error) {
+func TransactionDetailsFromCoreTxn(txn core.Transaction, addresses map[string]string, ctx *TxnContext) (*transactions.TransactionDetails, error) {
 	var traspassedHoursIn, traspassedHoursOut, skyAmountIn, skyAmountOut uint64
 	traspassedHoursIn = 0
 	traspassedHoursOut = 0
@@ -378,6 +658,7 @@ func TransactionDetailsFromCoreTxn(txn core.Transaction, addresses map[string]st
 			return nil, err
 		}
 		qIn.SetAddress(outAddr.String())
+		qIn.SetContact(ctx.Contacts[outAddr.String()])
 		skyUint64, err := in.GetCoins(params.SkycoinTicker)
 		if err != nil {
 			logHistoryManager.WithError(err).Warn("Couldn't get Skycoins balance")
@@ -429,6 +710,7 @@ func TransactionDetailsFromCoreTxn(txn core.Transaction, addresses map[string]st
 			return nil, err
 		}
 		qOu.SetAddress(outAddr.String())
+		qOu.SetContact(ctx.Contacts[outAddr.String()])
 		accuracy, err := util.AltcoinQuotient(params.SkycoinTicker)
 		if err != nil {
 			logHistoryManager.WithError(err).Warn("Couldn't get Skycoins quotient")
@@ -601,6 +883,8 @@ func TransactionDetailsFromCoreTxn(txn core.Transaction, addresses map[string]st
 	}
 	txnDetails.SetAddresses(txnAddresses)
 	txnDetails.SetTransactionID(txn.GetId())
+	txnDetails.SetLabel(ctx.Labels[txn.GetId()])
+	txnDetails.SetConfirmations(int(util.TxnConfirmations(txn, ctx.HeadSeq)))
 	return txnDetails, nil
 
 }
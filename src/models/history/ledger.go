@@ -0,0 +1,62 @@
+package history
+
+import (
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/fibercrypto/fibercryptowallet/src/util/historyutil"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+func init() {
+	QBalancePoint_QmlRegisterType2("HistoryModels", 1, 0, "QBalancePoint")
+}
+
+// QBalancePoint balance of a wallet at a given date
+type QBalancePoint struct {
+	qtCore.QObject
+	_ *qtCore.QDateTime `property:"date"`
+	_ string            `property:"sky"`
+	_ string            `property:"coinHours"`
+}
+
+func (hm *HistoryManager) balanceSeries(wltID string, since, until *qtCore.QDateTime, points int) []*QBalancePoint {
+	logHistoryManager.Info("Computing balance series")
+	ledger, err := historyutil.WalletLedger(hm.knownTxns(), hm.addresses, wltID)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't compute wallet ledger")
+		return nil
+	}
+	skyQuotient, err := util.AltcoinQuotient(params.SkycoinTicker)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't get Skycoins quotient")
+		return nil
+	}
+	hoursQuotient, err := util.AltcoinQuotient(params.CoinHoursTicker)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't get Coin Hours quotient")
+		return nil
+	}
+	var sinceTs, untilTs core.Timestamp
+	if since != nil && since.IsValid() {
+		sinceTs = core.Timestamp(since.ToSecsSinceEpoch())
+	}
+	if until != nil && until.IsValid() {
+		untilTs = core.Timestamp(until.ToSecsSinceEpoch())
+	}
+	series := ledger.Series(sinceTs, untilTs, points)
+	qPoints := make([]*QBalancePoint, 0, len(series))
+	for _, point := range series {
+		qPoint := NewQBalancePoint(nil)
+		qml.QQmlEngine_SetObjectOwnership(qPoint, qml.QQmlEngine__CppOwnership)
+		t := time.Unix(int64(point.Timestamp), 0)
+		qPoint.SetDate(qtCore.NewQDateTime3(qtCore.NewQDate3(t.Year(), int(t.Month()), t.Day()), qtCore.NewQTime3(t.Hour(), t.Minute(), t.Second(), 0), qtCore.Qt__LocalTime))
+		qPoint.SetSky(historyutil.FormatAmount(point.Balance.Coins, skyQuotient))
+		qPoint.SetCoinHours(historyutil.FormatAmount(point.Balance.Hours, hoursQuotient))
+		qPoints = append(qPoints, qPoint)
+	}
+	return qPoints
+}
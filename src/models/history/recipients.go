@@ -0,0 +1,74 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/data"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+func init() {
+	QRecentRecipient_QmlRegisterType2("HistoryModels", 1, 0, "QRecentRecipient")
+}
+
+// QRecentRecipient address funds were recently sent to , with the contact owning it
+type QRecentRecipient struct {
+	qtCore.QObject
+	_ string            `property:"address"`
+	_ uint64            `property:"contactId"`
+	_ string            `property:"contactName"`
+	_ string            `property:"label"`
+	_ bool              `property:"favourite"`
+	_ *qtCore.QDateTime `property:"lastSent"`
+	_ string            `property:"txnId"`
+	_ int               `property:"count"`
+}
+
+// recentRecipients lists at most limit addresses paid from known wallets , latest first.
+// Contact fields are empty for addresses not in the address book.
+func (hm *HistoryManager) recentRecipients(limit int) []*QRecentRecipient {
+	logHistoryManager.Info("Loading recent recipients")
+	txnIndex := getTxnIndex()
+	if txnIndex == nil {
+		logHistoryManager.Warn("Transaction index not available")
+		return nil
+	}
+	addresses := hm.getAddressesWithWallets()
+	own := make(map[string]struct{}, len(addresses))
+	wltIDs := make([]string, 0)
+	seen := make(map[string]struct{})
+	for addr, wltID := range addresses {
+		own[addr] = struct{}{}
+		if _, isSeen := seen[wltID]; !isSeen {
+			seen[wltID] = struct{}{}
+			wltIDs = append(wltIDs, wltID)
+		}
+	}
+	sort.Strings(wltIDs)
+	recipients, err := data.RecentRecipients(txnIndex, addressBook.GetAddrsBook(), wltIDs, own, limit)
+	if err != nil {
+		logHistoryManager.WithError(err).Warn("Couldn't load recent recipients")
+		return nil
+	}
+	qRecipients := make([]*QRecentRecipient, 0, len(recipients))
+	for _, r := range recipients {
+		qr := NewQRecentRecipient(nil)
+		qml.QQmlEngine_SetObjectOwnership(qr, qml.QQmlEngine__CppOwnership)
+		qr.SetAddress(r.Address)
+		if r.Contact != nil {
+			qr.SetContactId(r.Contact.GetID())
+			qr.SetContactName(r.Contact.GetName())
+			qr.SetFavourite(r.Contact.IsFavourite())
+		}
+		qr.SetLabel(r.Label)
+		t := time.Unix(int64(r.LastSent), 0)
+		qr.SetLastSent(qtCore.NewQDateTime3(qtCore.NewQDate3(t.Year(), int(t.Month()), t.Day()), qtCore.NewQTime3(t.Hour(), t.Minute(), t.Second(), 0), qtCore.Qt__LocalTime))
+		qr.SetTxnId(r.TxnID)
+		qr.SetCount(r.Count)
+		qRecipients = append(qRecipients, qr)
+	}
+	return qRecipients
+}
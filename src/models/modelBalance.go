@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+// QBalance breakdown of the funds of a wallet for a given asset
+type QBalance struct {
+	qtCore.QObject
+
+	_ string `property:"ticker"`
+	_ string `property:"confirmed"`
+	_ string `property:"predicted"`
+	_ string `property:"spendable"`
+	_ string `property:"locked"`
+	_ string `property:"accrued"`
+}
+
+func (walletM *WalletManager) getBalanceBreakdown(wltId, ticker string) *QBalance {
+	logWalletManager.Info("Getting balance breakdown")
+	wlt := walletM.WalletEnv.GetWalletSet().GetWallet(wltId)
+	if wlt == nil {
+		logWalletManager.WithField("id", wltId).Warn("Couldn't load wallet")
+		return nil
+	}
+	bl, err := wlt.GetCryptoAccount().GetBalanceBreakdown(ticker)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't get balance breakdown")
+		return nil
+	}
+	accuracy, err := util.AltcoinQuotient(ticker)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't get " + ticker + " quotient")
+		return nil
+	}
+	qBalance := NewQBalance(nil)
+	qml.QQmlEngine_SetObjectOwnership(qBalance, qml.QQmlEngine__CppOwnership)
+	qBalance.SetTicker(ticker)
+	qBalance.SetConfirmed(util.FormatCoins(bl.Confirmed, accuracy))
+	qBalance.SetPredicted(util.FormatCoins(bl.Predicted, accuracy))
+	qBalance.SetSpendable(util.FormatCoins(bl.Spendable, accuracy))
+	qBalance.SetLocked(util.FormatCoins(bl.Locked, accuracy))
+	qBalance.SetAccrued(util.FormatCoins(bl.Accrued, accuracy))
+	return qBalance
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
 	"github.com/therecipe/qt/core"
 )
 
@@ -10,6 +11,8 @@ const (
 	AddressCoinHours
 	AddressOwner
 	WalletOwner
+	OutputLabel
+	OutputStatus
 )
 
 type ModelOutputs struct {
@@ -37,6 +40,8 @@ type QOutput struct {
 	_ string `property:"addressCoinHours"`
 	_ string `property:"addressOwner"`
 	_ string `property:"walletOwner"`
+	_ string `property:"label"`
+	_ int    `property:"state"`
 }
 
 func (m *ModelOutputs) init() {
@@ -46,6 +51,8 @@ func (m *ModelOutputs) init() {
 		AddressCoinHours: core.NewQByteArray2("addressCoinHours", -1),
 		AddressOwner:     core.NewQByteArray2("addressOwner", -1),
 		WalletOwner:      core.NewQByteArray2("walletOwner", -1),
+		OutputLabel:      core.NewQByteArray2("label", -1),
+		OutputStatus:     core.NewQByteArray2("state", -1),
 	})
 
 	m.ConnectRowCount(m.rowCount)
@@ -121,6 +128,14 @@ func (m *ModelOutputs) data(index *core.QModelIndex, role int) *core.QVariant {
 		{
 			return core.NewQVariant1(qo.WalletOwner())
 		}
+	case OutputLabel:
+		{
+			return core.NewQVariant1(qo.Label())
+		}
+	case OutputStatus:
+		{
+			return core.NewQVariant1(qo.State())
+		}
 	default:
 		{
 			return core.NewQVariant()
@@ -186,3 +201,16 @@ func (m *ModelOutputs) cleanModel() {
 	m.outputs = make([]*QOutput, 0)
 	m.EndResetModel()
 }
+
+// labelOf returns the stored label of an entity , empty if it is unlabelled or labels are locked
+func labelOf(labelType, ref string) string {
+	store := addressBook.GetLabelStore()
+	if store == nil {
+		return ""
+	}
+	label, err := store.GetLabel(labelType, ref)
+	if err != nil || label == nil {
+		return ""
+	}
+	return label.Label
+}
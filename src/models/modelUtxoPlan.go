@@ -0,0 +1,176 @@
+package models
+
+import (
+	"github.com/SkycoinProject/skycoin/src/util/droplet"
+	sky "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+// QUtxoPlan preview of a transaction consolidating or splitting wallet outputs
+type QUtxoPlan struct {
+	qtCore.QObject
+
+	_ int           `property:"inputCount"`
+	_ string        `property:"inputSky"`
+	_ string        `property:"fee"`
+	_ *ModelOutputs `property:"outputs"`
+	_ string        `property:"changeSky"`
+	_ string        `property:"changeCoinHours"`
+
+	plan *sky.UtxoPlan
+}
+
+func (walletM *WalletManager) previewConsolidation(wltId, threshold, destination string) *QUtxoPlan {
+	logWalletManager.Info("Planning outputs consolidation")
+	wlt := walletM.WalletEnv.GetWalletSet().GetWallet(wltId)
+	if wlt == nil {
+		logWalletManager.WithField("id", wltId).Warn("Couldn't load wallet")
+		return nil
+	}
+	droplets, err := droplet.FromString(threshold)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't parse consolidation threshold")
+		return nil
+	}
+	dest, err := walletM.planDestination(wlt, destination)
+	if err != nil {
+		return nil
+	}
+	opt := util.NewKeyValueMap()
+	walletM.setCoinControlOptions(opt)
+	plan, err := sky.NewConsolidationPlan(wlt, droplets, dest, opt)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't plan outputs consolidation")
+		return nil
+	}
+	return newQUtxoPlan(plan, wltId)
+}
+
+func (walletM *WalletManager) previewSplit(wltId, outputId string, amounts []string, destination string) *QUtxoPlan {
+	logWalletManager.Info("Planning output split")
+	wlt := walletM.WalletEnv.GetWalletSet().GetWallet(wltId)
+	if wlt == nil {
+		logWalletManager.WithField("id", wltId).Warn("Couldn't load wallet")
+		return nil
+	}
+	droplets := make([]uint64, 0, len(amounts))
+	for _, amount := range amounts {
+		val, err := droplet.FromString(amount)
+		if err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't parse split amount")
+			return nil
+		}
+		droplets = append(droplets, val)
+	}
+	dest, err := walletM.planDestination(wlt, destination)
+	if err != nil {
+		return nil
+	}
+	opt := util.NewKeyValueMap()
+	walletM.setCoinControlOptions(opt)
+	plan, err := sky.NewSplitPlan(wlt, outputId, droplets, dest, opt)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't plan output split")
+		return nil
+	}
+	return newQUtxoPlan(plan, wltId)
+}
+
+func (walletM *WalletManager) buildUtxoPlan(qPlan *QUtxoPlan) *QTransaction {
+	logWalletManager.Info("Creating transaction out of outputs plan")
+	if qPlan == nil || qPlan.plan == nil {
+		logWalletManager.Warn("Invalid outputs plan")
+		return nil
+	}
+	opt := util.NewKeyValueMap()
+	walletM.setCoinControlOptions(opt)
+	txn, err := qPlan.plan.Build(opt)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't create transaction")
+		return nil
+	}
+	qTxn, err := NewQTransactionFromTransaction(txn)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't convert transaction")
+		return nil
+	}
+	return qTxn
+}
+
+// planDestination resolves the address receiving planned outputs , the first wallet address if empty
+func (walletM *WalletManager) planDestination(wlt core.Wallet, destination string) (core.Address, error) {
+	if destination != "" {
+		addr, err := sky.NewSkycoinAddress(destination)
+		if err != nil {
+			logWalletManager.WithError(err).Warn("Invalid destination address")
+			return nil, err
+		}
+		return &addr, nil
+	}
+	it, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't load addresses iterator")
+		return nil, err
+	}
+	if !it.Next() {
+		logWalletManager.Warn("Wallet has no addresses")
+		return nil, errors.ErrNotFound
+	}
+	return it.Value(), nil
+}
+
+func newQUtxoPlan(plan *sky.UtxoPlan, wltId string) *QUtxoPlan {
+	qPlan := NewQUtxoPlan(nil)
+	qml.QQmlEngine_SetObjectOwnership(qPlan, qml.QQmlEngine__CppOwnership)
+	qPlan.plan = plan
+	qPlan.SetInputCount(len(plan.Inputs))
+	var inputCoins uint64
+	for _, in := range plan.Inputs {
+		coins, err := in.GetCoins(sky.Sky)
+		if err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't get " + sky.Sky + " coins")
+			return nil
+		}
+		inputCoins += coins
+	}
+	qPlan.SetInputSky(formatPlanCoins(inputCoins, sky.Sky))
+	qPlan.SetFee(formatPlanCoins(plan.Fee, sky.CoinHour))
+	qOutputs := make([]*QOutput, 0, len(plan.Outputs))
+	for _, out := range plan.Outputs {
+		qOut := NewQOutput(nil)
+		qml.QQmlEngine_SetObjectOwnership(qOut, qml.QQmlEngine__CppOwnership)
+		if addr, err := out.GetAddress(); err == nil {
+			qOut.SetAddressOwner(addr.String())
+		}
+		coins, _ := out.GetCoins(sky.Sky)
+		hours, _ := out.GetCoins(sky.CoinHour)
+		qOut.SetAddressSky(formatPlanCoins(coins, sky.Sky))
+		qOut.SetAddressCoinHours(formatPlanCoins(hours, sky.CoinHour))
+		qOut.SetWalletOwner(wltId)
+		qOutputs = append(qOutputs, qOut)
+	}
+	outputs := NewModelOutputs(nil)
+	qml.QQmlEngine_SetObjectOwnership(outputs, qml.QQmlEngine__CppOwnership)
+	outputs.loadModel(qOutputs)
+	qPlan.SetOutputs(outputs)
+	if plan.Change != nil {
+		coins, _ := plan.Change.GetCoins(sky.Sky)
+		hours, _ := plan.Change.GetCoins(sky.CoinHour)
+		qPlan.SetChangeSky(formatPlanCoins(coins, sky.Sky))
+		qPlan.SetChangeCoinHours(formatPlanCoins(hours, sky.CoinHour))
+	}
+	return qPlan
+}
+
+func formatPlanCoins(n uint64, ticker string) string {
+	quotient, err := util.AltcoinQuotient(ticker)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't get " + ticker + " quotient")
+		return "N/A"
+	}
+	return util.FormatCoins(n, quotient)
+}
@@ -169,6 +169,10 @@ func (m *ModelWallets) loadModel() {
 					qo := NewQOutput(nil)
 					qml.QQmlEngine_SetObjectOwnership(qo, qml.QQmlEngine__CppOwnership)
 					qo.SetOutputID(to.GetId())
+					qo.SetLabel(labelOf(core.LabelTypeOutput, to.GetId()))
+					if walletM := GetWalletManager(); walletM != nil {
+						qo.SetState(walletM.outputState(wlt.GetId(), to.GetId()))
+					}
 					val, err := to.GetCoins(coin.Sky)
 					if err != nil {
 						logWalletModel.WithError(nil).Warn("Couldn't get " + coin.Sky + " coins")
@@ -10,6 +10,14 @@ func init() {
 	AddressesModel_QmlRegisterType2("WalletsManager", 1, 0, "AddressModel")
 	QAddress_QmlRegisterType2("WalletsManager", 1, 0, "QAddress")
 	WalletManager_QmlRegisterType2("WalletsManager", 1, 0, "WalletManager")
+	QBalance_QmlRegisterType2("WalletsManager", 1, 0, "QBalance")
+	QUtxoPlan_QmlRegisterType2("WalletsManager", 1, 0, "QUtxoPlan")
+	QOutboxTxn_QmlRegisterType2("WalletsManager", 1, 0, "QOutboxTxn")
+	QPaymentWatch_QmlRegisterType2("WalletsManager", 1, 0, "QPaymentWatch")
+	QPaymentRequest_QmlRegisterType2("WalletsManager", 1, 0, "QPaymentRequest")
+	QBatchPayout_QmlRegisterType2("WalletsManager", 1, 0, "QBatchPayout")
+	QPayoutResult_QmlRegisterType2("WalletsManager", 1, 0, "QPayoutResult")
+	QBackupInfo_QmlRegisterType2("WalletsManager", 1, 0, "QBackupInfo")
 	ConfigManager_QmlRegisterType2("Config", 1, 0, "ConfigManager")
 	KeyValueStorage_QmlRegisterType2("Config", 1, 0, "Options")
 	ModelManager_QmlRegisterType2("WalletsManager", 1, 0, "ModelManager")
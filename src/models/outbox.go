@@ -0,0 +1,270 @@
+package models
+
+import (
+	"time"
+
+	sky "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/skytypes"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/data"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
+	fcParams "github.com/fibercrypto/fibercryptowallet/src/params"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+const (
+	// outboxSyncInterval time between checks of broadcast transactions
+	outboxSyncInterval = time.Minute
+	// outboxRebroadcastInterval time a pending transaction waits before being broadcast again
+	outboxRebroadcastInterval = 5 * time.Minute
+	// outboxStuckAfter time a transaction may stay pending before being flagged as stuck
+	outboxStuckAfter = time.Hour
+	// confirmationsPollInterval time between checks of a transaction awaited to be confirmed
+	confirmationsPollInterval = 30 * time.Second
+	// confirmationsWaitTimeout time after which waiting for confirmations is given up
+	confirmationsWaitTimeout = 24 * time.Hour
+)
+
+// QOutboxTxn transaction broadcast by the wallet
+type QOutboxTxn struct {
+	qtCore.QObject
+
+	_ string `property:"transactionId"`
+	_ string `property:"walletId"`
+	_ string `property:"broadcastAt"`
+	_ string `property:"lastBroadcastAt"`
+	_ int    `property:"attempts"`
+	_ int    `property:"status"`
+	_ int    `property:"flag"`
+}
+
+// openOutbox returns broadcast transactions kept in the application database , nil if not available
+func openOutbox() core.Outbox {
+	outbox, isOutbox := addressBook.GetStorage().(core.Outbox)
+	if !isOutbox {
+		logWalletManager.Error("Couldn't open outbox database")
+		return nil
+	}
+	return outbox
+}
+
+// recordBroadcastTxn adds a transaction injected to the network to the outbox
+func (walletM *WalletManager) recordBroadcastTxn(txn core.Transaction) {
+	if walletM.outbox == nil {
+		return
+	}
+	record, err := newOutboxRecord(walletM.txnWalletID(txn), txn)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't record transaction in outbox")
+		return
+	}
+	now := time.Now()
+	record.BroadcastAt, record.LastBroadcastAt = now, now
+	record.Attempts = 1
+	record.Status = core.TXN_STATUS_PENDING
+	if err := walletM.outbox.PutOutboxTxn(*record); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't record transaction in outbox")
+		return
+	}
+	walletM.OutboxChanged()
+}
+
+// sendOutboxTxn broadcasts a signed transaction through the outbox so that it is tracked until confirmed
+func (walletM *WalletManager) sendOutboxTxn(wltId string, txn core.Transaction) error {
+	tracker, err := loadTxnTracker()
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't load transaction tracker")
+		return err
+	}
+	record, err := newOutboxRecord(wltId, txn)
+	if err != nil {
+		return err
+	}
+	if walletM.outbox == nil {
+		return tracker.BroadcastRawTxn(record.Raw)
+	}
+	if err := data.SendOutboxTxn(walletM.outbox, tracker, *record, time.Now()); err != nil {
+		return err
+	}
+	walletM.OutboxChanged()
+	return nil
+}
+
+// newOutboxRecord encodes a transaction for the outbox
+func newOutboxRecord(wltId string, txn core.Transaction) (*core.OutboxTxn, error) {
+	skyTxn, isSkyTxn := txn.(skytypes.SkycoinTxn)
+	if !isSkyTxn {
+		return nil, errors.ErrInvalidTxn
+	}
+	raw, err := skyTxn.EncodeSkycoinTransaction()
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't encode transaction")
+		return nil, err
+	}
+	return &core.OutboxTxn{
+		ID:         txn.GetId(),
+		WalletID:   wltId,
+		CoinTicker: params.SkycoinTicker,
+		Raw:        raw,
+	}, nil
+}
+
+// txnWalletID returns the ID of the wallet owning inputs of a transaction , empty if unknown
+func (walletM *WalletManager) txnWalletID(txn core.Transaction) string {
+	for _, in := range txn.GetInputs() {
+		out, err := in.GetSpentOutput()
+		if err != nil {
+			continue
+		}
+		addr, err := out.GetAddress()
+		if err != nil {
+			continue
+		}
+		for wltId, addrs := range walletM.addresseseByWallets {
+			if _, isOwned := addrs[addr.String()]; isOwned {
+				return wltId
+			}
+		}
+	}
+	return ""
+}
+
+// loadTxnTracker returns the tracker following transactions broadcast to the network
+func loadTxnTracker() (core.TxnTracker, error) {
+	plug, _ := local.LoadAltcoinManager().LookupAltcoinPlugin(params.SkycoinTicker)
+	pex, err := plug.LoadPEX("MainNet")
+	if err != nil {
+		return nil, err
+	}
+	tracker, isTracker := pex.(core.TxnTracker)
+	if !isTracker {
+		return nil, errors.ErrNotImplemented
+	}
+	return tracker, nil
+}
+
+// trackOutbox periodically updates the status of broadcast transactions , rebroadcasting those still pending
+func (walletM *WalletManager) trackOutbox() {
+	if walletM.outbox == nil {
+		return
+	}
+	policy := data.OutboxPolicy{
+		RebroadcastInterval: outboxRebroadcastInterval,
+		StuckAfter:          outboxStuckAfter,
+	}
+	ticker := time.NewTicker(outboxSyncInterval)
+	for range ticker.C {
+		tracker, err := loadTxnTracker()
+		if err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't load transaction tracker")
+			continue
+		}
+		changed, err := data.SyncOutbox(walletM.outbox, params.SkycoinTicker, tracker, policy, time.Now())
+		if err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't synchronize outbox")
+			continue
+		}
+		for _, txn := range changed {
+			switch txn.Flag {
+			case core.OutboxFlagStuck:
+				logWalletManager.WithField("id", txn.ID).Warn("Transaction is stuck")
+			case core.OutboxFlagInvalidated:
+				logWalletManager.WithField("id", txn.ID).Warn("Transaction was invalidated")
+			}
+		}
+		if len(changed) > 0 {
+			walletM.OutboxChanged()
+		}
+	}
+}
+
+func (walletM *WalletManager) getOutbox() []*QOutboxTxn {
+	qTxns := make([]*QOutboxTxn, 0)
+	if walletM.outbox == nil {
+		return qTxns
+	}
+	txns, err := walletM.outbox.ListOutboxTxns()
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't list outbox")
+		return qTxns
+	}
+	for _, txn := range txns {
+		qTxn := NewQOutboxTxn(nil)
+		qml.QQmlEngine_SetObjectOwnership(qTxn, qml.QQmlEngine__CppOwnership)
+		qTxn.SetTransactionId(txn.ID)
+		qTxn.SetWalletId(txn.WalletID)
+		qTxn.SetBroadcastAt(txn.BroadcastAt.Format(time.RFC3339))
+		qTxn.SetLastBroadcastAt(txn.LastBroadcastAt.Format(time.RFC3339))
+		qTxn.SetAttempts(txn.Attempts)
+		qTxn.SetStatus(int(txn.Status))
+		qTxn.SetFlag(int(txn.Flag))
+		qTxns = append(qTxns, qTxn)
+	}
+	return qTxns
+}
+
+func (walletM *WalletManager) rebroadcastOutboxTxn(txnId string) bool {
+	if walletM.outbox == nil {
+		return false
+	}
+	txn, err := walletM.outbox.GetOutboxTxn(txnId)
+	if err != nil || txn == nil {
+		logWalletManager.WithField("id", txnId).Warn("Couldn't find transaction in outbox")
+		return false
+	}
+	tracker, err := loadTxnTracker()
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't load transaction tracker")
+		return false
+	}
+	if err := tracker.BroadcastRawTxn(txn.Raw); err != nil {
+		logWalletManager.WithError(err).Warn("Error broadcasting transaction")
+		return false
+	}
+	txn.LastBroadcastAt = time.Now()
+	txn.Attempts++
+	if err := walletM.outbox.PutOutboxTxn(*txn); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't update outbox")
+		return false
+	}
+	walletM.OutboxChanged()
+	return true
+}
+
+func (walletM *WalletManager) dismissOutboxTxn(txnId string) bool {
+	if walletM.outbox == nil {
+		return false
+	}
+	if err := walletM.outbox.DeleteOutboxTxn(txnId); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't remove transaction from outbox")
+		return false
+	}
+	walletM.OutboxChanged()
+	return true
+}
+
+// waitForConfirmations emits transactionConfirmed once a broadcast transaction is buried under n blocks
+func (walletM *WalletManager) waitForConfirmations(txnId string, n int) {
+	if n <= 0 {
+		return
+	}
+	go func() {
+		tracker, err := loadTxnTracker()
+		if err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't load transaction tracker")
+			return
+		}
+		bc := sky.NewSkycoinBlockchain(fcParams.DataRefreshTimeout * uint64(time.Second))
+		confirmations, err := util.WaitForConfirmations(tracker, bc, txnId, uint64(n), confirmationsPollInterval, confirmationsWaitTimeout)
+		if err != nil {
+			logWalletManager.WithError(err).WithField("id", txnId).Warn("Couldn't wait for transaction confirmations")
+			return
+		}
+		walletM.TransactionConfirmed(txnId, int(confirmations))
+	}()
+}
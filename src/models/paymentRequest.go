@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/util/payutil"
+	"github.com/fibercrypto/fibercryptowallet/src/util/qrutil"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+// paymentQrScale SVG units per QR module
+const paymentQrScale = 4
+
+// QPaymentRequest fields of a payment request URI
+type QPaymentRequest struct {
+	qtCore.QObject
+
+	_ string `property:"address"`
+	_ string `property:"amount"`
+	_ string `property:"hours"`
+	_ string `property:"label"`
+	_ string `property:"message"`
+}
+
+func (walletM *WalletManager) encodePaymentRequest(address, amount, hours, label, message string) string {
+	req := payutil.PaymentRequest{
+		CoinTicker: params.SkycoinTicker,
+		Address:    address,
+		Label:      label,
+		Message:    message,
+	}
+	var err error
+	if amount != "" {
+		if req.Amount, err = payutil.ParseAmount(amount, skyAccuracy()); err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't parse requested amount")
+			return ""
+		}
+	}
+	if hours != "" {
+		if req.Hours, err = payutil.ParseAmount(hours, 0); err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't parse requested coin hours")
+			return ""
+		}
+	}
+	uri, err := payutil.EncodeURI(req)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't encode payment request")
+		return ""
+	}
+	return uri
+}
+
+func (walletM *WalletManager) parsePaymentRequest(uri string) *QPaymentRequest {
+	req, err := payutil.ParseURI(uri)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't parse payment request")
+		return nil
+	}
+	qReq := NewQPaymentRequest(nil)
+	qml.QQmlEngine_SetObjectOwnership(qReq, qml.QQmlEngine__CppOwnership)
+	qReq.SetAddress(req.Address)
+	qReq.SetAmount(payutil.FormatAmount(req.Amount, skyAccuracy()))
+	qReq.SetHours(strconv.FormatUint(req.Hours, 10))
+	qReq.SetLabel(req.Label)
+	qReq.SetMessage(req.Message)
+	return qReq
+}
+
+// paymentRequestQr renders an address or URI as a QR code , returned as an SVG data URI usable as image source
+func (walletM *WalletManager) paymentRequestQr(payload string) string {
+	code, err := qrutil.Encode([]byte(payload), qrutil.LevelM)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't encode QR code")
+		return ""
+	}
+	svg, err := code.SVG(paymentQrScale)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't render QR code")
+		return ""
+	}
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg))
+}
+
+// skyAccuracy decimal places of SKY amounts
+func skyAccuracy() int32 {
+	meta, _ := local.LoadAltcoinManager().DescribeAltcoin(params.SkycoinTicker)
+	return meta.Accuracy
+}
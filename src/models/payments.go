@@ -0,0 +1,153 @@
+package models
+
+import (
+	"time"
+
+	sky "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/data"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
+	fcParams "github.com/fibercrypto/fibercryptowallet/src/params"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	qtCore "github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/qml"
+)
+
+// paymentsSyncInterval time between checks of watched addresses
+const paymentsSyncInterval = time.Minute
+
+// QPaymentWatch payment expected to a wallet address
+type QPaymentWatch struct {
+	qtCore.QObject
+
+	_ string `property:"watchId"`
+	_ string `property:"walletId"`
+	_ string `property:"address"`
+	_ string `property:"amount"`
+	_ string `property:"minHours"`
+	_ string `property:"expiry"`
+	_ int    `property:"confirmations"`
+	_ string `property:"createdAt"`
+	_ int    `property:"state"`
+	_ string `property:"received"`
+	_ string `property:"receivedHours"`
+	_ string `property:"pending"`
+}
+
+// openPaymentWatches returns expected payments kept in the application database , nil if not available
+func openPaymentWatches() core.PaymentWatchStore {
+	payments, isPaymentWatches := addressBook.GetStorage().(core.PaymentWatchStore)
+	if !isPaymentWatches {
+		logWalletManager.Error("Couldn't open payments database")
+		return nil
+	}
+	return payments
+}
+
+func newQPaymentWatch(watch *core.PaymentWatch) *QPaymentWatch {
+	qWatch := NewQPaymentWatch(nil)
+	qml.QQmlEngine_SetObjectOwnership(qWatch, qml.QQmlEngine__CppOwnership)
+	qWatch.SetWatchId(watch.ID)
+	qWatch.SetWalletId(watch.WalletID)
+	qWatch.SetAddress(watch.Address)
+	qWatch.SetAmount(formatPlanCoins(watch.Amount, params.SkycoinTicker))
+	qWatch.SetMinHours(formatPlanCoins(watch.MinHours, params.CoinHoursTicker))
+	if !watch.Expiry.IsZero() {
+		qWatch.SetExpiry(watch.Expiry.Format(time.RFC3339))
+	}
+	qWatch.SetConfirmations(int(watch.Confirmations))
+	qWatch.SetCreatedAt(watch.CreatedAt.Format(time.RFC3339))
+	qWatch.SetState(int(watch.State))
+	qWatch.SetReceived(formatPlanCoins(watch.Received, params.SkycoinTicker))
+	qWatch.SetReceivedHours(formatPlanCoins(watch.ReceivedHours, params.CoinHoursTicker))
+	qWatch.SetPending(formatPlanCoins(watch.Pending, params.SkycoinTicker))
+	return qWatch
+}
+
+// newInvoice watches a payment to the next unused address of a wallet.
+// Expiry is given in seconds from now , zero for payments never expiring.
+func (walletM *WalletManager) newInvoice(wltId, amount, minHours string, expiresIn, confirmations int, password string) *QPaymentWatch {
+	logWalletManager.Info("Creating invoice")
+	if walletM.payments == nil {
+		return nil
+	}
+	wlt := walletM.WalletEnv.GetWalletSet().GetWallet(wltId)
+	if wlt == nil {
+		logWalletManager.WithField("id", wltId).Warn("Couldn't load wallet")
+		return nil
+	}
+	pwd := util.ConstantPassword(password)
+	// NOTE: No easy way to get plain passwords in memory
+	password = ""
+	watch := core.PaymentWatch{Confirmations: uint64(confirmations)}
+	var err error
+	if watch.Amount, err = util.GetCoinValue(amount, params.SkycoinTicker); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't parse invoice amount")
+		return nil
+	}
+	if minHours != "" {
+		if watch.MinHours, err = util.GetCoinValue(minHours, params.CoinHoursTicker); err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't parse invoice coin hours")
+			return nil
+		}
+	}
+	now := time.Now()
+	if expiresIn > 0 {
+		watch.Expiry = now.Add(time.Duration(expiresIn) * time.Second)
+	}
+	created, err := data.WatchPayment(walletM.payments, wlt, watch, pwd, now)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't watch payment")
+		return nil
+	}
+	go walletM.updateAddresses(wltId)
+	return newQPaymentWatch(created)
+}
+
+func (walletM *WalletManager) getPaymentWatches() []*QPaymentWatch {
+	qWatches := make([]*QPaymentWatch, 0)
+	if walletM.payments == nil {
+		return qWatches
+	}
+	watches, err := walletM.payments.ListPaymentWatches()
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't list payment watches")
+		return qWatches
+	}
+	for i := range watches {
+		qWatches = append(qWatches, newQPaymentWatch(&watches[i]))
+	}
+	return qWatches
+}
+
+func (walletM *WalletManager) cancelPaymentWatch(watchId string) bool {
+	if walletM.payments == nil {
+		return false
+	}
+	if err := walletM.payments.DeletePaymentWatch(watchId); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't remove payment watch")
+		return false
+	}
+	return true
+}
+
+// watchPayments periodically checks funds received by watched addresses , emitting paymentEvent on state changes
+func (walletM *WalletManager) watchPayments() {
+	if walletM.payments == nil {
+		return
+	}
+	bc := sky.NewSkycoinBlockchain(fcParams.DataRefreshTimeout * uint64(time.Second))
+	ticker := time.NewTicker(paymentsSyncInterval)
+	for range ticker.C {
+		events, err := data.SyncPaymentWatches(walletM.payments, walletM.WalletEnv.GetWalletSet(), bc, time.Now())
+		if err != nil {
+			logWalletManager.WithError(err).Warn("Couldn't synchronize payment watches")
+			continue
+		}
+		for _, event := range events {
+			logWalletManager.WithField("id", event.Watch.ID).Info("Payment state changed")
+			walletM.PaymentEvent(event.Watch.ID, int(event.Watch.State))
+		}
+	}
+}
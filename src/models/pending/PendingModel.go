@@ -1,9 +1,12 @@
 package pending
 
 import (
+	"time"
+
 	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models" //callable as skycoin
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/params"
 	"github.com/fibercrypto/fibercryptowallet/src/util"
 	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
 	qtCore "github.com/therecipe/qt/core"
@@ -13,8 +16,9 @@ var logPendingTxn = logging.MustGetLogger("modelsPendingTransaction")
 
 type PendingTransactionList struct {
 	qtCore.QObject
-	PEX       core.PEX
-	WalletEnv core.WalletEnv
+	PEX        core.PEX
+	WalletEnv  core.WalletEnv
+	Blockchain core.BlockchainStatus
 
 	_ func() `constructor:"init"`
 
@@ -34,6 +38,7 @@ type PendingTransaction struct {
 	_ *qtCore.QDateTime `property:"timeStamp"`
 	_ string            `property:"transactionID"`
 	_ int               `property:"mine"`
+	_ int               `property:"confirmations"`
 }
 
 func (model *PendingTransactionList) init() {
@@ -50,6 +55,7 @@ func (model *PendingTransactionList) init() {
 	}
 	model.PEX = &skycoin.SkycoinPEX{}
 	model.WalletEnv = walletsEnvs[0]
+	model.Blockchain = skycoin.NewSkycoinBlockchain(params.DataRefreshTimeout * uint64(time.Second))
 
 }
 
@@ -80,6 +86,7 @@ func (model *PendingTransactionList) getAll() {
 		t := txns.Value()
 		ptModel := TransactionToPendingTransaction(t)
 		ptModel.SetMine(0)
+		ptModel.SetConfirmations(int(model.confirmations(t)))
 		ptModels = append(ptModels, ptModel)
 	}
 	model.SetLoading(false)
@@ -110,6 +117,7 @@ func (model *PendingTransactionList) getMine() {
 			if txn.GetStatus() == core.TXN_STATUS_PENDING {
 				ptModel := TransactionToPendingTransaction(txn)
 				ptModel.SetMine(1)
+				ptModel.SetConfirmations(int(model.confirmations(txn)))
 				ptModels = append(ptModels, ptModel)
 			}
 		}
@@ -118,6 +126,16 @@ func (model *PendingTransactionList) getMine() {
 	model.SetTransactions(ptModels)
 }
 
+// confirmations returns how many blocks bury a transaction , zero while it stays in the pool
+func (model *PendingTransactionList) confirmations(txn core.Transaction) uint64 {
+	headSeq, err := model.Blockchain.GetNumberOfBlocks()
+	if err != nil {
+		logPendingTxn.WithError(err).Warn("Couldn't get number of blocks")
+		return 0
+	}
+	return util.TxnConfirmations(txn, headSeq)
+}
+
 func TransactionToPendingTransaction(stxn core.Transaction) *PendingTransaction {
 	pt := NewPendingTransaction(nil)
 	year, month, day, h, m, s := util.ParseDate(int64(stxn.GetTimestamp()))
@@ -4,6 +4,7 @@ import (
 	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	"github.com/fibercrypto/fibercryptowallet/src/models/address"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
 	"github.com/fibercrypto/fibercryptowallet/src/util"
 	qtcore "github.com/therecipe/qt/core"
 )
@@ -41,6 +42,8 @@ func NewQTransactionFromTransaction(txn core.Transaction) (*QTransaction, error)
 	fee := util.FormatCoins(ch, quotient)
 	qtxn.SetHoursBurned(fee)
 
+	// Known outputs are shown with the name of the contact owning them
+	contacts := addressBook.ContactNames(params.SkycoinTicker)
 	//Creating inputs
 	ins := txn.GetInputs()
 	for _, in := range ins {
@@ -56,6 +59,7 @@ func NewQTransactionFromTransaction(txn core.Transaction) (*QTransaction, error)
 		addr := outAddr.String()
 		inputsAddresses[addr] = struct{}{}
 		qIn.SetAddress(addr)
+		qIn.SetContact(contacts[addr])
 		quotient, err := util.AltcoinQuotient(params.SkycoinTicker)
 		if err != nil {
 			return nil, err
@@ -88,6 +92,7 @@ func NewQTransactionFromTransaction(txn core.Transaction) (*QTransaction, error)
 		}
 		addr := outAddr.String()
 		qOu.SetAddress(addr)
+		qOu.SetContact(contacts[addr])
 		quotient, err := util.AltcoinQuotient(params.SkycoinTicker)
 		sky, err := out.GetCoins(params.SkycoinTicker)
 		if err != nil {
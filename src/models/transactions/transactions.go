@@ -20,6 +20,8 @@ const (
 	Addresses
 	Inputs
 	Outputs
+	Label
+	Confirmations
 )
 
 const (
@@ -46,4 +48,6 @@ type TransactionDetails struct {
 	_ *address.AddressList `property:"addresses"`
 	_ *address.AddressList `property:"inputs"`
 	_ *address.AddressList `property:"outputs"`
+	_ string               `property:"label"`
+	_ int                  `property:"confirmations"`
 }
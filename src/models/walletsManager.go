@@ -17,6 +17,8 @@ import (
 	sky "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/models/addressBook"
+	"github.com/fibercrypto/fibercryptowallet/src/util/bip39util"
 	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
 	qtCore "github.com/therecipe/qt/core"
 )
@@ -37,14 +39,16 @@ type utilByWallet struct {
 }
 
 type updateWalletInfo struct {
-	isNew  bool
-	row    int
-	wallet *QWallet
+	isNew     bool
+	isRemoved bool
+	row       int
+	wallet    *QWallet
 }
 type WalletManager struct {
 	qtCore.QObject
 	WalletEnv                 core.WalletEnv
 	SeedGenerator             core.SeedGenerator
+	SeedSplitter              core.SeedSplitter
 	wallets                   []*QWallet
 	addresseseByWallets       map[string](map[string]*QAddress)
 	orderedAddressesByWallets map[string][]*QAddress
@@ -58,6 +62,9 @@ type WalletManager struct {
 	walletsIterator           core.WalletIterator
 	updaterChannel            chan *updateWalletInfo
 	timerUpdate               chan time.Duration
+	coinControl               core.CoinControl
+	outbox                    core.Outbox
+	payments                  core.PaymentWatchStore
 
 	_ func()                                                                                                                           `slot:"updateWalletEnvs"`
 	_ func(wltId, address string)                                                                                                      `slot:"updateOutputs"`
@@ -65,13 +72,28 @@ type WalletManager struct {
 	_ func()                                                                                                                           `slot:"updateWallets"`
 	_ func()                                                                                                                           `slot:"updateAll"`
 	_ func()                                                                                                                           `constructor:"init"`
-	_ func(seed string, label string, walletType string, password string, scanN int) *QWallet                                          `slot:"createEncryptedWallet"`
-	_ func(seed string, label string, walletType string, scanN int) *QWallet                                                           `slot:"createUnencryptedWallet"`
-	_ func(entropy int) string                                                                                                         `slot:"getNewSeed"`
+	_ func(seed, seedPassphrase, label, walletType, password string, scanN int) *QWallet                                               `slot:"createEncryptedWallet"`
+	_ func(seed, seedPassphrase, label, walletType string, scanN int) *QWallet                                                         `slot:"createUnencryptedWallet"`
+	_ func(id, confirmation, password string) bool                                                                                     `slot:"deleteWallet"`
+	_ func(id string) string                                                                                                           `slot:"archiveWallet"`
+	_ func() []*QWallet                                                                                                                `slot:"getArchivedWallets"`
+	_ func(archiveId string) *QWallet                                                                                                  `slot:"restoreWallet"`
+	_ func(entropy int, language string) string                                                                                        `slot:"getNewSeed"`
 	_ func(seed string) int                                                                                                            `slot:"verifySeed"`
+	_ func() []string                                                                                                                  `slot:"getSeedLanguages"`
+	_ func(seed string) string                                                                                                         `slot:"getSeedLanguage"`
+	_ func(prefix, language string) []string                                                                                           `slot:"completeSeedWord"`
+	_ func(word, language string) []string                                                                                             `slot:"suggestSeedWords"`
+	_ func(seed, passphrase string, groupThreshold int, memberThresholds, memberCounts []int) []string                                 `slot:"splitSeed"`
+	_ func(share string) int                                                                                                           `slot:"verifyShare"`
+	_ func(shares []string, passphrase, language string) string                                                                        `slot:"combineShares"`
 	_ func(id string, n int, password string)                                                                                          `slot:"newWalletAddress"`
-	_ func(id string, password string) int                                                                                             `slot:"encryptWallet"`
+	_ func(id, password, cryptoType string) int                                                                                        `slot:"encryptWallet"`
 	_ func(id string, password string) int                                                                                             `slot:"decryptWallet"`
+	_ func(id, oldPassword, newPassword, cryptoType string) bool                                                                       `slot:"changeWalletPassword"`
+	_ func(id, password string) bool                                                                                                   `slot:"upgradeWalletEncryption"`
+	_ func(id string) string                                                                                                           `slot:"getWalletCryptoType"`
+	_ func() []string                                                                                                                  `slot:"getSupportedCryptoTypes"`
 	_ func() []*QWallet                                                                                                                `slot:"getWallets"`
 	_ func(id string) []*QAddress                                                                                                      `slot:"getAddresses"`
 	_ func(wltIds, addresses []string, source string, pwd interface{}, index []int, qTxn *QTransaction) *QTransaction                  `slot:"signTxn"`
@@ -88,6 +110,39 @@ type WalletManager struct {
 	_ func() []string                                                                                                                  `slot:"getAvailableWalletTypes"`
 	_ func(address string, value int)                                                                                                  `slot:"editMarkAddress"`
 	_ func(address string) int                                                                                                         `slot:"markFieldOfAddress"`
+	_ func(wltId, ticker string) *QBalance                                                                                             `slot:"getBalanceBreakdown"`
+	_ func(wltId, outputId string) bool                                                                                                `slot:"freezeOutput"`
+	_ func(wltId, outputId string) bool                                                                                                `slot:"thawOutput"`
+	_ func(wltId string, outputIds []string) bool                                                                                      `slot:"releaseOutputs"`
+	_ func(wltId, outputId string) int                                                                                                 `slot:"outputState"`
+	_ func(outputId, label string) bool                                                                                                `slot:"setOutputLabel"`
+	_ func(wltId, threshold, destination string) *QUtxoPlan                                                                            `slot:"previewConsolidation"`
+	_ func(wltId, outputId string, amounts []string, destination string) *QUtxoPlan                                                    `slot:"previewSplit"`
+	_ func(plan *QUtxoPlan) *QTransaction                                                                                              `slot:"buildUtxoPlan"`
+	_ func() []*QOutboxTxn                                                                                                             `slot:"getOutbox"`
+	_ func(txnId string) bool                                                                                                          `slot:"rebroadcastOutboxTxn"`
+	_ func(txnId string) bool                                                                                                          `slot:"dismissOutboxTxn"`
+	_ func()                                                                                                                           `signal:"outboxChanged"`
+	_ func(txnId string, n int)                                                                                                        `slot:"waitForConfirmations"`
+	_ func(txnId string, confirmations int)                                                                                            `signal:"transactionConfirmed"`
+	_ func(wltId, amount, minHours string, expiresIn, confirmations int, password string) *QPaymentWatch                               `slot:"newInvoice"`
+	_ func() []*QPaymentWatch                                                                                                          `slot:"getPaymentWatches"`
+	_ func(watchId string) bool                                                                                                        `slot:"cancelPaymentWatch"`
+	_ func(watchId string, state int)                                                                                                  `signal:"paymentEvent"`
+	_ func()                                                                                                                           `signal:"labelsNotSaved"`
+	_ func(address, amount, hours, label, message string) string                                                                       `slot:"encodePaymentRequest"`
+	_ func(uri string) *QPaymentRequest                                                                                                `slot:"parsePaymentRequest"`
+	_ func(payload string) string                                                                                                      `slot:"paymentRequestQr"`
+	_ func(wltId, path, burnFactor string) *QBatchPayout                                                                               `slot:"previewBatchPayout"`
+	_ func(batch *QBatchPayout, source, password string) bool                                                                          `slot:"executeBatchPayout"`
+	_ func(batch *QBatchPayout) []*QPayoutResult                                                                                       `slot:"getBatchPayoutResults"`
+	_ func(batch *QBatchPayout, path string) bool                                                                                      `slot:"saveBatchPayoutReport"`
+	_ func(contact string) []string                                                                                                    `slot:"resolveContact"`
+	_ func(wltId, contact, amount string) *QTransaction                                                                                `slot:"sendToContact"`
+	_ func(path, passphrase string) bool                                                                                               `slot:"createBackup"`
+	_ func(path, passphrase string) *QBackupInfo                                                                                       `slot:"checkBackup"`
+	_ func(path, passphrase string, mode int) bool                                                                                     `slot:"restoreBackup"`
+	_ bool                                                                                                                             `property:"overrideFrozen"`
 }
 
 func (walletM *WalletManager) init() {
@@ -98,11 +153,26 @@ func (walletM *WalletManager) init() {
 		walletM.ConnectEditWallet(walletM.editWallet)
 		walletM.ConnectCreateEncryptedWallet(walletM.createEncryptedWallet)
 		walletM.ConnectCreateUnencryptedWallet(walletM.createUnencryptedWallet)
+		walletM.ConnectDeleteWallet(walletM.deleteWallet)
+		walletM.ConnectArchiveWallet(walletM.archiveWallet)
+		walletM.ConnectGetArchivedWallets(walletM.getArchivedWallets)
+		walletM.ConnectRestoreWallet(walletM.restoreWallet)
 		walletM.ConnectGetNewSeed(walletM.getNewSeed)
 		walletM.ConnectVerifySeed(walletM.verifySeed)
+		walletM.ConnectGetSeedLanguages(walletM.getSeedLanguages)
+		walletM.ConnectGetSeedLanguage(walletM.getSeedLanguage)
+		walletM.ConnectCompleteSeedWord(walletM.completeSeedWord)
+		walletM.ConnectSuggestSeedWords(walletM.suggestSeedWords)
+		walletM.ConnectSplitSeed(walletM.splitSeed)
+		walletM.ConnectVerifyShare(walletM.verifyShare)
+		walletM.ConnectCombineShares(walletM.combineShares)
 		walletM.ConnectNewWalletAddress(walletM.newWalletAddress)
 		walletM.ConnectEncryptWallet(walletM.encryptWallet)
 		walletM.ConnectDecryptWallet(walletM.decryptWallet)
+		walletM.ConnectChangeWalletPassword(walletM.changeWalletPassword)
+		walletM.ConnectUpgradeWalletEncryption(walletM.upgradeWalletEncryption)
+		walletM.ConnectGetWalletCryptoType(walletM.getWalletCryptoType)
+		walletM.ConnectGetSupportedCryptoTypes(walletM.getSupportedCryptoTypes)
 		walletM.ConnectGetWallets(walletM.getWallets)
 		walletM.ConnectGetAddresses(walletM.getAddresses)
 		walletM.ConnectSendTo(walletM.sendTo)
@@ -123,11 +193,43 @@ func (walletM *WalletManager) init() {
 		walletM.ConnectGetAvailableWalletTypes(walletM.getAvailableWalletTypes)
 		walletM.ConnectEditMarkAddress(walletM.editMarkAddress)
 		walletM.ConnectMarkFieldOfAddress(walletM.markFieldOfAddress)
+		walletM.ConnectGetBalanceBreakdown(walletM.getBalanceBreakdown)
+		walletM.ConnectFreezeOutput(walletM.freezeOutput)
+		walletM.ConnectThawOutput(walletM.thawOutput)
+		walletM.ConnectReleaseOutputs(walletM.releaseOutputs)
+		walletM.ConnectOutputState(walletM.outputState)
+		walletM.ConnectSetOutputLabel(walletM.setOutputLabel)
+		walletM.ConnectPreviewConsolidation(walletM.previewConsolidation)
+		walletM.ConnectPreviewSplit(walletM.previewSplit)
+		walletM.ConnectBuildUtxoPlan(walletM.buildUtxoPlan)
+		walletM.ConnectGetOutbox(walletM.getOutbox)
+		walletM.ConnectRebroadcastOutboxTxn(walletM.rebroadcastOutboxTxn)
+		walletM.ConnectDismissOutboxTxn(walletM.dismissOutboxTxn)
+		walletM.ConnectWaitForConfirmations(walletM.waitForConfirmations)
+		walletM.ConnectNewInvoice(walletM.newInvoice)
+		walletM.ConnectGetPaymentWatches(walletM.getPaymentWatches)
+		walletM.ConnectCancelPaymentWatch(walletM.cancelPaymentWatch)
+		walletM.ConnectEncodePaymentRequest(walletM.encodePaymentRequest)
+		walletM.ConnectParsePaymentRequest(walletM.parsePaymentRequest)
+		walletM.ConnectPaymentRequestQr(walletM.paymentRequestQr)
+		walletM.ConnectPreviewBatchPayout(walletM.previewBatchPayout)
+		walletM.ConnectExecuteBatchPayout(walletM.executeBatchPayout)
+		walletM.ConnectGetBatchPayoutResults(walletM.getBatchPayoutResults)
+		walletM.ConnectSaveBatchPayoutReport(walletM.saveBatchPayoutReport)
+		walletM.ConnectResolveContact(walletM.resolveContact)
+		walletM.ConnectSendToContact(walletM.sendToContact)
+		walletM.ConnectCreateBackup(walletM.createBackup)
+		walletM.ConnectCheckBackup(walletM.checkBackup)
+		walletM.ConnectRestoreBackup(walletM.restoreBackup)
+		walletM.coinControl = openCoinControl()
+		walletM.outbox = openOutbox()
+		walletM.payments = openPaymentWatches()
 		walletM.addresseseByWallets = make(map[string](map[string]*QAddress), 0)
 		walletM.orderedAddressesByWallets = make(map[string][]*QAddress, 0)
 		walletM.utilByWallets = make(map[string]*utilByWallet, 0)
 		walletM.outputsByAddress = make(map[string][]*QOutput, 0)
 		walletM.SeedGenerator = new(sky.SeedService)
+		walletM.SeedSplitter = new(sky.SeedService)
 		walletManager = walletM
 		walletM.markedAddress = make(map[string]int)
 
@@ -170,6 +272,8 @@ func (walletM *WalletManager) init() {
 	}
 	logWalletManager.Debug("Finish wallets")
 	walletM.wallets = qWallets
+	go walletM.trackOutbox()
+	go walletM.watchPayments()
 	go func() {
 		updateTime := config.GetDataUpdateTime()
 		logWalletManager.Debug("Update time is :=> ", time.Duration(updateTime)*time.Second)
@@ -206,12 +310,38 @@ func (walletM *WalletManager) suscribeForAddresses(wltId string) chan *updateAdd
 
 func (walletManager *WalletManager) editMarkAddress(address string, value int) {
 	walletManager.markedAddress[address] = value
+	store := addressBook.GetLabelStore()
+	if store == nil {
+		logWalletManager.Warn("Labels are not available")
+		walletManager.LabelsNotSaved()
+		return
+	}
+	label, err := store.GetLabel(core.LabelTypeAddress, address)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't load address label")
+		walletManager.LabelsNotSaved()
+		return
+	}
+	if label == nil {
+		label = &core.Label{Type: core.LabelTypeAddress, Ref: address}
+	}
+	label.Mark = value
+	if err := store.SetLabel(*label); err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't save address mark")
+		walletManager.LabelsNotSaved()
+	}
 }
 
 func (walletM *WalletManager) markFieldOfAddress(address string) int {
 	val, ok := walletM.markedAddress[address]
 	if !ok {
 		val = 0
+		if store := addressBook.GetLabelStore(); store != nil {
+			if label, err := store.GetLabel(core.LabelTypeAddress, address); err == nil && label != nil {
+				val = label.Mark
+			}
+		}
+		walletM.markedAddress[address] = val
 	}
 	return val
 }
@@ -451,6 +581,8 @@ func (walletM *WalletManager) updateOutputs(wltId, address string) {
 		qout := NewQOutput(nil)
 		qml.QQmlEngine_SetObjectOwnership(qout, qml.QQmlEngine__CppOwnership)
 		qout.SetOutputID(outsIter.Value().GetId())
+		qout.SetLabel(labelOf(core.LabelTypeOutput, outsIter.Value().GetId()))
+		qout.SetState(walletM.outputState(wltId, outsIter.Value().GetId()))
 		skyV, err := outsIter.Value().GetCoins(sky.Sky)
 		if err != nil {
 			qout.SetAddressSky("N/A")
@@ -580,6 +712,7 @@ func (walletM *WalletManager) broadcastTxn(txn *QTransaction) bool {
 		return false
 	}
 	logWalletManager.Info("Transaction Injected")
+	walletM.recordBroadcastTxn(txn.txn)
 	return true
 }
 
@@ -636,6 +769,7 @@ func (walletM *WalletManager) sendFromOutputs(wltIds []string, from, addrTo, sky
 	} else {
 		opt.SetValue("CoinHoursSelectionType", "manual")
 	}
+	walletM.setCoinControlOptions(opt)
 	var txn core.Transaction
 	var err error
 	if len(wltCache) > 1 {
@@ -716,6 +850,7 @@ func (walletM *WalletManager) sendFromAddresses(wltIds []string, from, addrTo, s
 	} else {
 		opt.SetValue("CoinHoursSelectionType", "manual")
 	}
+	walletM.setCoinControlOptions(opt)
 	var txn core.Transaction
 	var err error
 	if len(wltCache) > 1 {
@@ -776,6 +911,7 @@ func (walletM *WalletManager) sendTo(wltId, destinationAddress, amount string) *
 	opt := util.NewKeyValueMap()
 	opt.SetValue("BurnFactor", "0.5")
 	opt.SetValue("CoinHoursSelectionType", "auto")
+	walletM.setCoinControlOptions(opt)
 	if wlt == nil {
 		logWalletManager.Warn("Couldn't load wallet to create transaction")
 		return nil
@@ -834,6 +970,16 @@ func (walletM *WalletManager) signTxn(wltIds, address []string, source string, t
 	var txn core.Transaction
 	var err error
 
+	// Keep inputs out of coin selection while signing , released if signing fails
+	reserved := walletM.reserveInputs(qTxn.txn, wltByAddr)
+	isSigned := false
+	defer func() {
+		if !isSigned {
+			for wltId, outputIds := range reserved {
+				walletM.releaseOutputs(wltId, outputIds)
+			}
+		}
+	}()
 	if len(wltCache) > 1 {
 		signDescriptors := make([]core.InputSignDescriptor, 0)
 		for _, in := range qTxn.txn.GetInputs() {
@@ -884,6 +1030,7 @@ func (walletM *WalletManager) signTxn(wltIds, address []string, source string, t
 		return nil
 	}
 
+	isSigned = true
 	return qTxn
 
 }
@@ -923,12 +1070,12 @@ func (walletM *WalletManager) signAndBroadcastTxnAsync(wltIds, addresses []strin
 	}()
 }
 
-func (walletM *WalletManager) createEncryptedWallet(seed, label, wltType, password string, scanN int) *QWallet {
+func (walletM *WalletManager) createEncryptedWallet(seed, seedPassphrase, label, wltType, password string, scanN int) *QWallet {
 	logWalletManager.Info("Creating encrypted wallet")
 	pwd := util.ConstantPassword(password)
 	// NOTE: No easy way to get plain passwords in memory
 	password = ""
-	wlt, err := walletM.WalletEnv.GetWalletSet().CreateWallet(label, seed, wltType, true, pwd, scanN)
+	wlt, err := walletM.WalletEnv.GetWalletSet().CreateWallet(label, bip39util.NormalizeMnemonic(seed), seedPassphrase, wltType, true, pwd, scanN)
 	if err != nil {
 		logWalletManager.WithError(err).Error("Couldn't create encrypted wallet")
 		return nil
@@ -950,10 +1097,10 @@ func (walletM *WalletManager) createEncryptedWallet(seed, label, wltType, passwo
 	return qWallet
 }
 
-func (walletM *WalletManager) createUnencryptedWallet(seed, label, wltType string, scanN int) *QWallet {
+func (walletM *WalletManager) createUnencryptedWallet(seed, seedPassphrase, label, wltType string, scanN int) *QWallet {
 	logWalletManager.Info("Creating encrypted wallet")
 	pwd := util.EmptyPassword
-	wlt, err := walletM.WalletEnv.GetWalletSet().CreateWallet(label, seed, wltType, false, pwd, scanN)
+	wlt, err := walletM.WalletEnv.GetWalletSet().CreateWallet(label, bip39util.NormalizeMnemonic(seed), seedPassphrase, wltType, false, pwd, scanN)
 	if err != nil {
 		logWalletManager.WithError(err).Error("Couldn't create unencrypted wallet")
 		return nil
@@ -976,9 +1123,94 @@ func (walletM *WalletManager) createUnencryptedWallet(seed, label, wltType strin
 
 }
 
-func (walletM *WalletManager) getNewSeed(entropy int) string {
+// deleteWallet removes a wallet for good , confirmation must match wallet ID
+func (walletM *WalletManager) deleteWallet(id, confirmation, password string) bool {
+	logWalletManager.Info("Deleting wallet")
+	pwd := util.ConstantPassword(password)
+	// NOTE: No easy way to get plain passwords in memory
+	password = ""
+	if err := walletM.WalletEnv.GetWalletSet().DeleteWallet(id, confirmation, pwd); err != nil {
+		logWalletManager.WithError(err).Error("Couldn't delete wallet")
+		return false
+	}
+	walletM.removeQWallet(id)
+	logWalletManager.Info("Wallet deleted")
+	return true
+}
+
+// archiveWallet hides a wallet , returning the archive ID to restore it or empty on error
+func (walletM *WalletManager) archiveWallet(id string) string {
+	logWalletManager.Info("Archiving wallet")
+	archiveId, err := walletM.WalletEnv.GetWalletSet().ArchiveWallet(id)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't archive wallet")
+		return ""
+	}
+	walletM.removeQWallet(id)
+	logWalletManager.Info("Wallet archived")
+	return archiveId
+}
+
+func (walletM *WalletManager) getArchivedWallets() []*QWallet {
+	logWalletManager.Info("Getting archived wallets")
+	qWallets := make([]*QWallet, 0)
+	it := walletM.WalletEnv.GetWalletSet().ListArchivedWallets()
+	if it == nil {
+		logWalletManager.Warn("Couldn't get an archived wallet iterator")
+		return qWallets
+	}
+	for it.Next() {
+		// Archived wallets are out of storage reach , thus shown as unencrypted and without balance
+		qWallets = append(qWallets, fromWalletToQWallet(it.Value(), false, true))
+	}
+	return qWallets
+}
+
+func (walletM *WalletManager) restoreWallet(archiveId string) *QWallet {
+	logWalletManager.Info("Restoring archived wallet")
+	wlt, err := walletM.WalletEnv.GetWalletSet().RestoreWallet(archiveId)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't restore wallet")
+		return nil
+	}
+	encrypted, err := walletM.WalletEnv.GetStorage().IsEncrypted(wlt.GetId())
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't check whether restored wallet is encrypted")
+	}
+	qWallet := fromWalletToQWallet(wlt, encrypted, false)
+	walletM.wallets = append(walletM.wallets, qWallet)
+	wi := &updateWalletInfo{
+		isNew:  true,
+		row:    len(walletM.wallets) - 1,
+		wallet: qWallet,
+	}
+	walletM.utilByWallets[wlt.GetId()] = &utilByWallet{
+		m:           sync.Mutex{},
+		sendChannel: make(chan *updateAddressInfo),
+	}
+	walletM.updaterChannel <- wi
+	return qWallet
+}
+
+// removeQWallet announces models that wallet id is no longer in the set
+func (walletM *WalletManager) removeQWallet(id string) {
+	for row, qw := range walletM.wallets {
+		if qw.FileName() == id {
+			walletM.wallets = append(walletM.wallets[:row], walletM.wallets[row+1:]...)
+			delete(walletM.utilByWallets, id)
+			walletM.updaterChannel <- &updateWalletInfo{
+				isRemoved: true,
+				row:       row,
+				wallet:    qw,
+			}
+			return
+		}
+	}
+}
+
+func (walletM *WalletManager) getNewSeed(entropy int, language string) string {
 	logWalletManager.Info("Getting new seed")
-	seed, err := walletM.SeedGenerator.GenerateMnemonic(entropy)
+	seed, err := walletM.SeedGenerator.GenerateMnemonic(entropy, language)
 	if err != nil {
 		logWalletManager.WithError(err).Error("Couldn't get new seed")
 		return ""
@@ -1002,12 +1234,94 @@ func (walletM *WalletManager) verifySeed(seed string) int {
 
 }
 
-func (walletM *WalletManager) encryptWallet(id, password string) int {
+func (walletM *WalletManager) getSeedLanguages() []string {
+	return walletM.SeedGenerator.SupportedLanguages()
+}
+
+// getSeedLanguage returns the language of the words of seed , empty if unknown
+func (walletM *WalletManager) getSeedLanguage(seed string) string {
+	language, err := walletM.SeedGenerator.MnemonicLanguage(seed)
+	if err != nil {
+		logWalletManager.WithError(err).Warn("Couldn't detect seed language")
+		return ""
+	}
+	return language
+}
+
+func (walletM *WalletManager) completeSeedWord(prefix, language string) []string {
+	words, err := walletM.SeedGenerator.CompleteWord(prefix, language)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't complete seed word")
+		return nil
+	}
+	return words
+}
+
+func (walletM *WalletManager) suggestSeedWords(word, language string) []string {
+	words, err := walletM.SeedGenerator.SuggestWords(word, language)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't suggest seed words")
+		return nil
+	}
+	return words
+}
+
+// splitSeed returns the share mnemonics of seed ordered by group , memberCounts[i] of them for the i-th group
+func (walletM *WalletManager) splitSeed(seed, passphrase string, groupThreshold int, memberThresholds, memberCounts []int) []string {
+	logWalletManager.Info("Splitting seed in shares")
+	if len(memberThresholds) != len(memberCounts) {
+		logWalletManager.Error("Couldn't split seed , group parameters do not match")
+		return nil
+	}
+	groups := make([]core.ShareGroup, len(memberCounts))
+	for i := range groups {
+		groups[i] = core.ShareGroup{MemberThreshold: memberThresholds[i], MemberCount: memberCounts[i]}
+	}
+	groupShares, err := walletM.SeedSplitter.SplitMnemonic(seed, passphrase, groupThreshold, groups)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't split seed")
+		return nil
+	}
+	shares := make([]string, 0)
+	for _, group := range groupShares {
+		shares = append(shares, group...)
+	}
+	return shares
+}
+
+func (walletM *WalletManager) verifyShare(share string) int {
+	logWalletManager.Info("Verifying share")
+	ok, err := walletM.SeedSplitter.VerifyShare(share)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't verify share")
+		return 0
+	}
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// combineShares recovers the seed to be passed to createEncryptedWallet or createUnencryptedWallet
+func (walletM *WalletManager) combineShares(shares []string, passphrase, language string) string {
+	logWalletManager.Info("Recovering seed from shares")
+	seed, err := walletM.SeedSplitter.CombineShares(shares, passphrase, language)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't recover seed from shares")
+		return ""
+	}
+	return seed
+}
+
+// encryptWallet encrypts a wallet using cryptoType , empty for the default one
+func (walletM *WalletManager) encryptWallet(id, password, cryptoType string) int {
 	logWalletManager.Info("Encrypting wallet")
 	pwd := util.ConstantPassword(password)
 	// NOTE: No easy way to get plain passwords in memory
 	password = ""
-	walletM.WalletEnv.GetStorage().Encrypt(id, pwd)
+	if err := walletM.WalletEnv.GetStorage().Encrypt(id, cryptoType, pwd); err != nil {
+		logWalletManager.WithError(err).Error("Couldn't encrypt wallet")
+	}
 	ret, err := walletM.WalletEnv.GetStorage().IsEncrypted(id)
 	if err != nil {
 		logWalletManager.WithError(err).Error("Couldn't create encrypted wallets")
@@ -1024,7 +1338,9 @@ func (walletM *WalletManager) decryptWallet(id, password string) int {
 	pwd := util.ConstantPassword(password)
 	// NOTE: No easy way to get plain passwords in memory
 	password = ""
-	walletM.WalletEnv.GetStorage().Decrypt(id, pwd)
+	if err := walletM.WalletEnv.GetStorage().Decrypt(id, pwd); err != nil {
+		logWalletManager.WithError(err).Error("Couldn't decrypt wallet")
+	}
 	ret, err := walletM.WalletEnv.GetStorage().IsEncrypted(id)
 	if err != nil {
 		logWalletManager.WithError(err).Error("Couldn't decrypt wallet")
@@ -1036,6 +1352,47 @@ func (walletM *WalletManager) decryptWallet(id, password string) int {
 	return 0
 }
 
+// changeWalletPassword re-encrypts a wallet , an empty cryptoType keeps the current one
+func (walletM *WalletManager) changeWalletPassword(id, oldPassword, newPassword, cryptoType string) bool {
+	logWalletManager.Info("Changing wallet password")
+	oldPwd := util.ConstantPassword(oldPassword)
+	newPwd := util.ConstantPassword(newPassword)
+	// NOTE: No easy way to get plain passwords in memory
+	oldPassword, newPassword = "", ""
+	if err := walletM.WalletEnv.GetStorage().ChangePassword(id, cryptoType, oldPwd, newPwd); err != nil {
+		logWalletManager.WithError(err).Error("Couldn't change wallet password")
+		return false
+	}
+	logWalletManager.Info("Wallet password changed")
+	return true
+}
+
+// upgradeWalletEncryption re-encrypts a wallet using a weak crypto type with the default one
+func (walletM *WalletManager) upgradeWalletEncryption(id, password string) bool {
+	logWalletManager.Info("Upgrading wallet encryption")
+	pwd := util.ConstantPassword(password)
+	// NOTE: No easy way to get plain passwords in memory
+	password = ""
+	if err := walletM.WalletEnv.GetStorage().UpgradeEncryption(id, pwd); err != nil {
+		logWalletManager.WithError(err).Error("Couldn't upgrade wallet encryption")
+		return false
+	}
+	return true
+}
+
+func (walletM *WalletManager) getWalletCryptoType(id string) string {
+	cryptoType, err := walletM.WalletEnv.GetStorage().CryptoType(id)
+	if err != nil {
+		logWalletManager.WithError(err).Error("Couldn't get wallet crypto type")
+		return ""
+	}
+	return cryptoType
+}
+
+func (walletM *WalletManager) getSupportedCryptoTypes() []string {
+	return walletM.WalletEnv.GetStorage().SupportedCryptoTypes()
+}
+
 func (walletM *WalletManager) newWalletAddress(id string, n int, password string) {
 	logWalletManager.Info("Creating new wallet addresses")
 	wlt := walletM.WalletEnv.GetWalletSet().GetWallet(id)
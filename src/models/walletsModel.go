@@ -9,6 +9,7 @@ import (
 	skyWallet "github.com/fibercrypto/skywallet-go/src/skywallet"
 	"github.com/therecipe/qt/core"
 	"github.com/therecipe/qt/qml"
+	"sync"
 	"time"
 )
 
@@ -27,6 +28,25 @@ var dev skyWallet.Devicer
 var hadHwConnected = false
 var hwConnectedOn []int
 
+// hwSeedPassphrase seed passphrase of the hardware wallet , empty if not protected with one
+var hwSeedPassphrase string
+var hwSeedPassphraseMutex sync.Mutex
+
+// takeHwSeedPassphrase returns the seed passphrase of the hardware wallet and forgets it ,
+// it is only kept until the device asks for it
+func takeHwSeedPassphrase() string {
+	hwSeedPassphraseMutex.Lock()
+	defer hwSeedPassphraseMutex.Unlock()
+	passphrase := hwSeedPassphrase
+	hwSeedPassphrase = ""
+	return passphrase
+}
+
+// hwPassphraseReader reads the seed passphrase of the hardware wallet
+func hwPassphraseReader(string, fccore.KeyValueStore) (string, error) {
+	return takeHwSeedPassphrase(), nil
+}
+
 type WalletModel struct {
 	core.QAbstractListModel
 
@@ -41,6 +61,7 @@ type WalletModel struct {
 	_             func([]*QWallet)                                                                 `slot:"loadModel"`
 	_             func([]*QWallet)                                                                 `slot:"updateModel"`
 	_             func()                                                                           `slot:"sniffHw"`
+	_             func(passphrase string)                                                          `slot:"setHwSeedPassphrase"`
 	_             func(string)                                                                     `slot:"changeExpanded"`
 	_             int                                                                              `property:"count"`
 	receivChannel chan *updateWalletInfo
@@ -60,9 +81,8 @@ type QWallet struct {
 
 func (walletModel *WalletModel) init() {
 	logWalletsModel.Info("Initialize Wallet model")
-	dev = proxy.NewSequencer(skyWallet.NewDevice(skyWallet.DeviceTypeUSB), true, func() string {
-		return "not implemented"
-	})
+	// Device input other than the seed passphrase is not implemented
+	dev = proxy.NewSequencer(skyWallet.NewDevice(skyWallet.DeviceTypeUSB), true, takeHwSeedPassphrase)
 	walletModel.SetRoles(map[int]*core.QByteArray{
 		Name:              core.NewQByteArray2("name", -1),
 		EncryptionEnabled: core.NewQByteArray2("encryptionEnabled", -1),
@@ -85,6 +105,7 @@ func (walletModel *WalletModel) init() {
 	walletModel.ConnectLoadModel(walletModel.loadModel)
 	walletModel.ConnectUpdateModel(walletModel.updateModel)
 	walletModel.ConnectChangeExpanded(walletModel.changeExpanded)
+	walletModel.ConnectSetHwSeedPassphrase(walletModel.setHwSeedPassphrase)
 	walletModel.receivChannel = walletManager.suscribe()
 	walletModel.walletByName = make(map[string]*QWallet, 0)
 	go func() {
@@ -92,6 +113,9 @@ func (walletModel *WalletModel) init() {
 			wi := <-walletModel.receivChannel
 			if wi.isNew {
 				//walletModel.addWallet(wi.wallet)
+			} else if wi.isRemoved {
+				delete(walletModel.walletByName, wi.wallet.FileName())
+				walletModel.removeWallet(wi.row)
 			} else {
 				encrypted := false
 				if wi.wallet.EncryptionEnabled() == 1 {
@@ -107,6 +131,7 @@ func (walletModel *WalletModel) init() {
 // attachHwAsSigner add a hw as signer
 func attachHwAsSigner(wlt fccore.Wallet, dev skyWallet.Devicer) error {
 	hw := hardware.NewSkyWallet(wlt, dev)
+	hw.SetPassphraseReader(hwPassphraseReader)
 	am := wlcore.LoadAltcoinManager()
 	if err := am.AttachSignService(hw); err != nil {
 		logSignersModel.Errorln("error registering hardware wallet as signer")
@@ -118,7 +143,7 @@ func attachHwAsSigner(wlt fccore.Wallet, dev skyWallet.Devicer) error {
 // sniffHw notify the model about available hardware wallet device if any
 func (walletModel *WalletModel) sniffHw() {
 	checkForDerivationType := func(dt string) {
-		addr, err := hardware.HwFirstAddr(dev, dt)
+		addr, err := hardware.HwFirstAddr(dev, dt, hwPassphraseReader)
 		if err == nil {
 			wlt, err := walletManager.WalletEnv.LookupWallet(addr)
 			if err != nil {
@@ -154,6 +179,14 @@ func (walletModel *WalletModel) sniffHw() {
 	}()
 }
 
+// setHwSeedPassphrase set the seed passphrase answered to the next passphrase request
+// of the hardware wallet
+func (walletModel *WalletModel) setHwSeedPassphrase(passphrase string) {
+	hwSeedPassphraseMutex.Lock()
+	defer hwSeedPassphraseMutex.Unlock()
+	hwSeedPassphrase = passphrase
+}
+
 func (walletModel *WalletModel) updateWallet(fn string) {
 	index := &core.QModelIndex{}
 	for row := 0; row < walletModel.rowCount(core.NewQModelIndex()); row++ {
@@ -9,3 +9,12 @@ const (
 	ApplicationName    = "FiberCryptoWallet"
 	ApplicationVersion = "0.27.0"
 )
+
+const (
+	// AddrsBookScryptN default scrypt cost of keys protecting the address book
+	AddrsBookScryptN = 1 << 15
+	// AddrsBookScryptR default scrypt block size of keys protecting the address book
+	AddrsBookScryptR = 8
+	// AddrsBookScryptP default scrypt parallelization of keys protecting the address book
+	AddrsBookScryptP = 1
+)
@@ -21,15 +21,16 @@ Item {
     property alias buttonRightText: buttonRight.text
 
     property bool buttonsVisible: buttonRight.visible
+    property string seedLanguage: "english"
 
     readonly property alias inputControlWidth: textArea.width
     readonly property alias inputControlHeight: textArea.height
 
     function generateSeed( words){
         if (words == 12 ){
-            textArea.text = walletManager.getNewSeed(128)
+            textArea.text = walletManager.getNewSeed(128, seedLanguage)
         } else if (words == 24){
-            textArea.text = walletManager.getNewSeed(256)
+            textArea.text = walletManager.getNewSeed(256, seedLanguage)
         }
     }
     function clear() {
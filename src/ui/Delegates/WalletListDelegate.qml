@@ -211,7 +211,7 @@ Item {
         modal: true
 
         onAccepted: {
-            var isEncypted = walletManager.encryptWallet(fileName, password)
+            var isEncypted = walletManager.encryptWallet(fileName, password, "")
             walletModel.editWallet(index, name, isEncypted, sky, coinHours)
         }
     } // DialogSetPassword
@@ -17,6 +17,7 @@ Dialog {
     property alias name: createLoadWallet.name
     property alias seed: createLoadWallet.seed
     property alias encryptionEnabled: checkBoxEncryptWallet.checked
+    property string seedPassphrase: rowLayoutSeedPassphrase.visible ? textFieldSeedPassphrase.text : ""
 
     Component.onCompleted: {
         standardButton(Dialog.Ok).text = mode === CreateLoadWallet.Create ? qsTr("Create") : qsTr("Load")
@@ -32,16 +33,17 @@ Dialog {
                 scanA = 10
             }
             
-            walletModel.addWallet(walletManager.createEncryptedWallet(seed, name, comboBoxWalletType.model[comboBoxWalletType.currentIndex].name, textFieldPassword.text, scanA))
+            walletModel.addWallet(walletManager.createEncryptedWallet(seed, seedPassphrase, name, comboBoxWalletType.model[comboBoxWalletType.currentIndex].name, textFieldPassword.text, scanA))
             
         } else{
             
             if (mode === CreateLoadWallet.Load){
                 scanA = 10
             }
-            walletModel.addWallet(walletManager.createUnencryptedWallet(seed, name, comboBoxWalletType.model[comboBoxWalletType.currentIndex].name, scanA))
+            walletModel.addWallet(walletManager.createUnencryptedWallet(seed, seedPassphrase, name, comboBoxWalletType.model[comboBoxWalletType.currentIndex].name, scanA))
         }
         textFieldPassword.text = ""
+        textFieldSeedPassphrase.text = ""
     }
 
     function updateAcceptButtonStatus() {
@@ -167,6 +169,21 @@ Dialog {
                                     
                 }
             }
+
+            RowLayout {
+                id: rowLayoutSeedPassphrase
+                Layout.fillWidth: true
+                visible: comboBoxWalletType.currentIndex >= 0 && comboBoxWalletType.model[comboBoxWalletType.currentIndex].name === "bip44"
+
+                TextField {
+                    id: textFieldSeedPassphrase
+                    Layout.fillWidth: true
+
+                    placeholderText: qsTr("Seed passphrase (optional)")
+                    echoMode: TextField.Password
+                    selectByMouse: true
+                }
+            }
            
             CheckBox {
                 id: checkBoxEncryptWallet
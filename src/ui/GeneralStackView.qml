@@ -30,6 +30,11 @@ Item {
 
     WalletManager {
         id: walletManager
+
+        onLabelsNotSaved: {
+            msgDialog.text = qsTr("Address marks and labels could not be saved. Set up and unlock the address book to keep them.")
+            msgDialog.open()
+        }
     }
 
     WalletModel {
@@ -116,12 +121,12 @@ Item {
 
                 onWalletCreationRequested: {
                     stackView.replace(componentGeneralSwipeView)
-                    walletManager.createUnencryptedWallet(pageCreateLoadWallet.seed, pageCreateLoadWallet.name, walletManager.getDefaultWalletType() ,0)
+                    walletManager.createUnencryptedWallet(pageCreateLoadWallet.seed, "", pageCreateLoadWallet.name, walletManager.getDefaultWalletType() ,0)
                 }
 
                 onWalletLoadingRequested:{
                     stackView.replace(componentGeneralSwipeView)
-                    walletManager.createUnencryptedWallet(pageCreateLoadWallet.seed, pageCreateLoadWallet.name, walletManager.getDefaultWalletType(), 10)
+                    walletManager.createUnencryptedWallet(pageCreateLoadWallet.seed, "", pageCreateLoadWallet.name, walletManager.getDefaultWalletType(), 10)
                 }
             }
         }
@@ -130,6 +130,10 @@ Page {
                 modelTransactions.addMultipleTransactions(historyManager.getNewTransactionsWithFilters())
             }
         }
+        onLabelsNotSaved: {
+            msgDialog.text = qsTr("The label could not be saved. Set up and unlock the address book to keep labels.")
+            msgDialog.open()
+        }
     }
 
 }
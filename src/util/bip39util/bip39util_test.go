@@ -0,0 +1,221 @@
+package bip39util
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/SkycoinProject/skycoin/src/cipher/bip39"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestNewMnemonicVectors(t *testing.T) {
+	// BIP39 test vectors , see https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+	tests := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+		{
+			entropy:  "80808080808080808080808080808080",
+			mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entropy, func(t *testing.T) {
+			entropy, err := hex.DecodeString(tt.entropy)
+			require.NoError(t, err)
+			mnemonic, err := NewMnemonic(entropy, LanguageEnglish)
+			require.NoError(t, err)
+			require.Equal(t, tt.mnemonic, mnemonic)
+			decoded, err := EntropyFromMnemonic(mnemonic, "")
+			require.NoError(t, err)
+			require.Equal(t, entropy, decoded)
+		})
+	}
+}
+
+func TestNewMnemonicLanguages(t *testing.T) {
+	for _, size := range []int{16, 20, 24, 28, 32} {
+		entropy, err := bip39.NewEntropy(size * 8)
+		require.NoError(t, err)
+
+		// English mnemonics match the Skycoin implementation
+		want, err := bip39.NewMnemonic(entropy)
+		require.NoError(t, err)
+		mnemonic, err := NewMnemonic(entropy, "")
+		require.NoError(t, err)
+		require.Equal(t, want, mnemonic)
+
+		for _, language := range Languages() {
+			mnemonic, err := NewMnemonic(entropy, language)
+			require.NoError(t, err)
+			require.Len(t, strings.Fields(mnemonic), (size*8+size/4)/bitsPerWord)
+			decoded, err := EntropyFromMnemonic(mnemonic, language)
+			require.NoError(t, err)
+			require.Equal(t, entropy, decoded)
+			decoded, err = EntropyFromMnemonic(mnemonic, "")
+			require.NoError(t, err)
+			require.Equal(t, entropy, decoded)
+		}
+	}
+
+	_, err := NewMnemonic(make([]byte, 15), LanguageSpanish)
+	require.Equal(t, bip39.ErrInvalidEntropyLength, err)
+	_, err = NewMnemonic(make([]byte, 16), "klingon")
+	require.Equal(t, errors.ErrUnknownSeedLanguage, err)
+}
+
+func TestJapaneseMnemonic(t *testing.T) {
+	words, err := Wordlist(LanguageJapanese)
+	require.NoError(t, err)
+	mnemonic, err := NewMnemonic(make([]byte, 16), LanguageJapanese)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat(words[0]+"　", 11)+words[3], mnemonic)
+
+	language, err := DetectLanguage(mnemonic)
+	require.NoError(t, err)
+	require.Equal(t, LanguageJapanese, language)
+	// Words with voiced marks validate in composed and decomposed forms
+	require.NoError(t, ValidateMnemonic(norm.NFD.String(mnemonic)))
+	require.NoError(t, ValidateMnemonic(strings.Replace(mnemonic, "　", " ", -1)))
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	spanish, err := NewMnemonic(make([]byte, 16), LanguageSpanish)
+	require.NoError(t, err)
+	// Wordlists are NFKD normalized , as in the specification
+	abaco := norm.NFKD.String("ábaco")
+	require.True(t, strings.HasPrefix(spanish, abaco+" "))
+	require.NotEqual(t, abaco, norm.NFC.String(abaco))
+
+	tests := []struct {
+		name     string
+		mnemonic string
+		language string
+		err      error
+	}{
+		{name: "english", mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow", language: LanguageEnglish},
+		{name: "upper-case", mnemonic: "Legal Winner thank year wave sausage worth useful legal winner thank YELLOW", language: LanguageEnglish},
+		{name: "whitespace", mnemonic: " legal winner\tthank year wave sausage worth useful legal  winner thank yellow\n", language: LanguageEnglish},
+		{name: "spanish", mnemonic: spanish, language: LanguageSpanish},
+		{name: "spanish-nfc", mnemonic: norm.NFC.String(spanish), language: LanguageSpanish},
+		{name: "checksum", mnemonic: "legal winner thank year wave sausage worth useful legal winner thank year", language: LanguageEnglish, err: bip39.ErrChecksumIncorrect},
+		{name: "word-count", mnemonic: "legal winner thank year wave sausage worth useful legal winner thank", language: LanguageEnglish, err: bip39.ErrInvalidNumberOfWords},
+		{name: "unknown-word", mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yelow", err: bip39.ErrUnknownWord},
+		{name: "mixed-languages", mnemonic: strings.Replace(spanish, abaco, "abandon", 1), err: bip39.ErrUnknownWord},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.err, ValidateMnemonic(tt.mnemonic))
+			language, err := DetectLanguage(tt.mnemonic)
+			if tt.language == "" {
+				require.Equal(t, errors.ErrUnknownSeedLanguage, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.language, language)
+			}
+		})
+	}
+
+	// Words shared by both Chinese wordlists are told apart by the checksum
+	for _, language := range []string{LanguageChineseSimplified, LanguageChineseTraditional} {
+		entropy, err := bip39.NewEntropy(128)
+		require.NoError(t, err)
+		mnemonic, err := NewMnemonic(entropy, language)
+		require.NoError(t, err)
+		detected, err := DetectLanguage(mnemonic)
+		require.NoError(t, err)
+		decoded, err := EntropyFromMnemonic(mnemonic, detected)
+		require.NoError(t, err)
+		require.Equal(t, entropy, decoded)
+	}
+}
+
+func TestNormalizeMnemonic(t *testing.T) {
+	spanish, err := NewMnemonic(make([]byte, 16), LanguageSpanish)
+	require.NoError(t, err)
+	composed := norm.NFC.String(spanish)
+	require.NotEqual(t, spanish, composed)
+	require.Equal(t, spanish, NormalizeMnemonic(composed))
+	require.Equal(t, spanish, NormalizeMnemonic("  "+strings.Replace(spanish, " ", " \t ", -1)+"\n"))
+
+	// The ideographic space of Japanese mnemonics is normalized to a space
+	japanese, err := NewMnemonic(make([]byte, 16), LanguageJapanese)
+	require.NoError(t, err)
+	require.NotContains(t, NormalizeMnemonic(japanese), japaneseSeparator)
+	require.Len(t, strings.Split(NormalizeMnemonic(japanese), " "), 12)
+
+	english := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	require.Equal(t, english, NormalizeMnemonic(english))
+}
+
+func TestCompleteWord(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		language string
+		words    []string
+	}{
+		{name: "english", prefix: "zo", language: LanguageEnglish, words: []string{"zone", "zoo"}},
+		{name: "default-language", prefix: "abandon", words: []string{"abandon"}},
+		{name: "upper-case", prefix: "ABAN", language: LanguageEnglish, words: []string{"abandon"}},
+		{name: "no-match", prefix: "xq", language: LanguageEnglish, words: []string{}},
+		{name: "empty", prefix: " ", language: LanguageEnglish, words: []string{}},
+		{name: "accented", prefix: "ábac", language: LanguageSpanish, words: []string{norm.NFKD.String("ábaco")}},
+		{name: "without-accents", prefix: "abac", language: LanguageSpanish, words: []string{norm.NFKD.String("ábaco")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := CompleteWord(tt.prefix, tt.language)
+			require.NoError(t, err)
+			require.Equal(t, tt.words, words)
+		})
+	}
+	_, err := CompleteWord("ab", "klingon")
+	require.Equal(t, errors.ErrUnknownSeedLanguage, err)
+}
+
+func TestSuggestWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		word     string
+		language string
+		first    string
+	}{
+		{name: "exact", word: "abandon", language: LanguageEnglish, first: "abandon"},
+		{name: "substitution", word: "abandom", language: LanguageEnglish, first: "abandon"},
+		{name: "deletion", word: "abandn", language: LanguageEnglish, first: "abandon"},
+		{name: "insertion", word: "abandoon", language: LanguageEnglish, first: "abandon"},
+		{name: "transposition", word: "abnadon", language: LanguageEnglish, first: "abandon"},
+		{name: "missing-accent", word: "abaco", language: LanguageSpanish, first: norm.NFKD.String("ábaco")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := SuggestWords(tt.word, tt.language)
+			require.NoError(t, err)
+			require.NotEmpty(t, words)
+			require.True(t, len(words) <= maxSuggestions)
+			require.Equal(t, tt.first, words[0])
+		})
+	}
+
+	words, err := SuggestWords("qqqqqqqq", LanguageEnglish)
+	require.NoError(t, err)
+	require.Empty(t, words)
+	_, err = SuggestWords("abandon", "klingon")
+	require.Equal(t, errors.ErrUnknownSeedLanguage, err)
+}
@@ -0,0 +1,155 @@
+package bip39util
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/SkycoinProject/skycoin/src/cipher/bip39/wordlists"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Languages of the BIP39 wordlists , named after the wordlist files of the specification
+const (
+	LanguageEnglish            = "english"
+	LanguageSpanish            = "spanish"
+	LanguageFrench             = "french"
+	LanguageItalian            = "italian"
+	LanguageJapanese           = "japanese"
+	LanguageKorean             = "korean"
+	LanguageChineseSimplified  = "chinese_simplified"
+	LanguageChineseTraditional = "chinese_traditional"
+
+	// DefaultLanguage used when no language is given
+	DefaultLanguage = LanguageEnglish
+)
+
+// languages in the order tried when detecting the language of a mnemonic
+var languages = []string{
+	LanguageEnglish,
+	LanguageSpanish,
+	LanguageFrench,
+	LanguageItalian,
+	LanguageJapanese,
+	LanguageKorean,
+	LanguageChineseSimplified,
+	LanguageChineseTraditional,
+}
+
+var sourceWordlists = map[string][]string{
+	LanguageEnglish:            wordlists.English,
+	LanguageSpanish:            wordlists.Spanish,
+	LanguageFrench:             wordlists.French,
+	LanguageItalian:            wordlists.Italian,
+	LanguageJapanese:           wordlists.Japanese,
+	LanguageKorean:             wordlists.Korean,
+	LanguageChineseSimplified:  wordlists.ChineseSimplified,
+	LanguageChineseTraditional: wordlists.ChineseTraditional,
+}
+
+// wordlist words of a language along with their normalized forms
+type wordlist struct {
+	words []string
+	// normalized NFKD lower case words
+	normalized []string
+	// stripped normalized words without diacritics
+	stripped [][]rune
+	index    map[string]int
+}
+
+var (
+	loadedWordlists      = make(map[string]*wordlist)
+	loadedWordlistsMutex sync.Mutex
+)
+
+// Languages returns the languages of the supported wordlists
+func Languages() []string {
+	return append([]string(nil), languages...)
+}
+
+// Wordlist returns the words of language
+func Wordlist(language string) ([]string, error) {
+	wl, err := getWordlist(language)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), wl.words...), nil
+}
+
+func getWordlist(language string) (*wordlist, error) {
+	if language == "" {
+		language = DefaultLanguage
+	}
+	loadedWordlistsMutex.Lock()
+	defer loadedWordlistsMutex.Unlock()
+	if wl, isLoaded := loadedWordlists[language]; isLoaded {
+		return wl, nil
+	}
+	words, isSupported := sourceWordlists[language]
+	if !isSupported {
+		return nil, errors.ErrUnknownSeedLanguage
+	}
+	wl := &wordlist{
+		words:      words,
+		normalized: make([]string, len(words)),
+		stripped:   make([][]rune, len(words)),
+		index:      make(map[string]int, len(words)),
+	}
+	for i, w := range words {
+		wl.normalized[i] = normalizeWord(w)
+		wl.stripped[i] = []rune(stripDiacritics(wl.normalized[i]))
+		wl.index[wl.normalized[i]] = i
+	}
+	loadedWordlists[language] = wl
+	return wl, nil
+}
+
+// normalizeWord so that words typed with composed or decomposed accents match
+func normalizeWord(word string) string {
+	return norm.NFKD.String(strings.ToLower(word))
+}
+
+// normalizeMnemonic splits mnemonic in normalized words , any whitespace separates them
+func normalizeMnemonic(mnemonic string) []string {
+	return strings.Fields(norm.NFKD.String(strings.ToLower(mnemonic)))
+}
+
+// stripDiacritics removes the combining marks of a normalized word
+func stripDiacritics(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, word)
+}
+
+// DetectLanguage returns the language of the wordlist holding every word of mnemonic.
+// If words belong to several wordlists the one with a valid checksum is preferred
+func DetectLanguage(mnemonic string) (string, error) {
+	words := normalizeMnemonic(mnemonic)
+	if len(words) == 0 {
+		return "", errors.ErrUnknownSeedLanguage
+	}
+	candidate := ""
+	for _, language := range languages {
+		wl, err := getWordlist(language)
+		if err != nil {
+			return "", err
+		}
+		if _, err := wl.indices(words); err != nil {
+			continue
+		}
+		if _, err := wl.entropy(words); err == nil {
+			return language, nil
+		}
+		if candidate == "" {
+			candidate = language
+		}
+	}
+	if candidate == "" {
+		return "", errors.ErrUnknownSeedLanguage
+	}
+	return candidate, nil
+}
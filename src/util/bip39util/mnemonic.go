@@ -0,0 +1,122 @@
+package bip39util
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	"github.com/SkycoinProject/skycoin/src/cipher/bip39"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// bitsPerWord bits of entropy and checksum encoded by every word
+	bitsPerWord = 11
+	// japaneseSeparator ideographic space separating Japanese words
+	japaneseSeparator = "　"
+)
+
+// NewMnemonic encodes entropy as a mnemonic with words in language
+func NewMnemonic(entropy []byte, language string) (string, error) {
+	wl, err := getWordlist(language)
+	if err != nil {
+		return "", err
+	}
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", bip39.ErrInvalidEntropyLength
+	}
+	checksum := sha256.Sum256(entropy)
+	// Entropy followed by len(entropy) / 4 checksum bits
+	data := append(append([]byte(nil), entropy...), checksum[0])
+	count := (len(entropy)*8 + len(entropy)/4) / bitsPerWord
+	words := make([]string, count)
+	for i := range words {
+		words[i] = wl.words[readBits(data, i*bitsPerWord, bitsPerWord)]
+	}
+	separator := " "
+	if language == LanguageJapanese {
+		separator = japaneseSeparator
+	}
+	return strings.Join(words, separator), nil
+}
+
+// EntropyFromMnemonic decodes the entropy of mnemonic , verifying its checksum.
+// Language is detected if empty
+func EntropyFromMnemonic(mnemonic, language string) ([]byte, error) {
+	if language == "" {
+		detected, err := DetectLanguage(mnemonic)
+		if err != nil {
+			return nil, bip39.ErrUnknownWord
+		}
+		language = detected
+	}
+	wl, err := getWordlist(language)
+	if err != nil {
+		return nil, err
+	}
+	return wl.entropy(normalizeMnemonic(mnemonic))
+}
+
+// NormalizeMnemonic returns mnemonic NFKD normalized with words separated by a single space ,
+// as the specification requires before deriving the seed
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+}
+
+// ValidateMnemonic checks words and checksum of mnemonic in any supported language
+func ValidateMnemonic(mnemonic string) error {
+	_, err := EntropyFromMnemonic(mnemonic, "")
+	return err
+}
+
+// indices looks up the position of normalized words in the wordlist
+func (wl *wordlist) indices(words []string) ([]int, error) {
+	indices := make([]int, len(words))
+	for i, w := range words {
+		idx, isWord := wl.index[w]
+		if !isWord {
+			return nil, bip39.ErrUnknownWord
+		}
+		indices[i] = idx
+	}
+	return indices, nil
+}
+
+func (wl *wordlist) entropy(words []string) ([]byte, error) {
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, bip39.ErrInvalidNumberOfWords
+	}
+	indices, err := wl.indices(words)
+	if err != nil {
+		return nil, err
+	}
+	checksumBits := len(words) / 3
+	data := make([]byte, (len(words)*bitsPerWord+7)/8)
+	for i, idx := range indices {
+		writeBits(data, i*bitsPerWord, bitsPerWord, idx)
+	}
+	entropy := data[:checksumBits*4]
+	checksum := sha256.Sum256(entropy)
+	if readBits(data, len(entropy)*8, checksumBits) != int(checksum[0]>>uint(8-checksumBits)) {
+		return nil, bip39.ErrChecksumIncorrect
+	}
+	return append([]byte(nil), entropy...), nil
+}
+
+// readBits reads n bits of data starting at bit offset , most significant first
+func readBits(data []byte, offset, n int) int {
+	value := 0
+	for i := offset; i < offset+n; i++ {
+		value = value<<1 | int(data[i/8]>>uint(7-i%8))&1
+	}
+	return value
+}
+
+// writeBits writes the n lower bits of value in data starting at bit offset
+func writeBits(data []byte, offset, n, value int) {
+	for i := 0; i < n; i++ {
+		if value>>uint(n-1-i)&1 == 1 {
+			pos := offset + i
+			data[pos/8] |= 1 << uint(7-pos%8)
+		}
+	}
+}
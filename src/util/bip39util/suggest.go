@@ -0,0 +1,116 @@
+package bip39util
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// maxSuggestionDistance largest number of edits between a mistyped word and its suggestions
+	maxSuggestionDistance = 2
+	// maxSuggestions words returned by SuggestWords
+	maxSuggestions = 5
+)
+
+// CompleteWord lists the words of language starting with prefix , accents may be omitted
+func CompleteWord(prefix, language string) ([]string, error) {
+	wl, err := getWordlist(language)
+	if err != nil {
+		return nil, err
+	}
+	prefix = normalizeWord(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return []string{}, nil
+	}
+	stripped := stripDiacritics(prefix)
+	words := make([]string, 0)
+	for i, w := range wl.normalized {
+		if strings.HasPrefix(w, prefix) || strings.HasPrefix(string(wl.stripped[i]), stripped) {
+			words = append(words, wl.words[i])
+		}
+	}
+	return words, nil
+}
+
+// SuggestWords lists the words of language closest to a possibly mistyped word , best matches first
+func SuggestWords(word, language string) ([]string, error) {
+	wl, err := getWordlist(language)
+	if err != nil {
+		return nil, err
+	}
+	normalized := normalizeWord(strings.TrimSpace(word))
+	if normalized == "" {
+		return []string{}, nil
+	}
+	if idx, isWord := wl.index[normalized]; isWord {
+		return []string{wl.words[idx]}, nil
+	}
+
+	type suggestion struct {
+		index    int
+		distance int
+	}
+	target := []rune(stripDiacritics(normalized))
+	suggestions := make([]suggestion, 0)
+	for i, w := range wl.stripped {
+		if d := editDistance(target, w); d <= maxSuggestionDistance {
+			suggestions = append(suggestions, suggestion{index: i, distance: d})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	words := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		words[i] = wl.words[s.index]
+	}
+	return words, nil
+}
+
+// editDistance counts insertions , deletions , substitutions and transpositions turning a into b
+func editDistance(a, b []rune) int {
+	if abs(len(a)-len(b)) > maxSuggestionDistance {
+		return maxSuggestionDistance + 1
+	}
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minOf(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = minOf(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func minOf(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"time"
+
 	"github.com/fibercrypto/fibercryptowallet/src/core"
 	"github.com/fibercrypto/fibercryptowallet/src/errors"
 )
@@ -66,6 +68,53 @@ func (gOut *GenericOutput) SupportedAssets() (tickers []string) {
 	return
 }
 
+// Confirmations returns how many blocks , including its own , bury a block.
+// headSeq is the sequence of the block at the tip of the chain , as reported by BlockchainStatus.GetNumberOfBlocks
+func Confirmations(headSeq, blockSeq uint64) uint64 {
+	if headSeq < blockSeq {
+		return 0
+	}
+	return headSeq - blockSeq + 1
+}
+
+// TxnConfirmations returns the confirmations of a transaction aware of the block including it ,
+// zero if it is not confirmed yet
+func TxnConfirmations(txn core.Transaction, headSeq uint64) uint64 {
+	bcTxn, isBcTxn := txn.(core.BlockchainTransaction)
+	if !isBcTxn || txn.GetStatus() != core.TXN_STATUS_CONFIRMED {
+		return 0
+	}
+	return Confirmations(headSeq, bcTxn.GetBlockSeq())
+}
+
+// WaitForConfirmations polls the network every interval until a transaction is buried under n blocks.
+// It returns the confirmations reached , or errors.ErrConfirmationTimeout once timeout elapses.
+func WaitForConfirmations(tracker core.TxnTracker, bc core.BlockchainStatus, txnID string, n uint64, interval, timeout time.Duration) (uint64, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var confirmations uint64
+		blockSeq, err := tracker.GetTxnBlockSeq(txnID)
+		switch err {
+		case nil:
+			headSeq, err := bc.GetNumberOfBlocks()
+			if err != nil {
+				return 0, err
+			}
+			confirmations = Confirmations(headSeq, blockSeq)
+			if confirmations >= n {
+				return confirmations, nil
+			}
+		case errors.ErrTxnNotConfirmed:
+		default:
+			return 0, err
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return confirmations, errors.ErrConfirmationTimeout
+		}
+		time.Sleep(interval)
+	}
+}
+
 // Type assertions
 var (
 	_ core.TransactionOutput = &GenericOutput{}
@@ -3,12 +3,14 @@ package util
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
 	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
 )
 
 func TestNewGenericOutput(t *testing.T) {
@@ -65,3 +67,59 @@ func TestNewGenericOutput(t *testing.T) {
 	_, err2 := output.GetCoins("other_coin")
 	require.Error(t, err2)
 }
+
+type blockTxn struct {
+	*mocks.Transaction
+	blockSeq uint64
+}
+
+func (txn *blockTxn) GetBlockSeq() uint64 {
+	return txn.blockSeq
+}
+
+func TestTxnConfirmations(t *testing.T) {
+	confirmed := new(mocks.Transaction)
+	confirmed.On("GetStatus").Return(core.TXN_STATUS_CONFIRMED)
+	pending := new(mocks.Transaction)
+	pending.On("GetStatus").Return(core.TXN_STATUS_PENDING)
+
+	tests := []struct {
+		name          string
+		txn           core.Transaction
+		headSeq       uint64
+		confirmations uint64
+	}{
+		{name: "tip", txn: &blockTxn{Transaction: confirmed, blockSeq: 10}, headSeq: 10, confirmations: 1},
+		{name: "buried", txn: &blockTxn{Transaction: confirmed, blockSeq: 10}, headSeq: 15, confirmations: 6},
+		{name: "stale-head", txn: &blockTxn{Transaction: confirmed, blockSeq: 10}, headSeq: 9, confirmations: 0},
+		{name: "pending", txn: &blockTxn{Transaction: pending}, headSeq: 15, confirmations: 0},
+		{name: "unknown-block", txn: confirmed, headSeq: 15, confirmations: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.confirmations, TxnConfirmations(tt.txn, tt.headSeq))
+		})
+	}
+}
+
+func TestWaitForConfirmations(t *testing.T) {
+	tracker := new(mocks.TxnTracker)
+	tracker.On("GetTxnBlockSeq", "txn").Return(uint64(0), errors.ErrTxnNotConfirmed).Once()
+	tracker.On("GetTxnBlockSeq", "txn").Return(uint64(10), nil)
+	bc := new(mocks.BlockchainStatus)
+	bc.On("GetNumberOfBlocks").Return(uint64(10), nil).Once()
+	bc.On("GetNumberOfBlocks").Return(uint64(12), nil)
+
+	confirmations, err := WaitForConfirmations(tracker, bc, "txn", 3, time.Millisecond, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), confirmations)
+	tracker.AssertNumberOfCalls(t, "GetTxnBlockSeq", 3)
+
+	confirmations, err = WaitForConfirmations(tracker, bc, "txn", 10, time.Millisecond, 5*time.Millisecond)
+	require.Equal(t, errors.ErrConfirmationTimeout, err)
+	require.Equal(t, uint64(3), confirmations)
+
+	tracker.On("GetTxnBlockSeq", "failure").Return(uint64(0), errors.ErrNotFound)
+	_, err = WaitForConfirmations(tracker, bc, "failure", 1, time.Millisecond, time.Second)
+	require.Equal(t, errors.ErrNotFound, err)
+}
@@ -0,0 +1,253 @@
+package historyutil
+
+import (
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/fibercrypto/fibercryptowallet/src/util/logging"
+)
+
+var logHistory = logging.MustGetLogger("History util")
+
+// NetEffect balance variation caused by a transaction upon a wallet
+type NetEffect struct {
+	// Coins SKY droplets received minus droplets spent
+	Coins int64
+	// Hours coin hours received minus coin hours spent
+	Hours int64
+}
+
+// Entry summarizes a transaction from the point of view of a set of wallets
+type Entry struct {
+	// Txn the transaction being described
+	Txn core.Transaction
+	// TxnID transaction hash
+	TxnID string
+	// Timestamp of the block including the transaction
+	Timestamp core.Timestamp
+	// BlockSeq sequence number of the block including the transaction , zero if unknown
+	BlockSeq uint64
+	// Confirmations blocks burying the transaction , zero if not confirmed
+	Confirmations uint64
+	// Status of the transaction
+	Status core.TransactionStatus
+	// Direction of funds relative to the wallets
+	Direction core.TxnDirection
+	// Amount SKY droplets moved by the transaction
+	Amount uint64
+	// HoursTransferred coin hours moved by the transaction
+	HoursTransferred uint64
+	// Fee coin hours burned by the transaction
+	Fee uint64
+	// WalletAddresses own addresses involved in the transaction
+	WalletAddresses []string
+	// Counterparties foreign addresses involved in the transaction
+	Counterparties []string
+	// Contacts names of address book contacts owning counterparties
+	Contacts []string
+	// Net effect of the transaction upon each wallet , indexed by wallet ID
+	Net map[string]NetEffect
+	// Label user note attached to the transaction
+	Label string
+}
+
+// NewEntry describes txn relative to addresses , a map of known addresses to the ID of the wallet owning them.
+// Transaction classification matches the one rendered in wallet history.
+func NewEntry(txn core.Transaction, addresses map[string]string) (*Entry, error) {
+	entry := &Entry{
+		Txn:            txn,
+		TxnID:          txn.GetId(),
+		Timestamp:      txn.GetTimestamp(),
+		Status:         txn.GetStatus(),
+		Net:            make(map[string]NetEffect),
+		Counterparties: make([]string, 0),
+	}
+	if bcTxn, isBcTxn := txn.(core.BlockchainTransaction); isBcTxn {
+		entry.BlockSeq = bcTxn.GetBlockSeq()
+	}
+	seen := make(map[string]struct{})
+	addAddress := func(addr string) {
+		if _, isSeen := seen[addr]; isSeen {
+			return
+		}
+		seen[addr] = struct{}{}
+		if _, isOwn := addresses[addr]; isOwn {
+			entry.WalletAddresses = append(entry.WalletAddresses, addr)
+		} else {
+			entry.Counterparties = append(entry.Counterparties, addr)
+		}
+	}
+
+	var skyAmountIn, skyAmountOut, hoursIn, hoursOut uint64
+	sent := false
+	firstInWallet := ""
+	inAddresses := make(map[string]struct{})
+	for i, in := range txn.GetInputs() {
+		out, err := in.GetSpentOutput()
+		if err != nil {
+			logHistory.WithError(err).Error("Couldn't get spent output")
+			return nil, err
+		}
+		outAddr, err := out.GetAddress()
+		if err != nil {
+			logHistory.WithError(err).Error("Couldn't get address")
+			return nil, err
+		}
+		addr := outAddr.String()
+		addAddress(addr)
+		inAddresses[addr] = struct{}{}
+		sky, err := in.GetCoins(params.SkycoinTicker)
+		if err != nil {
+			logHistory.WithError(err).Warn("Couldn't get Skycoins balance")
+			return nil, err
+		}
+		hours, err := in.GetCoins(params.CalculatedHoursTicker)
+		if err != nil {
+			logHistory.WithError(err).Warn("Couldn't get Coin Hours balance")
+			return nil, err
+		}
+		if i == 0 {
+			firstInWallet = addresses[addr]
+		}
+		if wltID, isOwn := addresses[addr]; isOwn {
+			sent = true
+			skyAmountOut += sky
+			net := entry.Net[wltID]
+			net.Coins -= int64(sky)
+			net.Hours -= int64(hours)
+			entry.Net[wltID] = net
+		}
+	}
+
+	internally := true
+	var movedSky, movedHours uint64
+	for _, out := range txn.GetOutputs() {
+		outAddr, err := out.GetAddress()
+		if err != nil {
+			logHistory.WithError(err).Error("Couldn't get address")
+			return nil, err
+		}
+		addr := outAddr.String()
+		addAddress(addr)
+		sky, err := out.GetCoins(params.SkycoinTicker)
+		if err != nil {
+			logHistory.WithError(err).Warn("Couldn't get Skycoins balance")
+			return nil, err
+		}
+		hours, err := out.GetCoins(params.CoinHoursTicker)
+		if err != nil {
+			logHistory.WithError(err).Warn("Couldn't get Coin Hours balance")
+			return nil, err
+		}
+		wltID, isOwn := addresses[addr]
+		if isOwn {
+			net := entry.Net[wltID]
+			net.Coins += int64(sky)
+			net.Hours += int64(hours)
+			entry.Net[wltID] = net
+		}
+		if _, isInput := inAddresses[addr]; !isInput {
+			movedSky += sky
+			movedHours += hours
+		}
+		if sent {
+			if isOwn && wltID == firstInWallet {
+				skyAmountOut -= sky
+			} else {
+				internally = false
+				hoursOut += hours
+			}
+		} else if isOwn {
+			hoursIn += hours
+			skyAmountIn += sky
+		}
+	}
+
+	fee, err := txn.ComputeFee(params.CoinHoursTicker)
+	if err != nil {
+		logHistory.WithError(err).Warn("Couldn't compute fee of the operation")
+		return nil, err
+	}
+	entry.Fee = fee
+	switch {
+	case !sent:
+		entry.Direction = core.TxnDirectionReceived
+		entry.Amount, entry.HoursTransferred = skyAmountIn, hoursIn
+	case internally:
+		entry.Direction = core.TxnDirectionInternal
+		entry.Amount, entry.HoursTransferred = movedSky, movedHours
+	default:
+		entry.Direction = core.TxnDirectionSent
+		entry.Amount, entry.HoursTransferred = skyAmountOut, hoursOut
+	}
+	return entry, nil
+}
+
+// NewEntries describes a sequence of transactions , skipping duplicates
+func NewEntries(txns []core.Transaction, addresses map[string]string) ([]*Entry, error) {
+	entries := make([]*Entry, 0, len(txns))
+	added := make(map[string]struct{})
+	for _, txn := range txns {
+		if _, isAdded := added[txn.GetId()]; isAdded {
+			continue
+		}
+		entry, err := NewEntry(txn, addresses)
+		if err != nil {
+			return nil, err
+		}
+		added[txn.GetId()] = struct{}{}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// TxnLabels maps transaction IDs to their labels in store
+func TxnLabels(store core.LabelStore) (map[string]string, error) {
+	labels, err := store.ListLabels(core.LabelTypeTxn)
+	if err != nil {
+		logHistory.WithError(err).Warn("Couldn't load transaction labels")
+		return nil, err
+	}
+	byTxn := make(map[string]string, len(labels))
+	for _, label := range labels {
+		byTxn[label.Ref] = label.Label
+	}
+	return byTxn, nil
+}
+
+// AttachLabels sets the label of every entry out of labels , a map of transaction IDs to their labels
+func AttachLabels(entries []*Entry, labels map[string]string) {
+	for _, entry := range entries {
+		entry.Label = labels[entry.TxnID]
+	}
+}
+
+// AttachContacts sets the contacts of every entry out of names , a map of addresses to the name of the contact owning them
+func AttachContacts(entries []*Entry, names map[string]string) {
+	for _, entry := range entries {
+		entry.Contacts = nil
+		seen := make(map[string]struct{})
+		for _, addr := range entry.Counterparties {
+			name, isKnown := names[addr]
+			if !isKnown {
+				continue
+			}
+			if _, isSeen := seen[name]; !isSeen {
+				seen[name] = struct{}{}
+				entry.Contacts = append(entry.Contacts, name)
+			}
+		}
+	}
+}
+
+// AttachConfirmations sets the confirmations of every entry relative to the block at the tip of the chain
+func AttachConfirmations(entries []*Entry, headSeq uint64) {
+	for _, entry := range entries {
+		entry.Confirmations = 0
+		if entry.Status == core.TXN_STATUS_CONFIRMED {
+			if _, isBcTxn := entry.Txn.(core.BlockchainTransaction); isBcTxn {
+				entry.Confirmations = util.Confirmations(headSeq, entry.BlockSeq)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package historyutil
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	addrA1   = "9BSEAEE3XGtQ2X43BCT2XCYgheGLQQigEG"
+	addrA2   = "25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ"
+	addrB1   = "2TFC2Ktc6Y3UAUqo7WGA55X6mqoKZRaFp9s"
+	addrExt  = "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"
+	walletA  = "a.wlt"
+	walletB  = "b.wlt"
+	droplets = 1000000
+)
+
+var testAddresses = map[string]string{
+	addrA1: walletA,
+	addrA2: walletA,
+	addrB1: walletB,
+}
+
+func TestMain(m *testing.M) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	os.Exit(m.Run())
+}
+
+type testInput struct {
+	spent util.GenericOutput
+}
+
+func (in *testInput) GetId() string {
+	return in.spent.GetId()
+}
+
+func (in *testInput) GetSpentOutput() (core.TransactionOutput, error) {
+	return &in.spent, nil
+}
+
+func (in *testInput) GetCoins(ticker string) (uint64, error) {
+	if ticker == params.CalculatedHoursTicker {
+		ticker = params.CoinHoursTicker
+	}
+	return in.spent.GetCoins(ticker)
+}
+
+func (in *testInput) SupportedAssets() []string {
+	return in.spent.SupportedAssets()
+}
+
+type testTxn struct {
+	id      string
+	ts      core.Timestamp
+	status  core.TransactionStatus
+	fee     uint64
+	inputs  []core.TransactionInput
+	outputs []core.TransactionOutput
+}
+
+func (txn *testTxn) SupportedAssets() []string {
+	return []string{params.SkycoinTicker, params.CoinHoursTicker}
+}
+func (txn *testTxn) GetTimestamp() core.Timestamp             { return txn.ts }
+func (txn *testTxn) GetStatus() core.TransactionStatus        { return txn.status }
+func (txn *testTxn) GetInputs() []core.TransactionInput       { return txn.inputs }
+func (txn *testTxn) GetOutputs() []core.TransactionOutput     { return txn.outputs }
+func (txn *testTxn) GetId() string                            { return txn.id }
+func (txn *testTxn) ComputeFee(ticker string) (uint64, error) { return txn.fee, nil }
+func (txn *testTxn) VerifyUnsigned() error                    { return nil }
+func (txn *testTxn) VerifySigned() error                      { return nil }
+func (txn *testTxn) IsFullySigned() (bool, error)             { return true, nil }
+
+type flow struct {
+	addr  string
+	sky   uint64
+	hours uint64
+}
+
+func newGenericOutput(id string, f flow) util.GenericOutput {
+	addr := util.NewGenericAddress(f.addr)
+	out := util.NewGenericOutput(&addr, id)
+	out.SetCoins(params.SkycoinTicker, f.sky)
+	out.SetCoins(params.CoinHoursTicker, f.hours)
+	return out
+}
+
+func makeTxn(id string, ts core.Timestamp, fee uint64, ins, outs []flow) *testTxn {
+	txn := &testTxn{id: id, ts: ts, status: core.TXN_STATUS_CONFIRMED, fee: fee}
+	for i, f := range ins {
+		txn.inputs = append(txn.inputs, &testInput{spent: newGenericOutput(id+"-in"+string(rune('0'+i)), f)})
+	}
+	for i, f := range outs {
+		out := newGenericOutput(id+"-out"+string(rune('0'+i)), f)
+		txn.outputs = append(txn.outputs, &out)
+	}
+	return txn
+}
+
+func TestNewEntry(t *testing.T) {
+	tests := []struct {
+		name      string
+		txn       *testTxn
+		direction core.TxnDirection
+		amount    uint64
+		hours     uint64
+		net       map[string]NetEffect
+		counter   []string
+	}{
+		{
+			name: "received",
+			txn: makeTxn("t1", 100, 2,
+				[]flow{{addrExt, 5 * droplets, 10}},
+				[]flow{{addrA1, 3 * droplets, 4}, {addrExt, 2 * droplets, 4}}),
+			direction: core.TxnDirectionReceived,
+			amount:    3 * droplets,
+			hours:     4,
+			net:       map[string]NetEffect{walletA: {Coins: 3 * droplets, Hours: 4}},
+			counter:   []string{addrExt},
+		},
+		{
+			name: "sent",
+			txn: makeTxn("t2", 200, 2,
+				[]flow{{addrA1, 5 * droplets, 10}},
+				[]flow{{addrExt, 2 * droplets, 4}, {addrA2, 3 * droplets, 4}}),
+			direction: core.TxnDirectionSent,
+			amount:    2 * droplets,
+			hours:     4,
+			net:       map[string]NetEffect{walletA: {Coins: -2 * droplets, Hours: -6}},
+			counter:   []string{addrExt},
+		},
+		{
+			name: "internal",
+			txn: makeTxn("t3", 300, 2,
+				[]flow{{addrA1, 5 * droplets, 10}},
+				[]flow{{addrA2, 5 * droplets, 8}}),
+			direction: core.TxnDirectionInternal,
+			amount:    5 * droplets,
+			hours:     8,
+			net:       map[string]NetEffect{walletA: {Coins: 0, Hours: -2}},
+			counter:   []string{},
+		},
+		{
+			name: "between-wallets",
+			txn: makeTxn("t4", 400, 2,
+				[]flow{{addrA1, 5 * droplets, 10}},
+				[]flow{{addrB1, 5 * droplets, 8}}),
+			direction: core.TxnDirectionSent,
+			amount:    5 * droplets,
+			hours:     8,
+			net: map[string]NetEffect{
+				walletA: {Coins: -5 * droplets, Hours: -10},
+				walletB: {Coins: 5 * droplets, Hours: 8},
+			},
+			counter: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := NewEntry(tt.txn, testAddresses)
+			require.NoError(t, err)
+			require.Equal(t, tt.txn.id, entry.TxnID)
+			require.Equal(t, tt.txn.ts, entry.Timestamp)
+			require.Equal(t, tt.direction, entry.Direction)
+			require.Equal(t, tt.amount, entry.Amount)
+			require.Equal(t, tt.hours, entry.HoursTransferred)
+			require.Equal(t, uint64(2), entry.Fee)
+			require.Equal(t, tt.net, entry.Net)
+			require.Equal(t, tt.counter, entry.Counterparties)
+		})
+	}
+}
+
+func TestNewEntries(t *testing.T) {
+	txn := makeTxn("t1", 100, 2, []flow{{addrExt, 5 * droplets, 10}}, []flow{{addrA1, 5 * droplets, 8}})
+	entries, err := NewEntries([]core.Transaction{txn, txn}, testAddresses)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestAttachLabels(t *testing.T) {
+	entries, err := NewEntries([]core.Transaction{
+		makeTxn("t1", 100, 2, []flow{{addrExt, 5 * droplets, 10}}, []flow{{addrA1, 5 * droplets, 8}}),
+		makeTxn("t2", 200, 2, []flow{{addrA1, 5 * droplets, 10}}, []flow{{addrExt, 5 * droplets, 8}}),
+	}, testAddresses)
+	require.NoError(t, err)
+
+	store := new(mocks.LabelStore)
+	store.On("ListLabels", core.LabelTypeTxn).Return([]core.Label{
+		{Type: core.LabelTypeTxn, Ref: "t2", Label: "Rent"},
+	}, nil).Once()
+	labels, err := TxnLabels(store)
+	require.NoError(t, err)
+	AttachLabels(entries, labels)
+	require.Equal(t, "", entries[0].Label)
+	require.Equal(t, "Rent", entries[1].Label)
+	AttachLabels(entries, nil)
+	require.Equal(t, "", entries[1].Label)
+
+	store.On("ListLabels", core.LabelTypeTxn).Return(nil, errors.New("locked")).Once()
+	_, err = TxnLabels(store)
+	require.Error(t, err)
+	store.AssertExpectations(t)
+}
+
+func TestAttachContacts(t *testing.T) {
+	entries, err := NewEntries([]core.Transaction{
+		makeTxn("t1", 100, 2, []flow{{addrExt, 5 * droplets, 10}}, []flow{{addrA1, 5 * droplets, 8}}),
+		makeTxn("t2", 200, 2, []flow{{addrA1, 5 * droplets, 10}}, []flow{{addrA1, 1 * droplets, 2}}),
+	}, testAddresses)
+	require.NoError(t, err)
+
+	AttachContacts(entries, map[string]string{addrExt: "Alice", addrA1: "Myself"})
+	require.Equal(t, []string{"Alice"}, entries[0].Contacts)
+	require.Nil(t, entries[1].Contacts)
+	AttachContacts(entries, map[string]string{})
+	require.Nil(t, entries[0].Contacts)
+}
+
+type testBlockTxn struct {
+	*testTxn
+	blockSeq uint64
+}
+
+func (txn *testBlockTxn) GetBlockSeq() uint64 { return txn.blockSeq }
+
+func TestAttachConfirmations(t *testing.T) {
+	pending := makeTxn("t3", 300, 2, []flow{{addrExt, 5 * droplets, 10}}, []flow{{addrA1, 5 * droplets, 8}})
+	pending.status = core.TXN_STATUS_PENDING
+	entries, err := NewEntries([]core.Transaction{
+		&testBlockTxn{testTxn: makeTxn("t1", 100, 2, []flow{{addrExt, 5 * droplets, 10}}, []flow{{addrA1, 5 * droplets, 8}}), blockSeq: 10},
+		makeTxn("t2", 200, 2, []flow{{addrExt, 5 * droplets, 10}}, []flow{{addrA1, 5 * droplets, 8}}),
+		&testBlockTxn{testTxn: pending},
+	}, testAddresses)
+	require.NoError(t, err)
+
+	AttachConfirmations(entries, 12)
+	require.Equal(t, uint64(10), entries[0].BlockSeq)
+	require.Equal(t, uint64(3), entries[0].Confirmations)
+	require.Equal(t, uint64(0), entries[1].Confirmations)
+	require.Equal(t, uint64(0), entries[2].Confirmations)
+
+	AttachConfirmations(entries, 15)
+	require.Equal(t, uint64(6), entries[0].Confirmations)
+}
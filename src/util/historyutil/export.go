@@ -0,0 +1,196 @@
+package historyutil
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+)
+
+const (
+	// FormatCSV comma separated values , one row per transaction and wallet
+	FormatCSV = "csv"
+	// FormatJSON array of transaction objects
+	FormatJSON = "json"
+)
+
+// csvHeader columns written by WriteCSV
+var csvHeader = []string{
+	"txid", "timestamp", "block_seq", "status", "direction",
+	"amount_sky", "hours_sch", "fee_sch", "wallet", "net_sky", "net_sch", "counterparties", "label",
+}
+
+// exportedNet JSON representation of NetEffect
+type exportedNet struct {
+	Coins string `json:"sky"`
+	Hours string `json:"sch"`
+}
+
+// exportedEntry JSON representation of Entry
+type exportedEntry struct {
+	TxnID          string                 `json:"txid"`
+	Timestamp      string                 `json:"timestamp"`
+	BlockSeq       uint64                 `json:"block_seq"`
+	Status         string                 `json:"status"`
+	Direction      string                 `json:"direction"`
+	Amount         string                 `json:"amount_sky"`
+	Hours          string                 `json:"hours_sch"`
+	Fee            string                 `json:"fee_sch"`
+	Counterparties []string               `json:"counterparties"`
+	Contacts       []string               `json:"contacts,omitempty"`
+	Net            map[string]exportedNet `json:"net"`
+	Label          string                 `json:"label,omitempty"`
+}
+
+// StatusName readable transaction status
+func StatusName(status core.TransactionStatus) string {
+	switch status {
+	case core.TXN_STATUS_CREATED:
+		return "created"
+	case core.TXN_STATUS_PENDING:
+		return "pending"
+	case core.TXN_STATUS_CONFIRMED:
+		return "confirmed"
+	}
+	return "unknown"
+}
+
+// DirectionName readable transaction direction
+func DirectionName(direction core.TxnDirection) string {
+	switch direction {
+	case core.TxnDirectionSent:
+		return "sent"
+	case core.TxnDirectionReceived:
+		return "received"
+	case core.TxnDirectionInternal:
+		return "internal"
+	}
+	return "any"
+}
+
+// Export writes entries to w in the given format
+func Export(w io.Writer, format string, entries []*Entry) error {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return WriteCSV(w, entries)
+	case FormatJSON:
+		return WriteJSON(w, entries)
+	}
+	return errInvalidFormat
+}
+
+// WriteCSV writes entries as CSV including a header row.
+// Transactions affecting several wallets span a row per wallet.
+func WriteCSV(w io.Writer, entries []*Entry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		exported, err := exportEntry(entry)
+		if err != nil {
+			return err
+		}
+		wallets := sortedWallets(exported.Net)
+		if len(wallets) == 0 {
+			wallets = []string{""}
+		}
+		for _, wltID := range wallets {
+			net := exported.Net[wltID]
+			row := []string{
+				exported.TxnID, exported.Timestamp, strconv.FormatUint(exported.BlockSeq, 10),
+				exported.Status, exported.Direction, exported.Amount, exported.Hours, exported.Fee,
+				wltID, net.Coins, net.Hours, strings.Join(exported.Counterparties, " "), exported.Label,
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes entries as a JSON array
+func WriteJSON(w io.Writer, entries []*Entry) error {
+	exported := make([]*exportedEntry, 0, len(entries))
+	for _, entry := range entries {
+		e, err := exportEntry(entry)
+		if err != nil {
+			return err
+		}
+		exported = append(exported, e)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(exported)
+}
+
+func exportEntry(entry *Entry) (*exportedEntry, error) {
+	skyQuotient, err := util.AltcoinQuotient(params.SkycoinTicker)
+	if err != nil {
+		return nil, err
+	}
+	hoursQuotient, err := util.AltcoinQuotient(params.CoinHoursTicker)
+	if err != nil {
+		return nil, err
+	}
+	exported := &exportedEntry{
+		TxnID:          entry.TxnID,
+		Timestamp:      time.Unix(int64(entry.Timestamp), 0).UTC().Format(time.RFC3339),
+		BlockSeq:       entry.BlockSeq,
+		Status:         StatusName(entry.Status),
+		Direction:      DirectionName(entry.Direction),
+		Amount:         FormatAmount(int64(entry.Amount), skyQuotient),
+		Hours:          FormatAmount(int64(entry.HoursTransferred), hoursQuotient),
+		Fee:            FormatAmount(int64(entry.Fee), hoursQuotient),
+		Counterparties: entry.Counterparties,
+		Contacts:       entry.Contacts,
+		Net:            make(map[string]exportedNet, len(entry.Net)),
+		Label:          entry.Label,
+	}
+	for wltID, net := range entry.Net {
+		exported.Net[wltID] = exportedNet{
+			Coins: FormatAmount(net.Coins, skyQuotient),
+			Hours: FormatAmount(net.Hours, hoursQuotient),
+		}
+	}
+	return exported, nil
+}
+
+// FormatAmount renders a signed amount of the smallest coin units as a plain decimal number
+func FormatAmount(n int64, quotient uint64) string {
+	sign := ""
+	abs := uint64(n)
+	if n < 0 {
+		sign = "-"
+		abs = uint64(-n)
+	}
+	if quotient <= 1 {
+		return sign + strconv.FormatUint(abs, 10)
+	}
+	decimals := len(strconv.FormatUint(quotient, 10)) - 1
+	frac := strconv.FormatUint(abs%quotient, 10)
+	frac = strings.Repeat("0", decimals-len(frac)) + frac
+	frac = strings.TrimRight(frac, "0")
+	if frac == "" {
+		return sign + strconv.FormatUint(abs/quotient, 10)
+	}
+	return sign + strconv.FormatUint(abs/quotient, 10) + "." + frac
+}
+
+func sortedWallets(net map[string]exportedNet) []string {
+	wallets := make([]string, 0, len(net))
+	for wltID := range net {
+		wallets = append(wallets, wltID)
+	}
+	sort.Strings(wallets)
+	return wallets
+}
@@ -0,0 +1,65 @@
+package historyutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    int64
+		quotient uint64
+		result   string
+	}{
+		{name: "zero", value: 0, quotient: 1000000, result: "0"},
+		{name: "integer", value: 3000000, quotient: 1000000, result: "3"},
+		{name: "fraction", value: 1500000, quotient: 1000000, result: "1.5"},
+		{name: "small", value: 1, quotient: 1000000, result: "0.000001"},
+		{name: "negative", value: -2500000, quotient: 1000000, result: "-2.5"},
+		{name: "no-decimals", value: -42, quotient: 1, result: "-42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.result, FormatAmount(tt.value, tt.quotient))
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	entries := makeTestEntries(t)
+	page, err := Query{Until: 200}.Run(entries)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, FormatCSV, page.Entries))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		csvHeader,
+		{"t2", "1970-01-01T00:03:20Z", "0", "confirmed", "sent", "1", "2", "1", walletA, "-1", "-4", addrExt, "Rent March"},
+		{"t1", "1970-01-01T00:01:40Z", "0", "confirmed", "received", "5", "8", "1", walletA, "5", "8", addrExt, ""},
+	}, rows)
+}
+
+func TestWriteJSON(t *testing.T) {
+	entries := makeTestEntries(t)
+	page, err := Query{Since: 400, Until: 400}.Run(entries)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, FormatJSON, page.Entries))
+	var exported []exportedEntry
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	require.Len(t, exported, 1)
+	require.Equal(t, "t4", exported[0].TxnID)
+	require.Equal(t, "sent", exported[0].Direction)
+	require.Equal(t, "9", exported[0].Amount)
+	require.Equal(t, map[string]exportedNet{walletB: {Coins: "-9", Hours: "-10"}}, exported[0].Net)
+
+	require.Error(t, Export(&buf, "xml", page.Entries))
+}
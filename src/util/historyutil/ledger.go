@@ -0,0 +1,131 @@
+package historyutil
+
+import (
+	"sort"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+// LedgerRow effect of a transaction upon a wallet balance
+type LedgerRow struct {
+	// Entry describing the transaction
+	Entry *Entry
+	// Timestamp of the block including the transaction
+	Timestamp core.Timestamp
+	// Delta balance variation caused by the transaction
+	Delta NetEffect
+	// Balance after applying the transaction
+	Balance NetEffect
+}
+
+// Ledger chronological record of the balance of a wallet.
+// Coin hours balances only account for hours transferred and burned ,
+// hours accrued by holding coins are not included.
+type Ledger struct {
+	// WalletID of the wallet
+	WalletID string
+	// Rows oldest first
+	Rows []LedgerRow
+}
+
+// BalancePoint balance of a wallet at a given time
+type BalancePoint struct {
+	// Timestamp the balance refers to
+	Timestamp core.Timestamp
+	// Balance at the end of Timestamp
+	Balance NetEffect
+}
+
+// NewLedger computes the ledger of a wallet out of history entries.
+// Only confirmed transactions affecting the wallet are recorded.
+// Transfers between addresses of the wallet only count fees.
+func NewLedger(entries []*Entry, walletID string) *Ledger {
+	selected := make([]*Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Status != core.TXN_STATUS_CONFIRMED {
+			continue
+		}
+		if _, affected := entry.Net[walletID]; affected {
+			selected = append(selected, entry)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		a, b := selected[i], selected[j]
+		if a.Timestamp != b.Timestamp {
+			return a.Timestamp < b.Timestamp
+		}
+		if a.BlockSeq != b.BlockSeq {
+			return a.BlockSeq < b.BlockSeq
+		}
+		return a.TxnID < b.TxnID
+	})
+
+	ledger := &Ledger{WalletID: walletID, Rows: make([]LedgerRow, 0, len(selected))}
+	var balance NetEffect
+	for _, entry := range selected {
+		delta := entry.Net[walletID]
+		balance.Coins += delta.Coins
+		balance.Hours += delta.Hours
+		ledger.Rows = append(ledger.Rows, LedgerRow{
+			Entry:     entry,
+			Timestamp: entry.Timestamp,
+			Delta:     delta,
+			Balance:   balance,
+		})
+	}
+	return ledger
+}
+
+// WalletLedger computes the ledger of a wallet out of its transactions , see NewEntry for addresses
+func WalletLedger(txns []core.Transaction, addresses map[string]string, walletID string) (*Ledger, error) {
+	entries, err := NewEntries(txns, addresses)
+	if err != nil {
+		return nil, err
+	}
+	return NewLedger(entries, walletID), nil
+}
+
+// BalanceAt returns the balance after every transaction confirmed at or before ts
+func (l *Ledger) BalanceAt(ts core.Timestamp) NetEffect {
+	i := sort.Search(len(l.Rows), func(i int) bool {
+		return l.Rows[i].Timestamp > ts
+	})
+	if i == 0 {
+		return NetEffect{}
+	}
+	return l.Rows[i-1].Balance
+}
+
+// Series downsamples the ledger into points balances evenly spaced between since and until.
+// Zero bounds default to the time of the first and last transaction.
+// The last point always reports the balance at until.
+func (l *Ledger) Series(since, until core.Timestamp, points int) []BalancePoint {
+	series := make([]BalancePoint, 0)
+	if points <= 0 {
+		return series
+	}
+	if len(l.Rows) > 0 {
+		if since == 0 {
+			since = l.Rows[0].Timestamp
+		}
+		if until == 0 {
+			until = l.Rows[len(l.Rows)-1].Timestamp
+		}
+	}
+	if until < since {
+		return series
+	}
+	if points == 1 || until == since {
+		return append(series, BalancePoint{Timestamp: until, Balance: l.BalanceAt(until)})
+	}
+	span := uint64(until - since)
+	steps := uint64(points - 1)
+	for i := uint64(0); i <= steps; i++ {
+		ts := since + core.Timestamp(span*i/steps)
+		if len(series) > 0 && series[len(series)-1].Timestamp == ts {
+			continue
+		}
+		series = append(series, BalancePoint{Timestamp: ts, Balance: l.BalanceAt(ts)})
+	}
+	return series
+}
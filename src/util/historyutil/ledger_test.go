@@ -0,0 +1,100 @@
+package historyutil
+
+import (
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLedger(t *testing.T) {
+	entries := makeTestEntries(t)
+
+	ledger := NewLedger(entries, walletA)
+	require.Equal(t, walletA, ledger.WalletID)
+	require.Equal(t, []string{"t1", "t2", "t3"}, ledgerIDs(ledger))
+	require.Equal(t, []NetEffect{
+		{Coins: 5 * droplets, Hours: 8},
+		{Coins: -1 * droplets, Hours: -4},
+		{Coins: 0, Hours: -2},
+	}, ledgerDeltas(ledger))
+	require.Equal(t, NetEffect{Coins: 4 * droplets, Hours: 2}, ledger.Rows[2].Balance)
+	require.Equal(t, core.Timestamp(300), ledger.Rows[2].Timestamp)
+
+	// Pending transactions are not recorded
+	ledger = NewLedger(entries, walletB)
+	require.Equal(t, []string{"t4"}, ledgerIDs(ledger))
+
+	require.Empty(t, NewLedger(entries, "unknown.wlt").Rows)
+}
+
+func TestLedger_BalanceAt(t *testing.T) {
+	ledger := NewLedger(makeTestEntries(t), walletA)
+	tests := []struct {
+		name    string
+		ts      core.Timestamp
+		balance NetEffect
+	}{
+		{name: "before-history", ts: 50, balance: NetEffect{}},
+		{name: "at-first", ts: 100, balance: NetEffect{Coins: 5 * droplets, Hours: 8}},
+		{name: "between", ts: 250, balance: NetEffect{Coins: 4 * droplets, Hours: 4}},
+		{name: "after-history", ts: 1000, balance: NetEffect{Coins: 4 * droplets, Hours: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.balance, ledger.BalanceAt(tt.ts))
+		})
+	}
+}
+
+func TestLedger_Series(t *testing.T) {
+	ledger := NewLedger(makeTestEntries(t), walletA)
+	tests := []struct {
+		name   string
+		since  core.Timestamp
+		until  core.Timestamp
+		points int
+		want   []BalancePoint
+	}{
+		{name: "full-history", points: 3, want: []BalancePoint{
+			{Timestamp: 100, Balance: NetEffect{Coins: 5 * droplets, Hours: 8}},
+			{Timestamp: 200, Balance: NetEffect{Coins: 4 * droplets, Hours: 4}},
+			{Timestamp: 300, Balance: NetEffect{Coins: 4 * droplets, Hours: 2}},
+		}},
+		{name: "range", since: 0, until: 250, points: 2, want: []BalancePoint{
+			{Timestamp: 100, Balance: NetEffect{Coins: 5 * droplets, Hours: 8}},
+			{Timestamp: 250, Balance: NetEffect{Coins: 4 * droplets, Hours: 4}},
+		}},
+		{name: "single", points: 1, want: []BalancePoint{
+			{Timestamp: 300, Balance: NetEffect{Coins: 4 * droplets, Hours: 2}},
+		}},
+		{name: "dense", since: 100, until: 102, points: 10, want: []BalancePoint{
+			{Timestamp: 100, Balance: NetEffect{Coins: 5 * droplets, Hours: 8}},
+			{Timestamp: 101, Balance: NetEffect{Coins: 5 * droplets, Hours: 8}},
+			{Timestamp: 102, Balance: NetEffect{Coins: 5 * droplets, Hours: 8}},
+		}},
+		{name: "no-points", points: 0, want: []BalancePoint{}},
+		{name: "inverted", since: 300, until: 100, points: 3, want: []BalancePoint{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ledger.Series(tt.since, tt.until, tt.points))
+		})
+	}
+}
+
+func ledgerIDs(ledger *Ledger) []string {
+	ids := make([]string, 0, len(ledger.Rows))
+	for _, row := range ledger.Rows {
+		ids = append(ids, row.Entry.TxnID)
+	}
+	return ids
+}
+
+func ledgerDeltas(ledger *Ledger) []NetEffect {
+	deltas := make([]NetEffect, 0, len(ledger.Rows))
+	for _, row := range ledger.Rows {
+		deltas = append(deltas, row.Delta)
+	}
+	return deltas
+}
@@ -0,0 +1,174 @@
+package historyutil
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+)
+
+var (
+	// ErrInvalidCursor pagination cursor could not be decoded
+	ErrInvalidCursor = errors.New("invalid history cursor")
+	// errInvalidFormat unsupported export format
+	errInvalidFormat = errors.New("unsupported export format")
+)
+
+// Query selects history entries
+type Query struct {
+	// Since lower bound of transaction timestamp , ignored if zero
+	Since core.Timestamp
+	// Until upper bound of transaction timestamp , ignored if zero
+	Until core.Timestamp
+	// Direction of funds relative to the wallets
+	Direction core.TxnDirection
+	// MinAmount lower bound of SKY droplets moved , ignored if zero
+	MinAmount uint64
+	// MaxAmount upper bound of SKY droplets moved , ignored if zero
+	MaxAmount uint64
+	// Counterparties restricts results to transactions involving any of these addresses
+	Counterparties []string
+	// Contact restricts results to transactions involving any of the contact addresses
+	Contact core.Contact
+	// Statuses restricts results to transactions in any of these states
+	Statuses []core.TransactionStatus
+	// Label restricts results to transactions whose label contains this text , ignoring case
+	Label string
+	// Cursor returned along with the previous page , empty to start from the newest entry
+	Cursor string
+	// Limit maximum number of entries per page , unbounded if zero
+	Limit int
+}
+
+// Page of history entries matching a query
+type Page struct {
+	// Entries matching the query , newest first
+	Entries []*Entry
+	// NextCursor to request the following page , empty after the last one
+	NextCursor string
+}
+
+// SortEntries orders entries newest first
+func SortEntries(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entryBefore(entries[i], entries[j])
+	})
+}
+
+func entryBefore(a, b *Entry) bool {
+	if a.Timestamp != b.Timestamp {
+		return a.Timestamp > b.Timestamp
+	}
+	return a.TxnID < b.TxnID
+}
+
+// Run returns the page of entries matching query
+func (q Query) Run(entries []*Entry) (*Page, error) {
+	sorted := make([]*Entry, len(entries))
+	copy(sorted, entries)
+	SortEntries(sorted)
+
+	var after *Entry
+	if q.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(q.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	counterparties := q.counterparties()
+	q.Label = strings.ToLower(q.Label)
+	page := &Page{Entries: make([]*Entry, 0)}
+	for _, entry := range sorted {
+		if after != nil && !entryBefore(after, entry) {
+			continue
+		}
+		if !q.matches(entry, counterparties) {
+			continue
+		}
+		if q.Limit > 0 && len(page.Entries) == q.Limit {
+			page.NextCursor = encodeCursor(page.Entries[len(page.Entries)-1])
+			break
+		}
+		page.Entries = append(page.Entries, entry)
+	}
+	return page, nil
+}
+
+func (q Query) counterparties() map[string]struct{} {
+	addrs := make(map[string]struct{})
+	for _, addr := range q.Counterparties {
+		addrs[addr] = struct{}{}
+	}
+	if q.Contact != nil {
+		for _, addr := range q.Contact.GetAddresses() {
+			addrs[string(addr.GetValue())] = struct{}{}
+		}
+	}
+	return addrs
+}
+
+func (q Query) matches(entry *Entry, counterparties map[string]struct{}) bool {
+	if q.Since != 0 && entry.Timestamp < q.Since {
+		return false
+	}
+	if q.Until != 0 && entry.Timestamp > q.Until {
+		return false
+	}
+	if q.Direction != core.TxnDirectionAny && entry.Direction != q.Direction {
+		return false
+	}
+	if q.MinAmount != 0 && entry.Amount < q.MinAmount {
+		return false
+	}
+	if q.MaxAmount != 0 && entry.Amount > q.MaxAmount {
+		return false
+	}
+	if q.Label != "" && !strings.Contains(strings.ToLower(entry.Label), q.Label) {
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, status := range q.Statuses {
+			if entry.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(counterparties) > 0 {
+		for _, addr := range entry.Counterparties {
+			if _, isCounterparty := counterparties[addr]; isCounterparty {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func encodeCursor(entry *Entry) string {
+	raw := strconv.FormatUint(uint64(entry.Timestamp), 10) + ":" + entry.TxnID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*Entry, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	ts, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Entry{Timestamp: core.Timestamp(ts), TxnID: parts[1]}, nil
+}
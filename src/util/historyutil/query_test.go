@@ -0,0 +1,87 @@
+package historyutil
+
+import (
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/data"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestEntries(t *testing.T) []*Entry {
+	txns := []core.Transaction{
+		makeTxn("t1", 100, 1, []flow{{addrExt, 5 * droplets, 10}}, []flow{{addrA1, 5 * droplets, 8}}),
+		makeTxn("t2", 200, 1, []flow{{addrA1, 5 * droplets, 10}}, []flow{{addrExt, 1 * droplets, 2}, {addrA1, 4 * droplets, 6}}),
+		makeTxn("t3", 300, 1, []flow{{addrA1, 4 * droplets, 10}}, []flow{{addrA2, 4 * droplets, 8}}),
+		makeTxn("t4", 400, 1, []flow{{addrB1, 9 * droplets, 10}}, []flow{{addrExt, 9 * droplets, 8}}),
+	}
+	pending := makeTxn("t5", 500, 1, []flow{{addrExt, 2 * droplets, 10}}, []flow{{addrB1, 2 * droplets, 8}})
+	pending.status = core.TXN_STATUS_PENDING
+	txns = append(txns, pending)
+	entries, err := NewEntries(txns, testAddresses)
+	require.NoError(t, err)
+	entries[1].Label = "Rent March"
+	return entries
+}
+
+func entryIDs(entries []*Entry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.TxnID)
+	}
+	return ids
+}
+
+func TestQuery_Run(t *testing.T) {
+	entries := makeTestEntries(t)
+	contact := &data.Contact{}
+	contact.SetAddresses([]core.StringAddress{&data.Address{Value: []byte(addrExt), Coin: []byte("SKY")}})
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{name: "all", query: Query{}, want: []string{"t5", "t4", "t3", "t2", "t1"}},
+		{name: "date-range", query: Query{Since: 200, Until: 400}, want: []string{"t4", "t3", "t2"}},
+		{name: "sent", query: Query{Direction: core.TxnDirectionSent}, want: []string{"t4", "t2"}},
+		{name: "received", query: Query{Direction: core.TxnDirectionReceived}, want: []string{"t5", "t1"}},
+		{name: "internal", query: Query{Direction: core.TxnDirectionInternal}, want: []string{"t3"}},
+		{name: "min-amount", query: Query{MinAmount: 5 * droplets}, want: []string{"t4", "t1"}},
+		{name: "amount-range", query: Query{MinAmount: 1 * droplets, MaxAmount: 4 * droplets}, want: []string{"t5", "t3", "t2"}},
+		{name: "counterparty", query: Query{Counterparties: []string{addrExt}, Direction: core.TxnDirectionSent}, want: []string{"t4", "t2"}},
+		{name: "contact", query: Query{Contact: contact, Until: 100}, want: []string{"t1"}},
+		{name: "status", query: Query{Statuses: []core.TransactionStatus{core.TXN_STATUS_PENDING}}, want: []string{"t5"}},
+		{name: "label", query: Query{Label: "rent"}, want: []string{"t2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.query.Run(entries)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, entryIDs(page.Entries))
+			require.Empty(t, page.NextCursor)
+		})
+	}
+}
+
+func TestQuery_RunPagination(t *testing.T) {
+	entries := makeTestEntries(t)
+	query := Query{Limit: 2}
+	got := make([]string, 0)
+	pages := 0
+	for {
+		page, err := query.Run(entries)
+		require.NoError(t, err)
+		got = append(got, entryIDs(page.Entries)...)
+		pages++
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	require.Equal(t, []string{"t5", "t4", "t3", "t2", "t1"}, got)
+	require.Equal(t, 3, pages)
+
+	_, err := Query{Cursor: "not a cursor"}.Run(entries)
+	require.Equal(t, ErrInvalidCursor, err)
+}
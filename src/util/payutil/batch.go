@@ -0,0 +1,403 @@
+package payutil
+
+import (
+	"time"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+)
+
+// reservationTTL time inputs of batch transactions stay reserved after being signed
+const reservationTTL = 10 * time.Minute
+
+// PayoutStatus progress of a batch payout row
+type PayoutStatus int
+
+const (
+	// PayoutRejected row failed validation
+	PayoutRejected PayoutStatus = iota
+	// PayoutPlanned row assigned to a transaction not broadcast yet
+	PayoutPlanned
+	// PayoutBroadcast transaction paying the row was broadcast
+	PayoutBroadcast
+	// PayoutFailed transaction paying the row could not be signed or broadcast
+	PayoutFailed
+)
+
+// String returns a lowercase name of the status
+func (s PayoutStatus) String() string {
+	switch s {
+	case PayoutRejected:
+		return "rejected"
+	case PayoutPlanned:
+		return "planned"
+	case PayoutBroadcast:
+		return "broadcast"
+	case PayoutFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// PayoutResult outcome of a batch payout row
+type PayoutResult struct {
+	// Line position of the row in the source file
+	Line int
+	// Address receiving funds
+	Address string
+	// Label naming the recipient
+	Label string
+	// Status of the row
+	Status PayoutStatus
+	// Txn index of the transaction paying the row , -1 if rejected
+	Txn int
+	// TxnID hash of the broadcast transaction
+	TxnID string
+	// Err reason the row was rejected or failed
+	Err error
+}
+
+// Payout funds sent to a recipient , aggregating every row paying the same address
+type Payout struct {
+	// Address receiving funds
+	Address string
+	// Coins in the smallest coin unit
+	Coins uint64
+	// Hours coin hours , zero if selected automatically
+	Hours uint64
+	// Label of the first row naming the recipient
+	Label string
+	// Lines source rows paid by this payout
+	Lines []int
+}
+
+// BatchPayout transactions paying many recipients , split to respect node limits
+type BatchPayout struct {
+	// CoinTicker coin paid to recipients
+	CoinTicker string
+	// ManualHours whether coin hours were set for every payout , otherwise selected automatically
+	ManualHours bool
+	// MaxInputs inputs a transaction may spend , zero for no limit.
+	// Chunks whose transaction would spend more are split by Build.
+	MaxInputs int
+	// Chunks payouts grouped by transaction
+	Chunks [][]Payout
+	// Txns transactions paying each chunk , available after Build
+	Txns []core.Transaction
+	// Results outcome of every source row
+	Results []PayoutResult
+}
+
+// TxnSender sends a signed transaction spending outputs of a wallet , e.g. through the outbox
+type TxnSender func(walletID string, txn core.Transaction) error
+
+// NewBatchPayout validates rows , aggregates duplicate recipients and splits payouts
+// in transactions with at most maxOutputs recipients. Coin amounts must be multiple of coinStep.
+// Invalid rows are rejected and reported in Results , other rows are still paid.
+func NewBatchPayout(rows []PayoutRow, ticker string, maxOutputs int, coinStep uint64) (*BatchPayout, error) {
+	meta, isRegistered := local.LoadAltcoinManager().DescribeAltcoin(ticker)
+	if !isRegistered {
+		return nil, errors.ErrInvalidAltcoinTicker
+	}
+	if maxOutputs < 1 {
+		return nil, errors.ErrInvalidValue
+	}
+	b := &BatchPayout{CoinTicker: ticker, Results: make([]PayoutResult, len(rows))}
+	payouts := make([]Payout, 0, len(rows))
+	byAddress := make(map[string]int)
+	withHours, withoutHours := 0, 0
+	for i, row := range rows {
+		res := &b.Results[i]
+		*res = PayoutResult{Line: row.Line, Address: row.Address, Label: row.Label, Txn: -1}
+		coins, hours, err := validatePayoutRow(row, ticker, meta.Accuracy, coinStep)
+		if err != nil {
+			res.Status, res.Err = PayoutRejected, err
+			continue
+		}
+		res.Status = PayoutPlanned
+		if row.Hours == "" {
+			withoutHours++
+		} else {
+			withHours++
+		}
+		idx, isDuplicate := byAddress[row.Address]
+		if !isDuplicate {
+			idx = len(payouts)
+			byAddress[row.Address] = idx
+			payouts = append(payouts, Payout{Address: row.Address})
+		}
+		p := &payouts[idx]
+		p.Coins += coins
+		p.Hours += hours
+		p.Lines = append(p.Lines, row.Line)
+		if p.Label == "" {
+			p.Label = row.Label
+		}
+	}
+	if withHours > 0 && withoutHours > 0 {
+		return nil, errors.ErrPayoutHoursMismatch
+	}
+	b.ManualHours = withHours > 0
+
+	for start := 0; start < len(payouts); start += maxOutputs {
+		end := start + maxOutputs
+		if end > len(payouts) {
+			end = len(payouts)
+		}
+		b.Chunks = append(b.Chunks, payouts[start:end])
+	}
+	b.assignTxns()
+	return b, nil
+}
+
+// assignTxns sets the index of the transaction paying every planned row
+func (b *BatchPayout) assignTxns() {
+	txnByLine := make(map[int]int)
+	for i, chunk := range b.Chunks {
+		for _, p := range chunk {
+			for _, line := range p.Lines {
+				txnByLine[line] = i
+			}
+		}
+	}
+	for i := range b.Results {
+		if b.Results[i].Status == PayoutPlanned {
+			b.Results[i].Txn = txnByLine[b.Results[i].Line]
+		}
+	}
+}
+
+// validatePayoutRow checks address and amounts of a row , returning coins and hours to send
+func validatePayoutRow(row PayoutRow, ticker string, accuracy int32, coinStep uint64) (uint64, uint64, error) {
+	if _, err := util.AddressFromString(row.Address, ticker); err != nil {
+		return 0, 0, err
+	}
+	coins, err := ParseAmount(row.Coins, accuracy)
+	if err != nil {
+		return 0, 0, err
+	}
+	if coins == 0 {
+		return 0, 0, errors.ErrInvalidValue
+	}
+	if coinStep > 0 && coins%coinStep != 0 {
+		return 0, 0, errors.ErrInvalidAmountPrecision
+	}
+	var hours uint64
+	if row.Hours != "" {
+		if hours, err = ParseAmount(row.Hours, 0); err != nil {
+			return 0, 0, err
+		}
+	}
+	return coins, hours, nil
+}
+
+// Total returns coins and coin hours sent by the batch
+func (b *BatchPayout) Total() (coins, hours uint64) {
+	for _, chunk := range b.Chunks {
+		for _, p := range chunk {
+			coins += p.Coins
+			hours += p.Hours
+		}
+	}
+	return
+}
+
+// Build creates the unsigned transactions of the batch out of wallet funds , so they can be previewed.
+// Inputs spent by a transaction are excluded from coin selection for the following ones.
+// Chunks needing more than MaxInputs inputs are split in halves , reassigning rows to transactions.
+func (b *BatchPayout) Build(wlt core.Wallet, options core.KeyValueStore) error {
+	if len(b.Chunks) == 0 {
+		return errors.ErrNoValidPayouts
+	}
+	if options == nil {
+		options = util.NewKeyValueMap()
+	}
+	if b.ManualHours {
+		options.SetValue("CoinHoursSelectionType", "manual")
+	} else {
+		options.SetValue("CoinHoursSelectionType", "auto")
+	}
+	if _, isString := options.GetValue("BurnFactor").(string); !isString {
+		options.SetValue("BurnFactor", "0.5")
+	}
+	from := make([]core.Address, 0)
+	addrIter, err := wlt.GetLoadedAddresses()
+	if err != nil {
+		return err
+	}
+	for addrIter.Next() {
+		from = append(from, addrIter.Value())
+	}
+
+	base, _ := options.GetValue(core.StrCoinControl).(core.CoinControl)
+	coinControl := &batchCoinControl{base: base, walletID: wlt.GetId(), spent: make(map[string]struct{})}
+	options.SetValue(core.StrCoinControl, coinControl)
+	defer options.SetValue(core.StrCoinControl, base)
+
+	chunks := b.Chunks
+	txns := make([]core.Transaction, 0, len(chunks))
+	for i := 0; i < len(chunks); i++ {
+		chunk := chunks[i]
+		outs := make([]core.TransactionOutput, 0, len(chunk))
+		for _, p := range chunk {
+			addr, err := util.AddressFromString(p.Address, b.CoinTicker)
+			if err != nil {
+				return err
+			}
+			out := util.NewGenericOutput(addr, "")
+			out.SetCoins(b.CoinTicker, p.Coins)
+			if b.ManualHours {
+				out.SetCoins(params.CoinHoursTicker, p.Hours)
+			}
+			outs = append(outs, &out)
+		}
+		txn, err := wlt.SendFromAddress(from, outs, nil, options)
+		if err != nil {
+			return err
+		}
+		if b.MaxInputs > 0 && len(txn.GetInputs()) > b.MaxInputs {
+			if len(chunk) == 1 {
+				return errors.ErrTxnTooLarge
+			}
+			half := len(chunk) / 2
+			split := make([][]Payout, 0, len(chunks)+1)
+			split = append(split, chunks[:i]...)
+			split = append(split, chunk[:half], chunk[half:])
+			chunks = append(split, chunks[i+1:]...)
+			i--
+			continue
+		}
+		for _, in := range txn.GetInputs() {
+			coinControl.spent[in.GetId()] = struct{}{}
+		}
+		txns = append(txns, txn)
+	}
+	b.Chunks = chunks
+	b.Txns = txns
+	b.assignTxns()
+	return nil
+}
+
+// Execute signs and sends every transaction built for the batch , updating results of paid rows.
+// Inputs of all transactions are reserved in coin control , if any , before signing the first one
+// and released for those failing. A transaction failing does not prevent the others from being sent
+// since they spend different inputs.
+func (b *BatchPayout) Execute(wlt core.Wallet, signer core.TxnSigner, pwd core.PasswordReader, coinControl core.CoinControl, send TxnSender) error {
+	if len(b.Txns) != len(b.Chunks) || len(b.Txns) == 0 {
+		return errors.ErrInvalidTxn
+	}
+	inputs := make([][]string, len(b.Txns))
+	for i, txn := range b.Txns {
+		for _, in := range txn.GetInputs() {
+			inputs[i] = append(inputs[i], in.GetId())
+		}
+		if coinControl == nil {
+			continue
+		}
+		if err := coinControl.ReserveOutputs(wlt.GetId(), inputs[i], reservationTTL); err != nil {
+			for _, reserved := range inputs[:i] {
+				_ = coinControl.ReleaseOutputs(wlt.GetId(), reserved)
+			}
+			return err
+		}
+	}
+	for i, txn := range b.Txns {
+		signed, err := wlt.Sign(txn, signer, pwd, nil)
+		if err == nil {
+			b.Txns[i] = signed
+			err = send(wlt.GetId(), signed)
+		}
+		if err != nil && coinControl != nil {
+			// Reservations expire anyway if they can not be released
+			_ = coinControl.ReleaseOutputs(wlt.GetId(), inputs[i])
+		}
+		for j := range b.Results {
+			res := &b.Results[j]
+			if res.Status != PayoutPlanned || res.Txn != i {
+				continue
+			}
+			if err != nil {
+				res.Status, res.Err = PayoutFailed, err
+			} else {
+				res.Status, res.TxnID = PayoutBroadcast, signed.GetId()
+			}
+		}
+	}
+	return nil
+}
+
+// batchCoinControl extends the coin control set in transaction options , if any ,
+// excluding outputs spent by transactions previously built for the batch
+type batchCoinControl struct {
+	base     core.CoinControl
+	walletID string
+	spent    map[string]struct{}
+}
+
+// FreezeOutput delegates to the base coin control
+func (cc *batchCoinControl) FreezeOutput(walletID, outputID string) error {
+	if cc.base == nil {
+		return errors.ErrNotImplemented
+	}
+	return cc.base.FreezeOutput(walletID, outputID)
+}
+
+// ThawOutput delegates to the base coin control
+func (cc *batchCoinControl) ThawOutput(walletID, outputID string) error {
+	if cc.base == nil {
+		return errors.ErrNotImplemented
+	}
+	return cc.base.ThawOutput(walletID, outputID)
+}
+
+// ReserveOutputs delegates to the base coin control
+func (cc *batchCoinControl) ReserveOutputs(walletID string, outputIDs []string, ttl time.Duration) error {
+	if cc.base == nil {
+		return errors.ErrNotImplemented
+	}
+	return cc.base.ReserveOutputs(walletID, outputIDs, ttl)
+}
+
+// ReleaseOutputs delegates to the base coin control
+func (cc *batchCoinControl) ReleaseOutputs(walletID string, outputIDs []string) error {
+	if cc.base == nil {
+		return errors.ErrNotImplemented
+	}
+	return cc.base.ReleaseOutputs(walletID, outputIDs)
+}
+
+// GetOutputState reports outputs spent by the batch as reserved
+func (cc *batchCoinControl) GetOutputState(walletID, outputID string) (core.OutputState, error) {
+	if _, isSpent := cc.spent[outputID]; isSpent && walletID == cc.walletID {
+		return core.OutputReserved, nil
+	}
+	if cc.base == nil {
+		return core.OutputSpendable, nil
+	}
+	return cc.base.GetOutputState(walletID, outputID)
+}
+
+// ListExcludedOutputs adds outputs spent by the batch to those excluded by the base coin control
+func (cc *batchCoinControl) ListExcludedOutputs(walletID string) (map[string]core.OutputState, error) {
+	excluded := make(map[string]core.OutputState)
+	if cc.base != nil {
+		outs, err := cc.base.ListExcludedOutputs(walletID)
+		if err != nil {
+			return nil, err
+		}
+		for outID, state := range outs {
+			excluded[outID] = state
+		}
+	}
+	if walletID == cc.walletID {
+		for outID := range cc.spent {
+			excluded[outID] = core.OutputReserved
+		}
+	}
+	return excluded, nil
+}
+
+var _ core.CoinControl = &batchCoinControl{}
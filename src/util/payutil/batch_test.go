@@ -0,0 +1,257 @@
+package payutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/mocks"
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	payAddr1 = "9BSEAEE3XGtQ2X43BCT2XCYgheGLQQigEG"
+	payAddr2 = "25MP2EHPZyfEqUnXfapgUj1TQfZVXdn5RrZ"
+	payAddr3 = "2TFC2Ktc6Y3UAUqo7WGA55X6mqoKZRaFp9s"
+)
+
+func TestReadPayoutsCSV(t *testing.T) {
+	rows, err := ReadPayoutsCSV(strings.NewReader(payAddr1 + ",1.5\n\n  \n" + payAddr2 + ", 2 ,10,Bob\n"))
+	require.NoError(t, err)
+	require.Equal(t, []PayoutRow{
+		{Line: 1, Address: payAddr1, Coins: "1.5"},
+		{Line: 3, Address: payAddr2, Coins: "2", Hours: "10", Label: "Bob"},
+	}, rows)
+
+	rows, err = ReadPayoutsCSV(strings.NewReader("label,Amount,Address\nAlice,3," + payAddr3 + "\n"))
+	require.NoError(t, err)
+	require.Equal(t, []PayoutRow{{Line: 2, Address: payAddr3, Coins: "3", Label: "Alice"}}, rows)
+
+	_, err = ReadPayoutsCSV(strings.NewReader("\"unterminated"))
+	require.Equal(t, errors.ErrInvalidPayoutFile, err)
+}
+
+func TestReadPayoutsJSON(t *testing.T) {
+	rows, err := ReadPayouts(strings.NewReader(`[
+		{"address": "`+payAddr1+`", "sky": 0.000001},
+		{"address": "`+payAddr2+`", "sky": "2.5", "hours": 7, "label": "Bob"}
+	]`), FormatJSON)
+	require.NoError(t, err)
+	require.Equal(t, []PayoutRow{
+		{Line: 1, Address: payAddr1, Coins: "0.000001"},
+		{Line: 2, Address: payAddr2, Coins: "2.5", Hours: "7", Label: "Bob"},
+	}, rows)
+
+	_, err = ReadPayoutsJSON(strings.NewReader(`{"address": "x"}`))
+	require.Equal(t, errors.ErrInvalidPayoutFile, err)
+	_, err = ReadPayouts(strings.NewReader(""), "xml")
+	require.Equal(t, errors.ErrInvalidValue, err)
+}
+
+func TestNewBatchPayout(t *testing.T) {
+	rows := []PayoutRow{
+		{Line: 1, Address: payAddr1, Coins: "1", Label: "Alice"},
+		{Line: 2, Address: "invalid", Coins: "1"},
+		{Line: 3, Address: payAddr2, Coins: "0.0001"},
+		{Line: 4, Address: payAddr2, Coins: "2"},
+		{Line: 5, Address: payAddr1, Coins: "0.5", Label: "Alice again"},
+		{Line: 6, Address: payAddr3, Coins: "0"},
+		{Line: 7, Address: payAddr3, Coins: "3"},
+	}
+	b, err := NewBatchPayout(rows, params.SkycoinTicker, 2, skycoin.DropletPrecision())
+	require.NoError(t, err)
+	require.False(t, b.ManualHours)
+	require.Equal(t, [][]Payout{
+		{
+			{Address: payAddr1, Coins: 1500000, Label: "Alice", Lines: []int{1, 5}},
+			{Address: payAddr2, Coins: 2000000, Lines: []int{4}},
+		},
+		{
+			{Address: payAddr3, Coins: 3000000, Lines: []int{7}},
+		},
+	}, b.Chunks)
+	coins, hours := b.Total()
+	require.Equal(t, uint64(6500000), coins)
+	require.Equal(t, uint64(0), hours)
+
+	statuses := make([]PayoutStatus, len(b.Results))
+	txns := make([]int, len(b.Results))
+	for i, res := range b.Results {
+		require.Equal(t, rows[i].Line, res.Line)
+		statuses[i], txns[i] = res.Status, res.Txn
+	}
+	require.Equal(t, []PayoutStatus{PayoutPlanned, PayoutRejected, PayoutRejected, PayoutPlanned, PayoutPlanned, PayoutRejected, PayoutPlanned}, statuses)
+	require.Equal(t, []int{0, -1, -1, 0, 0, -1, 1}, txns)
+	require.Equal(t, errors.ErrInvalidAmountPrecision, b.Results[2].Err)
+	require.Equal(t, errors.ErrInvalidValue, b.Results[5].Err)
+
+	_, err = NewBatchPayout([]PayoutRow{
+		{Line: 1, Address: payAddr1, Coins: "1", Hours: "5"},
+		{Line: 2, Address: payAddr2, Coins: "1"},
+	}, params.SkycoinTicker, 10, 0)
+	require.Equal(t, errors.ErrPayoutHoursMismatch, err)
+	_, err = NewBatchPayout(rows, params.SkycoinTicker, 0, 0)
+	require.Equal(t, errors.ErrInvalidValue, err)
+	_, err = NewBatchPayout(rows, "XYZ", 1, 0)
+	require.Equal(t, errors.ErrInvalidAltcoinTicker, err)
+
+	b, err = NewBatchPayout([]PayoutRow{{Line: 1, Address: "invalid", Coins: "1"}}, params.SkycoinTicker, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, errors.ErrNoValidPayouts, b.Build(new(mocks.Wallet), nil))
+}
+
+// batchInput transaction input identified by the hash of the output it spends
+type batchInput struct {
+	core.TransactionInput
+	id string
+}
+
+func (in *batchInput) GetId() string {
+	return in.id
+}
+
+func mockBatchTxn(id string, inputs ...string) *mocks.Transaction {
+	ins := make([]core.TransactionInput, 0, len(inputs))
+	for _, inID := range inputs {
+		ins = append(ins, &batchInput{id: inID})
+	}
+	txn := new(mocks.Transaction)
+	txn.On("GetId").Return(id)
+	txn.On("GetInputs").Return(ins)
+	return txn
+}
+
+func TestBatchPayout_BuildAndExecute(t *testing.T) {
+	b, err := NewBatchPayout([]PayoutRow{
+		{Line: 1, Address: payAddr1, Coins: "1", Hours: "2"},
+		{Line: 2, Address: payAddr2, Coins: "2", Hours: "3"},
+		{Line: 3, Address: payAddr3, Coins: "3", Hours: "4"},
+	}, params.SkycoinTicker, 2, 0)
+	require.NoError(t, err)
+	require.True(t, b.ManualHours)
+
+	addr := new(mocks.Address)
+	addr.On("String").Return(payAddr1)
+	wlt := new(mocks.Wallet)
+	wlt.On("GetId").Return("batch.wlt")
+	wlt.On("GetLoadedAddresses").Return(skycoin.NewSkycoinAddressIterator([]core.Address{addr}), nil)
+
+	userControl := new(mocks.CoinControl)
+	userControl.On("ListExcludedOutputs", "batch.wlt").Return(map[string]core.OutputState{"frozen": core.OutputFrozen}, nil)
+	options := util.NewKeyValueMap()
+	options.SetValue(core.StrCoinControl, userControl)
+
+	txn1, txn2 := mockBatchTxn("txn1", "in1", "in2"), mockBatchTxn("txn2", "in3")
+	var excludedByTxn [][]string
+	sendFrom := func(outputs int, txn core.Transaction) {
+		wlt.On("SendFromAddress", []core.Address{addr}, mock.MatchedBy(func(to []core.TransactionOutput) bool {
+			return len(to) == outputs
+		}), nil, options).Return(func(from []core.Address, to []core.TransactionOutput, change core.Address, opt core.KeyValueStore) core.Transaction {
+			require.Equal(t, "manual", opt.GetValue("CoinHoursSelectionType"))
+			hours, err := to[0].GetCoins(params.CoinHoursTicker)
+			require.NoError(t, err)
+			require.NotZero(t, hours)
+			excluded, err := opt.GetValue(core.StrCoinControl).(core.CoinControl).ListExcludedOutputs("batch.wlt")
+			require.NoError(t, err)
+			ids := make([]string, 0, len(excluded))
+			for _, id := range []string{"frozen", "in1", "in2", "in3"} {
+				if _, isExcluded := excluded[id]; isExcluded {
+					ids = append(ids, id)
+				}
+			}
+			excludedByTxn = append(excludedByTxn, ids)
+			return txn
+		}, nil).Once()
+	}
+	sendFrom(2, txn1)
+	sendFrom(1, txn2)
+	require.NoError(t, b.Build(wlt, options))
+	require.Equal(t, []core.Transaction{txn1, txn2}, b.Txns)
+	require.Equal(t, [][]string{{"frozen"}, {"frozen", "in1", "in2"}}, excludedByTxn)
+	// Coin control set by the caller is restored
+	require.Equal(t, userControl, options.GetValue(core.StrCoinControl))
+
+	signed1, signed2 := mockBatchTxn("signed1"), mockBatchTxn("signed2")
+	wlt.On("Sign", txn1, nil, mock.Anything, []string(nil)).Return(signed1, nil)
+	wlt.On("Sign", txn2, nil, mock.Anything, []string(nil)).Return(signed2, nil)
+	send := func(walletID string, txn core.Transaction) error {
+		require.Equal(t, "batch.wlt", walletID)
+		if txn == signed2 {
+			return errors.ErrTxnTooLarge
+		}
+		return nil
+	}
+	// Inputs are reserved while sending and released if the transaction fails
+	userControl.On("ReserveOutputs", "batch.wlt", []string{"in1", "in2"}, mock.Anything).Return(nil).Once()
+	userControl.On("ReserveOutputs", "batch.wlt", []string{"in3"}, mock.Anything).Return(nil).Once()
+	userControl.On("ReleaseOutputs", "batch.wlt", []string{"in3"}).Return(nil).Once()
+	require.NoError(t, b.Execute(wlt, nil, util.EmptyPassword, userControl, send))
+	userControl.AssertExpectations(t)
+
+	require.Equal(t, PayoutBroadcast, b.Results[0].Status)
+	require.Equal(t, "signed1", b.Results[0].TxnID)
+	require.Equal(t, PayoutBroadcast, b.Results[1].Status)
+	require.Equal(t, PayoutFailed, b.Results[2].Status)
+	require.Equal(t, errors.ErrTxnTooLarge, b.Results[2].Err)
+
+	var report bytes.Buffer
+	require.NoError(t, WriteReportCSV(&report, b.Results))
+	require.Equal(t, "line,address,label,status,txid,error\n"+
+		"1,"+payAddr1+",,broadcast,signed1,\n"+
+		"2,"+payAddr2+",,broadcast,signed1,\n"+
+		"3,"+payAddr3+",,failed,,"+errors.ErrTxnTooLarge.Error()+"\n", report.String())
+}
+
+func TestBatchPayout_BuildSplitsChunks(t *testing.T) {
+	b, err := NewBatchPayout([]PayoutRow{
+		{Line: 1, Address: payAddr1, Coins: "1"},
+		{Line: 2, Address: payAddr2, Coins: "2"},
+		{Line: 3, Address: payAddr3, Coins: "3"},
+	}, params.SkycoinTicker, 3, 0)
+	require.NoError(t, err)
+	b.MaxInputs = 2
+	require.Len(t, b.Chunks, 1)
+
+	addr := new(mocks.Address)
+	addr.On("String").Return(payAddr1)
+	var wlt *mocks.Wallet
+	newWallet := func() {
+		wlt = new(mocks.Wallet)
+		wlt.On("GetId").Return("batch.wlt")
+		wlt.On("GetLoadedAddresses").Return(skycoin.NewSkycoinAddressIterator([]core.Address{addr}), nil)
+	}
+	sendFrom := func(outputs int, txn core.Transaction) {
+		wlt.On("SendFromAddress", []core.Address{addr}, mock.MatchedBy(func(to []core.TransactionOutput) bool {
+			return len(to) == outputs
+		}), nil, mock.Anything).Return(txn, nil).Once()
+	}
+	newWallet()
+	// Too many inputs to pay three recipients at once , the first one is paid alone
+	txnSmall, txnPair := mockBatchTxn("small", "in1"), mockBatchTxn("pair", "in2", "in3")
+	sendFrom(3, mockBatchTxn("large", "in1", "in2", "in3"))
+	sendFrom(1, txnSmall)
+	sendFrom(2, txnPair)
+	require.NoError(t, b.Build(wlt, nil))
+	require.Equal(t, []core.Transaction{txnSmall, txnPair}, b.Txns)
+	require.Len(t, b.Chunks, 2)
+	require.Equal(t, payAddr1, b.Chunks[0][0].Address)
+	txns := make([]int, len(b.Results))
+	for i, res := range b.Results {
+		txns[i] = res.Txn
+	}
+	require.Equal(t, []int{0, 1, 1}, txns)
+
+	// A single recipient needing too many inputs can not be paid
+	b, err = NewBatchPayout([]PayoutRow{{Line: 1, Address: payAddr1, Coins: "1"}}, params.SkycoinTicker, 3, 0)
+	require.NoError(t, err)
+	b.MaxInputs = 2
+	newWallet()
+	sendFrom(1, mockBatchTxn("large", "in1", "in2", "in3"))
+	require.Equal(t, errors.ErrTxnTooLarge, b.Build(wlt, nil))
+}
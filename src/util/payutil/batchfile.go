@@ -0,0 +1,181 @@
+package payutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+const (
+	// FormatCSV comma separated values , one row per recipient
+	FormatCSV = "csv"
+	// FormatJSON array of recipient objects
+	FormatJSON = "json"
+)
+
+// payoutColumns known CSV columns , positional order used by files without header
+var payoutColumns = []string{"address", "sky", "hours", "label"}
+
+// PayoutRow recipient of a batch payout as written in the source file
+type PayoutRow struct {
+	// Line position of the row in the source file , starting at 1 and skipping blank lines
+	Line int
+	// Address receiving funds
+	Address string
+	// Coins amount to send , decimal
+	Coins string
+	// Hours coin hours to send , empty for automatic selection
+	Hours string
+	// Label naming the recipient
+	Label string
+}
+
+// ReadPayouts parses recipients of a batch payout in the given format
+func ReadPayouts(r io.Reader, format string) ([]PayoutRow, error) {
+	switch format {
+	case FormatCSV:
+		return ReadPayoutsCSV(r)
+	case FormatJSON:
+		return ReadPayoutsJSON(r)
+	}
+	return nil, errors.ErrInvalidValue
+}
+
+// ReadPayoutsCSV parses rows of address , SKY , optional hours and optional label.
+// A header row naming these columns may set a different order.
+func ReadPayoutsCSV(r io.Reader) ([]PayoutRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.ErrInvalidPayoutFile
+	}
+	columns := make(map[string]int, len(payoutColumns))
+	for i, name := range payoutColumns {
+		columns[name] = i
+	}
+	first := 0
+	if len(records) > 0 && isPayoutHeader(records[0]) {
+		columns = make(map[string]int, len(payoutColumns))
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		if _, hasAmount := columns["sky"]; !hasAmount {
+			if i, hasAmount := columns["amount"]; hasAmount {
+				columns["sky"] = i
+			}
+		}
+		first = 1
+	}
+	rows := make([]PayoutRow, 0, len(records))
+	for i, record := range records[first:] {
+		field := func(name string) string {
+			if idx, hasColumn := columns[name]; hasColumn && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+		if len(record) == 1 && field("address") == "" {
+			// Line with whitespace only
+			continue
+		}
+		rows = append(rows, PayoutRow{
+			Line:    first + i + 1,
+			Address: field("address"),
+			Coins:   field("sky"),
+			Hours:   field("hours"),
+			Label:   field("label"),
+		})
+	}
+	return rows, nil
+}
+
+// isPayoutHeader tells whether a CSV record names payout columns
+func isPayoutHeader(record []string) bool {
+	for _, name := range record {
+		if strings.EqualFold(strings.TrimSpace(name), "address") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonPayout recipient object in JSON payout files , amounts as numbers or strings
+type jsonPayout struct {
+	Address string      `json:"address"`
+	Sky     jsonDecimal `json:"sky"`
+	Hours   jsonDecimal `json:"hours"`
+	Label   string      `json:"label"`
+}
+
+// jsonDecimal decimal number written either as JSON number or string
+type jsonDecimal string
+
+// UnmarshalJSON keeps the literal digits of numbers , avoiding float rounding
+func (d *jsonDecimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		str, err := strconv.Unquote(string(data))
+		if err != nil {
+			return err
+		}
+		*d = jsonDecimal(strings.TrimSpace(str))
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+	*d = jsonDecimal(num)
+	return nil
+}
+
+// ReadPayoutsJSON parses an array of objects with address , sky , optional hours and optional label
+func ReadPayoutsJSON(r io.Reader) ([]PayoutRow, error) {
+	var payouts []jsonPayout
+	if err := json.NewDecoder(r).Decode(&payouts); err != nil {
+		return nil, errors.ErrInvalidPayoutFile
+	}
+	rows := make([]PayoutRow, 0, len(payouts))
+	for i, p := range payouts {
+		rows = append(rows, PayoutRow{
+			Line:    i + 1,
+			Address: strings.TrimSpace(p.Address),
+			Coins:   string(p.Sky),
+			Hours:   string(p.Hours),
+			Label:   p.Label,
+		})
+	}
+	return rows, nil
+}
+
+// reportHeader columns written by WriteReportCSV
+var reportHeader = []string{"line", "address", "label", "status", "txid", "error"}
+
+// WriteReportCSV writes the outcome of each row of a batch payout
+func WriteReportCSV(w io.Writer, results []PayoutResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportHeader); err != nil {
+		return err
+	}
+	for _, res := range results {
+		errMsg := ""
+		if res.Err != nil {
+			errMsg = res.Err.Error()
+		}
+		record := []string{strconv.Itoa(res.Line), res.Address, res.Label, res.Status.String(), res.TxnID, errMsg}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
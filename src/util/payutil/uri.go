@@ -0,0 +1,210 @@
+package payutil
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/core"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/util"
+	"github.com/fibercrypto/fibercryptowallet/src/util/qrutil"
+)
+
+const (
+	paramAmount  = "amount"
+	paramHours   = "hours"
+	paramLabel   = "label"
+	paramMessage = "message"
+	// requiredPrefix marks parameters that must be understood , as in BIP21
+	requiredPrefix = "req-"
+)
+
+// PaymentRequest funds requested to an address , encoded as a URI in the style of BIP21
+// e.g. skycoin:2JJ8pgq8EDAnrzf9xxBJapE2qkYLefW4uF8?amount=12.5&hours=10&label=Shop
+type PaymentRequest struct {
+	// CoinTicker identifies the coin , the URI scheme is its lowercase name
+	CoinTicker string
+	// Address receiving funds
+	Address string
+	// Amount requested coins , in the smallest coin unit
+	Amount uint64
+	// Hours requested coin hours
+	Hours uint64
+	// Label name of the recipient
+	Label string
+	// Message describing the payment
+	Message string
+}
+
+// EncodeURI validates a payment request and renders it as a URI
+func EncodeURI(req PaymentRequest) (string, error) {
+	meta, isRegistered := local.LoadAltcoinManager().DescribeAltcoin(req.CoinTicker)
+	if !isRegistered {
+		return "", errors.ErrInvalidAltcoinTicker
+	}
+	if _, err := util.AddressFromString(req.Address, req.CoinTicker); err != nil {
+		return "", err
+	}
+	query := make([]string, 0, 4)
+	if req.Amount > 0 {
+		query = append(query, paramAmount+"="+FormatAmount(req.Amount, meta.Accuracy))
+	}
+	if req.Hours > 0 {
+		query = append(query, paramHours+"="+strconv.FormatUint(req.Hours, 10))
+	}
+	if req.Label != "" {
+		query = append(query, paramLabel+"="+escapeParam(req.Label))
+	}
+	if req.Message != "" {
+		query = append(query, paramMessage+"="+escapeParam(req.Message))
+	}
+	uri := uriScheme(meta) + ":" + req.Address
+	if len(query) > 0 {
+		uri += "?" + strings.Join(query, "&")
+	}
+	return uri, nil
+}
+
+// ParseURI decodes a payment request URI for any registered coin
+func ParseURI(uri string) (*PaymentRequest, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil || u.Opaque == "" {
+		return nil, errors.ErrInvalidPaymentURI
+	}
+	meta, isKnown := lookupScheme(u.Scheme)
+	if !isKnown {
+		return nil, errors.ErrInvalidPaymentURI
+	}
+	req := &PaymentRequest{CoinTicker: meta.Ticker, Address: u.Opaque}
+	if _, err := util.AddressFromString(req.Address, req.CoinTicker); err != nil {
+		return nil, err
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, errors.ErrInvalidPaymentURI
+	}
+	for key, values := range query {
+		if len(values) != 1 {
+			return nil, errors.ErrInvalidPaymentURI
+		}
+		value := values[0]
+		switch key {
+		case paramAmount:
+			if req.Amount, err = ParseAmount(value, meta.Accuracy); err != nil {
+				return nil, err
+			}
+		case paramHours:
+			if req.Hours, err = ParseAmount(value, 0); err != nil {
+				return nil, err
+			}
+		case paramLabel:
+			req.Label = value
+		case paramMessage:
+			req.Message = value
+		default:
+			if strings.HasPrefix(key, requiredPrefix) {
+				return nil, errors.ErrInvalidPaymentURI
+			}
+		}
+	}
+	return req, nil
+}
+
+// Destination returns the transaction output paying this request , ready for Wallet.Transfer
+func (req *PaymentRequest) Destination() (core.TransactionOutput, error) {
+	addr, err := util.AddressFromString(req.Address, req.CoinTicker)
+	if err != nil {
+		return nil, err
+	}
+	out := util.NewGenericOutput(addr, "")
+	out.SetCoins(req.CoinTicker, req.Amount)
+	if req.CoinTicker == params.SkycoinTicker {
+		out.SetCoins(params.CoinHoursTicker, req.Hours)
+	}
+	return &out, nil
+}
+
+// EncodeQR renders the payment request URI as a QR code
+func EncodeQR(req PaymentRequest, level qrutil.Level) (*qrutil.Code, error) {
+	uri, err := EncodeURI(req)
+	if err != nil {
+		return nil, err
+	}
+	return qrutil.Encode([]byte(uri), level)
+}
+
+// ParseAmount parses a decimal amount into the smallest coin unit given the coin accuracy
+func ParseAmount(value string, accuracy int32) (uint64, error) {
+	parts := strings.SplitN(value, ".", 2)
+	whole, fraction := parts[0], ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return 0, errors.ErrInvalidValue
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(accuracy) {
+		return 0, errors.ErrInvalidAmountPrecision
+	}
+	digits := strings.TrimLeft(whole+fraction+strings.Repeat("0", int(accuracy)-len(fraction)), "0")
+	if digits == "" {
+		return 0, nil
+	}
+	amount, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, errors.ErrInvalidValue
+	}
+	return amount, nil
+}
+
+// FormatAmount renders an amount in the smallest coin unit as a plain decimal number
+func FormatAmount(amount uint64, accuracy int32) string {
+	digits := strconv.FormatUint(amount, 10)
+	if accuracy <= 0 {
+		return digits
+	}
+	if pad := int(accuracy) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	whole, fraction := digits[:len(digits)-int(accuracy)], strings.TrimRight(digits[len(digits)-int(accuracy):], "0")
+	if fraction == "" {
+		return whole
+	}
+	return whole + "." + fraction
+}
+
+// uriScheme returns the URI scheme of a coin
+func uriScheme(meta core.AltcoinMetadata) string {
+	return strings.ToLower(strings.Replace(meta.Name, " ", "", -1))
+}
+
+// lookupScheme finds the registered coin identified by a URI scheme
+func lookupScheme(scheme string) (core.AltcoinMetadata, bool) {
+	scheme = strings.ToLower(scheme)
+	for _, plugin := range local.LoadAltcoinManager().ListRegisteredPlugins() {
+		for _, meta := range plugin.ListSupportedAltcoins() {
+			if uriScheme(meta) == scheme {
+				return meta, true
+			}
+		}
+	}
+	return core.AltcoinMetadata{}, false
+}
+
+// escapeParam escapes a query value , spaces as %20 rather than +
+func escapeParam(value string) string {
+	return strings.Replace(url.QueryEscape(value), "+", "%20", -1)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package payutil
+
+import (
+	"os"
+	"testing"
+
+	skycoin "github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/models"
+	"github.com/fibercrypto/fibercryptowallet/src/coin/skycoin/params"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	local "github.com/fibercrypto/fibercryptowallet/src/main"
+	"github.com/fibercrypto/fibercryptowallet/src/util/qrutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testAddr = "2JJ8pgq8EDAnrzf9xxBJapE2qkYLefW4uF8"
+
+func TestMain(m *testing.M) {
+	local.LoadAltcoinManager().RegisterPlugin(skycoin.NewSkyFiberPlugin(skycoin.SkycoinMainNetParams))
+	os.Exit(m.Run())
+}
+
+func TestEncodeURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     PaymentRequest
+		uri     string
+		wantErr bool
+	}{
+		{
+			name: "address only",
+			req:  PaymentRequest{CoinTicker: params.SkycoinTicker, Address: testAddr},
+			uri:  "skycoin:" + testAddr,
+		},
+		{
+			name: "all fields",
+			req:  PaymentRequest{CoinTicker: params.SkycoinTicker, Address: testAddr, Amount: 12500000, Hours: 10, Label: "Coffee shop", Message: "Order #42&more"},
+			uri:  "skycoin:" + testAddr + "?amount=12.5&hours=10&label=Coffee%20shop&message=Order%20%2342%26more",
+		},
+		{
+			name:    "invalid address",
+			req:     PaymentRequest{CoinTicker: params.SkycoinTicker, Address: "invalid"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown coin",
+			req:     PaymentRequest{CoinTicker: "XYZ", Address: testAddr},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := EncodeURI(tt.req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.uri, uri)
+
+			req, err := ParseURI(uri)
+			require.NoError(t, err)
+			require.Equal(t, tt.req, *req)
+		})
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	req, err := ParseURI("Skycoin:" + testAddr + "?amount=0.000001&label=A+B&ignored=1")
+	require.NoError(t, err)
+	require.Equal(t, params.SkycoinTicker, req.CoinTicker)
+	require.Equal(t, uint64(1), req.Amount)
+	require.Equal(t, "A B", req.Label)
+
+	for _, uri := range []string{
+		"bitcoin:" + testAddr,
+		"skycoin:",
+		"skycoin://" + testAddr,
+		"skycoin:" + testAddr + "?req-unknown=1",
+		"skycoin:" + testAddr + "?amount=1&amount=2",
+	} {
+		_, err := ParseURI(uri)
+		require.Equal(t, errors.ErrInvalidPaymentURI, err, uri)
+	}
+	_, err = ParseURI("skycoin:" + testAddr + "?amount=1.0000001")
+	require.Equal(t, errors.ErrInvalidAmountPrecision, err)
+	_, err = ParseURI("skycoin:" + testAddr + "?hours=1.5")
+	require.Equal(t, errors.ErrInvalidAmountPrecision, err)
+	_, err = ParseURI("skycoin:" + testAddr + "?amount=-1")
+	require.Equal(t, errors.ErrInvalidValue, err)
+	_, err = ParseURI("skycoin:invalid")
+	require.Error(t, err)
+}
+
+func TestPaymentRequest_Destination(t *testing.T) {
+	req, err := ParseURI("skycoin:" + testAddr + "?amount=2.5&hours=7")
+	require.NoError(t, err)
+	out, err := req.Destination()
+	require.NoError(t, err)
+	addr, err := out.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, testAddr, addr.String())
+	coins, err := out.GetCoins(params.SkycoinTicker)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2500000), coins)
+	hours, err := out.GetCoins(params.CoinHoursTicker)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), hours)
+}
+
+func TestEncodeQR(t *testing.T) {
+	req := PaymentRequest{CoinTicker: params.SkycoinTicker, Address: testAddr, Amount: 1000000}
+	code, err := EncodeQR(req, qrutil.LevelM)
+	require.NoError(t, err)
+	// 55 bytes fit in version 4 at level M
+	require.Equal(t, 4, code.Version)
+
+	_, err = EncodeQR(PaymentRequest{CoinTicker: params.SkycoinTicker, Address: "invalid"}, qrutil.LevelM)
+	require.Error(t, err)
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		accuracy int32
+		amount   uint64
+		err      error
+	}{
+		{value: "1", accuracy: 6, amount: 1000000},
+		{value: "1.", accuracy: 6, amount: 1000000},
+		{value: ".5", accuracy: 6, amount: 500000},
+		{value: "0.123456", accuracy: 6, amount: 123456},
+		{value: "12.3400000", accuracy: 6, amount: 12340000},
+		{value: "0", accuracy: 6, amount: 0},
+		{value: "42", accuracy: 0, amount: 42},
+		{value: "0.1234567", accuracy: 6, err: errors.ErrInvalidAmountPrecision},
+		{value: "1.5", accuracy: 0, err: errors.ErrInvalidAmountPrecision},
+		{value: "", accuracy: 6, err: errors.ErrInvalidValue},
+		{value: ".", accuracy: 6, err: errors.ErrInvalidValue},
+		{value: "1e3", accuracy: 6, err: errors.ErrInvalidValue},
+		{value: "1,000", accuracy: 6, err: errors.ErrInvalidValue},
+		{value: "99999999999999999999", accuracy: 6, err: errors.ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		amount, err := ParseAmount(tt.value, tt.accuracy)
+		require.Equal(t, tt.err, err, tt.value)
+		require.Equal(t, tt.amount, amount, tt.value)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "0", FormatAmount(0, 6))
+	require.Equal(t, "0.000001", FormatAmount(1, 6))
+	require.Equal(t, "12.5", FormatAmount(12500000, 6))
+	require.Equal(t, "3", FormatAmount(3000000, 6))
+	require.Equal(t, "1234567", FormatAmount(1234567, 0))
+}
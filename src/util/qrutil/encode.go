@@ -0,0 +1,253 @@
+package qrutil
+
+import (
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+// Level error correction level of a QR code
+type Level int
+
+const (
+	// LevelL recovers about 7% of damaged codewords
+	LevelL Level = iota
+	// LevelM recovers about 15% of damaged codewords
+	LevelM
+	// LevelQ recovers about 25% of damaged codewords
+	LevelQ
+	// LevelH recovers about 30% of damaged codewords
+	LevelH
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+	// autoMask lets the encoder pick the mask with the lowest penalty
+	autoMask = -1
+)
+
+// formatBits identifies each level in format information
+var formatBits = [...]uint32{LevelL: 1, LevelM: 0, LevelQ: 3, LevelH: 2}
+
+// eccCodewordsPerBlock indexed by level and version
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// numErrorCorrectionBlocks indexed by level and version
+var numErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code QR code symbol , a square matrix of dark and light modules
+type Code struct {
+	// Version from 1 to 40 , determines the symbol size
+	Version int
+	// Level of error correction
+	Level Level
+	// Size number of modules per side
+	Size int
+
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// Encode renders payload in byte mode using the smallest version able to hold it at the given level
+func Encode(payload []byte, level Level) (*Code, error) {
+	return encode(payload, level, autoMask)
+}
+
+// Dark tells whether the module at column x and row y is dark , modules outside the symbol are light
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+}
+
+func encode(payload []byte, level Level, mask int) (*Code, error) {
+	if level < LevelL || level > LevelH {
+		return nil, errors.ErrInvalidValue
+	}
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+charCountBits(version)+len(payload)*8 <= numDataCodewords(version, level)*8 {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, errors.ErrQRPayloadTooLong
+	}
+
+	c := &Code{Version: version, Level: level, Size: version*4 + 17}
+	c.modules = newMatrix(c.Size)
+	c.isFunction = newMatrix(c.Size)
+	c.drawFunctionPatterns()
+	c.drawCodewords(c.interleaveWithEcc(dataCodewords(payload, version, level)))
+	if mask == autoMask {
+		minPenalty := -1
+		for m := 0; m < 8; m++ {
+			c.applyMask(m)
+			c.drawFormatBits(m)
+			penalty := c.penalty()
+			if minPenalty < 0 || penalty < minPenalty {
+				mask, minPenalty = m, penalty
+			}
+			// Masks are XOR , applying again restores modules
+			c.applyMask(m)
+		}
+	}
+	c.applyMask(mask)
+	c.drawFormatBits(mask)
+	c.isFunction = nil
+	return c, nil
+}
+
+func newMatrix(size int) [][]bool {
+	matrix := make([][]bool, size)
+	for i := range matrix {
+		matrix[i] = make([]bool, size)
+	}
+	return matrix
+}
+
+// charCountBits width of the character count indicator in byte mode
+func charCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// numRawDataModules counts modules available for data and error correction codewords
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// numDataCodewords counts codewords available for data once error correction is reserved
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numErrorCorrectionBlocks[level][version]
+}
+
+// bitBuffer sequence of bits appended most significant first
+type bitBuffer []bool
+
+func (bb *bitBuffer) appendBits(val uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (val>>uint(i))&1 != 0)
+	}
+}
+
+// dataCodewords packs payload in a byte mode segment , terminated and padded to capacity
+func dataCodewords(payload []byte, version int, level Level) []byte {
+	capacity := numDataCodewords(version, level) * 8
+	var bb bitBuffer
+	bb.appendBits(0x4, 4)
+	bb.appendBits(uint32(len(payload)), charCountBits(version))
+	for _, b := range payload {
+		bb.appendBits(uint32(b), 8)
+	}
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.appendBits(0, terminator)
+	bb.appendBits(0, (8-len(bb)%8)%8)
+	for pad := uint32(0xEC); len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.appendBits(pad, 8)
+	}
+	result := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			result[i>>3] |= 1 << uint(7-i&7)
+		}
+	}
+	return result
+}
+
+// interleaveWithEcc splits data in blocks , appends Reed-Solomon codewords to each and interleaves them
+func (c *Code) interleaveWithEcc(data []byte) []byte {
+	numBlocks := numErrorCorrectionBlocks[c.Level][c.Version]
+	blockEccLen := eccCodewordsPerBlock[c.Level][c.Version]
+	rawCodewords := numRawDataModules(c.Version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		datLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+		block := append([]byte{}, data[k:k+datLen]...)
+		k += datLen
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			// Placeholder keeping columns aligned , skipped while interleaving
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor generator polynomial of the given degree , leading term omitted
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder error correction codewords for data
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply product in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
@@ -0,0 +1,264 @@
+package qrutil
+
+// setFunctionModule draws a module reserved for patterns and metadata
+func (c *Code) setFunctionModule(x, y int, isDark bool) {
+	c.modules[y][x] = isDark
+	c.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns draws timing , finder and alignment patterns plus version information.
+// Format bits are reserved with a dummy mask until the final mask is known.
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.setFunctionModule(6, i, i%2 == 0)
+		c.setFunctionModule(i, 6, i%2 == 0)
+	}
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(c.Size-4, 3)
+	c.drawFinderPattern(3, c.Size-4)
+
+	positions := alignmentPatternPositions(c.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Skip positions overlapping finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignmentPattern(x, y)
+		}
+	}
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+// drawFinderPattern draws a finder pattern and its separator centered at x , y
+func (c *Code) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+			dist := maxInt(absInt(dx), absInt(dy))
+			c.setFunctionModule(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignmentPattern draws a 5x5 alignment pattern centered at x , y
+func (c *Code) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunctionModule(x+dx, y+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPatternPositions centers of alignment patterns along each axis
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i > 0; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// drawFormatBits draws both copies of error correction level and mask , protected by a BCH code
+func (c *Code) drawFormatBits(mask int) {
+	data := formatBits[c.Level]<<3 | uint32(mask)
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool {
+		return (bits>>uint(i))&1 != 0
+	}
+
+	for i := 0; i <= 5; i++ {
+		c.setFunctionModule(8, i, bit(i))
+	}
+	c.setFunctionModule(8, 7, bit(6))
+	c.setFunctionModule(8, 8, bit(7))
+	c.setFunctionModule(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunctionModule(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunctionModule(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunctionModule(8, c.Size-15+i, bit(i))
+	}
+	// Always dark
+	c.setFunctionModule(8, c.Size-8, true)
+}
+
+// drawVersion draws both copies of version information , only present since version 7
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := uint32(c.Version)
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := uint32(c.Version)<<12 | rem
+	for i := 0; i < 18; i++ {
+		isDark := (bits>>uint(i))&1 != 0
+		a, b := c.Size-11+i%3, i/3
+		c.setFunctionModule(a, b, isDark)
+		c.setFunctionModule(b, a, isDark)
+	}
+}
+
+// drawCodewords places codewords in zigzag order across columns pairs , right to left
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// Skip vertical timing pattern
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !c.isFunction[y][x] && i < len(data)*8 {
+					c.modules[y][x] = (data[i>>3]>>uint(7-i&7))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts data modules selected by mask pattern
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			c.modules[y][x] = c.modules[y][x] != invert
+		}
+	}
+}
+
+// penalty scores patterns hindering scanners , lower is better
+func (c *Code) penalty() int {
+	result := 0
+	for i := 0; i < c.Size; i++ {
+		result += c.linePenalty(func(j int) bool { return c.modules[i][j] })
+		result += c.linePenalty(func(j int) bool { return c.modules[j][i] })
+	}
+
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < c.Size && y+1 < c.Size {
+				color := c.modules[y][x]
+				if color == c.modules[y][x+1] && color == c.modules[y+1][x] && color == c.modules[y+1][x+1] {
+					result += 3
+				}
+			}
+		}
+	}
+
+	total := c.Size * c.Size
+	k := (absInt(dark*20-total*10)+total-1)/total - 1
+	return result + k*10
+}
+
+// finderLike dark and light runs 1:1:3:1:1 surrounded by light modules
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// linePenalty scores runs of same color and finder-like patterns in a row or column
+func (c *Code) linePenalty(at func(int) bool) int {
+	result := 0
+	runLen := 0
+	for j := 0; j < c.Size; j++ {
+		if j > 0 && at(j) == at(j-1) {
+			runLen++
+		} else {
+			runLen = 1
+		}
+		if runLen == 5 {
+			result += 3
+		} else if runLen > 5 {
+			result++
+		}
+	}
+	isLight := func(j int) bool {
+		return j < 0 || j >= c.Size || !at(j)
+	}
+	for j := 0; j+len(finderLike) <= c.Size; j++ {
+		matches := true
+		for k, isDark := range finderLike {
+			if at(j+k) != isDark {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		before, after := true, true
+		for k := 1; k <= 4; k++ {
+			before = before && isLight(j-k)
+			after = after && isLight(j+len(finderLike)-1+k)
+		}
+		if before || after {
+			result += 40
+		}
+	}
+	return result
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
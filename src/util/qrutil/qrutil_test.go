@@ -0,0 +1,122 @@
+package qrutil
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeMatrix(t *testing.T) {
+	expected := []string{
+		"#######.......#######",
+		"#.....#...#.#.#.....#",
+		"#.###.#.##....#.###.#",
+		"#.###.#.#.###.#.###.#",
+		"#.###.#.#..##.#.###.#",
+		"#.....#.##..#.#.....#",
+		"#######.#.#.#.#######",
+		"........#####........",
+		"#.#####..#..#.#####..",
+		".##.......#.#.#.#...#",
+		".##.####.#.#..##.#.#.",
+		"#.##...#.##....######",
+		".#.#.###..##....##...",
+		"........#.#######..##",
+		"#######..#..#.#..###.",
+		"#.....#.#.#####..##..",
+		"#.###.#.#...#..##...#",
+		"#.###.#.#.#.#..##.#..",
+		"#.###.#.####.#...#...",
+		"#.....#...#.....###..",
+		"#######.#..#.#..#..#.",
+	}
+	c, err := encode([]byte("skycoin:"), LevelM, 2)
+	require.NoError(t, err)
+	require.Equal(t, 1, c.Version)
+	require.Equal(t, len(expected), c.Size)
+	for y, row := range expected {
+		for x, module := range row {
+			require.Equal(t, module == '#', c.Dark(x, y), "module at %d,%d", x, y)
+		}
+	}
+	require.False(t, c.Dark(-1, 0))
+	require.False(t, c.Dark(0, c.Size))
+}
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		size    int
+		level   Level
+		version int
+	}{
+		{size: 17, level: LevelL, version: 1},
+		{size: 18, level: LevelL, version: 2},
+		{size: 14, level: LevelM, version: 1},
+		{size: 15, level: LevelM, version: 2},
+		{size: 154, level: LevelL, version: 7},
+		{size: 155, level: LevelL, version: 8},
+		{size: 84, level: LevelQ, version: 7},
+		{size: 2953, level: LevelL, version: 40},
+		{size: 1273, level: LevelH, version: 40},
+	}
+	for _, tt := range tests {
+		c, err := Encode(bytes.Repeat([]byte{'a'}, tt.size), tt.level)
+		require.NoError(t, err)
+		require.Equal(t, tt.version, c.Version, "%d bytes at level %d", tt.size, tt.level)
+		require.Equal(t, tt.version*4+17, c.Size)
+	}
+
+	_, err := Encode(bytes.Repeat([]byte{'a'}, 2954), LevelL)
+	require.Equal(t, errors.ErrQRPayloadTooLong, err)
+	_, err = Encode([]byte("a"), Level(4))
+	require.Equal(t, errors.ErrInvalidValue, err)
+}
+
+func TestCode_PNG(t *testing.T) {
+	c, err := Encode([]byte("skycoin:2JJ8pgq8EDAnrzf9xxBJapE2qkYLefW4uF8"), LevelM)
+	require.NoError(t, err)
+	raw, err := c.PNG(3)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(raw))
+	require.NoError(t, err)
+	side := (c.Size + 2*quietZone) * 3
+	require.Equal(t, side, img.Bounds().Dx())
+	require.Equal(t, side, img.Bounds().Dy())
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			r, _, _, _ := img.At((x+quietZone)*3+1, (y+quietZone)*3+1).RGBA()
+			require.Equal(t, c.Dark(x, y), r == 0)
+		}
+	}
+	r, _, _, _ := img.At(0, 0).RGBA()
+	require.NotZero(t, r)
+
+	_, err = c.PNG(0)
+	require.Equal(t, errors.ErrInvalidValue, err)
+}
+
+func TestCode_SVG(t *testing.T) {
+	c, err := Encode([]byte("skycoin:"), LevelM)
+	require.NoError(t, err)
+	svg, err := c.SVG(2)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(svg, "<svg "))
+	require.Contains(t, svg, `width="58" height="58"`)
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				dark++
+			}
+		}
+	}
+	require.Equal(t, dark, strings.Count(svg, "z"))
+	require.Contains(t, svg, "M8,8h2v2h-2z")
+
+	_, err = c.SVG(0)
+	require.Equal(t, errors.ErrInvalidValue, err)
+}
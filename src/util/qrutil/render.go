@@ -0,0 +1,64 @@
+package qrutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+// quietZone light modules surrounding the symbol as required by scanners
+const quietZone = 4
+
+// Image returns the symbol with its quiet zone , scale pixels per module
+func (c *Code) Image(scale int) (image.Image, error) {
+	if scale < 1 {
+		return nil, errors.ErrInvalidValue
+	}
+	side := (c.Size + 2*quietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			if c.Dark(x/scale-quietZone, y/scale-quietZone) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img, nil
+}
+
+// PNG encodes the symbol as a PNG image , scale pixels per module
+func (c *Code) PNG(scale int) ([]byte, error) {
+	img, err := c.Image(scale)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the symbol as an SVG document , scale user units per module
+func (c *Code) SVG(scale int) (string, error) {
+	if scale < 1 {
+		return "", errors.ErrInvalidValue
+	}
+	side := (c.Size + 2*quietZone) * scale
+	var path strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				fmt.Fprintf(&path, "M%d,%dh%dv%dh-%dz", (x+quietZone)*scale, (y+quietZone)*scale, scale, scale, scale)
+			}
+		}
+	}
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="100%%" height="100%%" fill="#FFFFFF"/><path d="%s" fill="#000000"/></svg>`,
+		side, side, side, side, path.String()), nil
+}
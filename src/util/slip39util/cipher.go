@@ -0,0 +1,62 @@
+package slip39util
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// baseIterations total PBKDF2 iterations with iteration exponent 0
+	baseIterations = 10000
+	// feistelRounds rounds of the Feistel network encrypting the master secret
+	feistelRounds = 4
+)
+
+// encrypt turns the master secret into the encrypted master secret shared by shares
+func encrypt(masterSecret []byte, passphrase string, iterationExp int, identifier uint16, extendable bool) []byte {
+	half := len(masterSecret) / 2
+	l, r := masterSecret[:half], masterSecret[half:]
+	salt := cipherSalt(identifier, extendable)
+	for i := 0; i < feistelRounds; i++ {
+		l, r = r, xorBytes(l, roundFunction(i, passphrase, iterationExp, salt, r))
+	}
+	return append(append([]byte(nil), r...), l...)
+}
+
+// decrypt recovers the master secret from the encrypted master secret
+func decrypt(encrypted []byte, passphrase string, iterationExp int, identifier uint16, extendable bool) []byte {
+	half := len(encrypted) / 2
+	l, r := encrypted[:half], encrypted[half:]
+	salt := cipherSalt(identifier, extendable)
+	for i := feistelRounds - 1; i >= 0; i-- {
+		l, r = r, xorBytes(l, roundFunction(i, passphrase, iterationExp, salt, r))
+	}
+	return append(append([]byte(nil), r...), l...)
+}
+
+func roundFunction(i int, passphrase string, iterationExp int, salt, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	iterations := (baseIterations << uint(iterationExp)) / feistelRounds
+	return pbkdf2.Key(password, append(append([]byte(nil), salt...), r...), iterations, len(r), sha256.New)
+}
+
+// cipherSalt binds the encryption to the identifier , except for extendable share sets
+func cipherSalt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	salt := make([]byte, len(customization)+2)
+	copy(salt, customization)
+	binary.BigEndian.PutUint16(salt[len(customization):], identifier)
+	return salt
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
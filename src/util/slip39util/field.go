@@ -0,0 +1,56 @@
+package slip39util
+
+// expTable and logTable arithmetic in GF(256) with the Rijndael polynomial x^8 + x^4 + x^3 + x + 1 ,
+// using 3 as generator
+var expTable, logTable = buildFieldTables()
+
+func buildFieldTables() (exp [255]byte, log [256]byte) {
+	poly := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(poly)
+		log[poly] = byte(i)
+		// Multiply by the generator x + 1
+		poly = (poly << 1) ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11b
+		}
+	}
+	return exp, log
+}
+
+// interpolate evaluates at x the polynomial of lowest degree going through points ,
+// every point value must have the same length
+func interpolate(points []point, x byte) []byte {
+	for _, p := range points {
+		if p.x == x {
+			return append([]byte(nil), p.value...)
+		}
+	}
+	// Lagrange interpolation in log space , logProd is the log of prod(p.x ^ x)
+	logProd := 0
+	for _, p := range points {
+		logProd += int(logTable[p.x^x])
+	}
+	result := make([]byte, len(points[0].value))
+	for _, p := range points {
+		logBasis := logProd - int(logTable[p.x^x])
+		for _, other := range points {
+			if other.x != p.x {
+				logBasis -= int(logTable[p.x^other.x])
+			}
+		}
+		logBasis = ((logBasis % 255) + 255) % 255
+		for i, v := range p.value {
+			if v != 0 {
+				result[i] ^= expTable[(int(logTable[v])+logBasis)%255]
+			}
+		}
+	}
+	return result
+}
+
+// point value of a polynomial at x
+type point struct {
+	x     byte
+	value []byte
+}
@@ -0,0 +1,58 @@
+package slip39util
+
+const (
+	// checksumWords length of the Reed-Solomon checksum at the end of every share
+	checksumWords = 3
+	// customization strings of the checksum , depending on the extendable flag
+	customization           = "shamir"
+	customizationExtendable = "shamir_extendable"
+)
+
+// rs1024Generator coefficients of the Reed-Solomon code over GF(1024)
+var rs1024Generator = [10]uint32{
+	0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+	0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+}
+
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ uint32(v)
+		for i, gen := range rs1024Generator {
+			if (b>>uint(i))&1 != 0 {
+				chk ^= gen
+			}
+		}
+	}
+	return chk
+}
+
+func customizationValues(extendable bool) []int {
+	cs := customization
+	if extendable {
+		cs = customizationExtendable
+	}
+	values := make([]int, len(cs))
+	for i := range cs {
+		values[i] = int(cs[i])
+	}
+	return values
+}
+
+// rs1024Checksum returns the checksum words of data
+func rs1024Checksum(data []int, extendable bool) []int {
+	values := append(customizationValues(extendable), data...)
+	values = append(values, make([]int, checksumWords)...)
+	polymod := rs1024Polymod(values) ^ 1
+	checksum := make([]int, checksumWords)
+	for i := range checksum {
+		checksum[i] = int(polymod>>uint(radixBits*(checksumWords-1-i))) & (radix - 1)
+	}
+	return checksum
+}
+
+// rs1024Verify determines whether data ends with a valid checksum
+func rs1024Verify(data []int, extendable bool) bool {
+	return rs1024Polymod(append(customizationValues(extendable), data...)) == 1
+}
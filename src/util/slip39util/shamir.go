@@ -0,0 +1,81 @@
+package slip39util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+const (
+	// digestLength bytes of the digest protecting shared secrets
+	digestLength = 4
+	// digestIndex and secretIndex x coordinates of the digest and the secret
+	digestIndex = 254
+	secretIndex = 255
+)
+
+// splitSecret splits secret in count shares , any threshold of them recover it
+func splitSecret(threshold, count int, secret []byte) ([]point, error) {
+	if threshold < 1 || threshold > count || count > maxShareCount {
+		return nil, errors.ErrInvalidSharing
+	}
+	shares := make([]point, 0, count)
+	if threshold == 1 {
+		for i := 0; i < count; i++ {
+			shares = append(shares, point{x: byte(i), value: append([]byte(nil), secret...)})
+		}
+		return shares, nil
+	}
+
+	// threshold - 2 random shares , plus digest and secret , define the polynomial
+	for i := 0; i < threshold-2; i++ {
+		value, err := randomBytes(len(secret))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, point{x: byte(i), value: value})
+	}
+	randomPart, err := randomBytes(len(secret) - digestLength)
+	if err != nil {
+		return nil, err
+	}
+	digestValue := append(secretDigest(randomPart, secret), randomPart...)
+	base := append(append([]point(nil), shares...),
+		point{x: digestIndex, value: digestValue},
+		point{x: secretIndex, value: secret})
+	for i := threshold - 2; i < count; i++ {
+		shares = append(shares, point{x: byte(i), value: interpolate(base, byte(i))})
+	}
+	return shares, nil
+}
+
+// recoverSecret recovers the secret from threshold shares , verifying its digest
+func recoverSecret(threshold int, shares []point) ([]byte, error) {
+	if threshold == 1 {
+		return append([]byte(nil), shares[0].value...), nil
+	}
+	secret := interpolate(shares, secretIndex)
+	digestValue := interpolate(shares, digestIndex)
+	digest := secretDigest(digestValue[digestLength:], secret)
+	if subtle.ConstantTimeCompare(digest, digestValue[:digestLength]) != 1 {
+		return nil, errors.ErrShareDigest
+	}
+	return secret, nil
+}
+
+func secretDigest(randomPart, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomPart)
+	_, _ = mac.Write(secret)
+	return mac.Sum(nil)[:digestLength]
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
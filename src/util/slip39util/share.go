@@ -0,0 +1,166 @@
+package slip39util
+
+import (
+	"strings"
+
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+const (
+	// radixBits bits encoded by every word
+	radixBits = 10
+	radix     = 1 << radixBits
+	// idExpWords words holding identifier , extendable flag and iteration exponent
+	idExpWords = 2
+	// shareParamsWords words holding group and member indices and thresholds
+	shareParamsWords = 2
+	// metadataWords words of a share not holding its value
+	metadataWords = idExpWords + shareParamsWords + checksumWords
+	// minShareWords words of shares with the shortest secret
+	minShareWords = metadataWords + (minSecretBytes*8+radixBits-1)/radixBits
+	// prefixLength letters identifying a word
+	prefixLength = 4
+	// maxShareCount members per group and groups per secret
+	maxShareCount = 16
+)
+
+// wordIndex maps words and their prefixes to their index in the wordlist
+var wordIndex = buildWordIndex()
+
+func buildWordIndex() map[string]int {
+	index := make(map[string]int, 2*len(wordlist))
+	for i, w := range wordlist {
+		index[w] = i
+		index[w[:prefixLength]] = i
+	}
+	return index
+}
+
+// Share decoded share mnemonic
+type Share struct {
+	// Identifier random 15 bits shared by all shares of a secret
+	Identifier uint16
+	// Extendable whether more share sets may be created for the same secret
+	Extendable bool
+	// IterationExponent PBKDF2 iterations are 10000 * 2^IterationExponent
+	IterationExponent int
+	GroupIndex        int
+	GroupThreshold    int
+	GroupCount        int
+	MemberIndex       int
+	MemberThreshold   int
+	// Value share of the encrypted secret
+	Value []byte
+}
+
+// Mnemonic encodes share as words , followed by its checksum
+func (share *Share) Mnemonic() string {
+	ext := 0
+	if share.Extendable {
+		ext = 1
+	}
+	idExp := int(share.Identifier)<<5 | ext<<4 | share.IterationExponent
+	params := share.GroupIndex<<16 | (share.GroupThreshold-1)<<12 | (share.GroupCount-1)<<8 |
+		share.MemberIndex<<4 | (share.MemberThreshold - 1)
+	data := []int{idExp >> radixBits, idExp & (radix - 1), params >> radixBits, params & (radix - 1)}
+	data = append(data, bytesToIndices(share.Value)...)
+	data = append(data, rs1024Checksum(data, share.Extendable)...)
+
+	words := make([]string, len(data))
+	for i, idx := range data {
+		words[i] = wordlist[idx]
+	}
+	return strings.Join(words, " ")
+}
+
+// ParseShare decodes a share mnemonic , failing if any word is unknown or the checksum does not match.
+// Words may be abbreviated to their first four letters
+func ParseShare(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minShareWords {
+		return nil, errors.ErrInvalidShare
+	}
+	data := make([]int, len(words))
+	for i, w := range words {
+		idx, isWord := wordIndex[w]
+		if !isWord {
+			return nil, errors.ErrInvalidShare
+		}
+		data[i] = idx
+	}
+
+	idExp := data[0]<<radixBits | data[1]
+	share := &Share{
+		Identifier:        uint16(idExp >> 5),
+		Extendable:        (idExp>>4)&1 == 1,
+		IterationExponent: idExp & 0xf,
+	}
+	if !rs1024Verify(data, share.Extendable) {
+		return nil, errors.ErrShareChecksum
+	}
+
+	params := data[2]<<radixBits | data[3]
+	share.GroupIndex = params >> 16
+	share.GroupThreshold = (params>>12)&0xf + 1
+	share.GroupCount = (params>>8)&0xf + 1
+	share.MemberIndex = (params >> 4) & 0xf
+	share.MemberThreshold = params&0xf + 1
+	if share.GroupThreshold > share.GroupCount {
+		return nil, errors.ErrInvalidShare
+	}
+
+	value, err := indicesToBytes(data[idExpWords+shareParamsWords : len(data)-checksumWords])
+	if err != nil {
+		return nil, err
+	}
+	share.Value = value
+	return share, nil
+}
+
+// bytesToIndices encodes value as 10 bits words , left padded with zeros
+func bytesToIndices(value []byte) []int {
+	bits := len(value) * 8
+	count := (bits + radixBits - 1) / radixBits
+	indices := make([]int, count)
+	// Walk the padded bit string from its least significant bit
+	acc, accBits, pos := 0, 0, count-1
+	for i := len(value) - 1; i >= 0; i-- {
+		acc |= int(value[i]) << uint(accBits)
+		accBits += 8
+		for accBits >= radixBits {
+			indices[pos] = acc & (radix - 1)
+			acc >>= radixBits
+			accBits -= radixBits
+			pos--
+		}
+	}
+	if pos >= 0 {
+		indices[pos] = acc
+	}
+	return indices
+}
+
+// indicesToBytes decodes a share value , padding must take less than a byte and be zero
+func indicesToBytes(indices []int) ([]byte, error) {
+	padding := (radixBits * len(indices)) % 16
+	if padding > 8 {
+		return nil, errors.ErrInvalidShare
+	}
+	size := (radixBits*len(indices) - padding) / 8
+	if len(indices) == 0 || indices[0] >= 1<<uint(radixBits-padding) {
+		return nil, errors.ErrInvalidShare
+	}
+	value := make([]byte, size)
+	acc, accBits, pos := 0, 0, size-1
+	for i := len(indices) - 1; i >= 0; i-- {
+		acc |= indices[i] << uint(accBits)
+		accBits += radixBits
+		for accBits >= 8 && pos >= 0 {
+			value[pos] = byte(acc)
+			acc >>= 8
+			accBits -= 8
+			pos--
+		}
+	}
+	return value, nil
+}
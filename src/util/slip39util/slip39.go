@@ -0,0 +1,183 @@
+package slip39util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+)
+
+const (
+	// minSecretBytes shortest master secret , 128 bits
+	minSecretBytes = 16
+	// maxIterationExponent largest iteration exponent encoded in shares
+	maxIterationExponent = 15
+	// DefaultIterationExponent PBKDF2 iterations exponent used by reference implementations
+	DefaultIterationExponent = 1
+)
+
+// Group members of a group and how many of them recover the group share
+type Group struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// SplitOptions parameters of the shares
+type SplitOptions struct {
+	// IterationExponent slows down passphrase brute forcing , see Share
+	IterationExponent int
+	// Extendable allows splitting the same secret again with the same passphrase
+	Extendable bool
+}
+
+// SplitSecret splits masterSecret in share mnemonics , listed by group. Recovering it requires
+// groupThreshold groups , each with MemberThreshold of its shares. Passphrase is optional
+func SplitSecret(masterSecret []byte, passphrase string, groupThreshold int, groups []Group, opts SplitOptions) ([][]string, error) {
+	if len(masterSecret) < minSecretBytes || len(masterSecret)%2 != 0 {
+		return nil, errors.ErrInvalidSecretLength
+	}
+	if !isPrintableASCII(passphrase) {
+		return nil, errors.ErrInvalidSharePassphrase
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) || len(groups) > maxShareCount ||
+		opts.IterationExponent < 0 || opts.IterationExponent > maxIterationExponent {
+		return nil, errors.ErrInvalidSharing
+	}
+	for _, g := range groups {
+		// A single share would be recovered by any member , use 1-of-1 instead
+		if g.MemberThreshold == 1 && g.MemberCount > 1 {
+			return nil, errors.ErrInvalidSharing
+		}
+	}
+
+	idBytes, err := randomBytes(2)
+	if err != nil {
+		return nil, err
+	}
+	identifier := binary.BigEndian.Uint16(idBytes) & 0x7fff
+	encrypted := encrypt(masterSecret, passphrase, opts.IterationExponent, identifier, opts.Extendable)
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), encrypted)
+	if err != nil {
+		return nil, err
+	}
+	mnemonics := make([][]string, len(groups))
+	for i, g := range groups {
+		memberShares, err := splitSecret(g.MemberThreshold, g.MemberCount, groupShares[i].value)
+		if err != nil {
+			return nil, err
+		}
+		mnemonics[i] = make([]string, len(memberShares))
+		for j, member := range memberShares {
+			share := Share{
+				Identifier:        identifier,
+				Extendable:        opts.Extendable,
+				IterationExponent: opts.IterationExponent,
+				GroupIndex:        i,
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       int(member.x),
+				MemberThreshold:   g.MemberThreshold,
+				Value:             member.value,
+			}
+			mnemonics[i][j] = share.Mnemonic()
+		}
+	}
+	return mnemonics, nil
+}
+
+// CombineShares recovers the master secret from share mnemonics , every share is validated
+// before recovering it. As in the reference implementation exactly the threshold of groups ,
+// each with exactly its threshold of members , must be given. Repeated mnemonics are ignored
+func CombineShares(mnemonics []string, passphrase string) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, errors.ErrInsufficientShares
+	}
+	if !isPrintableASCII(passphrase) {
+		return nil, errors.ErrInvalidSharePassphrase
+	}
+	shares := make([]*Share, len(mnemonics))
+	for i, m := range mnemonics {
+		share, err := ParseShare(m)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+	}
+
+	first := shares[0]
+	groups := make(map[int][]*Share)
+	for _, s := range shares {
+		if s.Identifier != first.Identifier || s.Extendable != first.Extendable ||
+			s.IterationExponent != first.IterationExponent || s.GroupThreshold != first.GroupThreshold ||
+			s.GroupCount != first.GroupCount || len(s.Value) != len(first.Value) {
+			return nil, errors.ErrMismatchedShares
+		}
+		isRepeated := false
+		for _, other := range groups[s.GroupIndex] {
+			if other.MemberThreshold != s.MemberThreshold {
+				return nil, errors.ErrMismatchedShares
+			}
+			if other.MemberIndex == s.MemberIndex {
+				if !bytes.Equal(other.Value, s.Value) {
+					return nil, errors.ErrMismatchedShares
+				}
+				isRepeated = true
+			}
+		}
+		if !isRepeated {
+			groups[s.GroupIndex] = append(groups[s.GroupIndex], s)
+		}
+	}
+	if len(groups) < first.GroupThreshold {
+		return nil, errors.ErrInsufficientShares
+	}
+	if len(groups) > first.GroupThreshold {
+		return nil, errors.ErrExtraShares
+	}
+
+	// Recover the share of every group
+	indices := make([]int, 0, len(groups))
+	for idx := range groups {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	for _, idx := range indices {
+		members := groups[idx]
+		if len(members) < members[0].MemberThreshold {
+			return nil, errors.ErrInsufficientShares
+		}
+		if len(members) > members[0].MemberThreshold {
+			return nil, errors.ErrExtraShares
+		}
+	}
+	groupShares := make([]point, 0, first.GroupThreshold)
+	for _, idx := range indices {
+		members := groups[idx]
+		points := make([]point, len(members))
+		for i, m := range members {
+			points[i] = point{x: byte(m.MemberIndex), value: m.Value}
+		}
+		value, err := recoverSecret(len(members), points)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, point{x: byte(idx), value: value})
+	}
+
+	encrypted, err := recoverSecret(first.GroupThreshold, groupShares)
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(encrypted, passphrase, first.IterationExponent, first.Identifier, first.Extendable), nil
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 32 || s[i] > 126 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package slip39util
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SkycoinProject/skycoin/src/cipher/bip32"
+	"github.com/fibercrypto/fibercryptowallet/src/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testPassphrase passphrase of the SLIP-39 test vectors
+const testPassphrase = "TREZOR"
+
+// vector test vector in the format of the SLIP-39 reference vectors.json ,
+// an empty secret marks mnemonics which must be rejected
+type vector struct {
+	Description string
+	Mnemonics   []string
+	Secret      string
+	Xprv        string
+}
+
+func (v *vector) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &[]interface{}{&v.Description, &v.Mnemonics, &v.Secret, &v.Xprv})
+}
+
+func TestCombineSharesVectors(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "vectors.json"))
+	require.NoError(t, err)
+	var vectors []vector
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		t.Run(v.Description, func(t *testing.T) {
+			secret, err := CombineShares(v.Mnemonics, testPassphrase)
+			if v.Secret == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, v.Secret, hex.EncodeToString(secret))
+			if v.Xprv != "" {
+				key, err := bip32.NewMasterKey(secret)
+				require.NoError(t, err)
+				require.Equal(t, v.Xprv, key.String())
+			}
+		})
+	}
+}
+
+func TestParseShare(t *testing.T) {
+	mnemonic := "shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed"
+	share, err := ParseShare(mnemonic)
+	require.NoError(t, err)
+	require.Equal(t, 0, share.GroupIndex)
+	require.Equal(t, 1, share.GroupThreshold)
+	require.Equal(t, 1, share.GroupCount)
+	require.Equal(t, 2, share.MemberThreshold)
+	require.Len(t, share.Value, 16)
+	require.Equal(t, mnemonic, share.Mnemonic())
+
+	// Words may be abbreviated and typed in upper case
+	words := strings.Fields(mnemonic)
+	for i, w := range words {
+		if i%2 == 0 && len(w) > prefixLength {
+			words[i] = strings.ToUpper(w[:prefixLength])
+		}
+	}
+	abbreviated, err := ParseShare(strings.Join(words, " "))
+	require.NoError(t, err)
+	require.Equal(t, share, abbreviated)
+
+	_, err = ParseShare(strings.Replace(mnemonic, "wildlife", "wildcat", 1))
+	require.Equal(t, errors.ErrInvalidShare, err)
+	_, err = ParseShare(strings.Replace(mnemonic, "wildlife", "wolf", 1))
+	require.Equal(t, errors.ErrShareChecksum, err)
+	_, err = ParseShare("shadow pistol academic always")
+	require.Equal(t, errors.ErrInvalidShare, err)
+}
+
+func TestSplitSecret(t *testing.T) {
+	secret, err := hex.DecodeString("bb54aac4b89dc868ba37d9cc21b2cece")
+	require.NoError(t, err)
+	groups := []Group{{MemberThreshold: 2, MemberCount: 3}, {MemberThreshold: 1, MemberCount: 1}, {MemberThreshold: 3, MemberCount: 5}}
+
+	for _, opts := range []SplitOptions{{}, {IterationExponent: DefaultIterationExponent, Extendable: true}} {
+		mnemonics, err := SplitSecret(secret, testPassphrase, 2, groups, opts)
+		require.NoError(t, err)
+		require.Len(t, mnemonics, len(groups))
+		for i, g := range groups {
+			require.Len(t, mnemonics[i], g.MemberCount)
+			for _, m := range mnemonics[i] {
+				share, err := ParseShare(m)
+				require.NoError(t, err)
+				require.Equal(t, opts.Extendable, share.Extendable)
+				require.Equal(t, i, share.GroupIndex)
+				require.Equal(t, g.MemberThreshold, share.MemberThreshold)
+			}
+		}
+
+		combinations := [][]string{
+			{mnemonics[0][0], mnemonics[0][2], mnemonics[1][0]},
+			{mnemonics[2][4], mnemonics[1][0], mnemonics[2][1], mnemonics[2][0]},
+			{mnemonics[0][1], mnemonics[2][3], mnemonics[0][2], mnemonics[2][0], mnemonics[2][2]},
+			// Repeated mnemonics are ignored
+			{mnemonics[0][0], mnemonics[0][2], mnemonics[1][0], mnemonics[0][0]},
+		}
+		for _, shares := range combinations {
+			recovered, err := CombineShares(shares, testPassphrase)
+			require.NoError(t, err)
+			require.Equal(t, secret, recovered)
+		}
+
+		// A wrong passphrase decrypts a different secret
+		recovered, err := CombineShares(combinations[0], "")
+		require.NoError(t, err)
+		require.NotEqual(t, secret, recovered)
+
+		_, err = CombineShares([]string{mnemonics[0][0], mnemonics[2][0], mnemonics[2][1]}, testPassphrase)
+		require.Equal(t, errors.ErrInsufficientShares, err)
+		_, err = CombineShares([]string{mnemonics[0][0], mnemonics[0][0], mnemonics[1][0]}, testPassphrase)
+		require.Equal(t, errors.ErrInsufficientShares, err)
+		// Exactly the thresholds of groups and members must be given
+		_, err = CombineShares([]string{mnemonics[0][1], mnemonics[1][0], mnemonics[2][0], mnemonics[0][2]}, testPassphrase)
+		require.Equal(t, errors.ErrExtraShares, err)
+		_, err = CombineShares([]string{mnemonics[0][0], mnemonics[0][1], mnemonics[0][2], mnemonics[1][0]}, testPassphrase)
+		require.Equal(t, errors.ErrExtraShares, err)
+	}
+
+	// Shares of different secrets are not mixed
+	other, err := SplitSecret(secret, testPassphrase, 1, []Group{{MemberThreshold: 2, MemberCount: 2}}, SplitOptions{})
+	require.NoError(t, err)
+	mnemonics, err := SplitSecret(secret, testPassphrase, 1, []Group{{MemberThreshold: 2, MemberCount: 2}}, SplitOptions{})
+	require.NoError(t, err)
+	_, err = CombineShares([]string{mnemonics[0][0], other[0][1]}, testPassphrase)
+	require.Equal(t, errors.ErrMismatchedShares, err)
+
+	// 256 bits secrets
+	long := make([]byte, 32)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	mnemonics, err = SplitSecret(long, "", 1, []Group{{MemberThreshold: 3, MemberCount: 16}}, SplitOptions{})
+	require.NoError(t, err)
+	recovered, err := CombineShares(mnemonics[0][5:8], "")
+	require.NoError(t, err)
+	require.Equal(t, long, recovered)
+}
+
+func TestSplitSecretInvalid(t *testing.T) {
+	secret := make([]byte, 16)
+	tests := []struct {
+		name           string
+		secret         []byte
+		passphrase     string
+		groupThreshold int
+		groups         []Group
+		opts           SplitOptions
+		err            error
+	}{
+		{name: "short-secret", secret: make([]byte, 14), groupThreshold: 1, groups: []Group{{1, 1}}, err: errors.ErrInvalidSecretLength},
+		{name: "odd-secret", secret: make([]byte, 17), groupThreshold: 1, groups: []Group{{1, 1}}, err: errors.ErrInvalidSecretLength},
+		{name: "passphrase", secret: secret, passphrase: "contraseña", groupThreshold: 1, groups: []Group{{1, 1}}, err: errors.ErrInvalidSharePassphrase},
+		{name: "no-groups", secret: secret, groupThreshold: 1, err: errors.ErrInvalidSharing},
+		{name: "group-threshold", secret: secret, groupThreshold: 2, groups: []Group{{1, 1}}, err: errors.ErrInvalidSharing},
+		{name: "member-threshold", secret: secret, groupThreshold: 1, groups: []Group{{3, 2}}, err: errors.ErrInvalidSharing},
+		{name: "single-member-threshold", secret: secret, groupThreshold: 1, groups: []Group{{1, 3}}, err: errors.ErrInvalidSharing},
+		{name: "member-count", secret: secret, groupThreshold: 1, groups: []Group{{2, 17}}, err: errors.ErrInvalidSharing},
+		{name: "iteration-exponent", secret: secret, groupThreshold: 1, groups: []Group{{1, 1}}, opts: SplitOptions{IterationExponent: 16}, err: errors.ErrInvalidSharing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SplitSecret(tt.secret, tt.passphrase, tt.groupThreshold, tt.groups, tt.opts)
+			require.Equal(t, tt.err, err)
+		})
+	}
+}